@@ -10,6 +10,9 @@ import (
 type Config struct {
 	// DataRoot is the base directory for persistent data (cloned remotes, etc.)
 	DataRoot string
+	// IngestRoot is the only directory local ingest sources (repositories,
+	// bundles, archives) may be read from. Relative sources resolve against it.
+	IngestRoot string
 }
 
 // DefaultConfig returns the default configuration, reading from environment variables.
@@ -18,8 +21,13 @@ func DefaultConfig() *Config {
 	if dataRoot == "" {
 		dataRoot = ".gitgym-data"
 	}
+	ingestRoot := os.Getenv("GITGYM_INGEST_ROOT")
+	if ingestRoot == "" {
+		ingestRoot = filepath.Join(dataRoot, "ingest")
+	}
 	return &Config{
-		DataRoot: dataRoot,
+		DataRoot:   dataRoot,
+		IngestRoot: ingestRoot,
 	}
 }
 
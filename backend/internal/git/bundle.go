@@ -0,0 +1,312 @@
+package git
+
+// bundle.go - Git Bundle (v2) Reader/Writer
+//
+// A bundle is a single file carrying a ref advertisement and a packfile, used
+// to move history between repositories without a network connection:
+//
+//	# v2 git bundle
+//	-<prerequisite-sha> <subject>
+//	<sha> <refname>
+//	<blank line>
+//	<packfile>
+//
+// Bundles written here are byte-compatible with `git bundle` so learners can
+// carry them between GitGym and a real git installation.
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/packfile"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/revlist"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/go-git/go-git/v5/storage"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+// BundleSignatureV2 is the first line of every v2 bundle file.
+const BundleSignatureV2 = "# v2 git bundle"
+
+// ErrNotBundle is returned when data does not start with a bundle signature.
+var ErrNotBundle = errors.New("not a git bundle")
+
+// BundlePrerequisite is a commit the receiving repository must already have.
+type BundlePrerequisite struct {
+	Hash    plumbing.Hash
+	Comment string
+}
+
+// Bundle is a parsed bundle file.
+type Bundle struct {
+	Prerequisites []BundlePrerequisite
+	References    []*plumbing.Reference
+	Packfile      []byte
+}
+
+// IsBundle reports whether data looks like a git bundle.
+func IsBundle(data []byte) bool {
+	return bytes.HasPrefix(data, []byte(BundleSignatureV2+"\n"))
+}
+
+// ReadBundle parses a v2 bundle from r.
+func ReadBundle(r io.Reader) (*Bundle, error) {
+	br := bufio.NewReader(r)
+
+	sig, err := br.ReadString('\n')
+	if err != nil || strings.TrimSuffix(sig, "\n") != BundleSignatureV2 {
+		return nil, ErrNotBundle
+	}
+
+	b := &Bundle{}
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			return nil, fmt.Errorf("truncated bundle header: %w", err)
+		}
+		line = strings.TrimSuffix(line, "\n")
+		if line == "" {
+			break
+		}
+
+		if strings.HasPrefix(line, "-") {
+			hashStr, comment, _ := strings.Cut(line[1:], " ")
+			if !plumbing.IsHash(hashStr) {
+				return nil, fmt.Errorf("invalid prerequisite line: %q", line)
+			}
+			b.Prerequisites = append(b.Prerequisites, BundlePrerequisite{
+				Hash:    plumbing.NewHash(hashStr),
+				Comment: comment,
+			})
+			continue
+		}
+
+		hashStr, name, ok := strings.Cut(line, " ")
+		if !ok || !plumbing.IsHash(hashStr) {
+			return nil, fmt.Errorf("invalid ref line: %q", line)
+		}
+		b.References = append(b.References, plumbing.NewHashReference(plumbing.ReferenceName(name), plumbing.NewHash(hashStr)))
+	}
+
+	pack, err := io.ReadAll(br)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read bundle packfile: %w", err)
+	}
+	b.Packfile = pack
+	return b, nil
+}
+
+// WriteBundle writes a v2 bundle containing refs and every object reachable from
+// them that is not reachable from prerequisites.
+// It returns the number of objects packed.
+func WriteBundle(w io.Writer, s storer.EncodedObjectStorer, refs []*plumbing.Reference, prerequisites []plumbing.Hash) (int, error) {
+	if len(refs) == 0 {
+		return 0, fmt.Errorf("refusing to create empty bundle")
+	}
+
+	wants := make([]plumbing.Hash, 0, len(refs))
+	for _, ref := range refs {
+		wants = append(wants, ref.Hash())
+	}
+
+	hashes, err := revlist.Objects(s, wants, prerequisites)
+	if err != nil {
+		return 0, fmt.Errorf("failed to enumerate objects: %w", err)
+	}
+
+	var sb strings.Builder
+	sb.WriteString(BundleSignatureV2 + "\n")
+	for _, p := range prerequisites {
+		comment := ""
+		if c, err := object.GetCommit(s, p); err == nil {
+			comment = strings.SplitN(c.Message, "\n", 2)[0]
+		}
+		fmt.Fprintf(&sb, "-%s %s\n", p, comment)
+	}
+	for _, ref := range refs {
+		fmt.Fprintf(&sb, "%s %s\n", ref.Hash(), ref.Name())
+	}
+	sb.WriteString("\n")
+
+	if _, err := io.WriteString(w, sb.String()); err != nil {
+		return 0, err
+	}
+
+	enc := packfile.NewEncoder(w, s, false)
+	if _, err := enc.Encode(hashes, 10); err != nil {
+		return 0, fmt.Errorf("failed to encode packfile: %w", err)
+	}
+	return len(hashes), nil
+}
+
+// BundleBoundary computes the prerequisite commits for a bundle of the given
+// tips that excludes everything reachable from exclude. These are the excluded
+// commits whose children are included, matching `git bundle create A..B`.
+func BundleBoundary(s storer.EncodedObjectStorer, tips, exclude []plumbing.Hash) ([]plumbing.Hash, error) {
+	if len(exclude) == 0 {
+		return nil, nil
+	}
+
+	excluded := make(map[plumbing.Hash]bool)
+	queue := append([]plumbing.Hash{}, exclude...)
+	for len(queue) > 0 {
+		h := queue[0]
+		queue = queue[1:]
+		if excluded[h] {
+			continue
+		}
+		c, err := object.GetCommit(s, h)
+		if err != nil {
+			return nil, err
+		}
+		excluded[h] = true
+		queue = append(queue, c.ParentHashes...)
+	}
+
+	boundary := make(map[plumbing.Hash]bool)
+	seen := make(map[plumbing.Hash]bool)
+	queue = append([]plumbing.Hash{}, tips...)
+	for len(queue) > 0 {
+		h := queue[0]
+		queue = queue[1:]
+		if seen[h] {
+			continue
+		}
+		seen[h] = true
+		if excluded[h] {
+			continue
+		}
+		c, err := object.GetCommit(s, h)
+		if err != nil {
+			continue // Tips may be non-commits (e.g. tagged blobs)
+		}
+		for _, p := range c.ParentHashes {
+			if excluded[p] {
+				boundary[p] = true
+			} else {
+				queue = append(queue, p)
+			}
+		}
+	}
+
+	result := make([]plumbing.Hash, 0, len(boundary))
+	for h := range boundary {
+		result = append(result, h)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].String() < result[j].String() })
+	return result, nil
+}
+
+// MissingPrerequisites returns the prerequisites not present in s.
+func (b *Bundle) MissingPrerequisites(s storer.EncodedObjectStorer) []BundlePrerequisite {
+	var missing []BundlePrerequisite
+	for _, p := range b.Prerequisites {
+		if s == nil || s.HasEncodedObject(p.Hash) != nil {
+			missing = append(missing, p)
+		}
+	}
+	return missing
+}
+
+// Unbundle stores the bundle's objects into s. Prerequisites must already exist.
+func (b *Bundle) Unbundle(s storage.Storer) error {
+	if missing := b.MissingPrerequisites(s); len(missing) > 0 {
+		return &MissingPrerequisitesError{Missing: missing}
+	}
+	if len(b.Packfile) == 0 {
+		return nil
+	}
+	return packfile.UpdateObjectStorage(s, bytes.NewReader(b.Packfile))
+}
+
+// Repository materializes the bundle as a read-only style source repository
+// (refs/heads/*, refs/tags/*, HEAD) so clone/fetch can treat it like a remote.
+// base supplies prerequisite objects for incremental bundles; it may be nil.
+func (b *Bundle) Repository(base storage.Storer) (*gogit.Repository, error) {
+	var st storage.Storer = memory.NewStorage()
+	if base != nil {
+		st = NewHybridStorer(st, base)
+	}
+
+	if err := b.Unbundle(st); err != nil {
+		return nil, err
+	}
+	if err := b.setReferences(st); err != nil {
+		return nil, err
+	}
+	return gogit.Open(st, nil)
+}
+
+// setReferences stores the bundle's branches and tags in s and points HEAD at
+// the branch it matches.
+func (b *Bundle) setReferences(s storer.ReferenceStorer) error {
+	var headHash plumbing.Hash
+	hasHead := false
+	for _, ref := range b.References {
+		if ref.Name() == plumbing.HEAD {
+			headHash = ref.Hash()
+			hasHead = true
+			continue
+		}
+		if err := s.SetReference(ref); err != nil {
+			return err
+		}
+	}
+
+	// Point HEAD at the branch it matches, preferring main/master like git clone does.
+	if head := b.headTarget(headHash, hasHead); head != "" {
+		_ = s.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, head))
+	} else if hasHead {
+		_ = s.SetReference(plumbing.NewHashReference(plumbing.HEAD, headHash))
+	} else {
+		// gogit.Open requires a HEAD; an unborn main mirrors an empty remote.
+		_ = s.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.NewBranchReferenceName("main")))
+	}
+	return nil
+}
+
+func (b *Bundle) headTarget(headHash plumbing.Hash, hasHead bool) plumbing.ReferenceName {
+	var candidates []plumbing.ReferenceName
+	for _, ref := range b.References {
+		if !ref.Name().IsBranch() {
+			continue
+		}
+		if hasHead && ref.Hash() != headHash {
+			continue
+		}
+		candidates = append(candidates, ref.Name())
+	}
+	for _, preferred := range []plumbing.ReferenceName{"refs/heads/main", "refs/heads/master"} {
+		for _, c := range candidates {
+			if c == preferred {
+				return c
+			}
+		}
+	}
+	if len(candidates) > 0 {
+		return candidates[0]
+	}
+	return ""
+}
+
+// MissingPrerequisitesError reports prerequisite commits absent from the target repository.
+type MissingPrerequisitesError struct {
+	Missing []BundlePrerequisite
+}
+
+func (e *MissingPrerequisitesError) Error() string {
+	var sb strings.Builder
+	sb.WriteString("Repository lacks these prerequisite commits:")
+	for _, p := range e.Missing {
+		fmt.Fprintf(&sb, "\nerror: %s %s", p.Hash, p.Comment)
+	}
+	return sb.String()
+}
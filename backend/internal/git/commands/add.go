@@ -49,7 +49,7 @@ func (c *AddCommand) Execute(ctx context.Context, s *git.Session, args []string)
 	}
 
 	// 3. Execution
-	return c.executeAdd(w, opts)
+	return c.executeAdd(repo, w, opts)
 }
 
 func (c *AddCommand) parseArgs(args []string) (*AddOptions, error) {
@@ -79,7 +79,7 @@ func (c *AddCommand) parseArgs(args []string) (*AddOptions, error) {
 	return opts, nil
 }
 
-func (c *AddCommand) executeAdd(w *gogit.Worktree, opts *AddOptions) (string, error) {
+func (c *AddCommand) executeAdd(repo *gogit.Repository, w *gogit.Worktree, opts *AddOptions) (string, error) {
 	if len(opts.Pathspecs) == 0 && !opts.All {
 		return "", fmt.Errorf("nothing specified, nothing added.\nMaybe you wanted to say 'git add .'?")
 	}
@@ -100,6 +100,9 @@ func (c *AddCommand) executeAdd(w *gogit.Worktree, opts *AddOptions) (string, er
 	if err != nil {
 		return "", err
 	}
+	if err := clearIntentToAdd(repo, opts.Pathspecs); err != nil {
+		return "", err
+	}
 
 	if opts.All {
 		return "Added changes", nil
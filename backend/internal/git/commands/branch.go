@@ -2,11 +2,17 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"path"
+	"sort"
 	"strings"
+	"time"
 
 	gogit "github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	format "github.com/go-git/go-git/v5/plumbing/format/config"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/kurobon/gitgym/backend/internal/git"
 )
 
@@ -23,12 +29,30 @@ type BranchOptions struct {
 	Delete      bool
 	DeleteForce bool
 	Move        bool
+	Copy        bool
 	StartPoint  string
 	BranchName  string
 	NewName     string
 	Remote      bool
 	All         bool
 	Force       bool
+
+	// Listing
+	List       bool
+	Verbose    int      // -v: last commit, -vv: also the upstream
+	Patterns   []string // --list <pattern>...
+	Merged     string   // --merged [<commit>]: tips reachable from commit
+	NoMerged   string   // --no-merged [<commit>]
+	Contains   string   // --contains [<commit>]: tips that have commit in their history
+	NoContains string   // --no-contains [<commit>]
+	PointsAt   string   // --points-at <object>
+	Sort       string   // --sort=[-]<key>
+	Format     string   // --format=<format>
+
+	ShowCurrent     bool
+	EditDescription bool
+
+	names []string // Positional arguments
 }
 
 func (c *BranchCommand) Execute(ctx context.Context, s *git.Session, args []string) (string, error) {
@@ -50,41 +74,31 @@ func (c *BranchCommand) Execute(ctx context.Context, s *git.Session, args []stri
 	}
 
 	// 2. Dispatch
-	// LIST
-	if !opts.Delete && !opts.DeleteForce && !opts.Move {
-		if opts.BranchName == "" {
-			return c.listBranches(repo, opts.Remote, opts.All)
-		}
-		// Special case: "git branch -r" or "git branch -a" without name is list
-		if opts.Remote && !opts.Move && !opts.Delete { // "git branch -r"
-			return c.listBranches(repo, opts.Remote, opts.All)
-		}
-		if opts.All && !opts.Move && !opts.Delete { // "git branch -a"
-			return c.listBranches(repo, opts.Remote, opts.All)
-		}
-
-		// If name provided but not Delete/Move, it's CREATE
-		return c.createBranch(repo, opts)
-	}
-
-	// DELETE
-	if opts.Delete || opts.DeleteForce {
-		if opts.BranchName == "" {
-			return "", fmt.Errorf("branch name required")
+	switch {
+	case opts.ShowCurrent:
+		return c.showCurrent(repo)
+	case opts.EditDescription:
+		return c.editDescription(repo, opts)
+	case opts.Delete || opts.DeleteForce:
+		if len(opts.names) == 0 {
+			return "", fmt.Errorf("fatal: branch name required")
 		}
-		return c.deleteBranch(repo, opts)
-	}
-
-	// MOVE
-	if opts.Move {
+		return c.deleteBranches(repo, opts)
+	case opts.Move:
 		// If explicit old name not provided, we resolve "current" inside moveBranch
-		// So we don't enforce opts.BranchName != "" here if we support current branch rename.
-		// However, parseArgs sets BranchName="" for implicit case.
-		// So we should allow it.
 		return c.moveBranch(repo, opts)
+	case opts.Copy:
+		return c.copyBranch(repo, opts)
+	case (opts.Remote || opts.All) && !opts.List && opts.BranchName != "":
+		// Without --list a name would be a branch to create, which -r/-a cannot do
+		return "", fmt.Errorf("fatal: the -a, and -r, options to 'git branch' do not take a branch name.\nDid you mean to use: -a|-r --list <pattern>?")
+	case opts.List || opts.BranchName == "":
+		// "git branch", "git branch -r/-a", filters and -v all list
+		return c.listBranches(repo, opts)
 	}
 
-	return "", nil
+	// If name provided but not Delete/Move/Copy, it's CREATE
+	return c.createBranch(repo, opts)
 }
 
 func (c *BranchCommand) parseArgs(args []string) (*BranchOptions, error) {
@@ -93,53 +107,112 @@ func (c *BranchCommand) parseArgs(args []string) (*BranchOptions, error) {
 	}
 	cmdArgs := args[1:]
 
-	// Collect arguments to determine Name and StartPoint/NewName
-	var cleanArgs []string
+	// optionalCommit reads the optional <commit> of --merged and friends
+	optionalCommit := func(i *int) string {
+		if *i+1 < len(cmdArgs) && !strings.HasPrefix(cmdArgs[*i+1], "-") {
+			*i++
+			return cmdArgs[*i]
+		}
+		return "HEAD"
+	}
 
-	for _, arg := range cmdArgs {
-		switch arg {
-		case "--help", "-h":
+	for i := 0; i < len(cmdArgs); i++ {
+		arg := cmdArgs[i]
+		name, value, hasValue := strings.Cut(arg, "=")
+		switch {
+		case arg == "--help" || arg == "-h":
 			return nil, fmt.Errorf("help requested")
-		case "-d", "--delete":
+		case arg == "-d" || arg == "--delete":
 			opts.Delete = true
-		case "-D":
+		case arg == "-D":
 			opts.DeleteForce = true // Implies Force for deletion logic
-		case "-m", "--move":
+		case arg == "-m" || arg == "--move":
 			opts.Move = true
-		case "-f", "--force":
+		case arg == "-M":
+			opts.Move, opts.Force = true, true
+		case arg == "-c" || arg == "--copy":
+			opts.Copy = true
+		case arg == "-C":
+			opts.Copy, opts.Force = true, true
+		case arg == "-f" || arg == "--force":
 			opts.Force = true
-		case "-r", "--remotes":
+		case arg == "-r" || arg == "--remotes":
 			opts.Remote = true
-		case "-a", "--all":
+		case arg == "-a" || arg == "--all":
 			opts.All = true
+		case arg == "-l" || arg == "--list":
+			opts.List = true
+		case arg == "-v" || arg == "--verbose":
+			opts.Verbose++
+		case arg == "-vv":
+			opts.Verbose += 2
+		case arg == "--show-current":
+			opts.ShowCurrent = true
+		case arg == "--edit-description":
+			opts.EditDescription = true
+		case name == "--merged" || name == "--no-merged" || name == "--contains" || name == "--no-contains":
+			commit := value
+			if !hasValue {
+				commit = optionalCommit(&i)
+			}
+			switch name {
+			case "--merged":
+				opts.Merged = commit
+			case "--no-merged":
+				opts.NoMerged = commit
+			case "--contains":
+				opts.Contains = commit
+			default:
+				opts.NoContains = commit
+			}
+			opts.List = true
+		case name == "--points-at" || name == "--sort" || name == "--format":
+			if !hasValue {
+				if i+1 >= len(cmdArgs) {
+					return nil, fmt.Errorf("error: option `%s' requires a value", strings.TrimPrefix(name, "--"))
+				}
+				i++
+				value = cmdArgs[i]
+			}
+			switch name {
+			case "--points-at":
+				opts.PointsAt = value
+			case "--sort":
+				opts.Sort = value
+			default:
+				opts.Format = value
+			}
+			opts.List = true
 		default:
 			if strings.HasPrefix(arg, "-") {
 				return nil, fmt.Errorf("unknown option: %s", arg)
 			}
-			cleanArgs = append(cleanArgs, arg)
+			opts.names = append(opts.names, arg)
 		}
 	}
+	if opts.Verbose > 0 {
+		opts.List = true
+	}
 
+	if opts.List {
+		opts.Patterns = opts.names
+		return opts, nil
+	}
+
+	cleanArgs := opts.names
 	if len(cleanArgs) > 0 {
 		opts.BranchName = cleanArgs[0]
 	}
 	if len(cleanArgs) > 1 {
-		if opts.Move {
-			opts.NewName = cleanArgs[1]
-		} else {
-			opts.StartPoint = cleanArgs[1]
-		}
+		opts.StartPoint = cleanArgs[1]
 	}
 
-	// Handle Rename Current Branch: "git branch -m newname"
-	// Here cleanArgs[0] is newname if we are renaming CURRENT.
-	// Logic inside moveBranch needs to handle implicit "current" if only 1 arg.
-	// Actually, if len==1 and Move==true, cleanArgs[0] *is* NewName, and BranchName (old) is implicit.
-	// Let's refine parsing for Move case:
-	if opts.Move && len(cleanArgs) == 1 {
+	// Rename/copy the current branch: "git branch -m newname"
+	// With one argument it *is* the new name and the old one is implicit.
+	if (opts.Move || opts.Copy) && len(cleanArgs) == 1 {
 		opts.NewName = cleanArgs[0]
 		opts.BranchName = "" // Signal to resolve current
-	} else if opts.Move && len(cleanArgs) >= 2 {
+	} else if (opts.Move || opts.Copy) && len(cleanArgs) >= 2 {
 		opts.BranchName = cleanArgs[0] // Old
 		opts.NewName = cleanArgs[1]    // New
 	}
@@ -147,48 +220,466 @@ func (c *BranchCommand) parseArgs(args []string) (*BranchOptions, error) {
 	return opts, nil
 }
 
-func (c *BranchCommand) listBranches(repo *gogit.Repository, remote, all bool) (string, error) {
-	// Collect branches
-	var branches []string
+// branchEntry is one line of the branch listing.
+type branchEntry struct {
+	ref     plumbing.ReferenceName // Full name ("" for a detached HEAD)
+	display string                 // As listed: main, origin/main, remotes/origin/main
+	hash    plumbing.Hash
+	commit  *object.Commit
+	current bool
+	symref  string // Target of a symbolic ref (origin/HEAD -> origin/main)
+}
 
-	// Local branches
-	if !remote || all {
-		bs, err := repo.Branches()
+func (c *BranchCommand) listBranches(repo *gogit.Repository, opts *BranchOptions) (string, error) {
+	entries, err := c.collectBranches(repo, opts)
+	if err != nil {
+		return "", err
+	}
+	if entries, err = c.filterBranches(repo, entries, opts); err != nil {
+		return "", err
+	}
+	if err := c.sortBranches(repo, entries, opts); err != nil {
+		return "", err
+	}
+
+	width := 0
+	for _, e := range entries {
+		if len(e.display) > width {
+			width = len(e.display)
+		}
+	}
+
+	var lines []string
+	for _, e := range entries {
+		if opts.Format != "" {
+			lines = append(lines, c.formatBranch(repo, e, opts.Format))
+			continue
+		}
+		marker := "  "
+		if e.current {
+			marker = "* "
+		}
+		switch {
+		case e.symref != "":
+			lines = append(lines, fmt.Sprintf("%s%s -> %s", marker, e.display, e.symref))
+		case opts.Verbose > 0:
+			track := ""
+			if e.ref.IsBranch() {
+				if track = c.trackingInfo(repo, e, opts.Verbose > 1); track != "" {
+					track += " "
+				}
+			}
+			subject := ""
+			if e.commit != nil {
+				subject = commitSubject(e.commit)
+			}
+			lines = append(lines, fmt.Sprintf("%s%-*s %s %s%s", marker, width, e.display, e.hash.String()[:7], track, subject))
+		default:
+			lines = append(lines, marker+e.display)
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// collectBranches gathers the detached HEAD, the local branches and (with -r
+// or -a) the remote-tracking branches.
+func (c *BranchCommand) collectBranches(repo *gogit.Repository, opts *BranchOptions) ([]*branchEntry, error) {
+	var entries []*branchEntry
+	head, headErr := repo.Head()
+	local := !opts.Remote || opts.All
+	remote := opts.Remote || opts.All
+
+	if local && headErr == nil && !head.Name().IsBranch() && len(opts.Patterns) == 0 {
+		entries = append(entries, &branchEntry{
+			display: fmt.Sprintf("(HEAD detached at %s)", head.Hash().String()[:7]),
+			hash:    head.Hash(),
+			current: true,
+		})
+	}
+
+	refs, err := repo.References()
+	if err != nil {
+		return nil, err
+	}
+	err = refs.ForEach(func(r *plumbing.Reference) error {
+		e := &branchEntry{ref: r.Name()}
+		switch {
+		case r.Name().IsBranch() && local:
+			e.display = r.Name().Short()
+			e.current = headErr == nil && head.Name() == r.Name()
+		case r.Name().IsRemote() && remote:
+			e.display = r.Name().Short()
+			if opts.All {
+				e.display = "remotes/" + e.display
+			}
+		default:
+			return nil
+		}
+		if r.Type() == plumbing.SymbolicReference {
+			e.symref = r.Target().Short()
+		}
+		resolved, err := repo.Reference(r.Name(), true)
 		if err != nil {
-			return "", err
+			return nil // Dangling symbolic ref
 		}
-		_ = bs.ForEach(func(r *plumbing.Reference) error {
-			branches = append(branches, r.Name().Short())
+		e.hash = resolved.Hash()
+		e.commit, _ = repo.CommitObject(e.hash)
+
+		if len(opts.Patterns) > 0 && !branchPatternMatches(opts.Patterns, r.Name().Short()) {
 			return nil
-		})
+		}
+		entries = append(entries, e)
+		return nil
+	})
+	return entries, err
+}
+
+func branchPatternMatches(patterns []string, name string) bool {
+	for _, p := range patterns {
+		if ok, _ := path.Match(p, name); ok || p == name {
+			return true
+		}
 	}
+	return false
+}
 
-	// Remote branches
-	if remote || all {
-		remotes, err := c.listRemoteBranches(repo)
+// filterBranches applies --merged, --no-merged, --contains, --no-contains and --points-at.
+func (c *BranchCommand) filterBranches(repo *gogit.Repository, entries []*branchEntry, opts *BranchOptions) ([]*branchEntry, error) {
+	resolve := func(rev string) (plumbing.Hash, error) {
+		hash, err := git.ResolveRevision(repo, rev)
 		if err != nil {
-			return "", err
+			return plumbing.ZeroHash, fmt.Errorf("error: malformed object name %s", rev)
 		}
-		// Merge specific logic: deduplicate against existing local branches?
-		// The original logic was appending to 'branches'.
-		// Let's verify duplication handling.
-		// Original logic:
-		// Check duplicates against 'branches' (which contains local branches if 'all' is true)
-		for _, rBranch := range remotes {
-			exists := false
-			for _, b := range branches {
-				if b == rBranch {
-					exists = true
-					break
-				}
+		return *hash, nil
+	}
+	// isAncestor reports whether a is in the history of b
+	isAncestor := func(a, b plumbing.Hash) bool {
+		ok, err := git.IsFastForward(repo, a, b)
+		return err == nil && ok
+	}
+
+	type filter struct {
+		rev  string
+		keep func(commit, tip plumbing.Hash) bool
+	}
+	filters := []filter{
+		{opts.Merged, func(commit, tip plumbing.Hash) bool { return isAncestor(tip, commit) }},
+		{opts.NoMerged, func(commit, tip plumbing.Hash) bool { return !isAncestor(tip, commit) }},
+		{opts.Contains, func(commit, tip plumbing.Hash) bool { return isAncestor(commit, tip) }},
+		{opts.NoContains, func(commit, tip plumbing.Hash) bool { return !isAncestor(commit, tip) }},
+		{opts.PointsAt, func(commit, tip plumbing.Hash) bool { return commit == tip }},
+	}
+	for _, f := range filters {
+		if f.rev == "" {
+			continue
+		}
+		commit, err := resolve(f.rev)
+		if err != nil {
+			return nil, err
+		}
+		var kept []*branchEntry
+		for _, e := range entries {
+			if f.keep(commit, e.hash) {
+				kept = append(kept, e)
 			}
-			if !exists {
-				branches = append(branches, rBranch)
+		}
+		entries = kept
+	}
+	return entries, nil
+}
+
+// sortBranches orders the listing by --sort (or branch.sort), by full ref
+// name by default. A detached HEAD always comes first.
+func (c *BranchCommand) sortBranches(repo *gogit.Repository, entries []*branchEntry, opts *BranchOptions) error {
+	key := opts.Sort
+	if key == "" {
+		if cfg, err := repo.Config(); err == nil {
+			key = cfg.Raw.Section("branch").Option("sort")
+		}
+	}
+	if key == "" {
+		key = "refname"
+	}
+	reverse := strings.HasPrefix(key, "-")
+	key = strings.TrimPrefix(key, "-")
+
+	var less func(a, b *branchEntry) bool
+	when := func(e *branchEntry, author bool) time.Time {
+		if e.commit == nil {
+			return time.Time{}
+		}
+		if author {
+			return e.commit.Author.When
+		}
+		return e.commit.Committer.When
+	}
+	switch key {
+	case "refname":
+		less = func(a, b *branchEntry) bool { return a.ref < b.ref }
+	case "committerdate", "creatordate":
+		less = func(a, b *branchEntry) bool { return when(a, false).Before(when(b, false)) }
+	case "authordate":
+		less = func(a, b *branchEntry) bool { return when(a, true).Before(when(b, true)) }
+	case "objectname":
+		less = func(a, b *branchEntry) bool { return a.hash.String() < b.hash.String() }
+	case "subject":
+		less = func(a, b *branchEntry) bool {
+			return a.commit != nil && b.commit != nil && commitSubject(a.commit) < commitSubject(b.commit)
+		}
+	default:
+		return fmt.Errorf("fatal: unknown field name: %s", key)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if (a.ref == "") != (b.ref == "") {
+			return a.ref == ""
+		}
+		if reverse {
+			a, b = b, a
+		}
+		if less(a, b) {
+			return true
+		}
+		if less(b, a) {
+			return false
+		}
+		return entries[i].ref < entries[j].ref
+	})
+	return nil
+}
+
+// trackingInfo describes a local branch's upstream like -v ("[ahead 1]") or
+// -vv ("[origin/main: ahead 1, behind 2]"). It is empty without an upstream,
+// and for -v also when the branch is up to date.
+func (c *BranchCommand) trackingInfo(repo *gogit.Repository, e *branchEntry, withName bool) string {
+	upstream, track := c.upstreamState(repo, e)
+	if upstream == "" {
+		return ""
+	}
+	switch {
+	case withName && track == "":
+		return "[" + upstream + "]"
+	case withName:
+		return "[" + upstream + ": " + track + "]"
+	case track == "":
+		return ""
+	default:
+		return "[" + track + "]"
+	}
+}
+
+// upstreamState returns the short name of a branch's upstream and how the
+// branch compares to it: "ahead 1, behind 2", "gone" or "" (up to date).
+func (c *BranchCommand) upstreamState(repo *gogit.Repository, e *branchEntry) (upstream, track string) {
+	if !e.ref.IsBranch() {
+		return "", ""
+	}
+	remote, merge, ok := git.BranchUpstream(repo, e.ref.Short())
+	if !ok {
+		return "", ""
+	}
+	trackingName := git.RemoteTrackingRef(remote, merge)
+	if remote == "." {
+		trackingName = merge
+	}
+	upstream = trackingName.Short()
+
+	tracking, err := repo.Reference(trackingName, true)
+	if err != nil {
+		return upstream, "gone"
+	}
+	ahead, errAhead := git.CommitsBetween(repo, tracking.Hash(), e.hash)
+	behind, errBehind := git.CommitsBetween(repo, e.hash, tracking.Hash())
+	if errAhead != nil || errBehind != nil {
+		return upstream, ""
+	}
+	var parts []string
+	if len(ahead) > 0 {
+		parts = append(parts, fmt.Sprintf("ahead %d", len(ahead)))
+	}
+	if len(behind) > 0 {
+		parts = append(parts, fmt.Sprintf("behind %d", len(behind)))
+	}
+	return upstream, strings.Join(parts, ", ")
+}
+
+// formatBranch expands the %(atom) placeholders of --format for one branch.
+func (c *BranchCommand) formatBranch(repo *gogit.Repository, e *branchEntry, format string) string {
+	var sb strings.Builder
+	for {
+		start := strings.Index(format, "%(")
+		if start < 0 {
+			break
+		}
+		end := strings.Index(format[start:], ")")
+		if end < 0 {
+			break
+		}
+		sb.WriteString(format[:start])
+		sb.WriteString(c.branchAtom(repo, e, format[start+2:start+end]))
+		format = format[start+end+1:]
+	}
+	sb.WriteString(format)
+	return unescapeContent(sb.String())
+}
+
+func (c *BranchCommand) branchAtom(repo *gogit.Repository, e *branchEntry, atom string) string {
+	name, modifier, _ := strings.Cut(atom, ":")
+	signature := func() *object.Signature {
+		if e.commit == nil {
+			return nil
+		}
+		if strings.HasPrefix(name, "author") {
+			return &e.commit.Author
+		}
+		return &e.commit.Committer
+	}
+
+	switch name {
+	case "HEAD":
+		if e.current {
+			return "*"
+		}
+		return " "
+	case "refname":
+		if e.ref == "" {
+			return e.display
+		}
+		if modifier == "short" {
+			return e.ref.Short()
+		}
+		return e.ref.String()
+	case "objectname":
+		if modifier == "short" {
+			return e.hash.String()[:7]
+		}
+		return e.hash.String()
+	case "subject", "contents:subject":
+		if e.commit == nil {
+			return ""
+		}
+		return commitSubject(e.commit)
+	case "committername", "authorname":
+		if sig := signature(); sig != nil {
+			return sig.Name
+		}
+	case "committeremail", "authoremail":
+		if sig := signature(); sig != nil {
+			return "<" + sig.Email + ">"
+		}
+	case "committerdate", "authordate", "creatordate":
+		if sig := signature(); sig != nil {
+			return formatBranchDate(sig.When, modifier)
+		}
+	case "upstream":
+		upstream, track := c.upstreamState(repo, e)
+		switch modifier {
+		case "short":
+			return upstream
+		case "track":
+			if track == "" {
+				return ""
+			}
+			return "[" + track + "]"
+		case "trackshort":
+			switch {
+			case upstream == "" || track == "gone":
+				return ""
+			case strings.Contains(track, "ahead") && strings.Contains(track, "behind"):
+				return "<>"
+			case strings.Contains(track, "ahead"):
+				return ">"
+			case strings.Contains(track, "behind"):
+				return "<"
 			}
+			return "="
+		}
+		if upstream == "" {
+			return ""
 		}
+		return "refs/remotes/" + upstream
 	}
+	return ""
+}
 
-	return strings.Join(branches, "\n"), nil
+// formatBranchDate renders a date for --format: git's default format, or
+// short, iso, unix or relative.
+func formatBranchDate(when time.Time, modifier string) string {
+	switch modifier {
+	case "short":
+		return when.Format("2006-01-02")
+	case "iso":
+		return when.Format("2006-01-02 15:04:05 -0700")
+	case "unix":
+		return fmt.Sprintf("%d", when.Unix())
+	case "relative":
+		return relativeDate(when, time.Now())
+	}
+	return when.Format("Mon Jan 2 15:04:05 2006 -0700")
+}
+
+// relativeDate describes when like git's --date=relative ("3 days ago").
+func relativeDate(when, now time.Time) string {
+	d := now.Sub(when)
+	if d < 0 {
+		return "in the future"
+	}
+	units := []struct {
+		limit time.Duration
+		size  time.Duration
+		name  string
+	}{
+		{90 * time.Second, time.Second, "second"},
+		{90 * time.Minute, time.Minute, "minute"},
+		{36 * time.Hour, time.Hour, "hour"},
+		{14 * 24 * time.Hour, 24 * time.Hour, "day"},
+		{60 * 24 * time.Hour, 7 * 24 * time.Hour, "week"},
+		{365 * 24 * time.Hour, 30 * 24 * time.Hour, "month"},
+	}
+	for _, u := range units {
+		if d < u.limit {
+			n := int(d / u.size)
+			return fmt.Sprintf("%d %s ago", n, plural(n, u.name, u.name+"s"))
+		}
+	}
+	n := int(d / (365 * 24 * time.Hour))
+	return fmt.Sprintf("%d %s ago", n, plural(n, "year", "years"))
+}
+
+func (c *BranchCommand) showCurrent(repo *gogit.Repository) (string, error) {
+	head, err := repo.Storer.Reference(plumbing.HEAD)
+	if err != nil || head.Type() != plumbing.SymbolicReference {
+		return "", nil // Detached HEAD prints nothing
+	}
+	return head.Target().Short(), nil
+}
+
+// editDescription shows a branch's description. There is no editor to
+// change it, so it points at git config instead.
+func (c *BranchCommand) editDescription(repo *gogit.Repository, opts *BranchOptions) (string, error) {
+	name := ""
+	if len(opts.names) > 0 {
+		name = opts.names[0]
+	} else if head, err := repo.Storer.Reference(plumbing.HEAD); err == nil && head.Type() == plumbing.SymbolicReference {
+		name = head.Target().Short()
+	} else {
+		return "", fmt.Errorf("fatal: cannot edit description of more than one branch")
+	}
+	if _, err := repo.Reference(plumbing.NewBranchReferenceName(name), true); err != nil {
+		return "", fmt.Errorf("error: no branch named '%s'", name)
+	}
+
+	cfg, err := repo.Config()
+	if err != nil {
+		return "", err
+	}
+	description := cfg.Raw.Section("branch").Subsection(name).Option("description")
+	hint := fmt.Sprintf("hint: There is no editor here. Set the description with:\nhint:   git config branch.%s.description \"<text>\"", name)
+	if description == "" {
+		return fmt.Sprintf("Branch '%s' has no description.\n%s", name, hint), nil
+	}
+	return fmt.Sprintf("%s\n\n%s", strings.TrimRight(description, "\n"), hint), nil
 }
 
 func (c *BranchCommand) createBranch(repo *gogit.Repository, opts *BranchOptions) (string, error) {
@@ -230,13 +721,44 @@ func (c *BranchCommand) createBranch(repo *gogit.Repository, opts *BranchOptions
 	return "Created branch " + name, nil
 }
 
-func (c *BranchCommand) deleteBranch(repo *gogit.Repository, opts *BranchOptions) (string, error) {
-	name := opts.BranchName
-	// TODO: support remote delete (git branch -dr origin/branch)
-	if opts.Remote {
-		return "", fmt.Errorf("deleting remote-tracking branches not fully supported yet in simulation")
+// deleteBranches deletes each named branch (remote-tracking branches with
+// -r). A branch that cannot be deleted is reported and the rest are still
+// deleted; the command fails if any of them failed.
+func (c *BranchCommand) deleteBranches(repo *gogit.Repository, opts *BranchOptions) (string, error) {
+	var lines []string
+	failed := false
+	for _, name := range opts.names {
+		var line string
+		var err error
+		if opts.Remote {
+			line, err = c.deleteRemoteBranch(repo, name)
+		} else {
+			line, err = c.deleteBranch(repo, name, opts.DeleteForce)
+		}
+		if err != nil {
+			line, failed = err.Error(), true
+		}
+		lines = append(lines, line)
+	}
+	if failed {
+		return "", errors.New(strings.Join(lines, "\n"))
 	}
+	return strings.Join(lines, "\n"), nil
+}
 
+func (c *BranchCommand) deleteRemoteBranch(repo *gogit.Repository, name string) (string, error) {
+	refName := plumbing.ReferenceName("refs/remotes/" + name)
+	ref, err := repo.Reference(refName, true)
+	if err != nil {
+		return "", fmt.Errorf("error: remote-tracking branch '%s' not found", name)
+	}
+	if err := repo.Storer.RemoveReference(refName); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Deleted remote-tracking branch %s (was %s).", name, ref.Hash().String()[:7]), nil
+}
+
+func (c *BranchCommand) deleteBranch(repo *gogit.Repository, name string, force bool) (string, error) {
 	refName := plumbing.ReferenceName("refs/heads/" + name)
 	targetRef, err := repo.Reference(refName, true)
 	if err != nil {
@@ -249,56 +771,64 @@ func (c *BranchCommand) deleteBranch(repo *gogit.Repository, opts *BranchOptions
 		return "", fmt.Errorf("cannot delete branch '%s' checked out at current worktree", name)
 	}
 
-	// Determine if Force is needed (DeleteForce or just force flag logic?)
 	// git branch -d checks merge. git branch -D skips check.
-	force := opts.DeleteForce
-
 	if !force {
-		// Check if fully merged into HEAD
-		// We need to check if branch (targetRef.Hash) is ancestor of HEAD (headRef.Hash)
-		// IsFastForward(repo, base, target) -> returns true if base is ancestor of target
-		// So IsFastForward(repo, targetRef.Hash, headRef.Hash)
-
+		// Check if fully merged into HEAD: the branch tip must be an ancestor of HEAD
 		isMerged, err := git.IsFastForward(repo, targetRef.Hash(), headRef.Hash())
 		if err != nil {
 			return "", fmt.Errorf("failed to check merge status: %w", err)
 		}
 
 		if !isMerged {
-			return "", fmt.Errorf("the branch '%s' is not fully merged.\nIf you are sure you want to delete it, run 'git branch -D %s'", name, name)
+			return "", fmt.Errorf("error: the branch '%s' is not fully merged.\nIf you are sure you want to delete it, run 'git branch -D %s'", name, name)
 		}
 	}
 
 	if err := repo.Storer.RemoveReference(refName); err != nil {
 		return "", err
 	}
-	return "Deleted branch " + name, nil
+	// The branch's settings (upstream, description) go with it
+	if err := updateRawConfig(repo, func(raw *format.Config) {
+		raw.Section("branch").RemoveSubsection(name)
+	}); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Deleted branch %s (was %s).", name, targetRef.Hash().String()[:7]), nil
 }
 
-func (c *BranchCommand) moveBranch(repo *gogit.Repository, opts *BranchOptions) (string, error) {
-	oldName := opts.BranchName
-	newName := opts.NewName
-
+// resolveSourceBranch returns the branch -m/-c act on: the named one, or the
+// current branch.
+func (c *BranchCommand) resolveSourceBranch(repo *gogit.Repository, oldName, action string) (*plumbing.Reference, error) {
 	var oldRefName plumbing.ReferenceName
-
 	if oldName == "" {
-		// Renaming current branch
 		head, err := repo.Head()
 		if err != nil {
-			return "", fmt.Errorf("cannot rename current branch: failed to resolve HEAD: %w", err)
+			return nil, fmt.Errorf("cannot %s current branch: failed to resolve HEAD: %w", action, err)
 		}
 		if !head.Name().IsBranch() {
-			return "", fmt.Errorf("cannot rename detached HEAD")
+			return nil, fmt.Errorf("cannot %s detached HEAD", action)
 		}
 		oldRefName = head.Name()
-		oldName = oldRefName.Short()
 	} else {
 		oldRefName = plumbing.ReferenceName("refs/heads/" + oldName)
 	}
 
 	oldRef, err := repo.Reference(oldRefName, true)
 	if err != nil {
-		return "", fmt.Errorf("branch '%s' not found", oldName)
+		return nil, fmt.Errorf("branch '%s' not found", oldRefName.Short())
+	}
+	return plumbing.NewHashReference(oldRefName, oldRef.Hash()), nil
+}
+
+func (c *BranchCommand) moveBranch(repo *gogit.Repository, opts *BranchOptions) (string, error) {
+	oldRef, err := c.resolveSourceBranch(repo, opts.BranchName, "rename")
+	if err != nil {
+		return "", err
+	}
+	oldName, newName := oldRef.Name().Short(), opts.NewName
+	// Renaming a branch to itself changes nothing
+	if oldName == newName {
+		return fmt.Sprintf("Renamed branch %s to %s", oldName, newName), nil
 	}
 
 	newRefName := plumbing.ReferenceName("refs/heads/" + newName)
@@ -313,29 +843,69 @@ func (c *BranchCommand) moveBranch(repo *gogit.Repository, opts *BranchOptions)
 	if err := repo.Storer.SetReference(newRef); err != nil {
 		return "", err
 	}
-	if err := repo.Storer.RemoveReference(oldRefName); err != nil {
+	if err := repo.Storer.RemoveReference(oldRef.Name()); err != nil {
 		return "", err // inconsistent state risk, but simulation
 	}
+	if err := c.copyBranchConfig(repo, oldName, newName, true); err != nil {
+		return "", err
+	}
+
+	// HEAD follows the renamed current branch
+	if head, err := repo.Storer.Reference(plumbing.HEAD); err == nil && head.Type() == plumbing.SymbolicReference && head.Target() == oldRef.Name() {
+		if err := repo.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, newRefName)); err != nil {
+			return "", err
+		}
+	}
 
 	return fmt.Sprintf("Renamed branch %s to %s", oldName, newName), nil
 }
 
-func (c *BranchCommand) listRemoteBranches(repo *gogit.Repository) ([]string, error) {
-	var remoteBranches []string
-	refs, err := repo.References()
+// copyBranch creates a new branch at the same commit as an existing one,
+// with a copy of its configuration (upstream, description, ...).
+func (c *BranchCommand) copyBranch(repo *gogit.Repository, opts *BranchOptions) (string, error) {
+	if opts.NewName == "" {
+		return "", fmt.Errorf("fatal: branch name required")
+	}
+	oldRef, err := c.resolveSourceBranch(repo, opts.BranchName, "copy")
 	if err != nil {
-		return nil, err
+		return "", err
+	}
+	oldName, newName := oldRef.Name().Short(), opts.NewName
+
+	newRefName := plumbing.NewBranchReferenceName(newName)
+	if _, err := repo.Reference(newRefName, true); err == nil {
+		if !opts.Force {
+			return "", fmt.Errorf("fatal: a branch named '%s' already exists", newName)
+		}
+		if head, err := repo.Head(); err == nil && head.Name() == newRefName {
+			return "", fmt.Errorf("fatal: cannot force update the current branch")
+		}
+	}
+
+	if err := repo.Storer.SetReference(plumbing.NewHashReference(newRefName, oldRef.Hash())); err != nil {
+		return "", err
 	}
-	_ = refs.ForEach(func(r *plumbing.Reference) error {
-		if r.Name().IsRemote() {
-			short := r.Name().Short()
-			// Basic deduplication within remote list itself?
-			// The caller deduplicates against local.
-			remoteBranches = append(remoteBranches, short)
+	if err := c.copyBranchConfig(repo, oldName, newName, false); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Copied branch %s to %s", oldName, newName), nil
+}
+
+// copyBranchConfig copies the branch.<old>.* settings to branch.<new>.*,
+// removing the old ones when moving.
+func (c *BranchCommand) copyBranchConfig(repo *gogit.Repository, oldName, newName string, move bool) error {
+	return updateRawConfig(repo, func(raw *format.Config) {
+		section := raw.Section("branch")
+		if !section.HasSubsection(oldName) {
+			return
+		}
+		options := append(format.Options(nil), section.Subsection(oldName).Options...)
+		section.RemoveSubsection(newName)
+		section.Subsection(newName).Options = options
+		if move {
+			section.RemoveSubsection(oldName)
 		}
-		return nil
 	})
-	return remoteBranches, nil
 }
 
 func (c *BranchCommand) Help() string {
@@ -345,19 +915,51 @@ func (c *BranchCommand) Help() string {
     ブランチ（作業の分岐）に関する以下の操作を行います：
     ・ブランチの一覧を表示する（引数なし）
     ・新しいブランチを作成する
-    ・ブランチ名を変更する（-m）
+    ・ブランチ名を変更する（-m）・コピーする（-c）
     ・不要なブランチを削除する（-d）
+    一覧は --merged / --contains などで絞り込めるので、古いブランチの整理に役立ちます。
 
  📋 SYNOPSIS
-    git branch [--list] [-a] [-r]
+    git branch [--list] [-a] [-r] [-v | -vv] [--merged [<commit>]] [--no-merged [<commit>]]
+               [--contains [<commit>]] [--no-contains [<commit>]] [--points-at <object>]
+               [--sort=<key>] [--format=<format>] [<pattern>...]
     git branch [-f] <branchname> [<start-point>]
-    git branch -d|-D <branchname>
-    git branch -m <old> <new>
+    git branch -d|-D <branchname>...
+    git branch -d -r <remote>/<branch>...
+    git branch -m|-M [<old>] <new>
+    git branch -c|-C [<old>] <new>
+    git branch --show-current
+    git branch --edit-description [<branchname>]
 
  ⚙️  COMMON OPTIONS
     -a, --all
         ローカルとリモート（追跡）の両方のブランチを表示します。
 
+    -r, --remotes
+        リモート追跡ブランチを表示します。-d と組み合わせると削除します。
+
+    -v, -vv
+        各ブランチの最新コミット（ハッシュと件名）を表示します。
+        上流ブランチとの差分を [ahead 1, behind 2] のように表示し、
+        -vv では上流ブランチ名も表示します（消えた上流は [origin/x: gone]）。
+
+    --merged [<commit>] / --no-merged [<commit>]
+        <commit>（既定は HEAD）にマージ済み / 未マージのブランチだけを表示します。
+
+    --contains [<commit>] / --no-contains [<commit>]
+        <commit> を含む / 含まないブランチだけを表示します。
+
+    --points-at <object>
+        <object> を指しているブランチだけを表示します。
+
+    --sort=<key>
+        並び順を指定します。refname（既定）, committerdate, authordate, objectname, subject。
+        先頭に - を付けると逆順になります（例: --sort=-committerdate で新しい順）。
+
+    --format=<format>
+        表示形式を指定します。%(refname:short), %(objectname:short), %(subject),
+        %(committerdate:relative), %(authorname), %(upstream:short), %(upstream:track), %(HEAD) など。
+
     -d, --delete
         ブランチを削除します（マージ済みの安全な場合のみ）。
 
@@ -365,23 +967,47 @@ func (c *BranchCommand) Help() string {
         ブランチを強制削除します（マージされていなくても削除）。
         ※ ゴミ箱機能はないので、消すと元に戻すのは大変です。注意！
 
-    -m, --move
-        ブランチ名を変更（移動）します。
+    -m, --move / -M
+        ブランチ名を変更（移動）します。設定（上流ブランチなど）も引き継ぎます。
+
+    -c, --copy / -C
+        ブランチをコピーします。設定（上流ブランチ、説明など）もコピーされます。
+
+    --show-current
+        現在のブランチ名を表示します（detached HEAD では何も表示しません）。
+
+    --edit-description
+        ブランチの説明を表示します。エディタがないため、変更は
+        git config branch.<name>.description で行います。
 
  🛠  PRACTICAL EXAMPLES
     1. 基本: 全ブランチを表示
        リモートブランチも含めてリストアップします。
        $ git branch -a
 
-    2. 実践: ブランチを強制削除
+    2. 実践: 上流との差分を確認
+       $ git branch -vv
+
+    3. 実践: マージ済みのブランチを探して削除
+       $ git branch --merged main
+       $ git branch -d feature/done
+
+    4. 実践: 最近更新されたブランチ順に表示
+       $ git branch --sort=-committerdate --format="%(refname:short) %(committerdate:relative)"
+
+    5. 実践: ブランチを強制削除
        「実験したけどダメだったブランチ」を消す時などに使います。
        マージしていなくても問答無用で削除されます。
        $ git branch -D feature/login
 
-    3. 実践: 今のブランチ名を変更
+    6. 実践: 今のブランチ名を変更
        「綴り間違えた！」という時に便利です。
        $ git branch -m new-name
 
+ 💡 TIPS
+    上流が [gone] と表示されるブランチは、リモートで削除済みです（git fetch --prune の後）。
+    git branch -vv で探して、不要なら削除しましょう。
+
  🔗 REFERENCE
     Full documentation: https://git-scm.com/docs/git-branch
 `
@@ -293,3 +293,105 @@ func TestBranchCommand_DeleteSafety(t *testing.T) {
 		t.Errorf("Expected deletion message, got: %s", res)
 	}
 }
+
+func TestBranchCommand_PowerFeatures(t *testing.T) {
+	sm := git.NewSessionManager()
+	s := setupBranchTestSession(t, sm, "test-branch-power")
+	ctx := context.Background()
+	repo := s.GetRepo()
+	run := func(args ...string) string {
+		t.Helper()
+		out, err := git.Dispatch(ctx, s, "branch", append([]string{"branch"}, args...))
+		if err != nil {
+			t.Fatalf("git branch %v failed: %v", args, err)
+		}
+		return out
+	}
+
+	run("merged-one")
+	commitFile(t, repo, "more.txt", "more", "Second commit")
+	head, _ := repo.Head()
+	run("ahead")
+	repo.Storer.SetReference(plumbing.NewHashReference("refs/remotes/origin/ahead", head.Hash()))
+	if err := git.SetBranchUpstream(repo, "ahead", "origin", "refs/heads/ahead"); err != nil {
+		t.Fatal(err)
+	}
+	if out := run("--show-current"); out != "main" {
+		t.Errorf("--show-current = %q", out)
+	}
+
+	// --merged / --no-merged / --contains
+	if out := run("--merged"); out != "  ahead\n* main\n  merged-one" {
+		t.Errorf("--merged = %q", out)
+	}
+	if out := run("--contains", "HEAD"); strings.Contains(out, "merged-one") {
+		t.Errorf("--contains should skip the older branch: %q", out)
+	}
+	if out := run("--points-at", "HEAD~1"); out != "  merged-one" {
+		t.Errorf("--points-at = %q", out)
+	}
+
+	// -vv shows the upstream, -v only the divergence
+	if out := run("-vv"); !strings.Contains(out, "[origin/ahead] Second commit") {
+		t.Errorf("-vv = %q", out)
+	}
+	repo.Storer.RemoveReference("refs/remotes/origin/ahead")
+	if out := run("-vv"); !strings.Contains(out, "[origin/ahead: gone]") {
+		t.Errorf("-vv with a deleted upstream = %q", out)
+	}
+
+	// --sort and --format
+	if out := run("--sort=-objectname", "--format=%(refname:short)", "ma*"); out != "main" {
+		t.Errorf("--format with pattern = %q", out)
+	}
+	if out, err := git.Dispatch(ctx, s, "branch", []string{"branch", "--sort=bogus"}); err == nil {
+		t.Errorf("unknown sort key should fail, got %q", out)
+	}
+
+	// -c copies the branch and its configuration
+	if out := run("-c", "ahead", "ahead-copy"); out != "Copied branch ahead to ahead-copy" {
+		t.Errorf("copy = %q", out)
+	}
+	if remote, _, ok := git.BranchUpstream(repo, "ahead-copy"); !ok || remote != "origin" {
+		t.Errorf("copy should keep the upstream, got %q %v", remote, ok)
+	}
+	run("-m", "ahead-copy", "renamed")
+	if _, _, ok := git.BranchUpstream(repo, "ahead-copy"); ok {
+		t.Error("rename should move the configuration")
+	}
+	if out := run("-d", "renamed", "merged-one"); !strings.Contains(out, "Deleted branch renamed (was ") || !strings.Contains(out, "Deleted branch merged-one") {
+		t.Errorf("delete = %q", out)
+	}
+
+	// -r and -a list; a name needs --list to be taken as a pattern
+	for _, flag := range []string{"-r", "-a"} {
+		_, err := git.Dispatch(ctx, s, "branch", []string{"branch", flag, "stray"})
+		if err == nil || !strings.Contains(err.Error(), "do not take a branch name") {
+			t.Errorf("branch %s stray should fail, got %v", flag, err)
+		}
+		if _, err := repo.Reference("refs/heads/stray", true); err == nil {
+			t.Errorf("branch %s stray must not create a branch", flag)
+		}
+	}
+	if out := run("-a", "--list", "ma*"); out != "* main" {
+		t.Errorf("-a --list ma* = %q", out)
+	}
+
+	// Renaming a branch to itself keeps it and its configuration
+	run("-M", "ahead", "ahead")
+	if _, err := repo.Reference("refs/heads/ahead", true); err != nil {
+		t.Errorf("-M ahead ahead should keep the branch: %v", err)
+	}
+	if _, _, ok := git.BranchUpstream(repo, "ahead"); !ok {
+		t.Error("-M ahead ahead should keep the upstream")
+	}
+
+	// A failing name does not stop the others from being deleted
+	out, err := git.Dispatch(ctx, s, "branch", []string{"branch", "-d", "missing", "ahead"})
+	if err == nil || !strings.Contains(err.Error(), "branch 'missing' not found") || !strings.Contains(err.Error(), "Deleted branch ahead") {
+		t.Errorf("delete with a missing branch = %q, %v", out, err)
+	}
+	if _, err := repo.Reference("refs/heads/ahead", true); err == nil {
+		t.Error("ahead should be deleted despite the missing branch")
+	}
+}
@@ -0,0 +1,441 @@
+package commands
+
+// bundle.go - Git Bundle Command
+//
+// Packs refs and their history into a single v2 bundle file stored in the
+// session filesystem. Bundles can be verified, inspected, unbundled, and used
+// as a source for `git clone` and `git fetch` (offline transport).
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/storage"
+	"github.com/kurobon/gitgym/backend/internal/git"
+)
+
+func init() {
+	git.RegisterCommand("bundle", func() git.Command { return &BundleCommand{} })
+}
+
+type BundleCommand struct{}
+
+// Ensure BundleCommand implements git.Command
+var _ git.Command = (*BundleCommand)(nil)
+
+type BundleOptions struct {
+	SubCmd string
+	File   string
+	Args   []string
+}
+
+func (c *BundleCommand) Execute(ctx context.Context, s *git.Session, args []string) (string, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	opts, err := c.parseArgs(args)
+	if err != nil {
+		if err.Error() == "help requested" {
+			return c.Help(), nil
+		}
+		return "", err
+	}
+
+	switch opts.SubCmd {
+	case "create":
+		return c.create(s, opts)
+	case "verify":
+		return c.verify(s, opts)
+	case "list-heads":
+		return c.listHeads(s, opts)
+	case "unbundle":
+		return c.unbundle(s, opts)
+	default:
+		return "", fmt.Errorf("fatal: unknown subcommand: `%s'", opts.SubCmd)
+	}
+}
+
+func (c *BundleCommand) parseArgs(args []string) (*BundleOptions, error) {
+	cmdArgs := args[1:]
+	if len(cmdArgs) == 0 {
+		return nil, fmt.Errorf("usage: git bundle (create|verify|list-heads|unbundle) <file> [<args>]")
+	}
+	for _, arg := range cmdArgs {
+		if arg == "-h" || arg == "--help" {
+			return nil, fmt.Errorf("help requested")
+		}
+	}
+	if len(cmdArgs) < 2 {
+		return nil, fmt.Errorf("usage: git bundle %s <file>", cmdArgs[0])
+	}
+
+	opts := &BundleOptions{
+		SubCmd: cmdArgs[0],
+		File:   cmdArgs[1],
+	}
+	for _, arg := range cmdArgs[2:] {
+		if arg == "-q" || arg == "--quiet" || arg == "--progress" {
+			continue
+		}
+		opts.Args = append(opts.Args, arg)
+	}
+	return opts, nil
+}
+
+func (c *BundleCommand) create(s *git.Session, opts *BundleOptions) (string, error) {
+	repo := s.GetRepo()
+	if repo == nil {
+		return "", fmt.Errorf("fatal: not a git repository")
+	}
+
+	refs, excludes, err := c.resolveRevArgs(repo, opts.Args)
+	if err != nil {
+		return "", err
+	}
+	if len(refs) == 0 {
+		return "", fmt.Errorf("fatal: Refusing to create empty bundle.")
+	}
+
+	tips := make([]plumbing.Hash, 0, len(refs))
+	for _, r := range refs {
+		tips = append(tips, r.Hash())
+	}
+	prerequisites, err := git.BundleBoundary(repo.Storer, tips, excludes)
+	if err != nil {
+		return "", fmt.Errorf("fatal: %w", err)
+	}
+
+	var buf bytes.Buffer
+	count, err := git.WriteBundle(&buf, repo.Storer, refs, prerequisites)
+	if err != nil {
+		return "", fmt.Errorf("fatal: %w", err)
+	}
+
+	f, err := s.Filesystem.Create(sessionFilePath(s, opts.File))
+	if err != nil {
+		return "", fmt.Errorf("fatal: cannot create '%s': %w", opts.File, err)
+	}
+	defer f.Close()
+	if _, err := f.Write(buf.Bytes()); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("Enumerating objects: %d, done.\nTotal %d (delta 0), reused 0 (delta 0), pack-reused 0", count, count), nil
+}
+
+// resolveRevArgs turns `git bundle create` rev-list arguments into the refs to
+// advertise and the commits to exclude (from `^rev`, `A..B` and `A...B`).
+func (c *BundleCommand) resolveRevArgs(repo *gogit.Repository, args []string) ([]*plumbing.Reference, []plumbing.Hash, error) {
+	var refs []*plumbing.Reference
+	var excludes []plumbing.Hash
+	seen := make(map[plumbing.ReferenceName]bool)
+
+	addRef := func(ref *plumbing.Reference) {
+		if !seen[ref.Name()] {
+			seen[ref.Name()] = true
+			refs = append(refs, ref)
+		}
+	}
+
+	addMatching := func(match func(plumbing.ReferenceName) bool) error {
+		iter, err := repo.References()
+		if err != nil {
+			return err
+		}
+		return iter.ForEach(func(r *plumbing.Reference) error {
+			if r.Type() == plumbing.HashReference && match(r.Name()) {
+				addRef(r)
+			}
+			return nil
+		})
+	}
+
+	for _, arg := range args {
+		switch {
+		case arg == "--all":
+			if head, err := repo.Head(); err == nil {
+				addRef(plumbing.NewHashReference(plumbing.HEAD, head.Hash()))
+			}
+			if err := addMatching(func(n plumbing.ReferenceName) bool {
+				return n.IsBranch() || n.IsTag() || n.IsRemote()
+			}); err != nil {
+				return nil, nil, err
+			}
+		case arg == "--branches":
+			if err := addMatching(plumbing.ReferenceName.IsBranch); err != nil {
+				return nil, nil, err
+			}
+		case arg == "--tags":
+			if err := addMatching(plumbing.ReferenceName.IsTag); err != nil {
+				return nil, nil, err
+			}
+		case strings.HasPrefix(arg, "^"):
+			h, err := git.ResolveRevision(repo, arg[1:])
+			if err != nil {
+				return nil, nil, fmt.Errorf("fatal: bad revision '%s'", arg)
+			}
+			excludes = append(excludes, *h)
+		case strings.Contains(arg, ".."):
+			r, _ := parseRevRange(arg)
+			ref, err := c.resolveBundleRef(repo, r.To)
+			if err != nil {
+				return nil, nil, err
+			}
+			addRef(ref)
+			if !r.Symmetric {
+				h, err := git.ResolveRevision(repo, r.From)
+				if err != nil {
+					return nil, nil, fmt.Errorf("fatal: bad revision '%s'", r.From)
+				}
+				excludes = append(excludes, *h)
+				continue
+			}
+			// A...B: both tips, without the history they share
+			fromRef, err := c.resolveBundleRef(repo, r.From)
+			if err != nil {
+				return nil, nil, err
+			}
+			addRef(fromRef)
+			bases, err := mergeBases(repo, fromRef.Hash(), ref.Hash())
+			if err != nil {
+				return nil, nil, err
+			}
+			excludes = append(excludes, bases...)
+		default:
+			ref, err := c.resolveBundleRef(repo, arg)
+			if err != nil {
+				return nil, nil, err
+			}
+			addRef(ref)
+		}
+	}
+	return refs, excludes, nil
+}
+
+// resolveBundleRef resolves a positive argument to a fully-qualified ref.
+// Bare commit IDs cannot be advertised, mirroring git's behaviour.
+func (c *BundleCommand) resolveBundleRef(repo *gogit.Repository, name string) (*plumbing.Reference, error) {
+	if name == "HEAD" {
+		head, err := repo.Head()
+		if err != nil {
+			return nil, fmt.Errorf("fatal: bad revision 'HEAD'")
+		}
+		return plumbing.NewHashReference(plumbing.HEAD, head.Hash()), nil
+	}
+
+	candidates := []string{name, "refs/" + name, "refs/tags/" + name, "refs/heads/" + name, "refs/remotes/" + name}
+	for _, candidate := range candidates {
+		ref, err := repo.Reference(plumbing.ReferenceName(candidate), true)
+		if err == nil && strings.HasPrefix(candidate, "refs/") {
+			return plumbing.NewHashReference(plumbing.ReferenceName(candidate), ref.Hash()), nil
+		}
+	}
+
+	if _, err := git.ResolveRevision(repo, name); err == nil {
+		return nil, fmt.Errorf("fatal: Refusing to create empty bundle.\nhint: '%s' is not a ref; bundle a branch or tag instead", name)
+	}
+	return nil, fmt.Errorf("fatal: bad revision '%s'", name)
+}
+
+func (c *BundleCommand) verify(s *git.Session, opts *BundleOptions) (string, error) {
+	b, err := readSessionBundle(s, opts.File)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	if len(b.References) == 1 {
+		sb.WriteString("The bundle contains this ref:\n")
+	} else {
+		fmt.Fprintf(&sb, "The bundle contains these %d refs:\n", len(b.References))
+	}
+	for _, ref := range b.References {
+		fmt.Fprintf(&sb, "%s %s\n", ref.Hash(), ref.Name())
+	}
+
+	if len(b.Prerequisites) == 0 {
+		sb.WriteString("The bundle records a complete history.\n")
+	} else {
+		if len(b.Prerequisites) == 1 {
+			sb.WriteString("The bundle requires this ref:\n")
+		} else {
+			fmt.Fprintf(&sb, "The bundle requires these %d refs:\n", len(b.Prerequisites))
+		}
+		for _, p := range b.Prerequisites {
+			fmt.Fprintf(&sb, "%s %s\n", p.Hash, p.Comment)
+		}
+
+		repo := s.GetRepo()
+		if repo == nil {
+			return "", fmt.Errorf("fatal: need a repository to verify a bundle")
+		}
+		if missing := b.MissingPrerequisites(repo.Storer); len(missing) > 0 {
+			return "", fmt.Errorf("error: %s\n%s", (&git.MissingPrerequisitesError{Missing: missing}).Error(), opts.File+" is NOT okay")
+		}
+	}
+
+	sb.WriteString("The bundle uses this hash algorithm: sha1\n")
+	fmt.Fprintf(&sb, "%s is okay", opts.File)
+	return sb.String(), nil
+}
+
+func (c *BundleCommand) listHeads(s *git.Session, opts *BundleOptions) (string, error) {
+	b, err := readSessionBundle(s, opts.File)
+	if err != nil {
+		return "", err
+	}
+	return formatBundleHeads(b, opts.Args), nil
+}
+
+func (c *BundleCommand) unbundle(s *git.Session, opts *BundleOptions) (string, error) {
+	repo := s.GetRepo()
+	if repo == nil {
+		return "", fmt.Errorf("fatal: not a git repository")
+	}
+
+	b, err := readSessionBundle(s, opts.File)
+	if err != nil {
+		return "", err
+	}
+	if err := b.Unbundle(repo.Storer); err != nil {
+		var missing *git.MissingPrerequisitesError
+		if errors.As(err, &missing) {
+			return "", fmt.Errorf("error: %s", err.Error())
+		}
+		return "", fmt.Errorf("fatal: failed to unbundle: %w", err)
+	}
+
+	// Like git, unbundle only stores objects and reports the refs; it does not update them.
+	return formatBundleHeads(b, opts.Args), nil
+}
+
+func formatBundleHeads(b *git.Bundle, filter []string) string {
+	var lines []string
+	for _, ref := range b.References {
+		if len(filter) > 0 && !bundleRefMatches(ref.Name(), filter) {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%s %s", ref.Hash(), ref.Name()))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func bundleRefMatches(name plumbing.ReferenceName, filter []string) bool {
+	for _, f := range filter {
+		if string(name) == f || strings.HasSuffix(string(name), "/"+f) {
+			return true
+		}
+	}
+	return false
+}
+
+// sessionFilePath resolves p against the session's current directory and
+// returns the path in the form billy expects (no leading slash).
+func sessionFilePath(s *git.Session, p string) string {
+	if !strings.HasPrefix(p, "/") {
+		p = path.Join(s.CurrentDir, p)
+	}
+	return strings.TrimPrefix(path.Clean(p), "/")
+}
+
+// readSessionBundle reads and parses a bundle from the session filesystem.
+func readSessionBundle(s *git.Session, p string) (*git.Bundle, error) {
+	f, err := s.Filesystem.Open(sessionFilePath(s, p))
+	if err != nil {
+		return nil, fmt.Errorf("fatal: could not open '%s'", p)
+	}
+	defer f.Close()
+
+	b, err := git.ReadBundle(f)
+	if err != nil {
+		if errors.Is(err, git.ErrNotBundle) {
+			return nil, fmt.Errorf("fatal: '%s' does not look like a v2 or v3 bundle file", p)
+		}
+		return nil, fmt.Errorf("fatal: %w", err)
+	}
+	return b, nil
+}
+
+// openSessionBundleRepo returns a repository view of the bundle at p if p
+// names a bundle file in the session filesystem. ok is false when p is not a
+// bundle, so callers can fall back to other remote resolution.
+func openSessionBundleRepo(s *git.Session, p string, base storage.Storer) (repo *gogit.Repository, ok bool, err error) {
+	if s.Filesystem == nil {
+		return nil, false, nil
+	}
+	f, openErr := s.Filesystem.Open(sessionFilePath(s, p))
+	if openErr != nil {
+		return nil, false, nil
+	}
+	defer f.Close()
+
+	header := make([]byte, len(git.BundleSignatureV2)+1)
+	if _, readErr := io.ReadFull(f, header); readErr != nil || !git.IsBundle(header) {
+		return nil, false, nil
+	}
+	if _, seekErr := f.Seek(0, io.SeekStart); seekErr != nil {
+		return nil, true, seekErr
+	}
+
+	b, err := git.ReadBundle(f)
+	if err != nil {
+		return nil, true, err
+	}
+	repo, err = b.Repository(base)
+	if err != nil {
+		return nil, true, err
+	}
+	return repo, true, nil
+}
+
+func (c *BundleCommand) Help() string {
+	return `📘 GIT-BUNDLE (1)                                       Git Manual
+
+ 💡 DESCRIPTION
+    ・ネットワークを使わずにリポジトリの履歴を1つのファイルにまとめて持ち運ぶ
+    ・作成したバンドルは git clone / git fetch の取得元として使えます
+    ・本物の git と互換性のある v2 形式で書き出されます
+
+ 📋 SYNOPSIS
+    git bundle create <file> <git-rev-list-args>
+    git bundle verify <file>
+    git bundle list-heads <file> [<refname>...]
+    git bundle unbundle <file> [<refname>...]
+
+ ⚙️  SUBCOMMANDS
+    create
+        指定したブランチ・タグ（--all, --branches, --tags, A..B も可）を
+        バンドルファイルに書き出します。
+
+    verify
+        バンドルが正しい形式か、必要な前提コミットが手元にあるかを確認します。
+
+    list-heads
+        バンドルに含まれる参照（ref）の一覧を表示します。
+
+    unbundle
+        バンドル内のオブジェクトを現在のリポジトリに取り込みます。
+        （参照は更新されません。fetch と組み合わせて使います）
+
+ 🛠  PRACTICAL EXAMPLES
+    1. 基本: main ブランチをバンドルにする
+       $ git bundle create repo.bundle main
+
+    2. 実践: バンドルからクローンする
+       $ git clone repo.bundle my-copy
+
+    3. 実践: 差分だけを持ち運ぶ（増分バンドル）
+       $ git bundle create update.bundle v1.0..main
+       $ git fetch update.bundle main
+
+ 🔗 REFERENCE
+    Full documentation: https://git-scm.com/docs/git-bundle
+`
+}
@@ -0,0 +1,134 @@
+package commands
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/kurobon/gitgym/backend/internal/git"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBundleCommand(t *testing.T) {
+	ctx := context.Background()
+	sm := git.NewSessionManager()
+	s, _ := sm.CreateSession("test-bundle")
+
+	repo, err := s.InitRepo("src")
+	require.NoError(t, err)
+	s.CurrentDir = "/src"
+	commitFile(t, repo, "README.md", "hello", "Initial commit")
+	commitFile(t, repo, "a.txt", "a", "Add a")
+	first, _ := repo.Head()
+
+	cmd := &BundleCommand{}
+
+	t.Run("Create and verify full bundle", func(t *testing.T) {
+		out, err := cmd.Execute(ctx, s, []string{"bundle", "create", "/repo.bundle", "main"})
+		require.NoError(t, err)
+		assert.Contains(t, out, "Enumerating objects")
+
+		out, err = cmd.Execute(ctx, s, []string{"bundle", "verify", "/repo.bundle"})
+		require.NoError(t, err)
+		assert.Contains(t, out, "The bundle contains this ref:")
+		assert.Contains(t, out, first.Hash().String()+" refs/heads/main")
+		assert.Contains(t, out, "The bundle records a complete history.")
+		assert.Contains(t, out, "/repo.bundle is okay")
+	})
+
+	t.Run("List heads", func(t *testing.T) {
+		out, err := cmd.Execute(ctx, s, []string{"bundle", "list-heads", "/repo.bundle"})
+		require.NoError(t, err)
+		assert.Equal(t, first.Hash().String()+" refs/heads/main", out)
+	})
+
+	t.Run("Empty bundle refused", func(t *testing.T) {
+		_, err := cmd.Execute(ctx, s, []string{"bundle", "create", "/empty.bundle"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Refusing to create empty bundle")
+	})
+
+	t.Run("Not a bundle", func(t *testing.T) {
+		_, err := cmd.Execute(ctx, s, []string{"bundle", "verify", "README.md"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "does not look like a v2 or v3 bundle file")
+	})
+
+	t.Run("Clone from bundle", func(t *testing.T) {
+		s.CurrentDir = "/"
+		out, err := (&CloneCommand{}).Execute(ctx, s, []string{"clone", "repo.bundle", "copy"})
+		require.NoError(t, err)
+		assert.Contains(t, out, "Cloned into 'copy'")
+
+		clone := s.Repos["copy"]
+		require.NotNil(t, clone)
+		head, err := clone.Head()
+		require.NoError(t, err)
+		assert.Equal(t, plumbing.NewBranchReferenceName("main"), head.Name())
+		assert.Equal(t, first.Hash(), head.Hash())
+
+		remoteRef, err := clone.Reference("refs/remotes/origin/main", true)
+		require.NoError(t, err)
+		assert.Equal(t, first.Hash(), remoteRef.Hash())
+	})
+
+	t.Run("Incremental bundle and fetch", func(t *testing.T) {
+		s.CurrentDir = "/src"
+		commitFile(t, repo, "b.txt", "b", "Add b")
+		second, _ := repo.Head()
+
+		_, err := cmd.Execute(ctx, s, []string{"bundle", "create", "/update.bundle", first.Hash().String() + "..main"})
+		require.NoError(t, err)
+
+		out, err := cmd.Execute(ctx, s, []string{"bundle", "verify", "/update.bundle"})
+		require.NoError(t, err)
+		assert.Contains(t, out, "The bundle requires this ref:")
+		assert.Contains(t, out, first.Hash().String()+" Add a")
+
+		s.CurrentDir = "/copy"
+		out, err = (&FetchCommand{}).Execute(ctx, s, []string{"fetch", "/update.bundle", "main"})
+		require.NoError(t, err)
+		assert.Contains(t, out, "-> FETCH_HEAD")
+
+		clone := s.Repos["copy"]
+		fetchHead, err := clone.Reference("FETCH_HEAD", true)
+		require.NoError(t, err)
+		assert.Equal(t, second.Hash(), fetchHead.Hash())
+		_, err = clone.CommitObject(second.Hash())
+		require.NoError(t, err)
+	})
+
+	t.Run("Symmetric range bundles both tips", func(t *testing.T) {
+		s.CurrentDir = "/src"
+		main, _ := repo.Head()
+		_, err := (&CheckoutCommand{}).Execute(ctx, s, []string{"checkout", "-b", "side", first.Hash().String()})
+		require.NoError(t, err)
+		commitFile(t, repo, "c.txt", "c", "Add c")
+
+		_, err = cmd.Execute(ctx, s, []string{"bundle", "create", "/sym.bundle", "main...side"})
+		require.NoError(t, err)
+
+		out, err := cmd.Execute(ctx, s, []string{"bundle", "verify", "/sym.bundle"})
+		require.NoError(t, err)
+		assert.Contains(t, out, main.Hash().String()+" refs/heads/main")
+		assert.Contains(t, out, "refs/heads/side")
+		assert.Contains(t, out, first.Hash().String()+" Add a", "the merge base is a prerequisite")
+	})
+
+	t.Run("Incremental bundle needs prerequisites", func(t *testing.T) {
+		_, err := s.InitRepo("fresh")
+		require.NoError(t, err)
+		s.CurrentDir = "/fresh"
+
+		_, err = cmd.Execute(ctx, s, []string{"bundle", "unbundle", "/update.bundle"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "Repository lacks these prerequisite commits")
+
+		s.CurrentDir = "/"
+		_, err = (&CloneCommand{}).Execute(ctx, s, []string{"clone", "update.bundle", "partial"})
+		require.Error(t, err)
+		assert.True(t, strings.Contains(err.Error(), "prerequisite"))
+	})
+}
@@ -11,7 +11,9 @@ import (
 	"fmt"
 	"log"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 
 	gogit "github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
@@ -35,10 +37,27 @@ var _ git.Command = (*CloneCommand)(nil)
 var SafeRepoNameRegex = regexp.MustCompile(`^[a-zA-Z0-9_\-]+$`)
 
 type CloneOptions struct {
-	URL       string
-	Directory string
-	Depth     int
-	Branch    string
+	URL            string
+	Directory      string
+	Depth          int
+	ShallowSince   time.Time
+	Branch         string
+	Origin         string // Remote name (-o); "origin" by default
+	Bare           bool
+	Mirror         bool
+	SingleBranch   bool
+	NoSingleBranch bool
+	NoCheckout     bool
+}
+
+// singleBranch reports whether only one branch is cloned: --single-branch,
+// or a shallow clone without --no-single-branch.
+func (o *CloneOptions) singleBranch() bool {
+	return o.SingleBranch || (o.shallow() && !o.NoSingleBranch)
+}
+
+func (o *CloneOptions) shallow() bool {
+	return o.Depth > 0 || !o.ShallowSince.IsZero()
 }
 
 type cloneContext struct {
@@ -70,33 +89,79 @@ func (c *CloneCommand) Execute(ctx context.Context, s *git.Session, args []strin
 	}
 
 	// 3. Perform Clone
-	return c.performClone(s, clCtx)
+	return c.performClone(s, clCtx, opts)
 }
 
 func (c *CloneCommand) parseArgs(args []string) (*CloneOptions, error) {
 	opts := &CloneOptions{}
 	cmdArgs := args[1:]
+	// value returns the argument of an option given as "--opt=value" or "--opt value"
+	value := func(i *int, arg, name string) (string, error) {
+		if v, ok := strings.CutPrefix(arg, name+"="); ok {
+			return v, nil
+		}
+		if *i+1 >= len(cmdArgs) {
+			return "", fmt.Errorf("error: option `%s' requires a value", strings.TrimLeft(name, "-"))
+		}
+		*i++
+		return cmdArgs[*i], nil
+	}
 	for i := 0; i < len(cmdArgs); i++ {
 		arg := cmdArgs[i]
-		switch arg {
+		name, _, _ := strings.Cut(arg, "=")
+		switch name {
 		case "-h", "--help":
 			return nil, fmt.Errorf("help requested")
 		case "--depth":
-			if i+1 < len(cmdArgs) {
-				i++
-				var depth int
-				_, err := fmt.Sscanf(cmdArgs[i], "%d", &depth)
-				if err != nil || depth < 1 {
-					return nil, fmt.Errorf("fatal: depth must be a positive integer")
-				}
-				opts.Depth = depth
+			v, err := value(&i, arg, name)
+			if err != nil {
+				return nil, err
+			}
+			depth, err := strconv.Atoi(v)
+			if err != nil || depth < 1 {
+				return nil, fmt.Errorf("fatal: depth %s is not a positive number", v)
 			}
+			opts.Depth = depth
+		case "--shallow-since":
+			v, err := value(&i, arg, name)
+			if err != nil {
+				return nil, err
+			}
+			since, err := parseSimulatedDate(v)
+			if err != nil {
+				return nil, fmt.Errorf("fatal: %v", err)
+			}
+			opts.ShallowSince = since
 		case "-b", "--branch":
-			if i+1 < len(cmdArgs) {
-				i++
-				opts.Branch = cmdArgs[i]
+			v, err := value(&i, arg, name)
+			if err != nil {
+				return nil, err
+			}
+			opts.Branch = v
+		case "-o", "--origin":
+			v, err := value(&i, arg, name)
+			if err != nil {
+				return nil, err
+			}
+			if !SafeRepoNameRegex.MatchString(v) {
+				return nil, fmt.Errorf("fatal: '%s' is not a valid remote name", v)
 			}
+			opts.Origin = v
+		case "--bare":
+			opts.Bare = true
+		case "--mirror":
+			opts.Mirror = true
+			opts.Bare = true
+		case "--single-branch":
+			opts.SingleBranch = true
+		case "--no-single-branch":
+			opts.NoSingleBranch = true
+		case "-n", "--no-checkout":
+			opts.NoCheckout = true
 		default:
+			if strings.HasPrefix(arg, "-") {
+				return nil, fmt.Errorf("error: unknown option `%s'", strings.TrimLeft(arg, "-"))
+			}
 			if opts.URL == "" {
 				opts.URL = arg
 			} else if opts.Directory == "" {
@@ -108,6 +173,12 @@ func (c *CloneCommand) parseArgs(args []string) (*CloneOptions, error) {
 	if opts.URL == "" {
 		return nil, fmt.Errorf("usage: git clone <url> [<directory>]")
 	}
+	if opts.Bare && opts.Origin != "" {
+		return nil, fmt.Errorf("fatal: options '--bare' and '--origin %s' cannot be used together", opts.Origin)
+	}
+	if opts.Origin == "" {
+		opts.Origin = "origin"
+	}
 	return opts, nil
 }
 
@@ -124,10 +195,18 @@ func (c *CloneCommand) resolveContext(s *git.Session, opts *CloneOptions) (*clon
 		}
 		repoName = parts[len(parts)-1]
 		repoName = strings.TrimSuffix(repoName, ".git")
+		repoName = strings.TrimSuffix(repoName, ".bundle")
+		if opts.Bare {
+			repoName += ".git"
+		}
 	}
 
-	// SECURITY: Input Validation
-	if !SafeRepoNameRegex.MatchString(repoName) {
+	// SECURITY: Input Validation (bare repositories may end in ".git")
+	baseName := repoName
+	if opts.Bare {
+		baseName = strings.TrimSuffix(repoName, ".git")
+	}
+	if !SafeRepoNameRegex.MatchString(baseName) {
 		return nil, fmt.Errorf("invalid repository name '%s': must contain only alphanumeric characters, underscores, or hyphens", repoName)
 	}
 	if repoName == "." || repoName == ".." {
@@ -143,7 +222,15 @@ func (c *CloneCommand) resolveContext(s *git.Session, opts *CloneOptions) (*clon
 	var remoteSt storage.Storer
 	var remotePath string
 
-	if s.Manager != nil {
+	// Bundle files in the session filesystem act as offline remotes
+	if bundleRepo, isBundle, err := openSessionBundleRepo(s, opts.URL, nil); isBundle {
+		if err != nil {
+			return nil, fmt.Errorf("fatal: %w", err)
+		}
+		remoteRepo = bundleRepo
+		remoteSt = bundleRepo.Storer
+		remotePath = "/" + sessionFilePath(s, opts.URL)
+	} else if s.Manager != nil {
 		// Check SharedRemotes
 		if r, ok := s.Manager.GetSharedRemote(opts.URL); ok {
 			remoteRepo = r
@@ -157,7 +244,7 @@ func (c *CloneCommand) resolveContext(s *git.Session, opts *CloneOptions) (*clon
 			} else {
 				remotePath = opts.URL
 			}
-		} else if r, ok := s.Manager.GetSharedRemote(repoName); ok {
+		} else if r, ok := s.Manager.GetSharedRemote(strings.TrimSuffix(repoName, ".git")); ok {
 			// This fallback might be ambiguous if repoName is custom 'my-project' but remote is 'repo'
 			// Only rely on URL matching if possible, but keep fallback for short names
 			// However, if directory is custom, repoName is custom. Remote lookup should use URL mainly.
@@ -166,7 +253,7 @@ func (c *CloneCommand) resolveContext(s *git.Session, opts *CloneOptions) (*clon
 			remoteSt = r.Storer
 
 			// ...
-			remotePath = repoName
+			remotePath = strings.TrimSuffix(repoName, ".git")
 		}
 	}
 
@@ -183,8 +270,25 @@ func (c *CloneCommand) resolveContext(s *git.Session, opts *CloneOptions) (*clon
 	}, nil
 }
 
-func (c *CloneCommand) performClone(s *git.Session, clCtx *cloneContext) (string, error) {
-	// Create Local Working Copy
+func (c *CloneCommand) performClone(s *git.Session, clCtx *cloneContext, opts *CloneOptions) (string, error) {
+	// Resolve what to check out before touching the filesystem
+	headBranch := c.remoteHeadBranch(clCtx.RemoteRepo)
+	checkout := headBranch
+	if opts.Branch != "" {
+		name, ok := dwimRef(opts.Branch, func(n plumbing.ReferenceName) bool {
+			if !n.IsBranch() && !n.IsTag() {
+				return false
+			}
+			_, err := clCtx.RemoteRepo.Storer.Reference(n)
+			return err == nil
+		})
+		if !ok {
+			return "", fmt.Errorf("fatal: Remote branch %s not found in upstream %s", opts.Branch, opts.Origin)
+		}
+		checkout = name
+	}
+
+	// Create Local Repository (.git inside a working copy, or bare)
 	if errMkdir := s.Filesystem.MkdirAll(clCtx.RepoName, 0755); errMkdir != nil {
 		return "", fmt.Errorf("failed to create directory: %w", errMkdir)
 	}
@@ -194,48 +298,39 @@ func (c *CloneCommand) performClone(s *git.Session, clCtx *cloneContext) (string
 		return "", fmt.Errorf("failed to chroot: %w", err)
 	}
 
-	// Create .git
-	if errDotGit := repoFS.MkdirAll(".git", 0755); errDotGit != nil {
-		return "", fmt.Errorf("failed to create .git directory: %w", errDotGit)
+	var localRepo *gogit.Repository
+	if opts.Bare {
+		localRepo, err = gogit.Init(filesystem.NewStorage(repoFS, cache.NewObjectLRUDefault()), nil)
+	} else {
+		// Create .git
+		if errDotGit := repoFS.MkdirAll(".git", 0755); errDotGit != nil {
+			return "", fmt.Errorf("failed to create .git directory: %w", errDotGit)
+		}
+		dotGitFS, chrootErr := repoFS.Chroot(".git")
+		if chrootErr != nil {
+			return "", fmt.Errorf("failed to chroot .git: %w", chrootErr)
+		}
+		localRepo, err = gogit.Init(filesystem.NewStorage(dotGitFS, cache.NewObjectLRUDefault()), repoFS)
 	}
-	dotGitFS, err := repoFS.Chroot(".git")
 	if err != nil {
-		return "", fmt.Errorf("failed to chroot .git: %w", err)
-	}
-
-	localSt := filesystem.NewStorage(dotGitFS, cache.NewObjectLRUDefault())
-
-	// Perform Full Object Copy (No HybridStorer)
-	if err := c.copyObjects(clCtx.RemoteSt, localSt); err != nil {
-		return "", fmt.Errorf("failed to copy objects: %w", err)
+		return "", fmt.Errorf("failed to init local repo: %w", err)
 	}
 
-	localRepo, err := gogit.Init(localSt, repoFS)
+	// Copy Objects and References
+	refs, err := c.selectReferences(clCtx.RemoteRepo, opts, checkout)
 	if err != nil {
-		return "", fmt.Errorf("failed to init local repo: %w", err)
+		return "", err
 	}
-
-	// Copy References
-	if err := c.copyReferences(localRepo, clCtx.RemoteRepo); err != nil {
+	if err := c.copyHistory(clCtx, localRepo, refs, opts); err != nil {
+		return "", fmt.Errorf("failed to copy objects: %w", err)
+	}
+	if err := c.copyReferences(localRepo, clCtx.RemoteRepo, refs, opts); err != nil {
 		log.Printf("Clone: Warning - Issue copying references: %v", err)
 	}
 
 	// Configure Origin
-	_, err = localRepo.CreateRemote(&config.RemoteConfig{
-		Name: "origin",
-		URLs: []string{clCtx.RemotePath}, // Use internal path for functionality
-	})
-	if err != nil {
-		return "", fmt.Errorf("failed to configure origin: %w", err)
-	}
-
-	// Store the friendly URL for display purposes (git remote -v)
-	cfg, err := localRepo.Config()
-	if err == nil {
-		cfg.Raw.Section("remote").Subsection("origin").AddOption("displayurl", clCtx.RemoteURL)
-		if err := localRepo.Storer.SetConfig(cfg); err != nil {
-			log.Printf("Clone: Warning - failed to set display URL config: %v", err)
-		}
+	if err := c.configureRemote(localRepo, clCtx, opts, checkout); err != nil {
+		return "", err
 	}
 
 	s.Repos[clCtx.RepoName] = localRepo
@@ -244,59 +339,199 @@ func (c *CloneCommand) performClone(s *git.Session, clCtx *cloneContext) (string
 	s.CurrentDir = "/" + clCtx.RepoName
 
 	// Checkout Default Branch
-	if err := c.checkoutDefaultBranch(localRepo, clCtx.RemoteRepo); err != nil {
+	if err := c.checkoutDefaultBranch(localRepo, opts, checkout); err != nil {
 		log.Printf("Clone: Warning - Checkout default branch issue: %v", err)
 	}
 
-	return fmt.Sprintf("Cloned into '%s'... (Using shared remote)", clCtx.RepoName), nil
+	var sb strings.Builder
+	if opts.Bare {
+		fmt.Fprintf(&sb, "Cloned into bare repository '%s'... (Using shared remote)", clCtx.RepoName)
+	} else {
+		fmt.Fprintf(&sb, "Cloned into '%s'... (Using shared remote)", clCtx.RepoName)
+	}
+	if len(refs) == 0 {
+		sb.WriteString("\nwarning: You appear to have cloned an empty repository.")
+	} else if checkout.IsTag() && !opts.Bare {
+		fmt.Fprintf(&sb, "\nNote: switching to '%s'.\n\nYou are in 'detached HEAD' state.", checkout.Short())
+	}
+	if shallow := git.ShallowCommits(localRepo); len(shallow) > 0 {
+		fmt.Fprintf(&sb, "\nShallow clone: history is cut at %d grafted %s (git fetch --unshallow to get the rest).", len(shallow), plural(len(shallow), "commit", "commits"))
+	}
+	return sb.String(), nil
 }
 
-func (c *CloneCommand) copyReferences(local *gogit.Repository, remote *gogit.Repository) error {
-	refs, err := remote.References()
+// remoteHeadBranch returns the branch the remote's HEAD points to.
+func (c *CloneCommand) remoteHeadBranch(remote *gogit.Repository) plumbing.ReferenceName {
+	headRef, err := remote.Storer.Reference(plumbing.HEAD)
+	targetBranch := plumbing.ReferenceName("refs/heads/main")
+	if err == nil {
+		if headRef.Type() == plumbing.SymbolicReference {
+			targetBranch = headRef.Target()
+		} else if resolved, err := remote.Head(); err == nil && resolved.Name().IsBranch() {
+			targetBranch = resolved.Name()
+		}
+	}
+	return targetBranch
+}
+
+// selectReferences lists the remote refs the clone copies: every ref for
+// --mirror, branches and tags otherwise, or only the checked-out branch with
+// --single-branch. Shallow clones leave tags to copyReferences.
+func (c *CloneCommand) selectReferences(remote *gogit.Repository, opts *CloneOptions, checkout plumbing.ReferenceName) ([]*plumbing.Reference, error) {
+	all, err := sortedHashRefs(remote)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	return refs.ForEach(func(ref *plumbing.Reference) error {
+	var refs []*plumbing.Reference
+	for _, ref := range all {
 		name := ref.Name()
-		if name.IsBranch() {
-			newRefName := plumbing.ReferenceName(fmt.Sprintf("refs/remotes/origin/%s", name.Short()))
-			newRef := plumbing.NewHashReference(newRefName, ref.Hash())
-			return local.Storer.SetReference(newRef)
-		} else if name.IsRemote() || name.IsTag() {
-			return local.Storer.SetReference(ref)
+		switch {
+		case opts.Mirror, name == checkout:
+			refs = append(refs, ref)
+		case opts.singleBranch():
+		case name.IsTag() && opts.shallow():
+			// Added by copyReferences if their commit is within the history copied
+		case name.IsBranch() || name.IsTag() || (name.IsRemote() && !opts.Bare):
+			refs = append(refs, ref)
 		}
-		return nil
-	})
+	}
+	return refs, nil
 }
 
-func (c *CloneCommand) checkoutDefaultBranch(local *gogit.Repository, remote *gogit.Repository) error {
-	w, err := local.Worktree()
+// copyHistory copies the objects of the selected refs. A full clone copies
+// every object of the remote; --single-branch and shallow clones copy only
+// the history they keep, and tags whose commits came along.
+func (c *CloneCommand) copyHistory(clCtx *cloneContext, local *gogit.Repository, refs []*plumbing.Reference, opts *CloneOptions) error {
+	if !opts.singleBranch() && !opts.shallow() {
+		return c.copyObjects(clCtx.RemoteSt, local.Storer)
+	}
+
+	var tips []plumbing.Hash
+	for _, ref := range refs {
+		tips = append(tips, ref.Hash())
+	}
+	limit := git.ShallowLimit{Depth: opts.Depth, Since: opts.ShallowSince}
+	return git.CopyShallowHistory(clCtx.RemoteRepo, local, tips, limit)
+}
+
+// copyReferences creates the local refs: remote-tracking branches under
+// refs/remotes/<origin>/ for a normal clone, the remote's own names for a
+// bare or mirror clone. For single-branch and shallow clones, tags come
+// along when their commit was copied.
+func (c *CloneCommand) copyReferences(local *gogit.Repository, remote *gogit.Repository, refs []*plumbing.Reference, opts *CloneOptions) error {
+	for _, ref := range refs {
+		name := ref.Name()
+		if name.IsBranch() && !opts.Bare {
+			name = plumbing.ReferenceName(fmt.Sprintf("refs/remotes/%s/%s", opts.Origin, name.Short()))
+		}
+		if err := local.Storer.SetReference(plumbing.NewHashReference(name, ref.Hash())); err != nil {
+			return err
+		}
+	}
+
+	if opts.Mirror || (!opts.singleBranch() && !opts.shallow()) {
+		return nil
+	}
+	tags, err := remote.Tags()
 	if err != nil {
 		return err
 	}
+	return tags.ForEach(func(ref *plumbing.Reference) error {
+		commit, ok := git.PeelToCommit(remote, ref.Hash())
+		if !ok || !git.HasObject(local, commit) {
+			return nil
+		}
+		if err := git.CopyObjectRecursive(remote, local, ref.Hash()); err != nil {
+			return err
+		}
+		return local.Storer.SetReference(ref)
+	})
+}
 
-	headRef, err := remote.Head()
-	targetBranch := plumbing.ReferenceName("refs/heads/main")
+// configureRemote records the remote the clone came from. A mirror fetches
+// every ref onto itself; --single-branch fetches only the cloned branch.
+func (c *CloneCommand) configureRemote(local *gogit.Repository, clCtx *cloneContext, opts *CloneOptions, checkout plumbing.ReferenceName) error {
+	remote := &config.RemoteConfig{
+		Name: opts.Origin,
+		URLs: []string{clCtx.RemotePath}, // Use internal path for functionality
+	}
+	switch {
+	case opts.Mirror:
+		remote.Fetch = []config.RefSpec{"+refs/*:refs/*"}
+	case opts.singleBranch() && checkout.IsTag():
+		remote.Fetch = []config.RefSpec{config.RefSpec(fmt.Sprintf("+%s:%s", checkout, checkout))}
+	case opts.singleBranch():
+		remote.Fetch = []config.RefSpec{config.RefSpec(fmt.Sprintf("+%s:refs/remotes/%s/%s", checkout, opts.Origin, checkout.Short()))}
+	}
+	if _, err := local.CreateRemote(remote); err != nil {
+		return fmt.Errorf("failed to configure %s: %w", opts.Origin, err)
+	}
+
+	// Store the friendly URL for display purposes (git remote -v)
+	cfg, err := local.Config()
 	if err == nil {
-		if headRef.Type() == plumbing.SymbolicReference {
-			targetBranch = headRef.Target()
-		} else if headRef.Name().IsBranch() {
-			targetBranch = headRef.Name()
+		section := cfg.Raw.Section("remote").Subsection(opts.Origin)
+		section.AddOption("displayurl", clCtx.RemoteURL)
+		if opts.Mirror {
+			section.AddOption("mirror", "true")
+		}
+		if err := local.Storer.SetConfig(cfg); err != nil {
+			log.Printf("Clone: Warning - failed to set display URL config: %v", err)
 		}
 	}
+	return nil
+}
+
+// checkoutDefaultBranch points HEAD at the cloned branch. A bare clone just
+// moves HEAD; a normal clone creates the local branch tracking the remote one
+// and checks it out unless --no-checkout. Cloning a tag detaches HEAD.
+func (c *CloneCommand) checkoutDefaultBranch(local *gogit.Repository, opts *CloneOptions, targetBranch plumbing.ReferenceName) error {
+	if opts.Bare {
+		return local.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, targetBranch))
+	}
 
 	shortName := targetBranch.Short()
-	remoteRefName := plumbing.ReferenceName(fmt.Sprintf("refs/remotes/origin/%s", shortName))
-
-	if ref, err := local.Reference(remoteRefName, true); err == nil {
-		newBranchRef := plumbing.NewHashReference(targetBranch, ref.Hash())
-		_ = local.Storer.SetReference(newBranchRef)
-		return w.Checkout(&gogit.CheckoutOptions{
-			Branch: targetBranch,
-			Force:  true,
-		})
+	remoteRefName := plumbing.ReferenceName(fmt.Sprintf("refs/remotes/%s/%s", opts.Origin, shortName))
+	if targetBranch.IsTag() {
+		remoteRefName = targetBranch
+	}
+
+	ref, err := local.Reference(remoteRefName, true)
+	if err != nil {
+		// Empty repository: HEAD names the remote's (unborn) default branch
+		_ = local.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, targetBranch))
+		return fmt.Errorf("could not resolve default branch '%s'", shortName)
+	}
+	commit, ok := git.PeelToCommit(local, ref.Hash())
+	if !ok {
+		return fmt.Errorf("could not resolve default branch '%s'", shortName)
+	}
+
+	w, err := local.Worktree()
+	if err != nil {
+		return err
+	}
+	if targetBranch.IsTag() {
+		if opts.NoCheckout {
+			return local.Storer.SetReference(plumbing.NewHashReference(plumbing.HEAD, commit))
+		}
+		return w.Checkout(&gogit.CheckoutOptions{Hash: commit, Force: true})
+	}
+
+	newBranchRef := plumbing.NewHashReference(targetBranch, commit)
+	if err := local.Storer.SetReference(newBranchRef); err != nil {
+		return err
+	}
+	if err := git.SetBranchUpstream(local, shortName, opts.Origin, targetBranch); err != nil {
+		return err
+	}
+	if opts.NoCheckout {
+		return local.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, targetBranch))
 	}
-	return fmt.Errorf("could not resolve default branch '%s'", shortName)
+	return w.Checkout(&gogit.CheckoutOptions{
+		Branch: targetBranch,
+		Force:  true,
+	})
 }
 
 func (c *CloneCommand) copyObjects(src storage.Storer, dst storage.Storer) error {
@@ -317,18 +552,42 @@ func (c *CloneCommand) Help() string {
 
  💡 DESCRIPTION
     ・リモートリポジトリを複製して、手元にローカルリポジトリを作成します。
-    ・GitGymでは事前定義されたリポジトリURLのみサポートしています。
+    ・GitGymでは事前定義されたリポジトリURLと、git bundle で作成した
+      バンドルファイルのみサポートしています。
 
  📋 SYNOPSIS
     git clone [options] <url> [<directory>]
+    git clone --depth <n> [--no-single-branch] <url>
+    git clone --bare | --mirror <url> [<directory>]
 
  ⚙️  OPTIONS
     -b <branch>, --branch <branch>
-        クローン後に指定したブランチをチェックアウトします。
+        クローン後に指定したブランチ（またはタグ）をチェックアウトします。
+
+    -o <name>, --origin <name>
+        リモートの名前を origin の代わりに <name> にします。
 
     --depth <depth>
-        指定した数のコミットのみを取得します（シャロークローン）。
-        GitGymでは参考情報として受け付けますが、全オブジェクトがコピーされます。
+        各ブランチの先頭から指定した数のコミットのみを取得します（シャロークローン）。
+        それより古い履歴は取得されず、境界のコミットは grafted と表示されます。
+        --single-branch を含みます（全ブランチ欲しい時は --no-single-branch）。
+
+    --shallow-since <date>
+        指定した日時（例: 2024-01-01）以降のコミットのみを取得します。
+
+    --single-branch
+        1つのブランチ（--branch かリモートの HEAD）の履歴だけを取得します。
+
+    -n, --no-checkout
+        クローン後にファイルをチェックアウトしません（ワーキングツリーは空のまま）。
+
+    --bare
+        ワーキングツリーのないベアリポジトリ（<name>.git）を作成します。
+        ブランチはリモートと同じ名前（refs/heads/*）でコピーされます。
+
+    --mirror
+        --bare に加えて、全ての参照（refs/*）をそのままコピーします。
+        git fetch でリモートと完全に同じ状態に更新されます。
 
  🛠  PRACTICAL EXAMPLES
     1. 基本: リポジトリをクローン
@@ -343,6 +602,17 @@ func (c *CloneCommand) Help() string {
     4. シャロークローン（履歴を制限）
        $ git clone --depth 1 https://github.com/org/repo.git
 
+    5. オフライン: バンドルファイルからクローン
+       $ git clone repo.bundle my-project
+
+    6. シャロークローンの履歴を後から取得
+       $ git clone --depth 1 https://github.com/org/repo.git
+       $ git fetch --deepen=3     # 3コミット分さらに取得
+       $ git fetch --unshallow    # 残りの履歴を全て取得
+
+    7. サーバー用のミラーを作成
+       $ git clone --mirror https://github.com/org/repo.git
+
  🔗 REFERENCE
     Full documentation: https://git-scm.com/docs/git-clone
 `
@@ -2,12 +2,18 @@ package commands
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"testing"
+	"time"
 
+	"github.com/go-git/go-billy/v5/memfs"
 	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/go-git/go-git/v5/storage/memory"
 	"github.com/kurobon/gitgym/backend/internal/git"
+	"github.com/kurobon/gitgym/backend/internal/state"
 )
 
 func TestCloneCommand(t *testing.T) {
@@ -54,3 +60,161 @@ func TestCloneCommand(t *testing.T) {
 		}
 	})
 }
+
+func TestCloneCommand_Variants(t *testing.T) {
+	ctx := context.Background()
+	sm := git.NewSessionManager()
+	sm.DataDir = t.TempDir()
+
+	// main: c1 - c2 - c3 - c4 (one commit a day from 2024-01-01), feature branches off c2
+	remote, _ := gogit.Init(memory.NewStorage(), memfs.New())
+	w, _ := remote.Worktree()
+	var hashes []plumbing.Hash
+	for i := 1; i <= 4; i++ {
+		f, _ := w.Filesystem.Create("file.txt")
+		fmt.Fprintf(f, "v%d\n", i)
+		f.Close()
+		w.Add("file.txt")
+		when := time.Date(2024, 1, i, 12, 0, 0, 0, time.UTC)
+		sig := &object.Signature{Name: "Test", Email: "test@example.com", When: when}
+		h, err := w.Commit(fmt.Sprintf("c%d", i), &gogit.CommitOptions{Author: sig, Committer: sig})
+		if err != nil {
+			t.Fatal(err)
+		}
+		hashes = append(hashes, h)
+	}
+	remote.Storer.SetReference(plumbing.NewHashReference("refs/heads/feature", hashes[1]))
+	remote.Storer.SetReference(plumbing.NewHashReference("refs/tags/v1", hashes[0]))
+	remote.Storer.SetReference(plumbing.NewHashReference("refs/pull/1/head", hashes[1]))
+	url := "https://example.com/variants.git"
+	sm.SharedRemotes[url] = remote
+
+	clone := func(t *testing.T, args ...string) (*git.Session, string) {
+		t.Helper()
+		s, _ := sm.CreateSession(t.Name())
+		out, err := (&CloneCommand{}).Execute(ctx, s, append([]string{"clone"}, args...))
+		if err != nil {
+			t.Fatalf("clone %v failed: %v", args, err)
+		}
+		return s, out
+	}
+	hasRef := func(repo *gogit.Repository, name string) bool {
+		_, err := repo.Storer.Reference(plumbing.ReferenceName(name))
+		return err == nil
+	}
+
+	t.Run("depth cuts history and fetch deepens it", func(t *testing.T) {
+		s, out := clone(t, "--depth", "2", url)
+		repo := s.GetRepo()
+		if !strings.Contains(out, "grafted") {
+			t.Errorf("expected shallow note, got %q", out)
+		}
+		if shallow := git.ShallowCommits(repo); len(shallow) != 1 || shallow[0] != hashes[2] {
+			t.Fatalf("expected c3 to be grafted, got %v", shallow)
+		}
+		if git.HasObject(repo, hashes[1]) || hasRef(repo, "refs/remotes/origin/feature") {
+			t.Errorf("expected single-branch shallow clone")
+		}
+
+		graph := state.BuildGraphState(repo, false)
+		if len(graph.Commits) != 2 {
+			t.Fatalf("expected 2 commits in graph, got %d", len(graph.Commits))
+		}
+		for _, c := range graph.Commits {
+			if c.ID == hashes[2].String() && (!c.Grafted || c.ParentID != "") {
+				t.Errorf("expected c3 to be marked grafted without parent: %+v", c)
+			}
+		}
+
+		logOut, err := git.Dispatch(ctx, s, "log", []string{"log", "--oneline"})
+		if err != nil || strings.Count(logOut, "\n") != 2 {
+			t.Fatalf("log on shallow clone: %v\n%s", err, logOut)
+		}
+
+		if _, err := git.Dispatch(ctx, s, "fetch", []string{"fetch", "--deepen=1"}); err != nil {
+			t.Fatalf("fetch --deepen failed: %v", err)
+		}
+		if shallow := git.ShallowCommits(repo); len(shallow) != 1 || shallow[0] != hashes[1] {
+			t.Fatalf("expected c2 to be grafted after deepen, got %v", shallow)
+		}
+
+		if _, err := git.Dispatch(ctx, s, "fetch", []string{"fetch", "--unshallow"}); err != nil {
+			t.Fatalf("fetch --unshallow failed: %v", err)
+		}
+		if shallow := git.ShallowCommits(repo); len(shallow) != 0 {
+			t.Fatalf("expected complete history, still grafted: %v", shallow)
+		}
+		if _, err := git.Dispatch(ctx, s, "fetch", []string{"fetch", "--unshallow"}); err == nil {
+			t.Error("expected --unshallow on a complete repository to fail")
+		}
+	})
+
+	t.Run("shallow-since", func(t *testing.T) {
+		s, _ := clone(t, "--shallow-since=2024-01-03", url)
+		if shallow := git.ShallowCommits(s.GetRepo()); len(shallow) != 1 || shallow[0] != hashes[2] {
+			t.Errorf("expected c3 to be grafted, got %v", shallow)
+		}
+
+		// The feature tip predates the cut: it is kept, as a grafted commit
+		s, _ = clone(t, "--shallow-since=2024-01-03", "--no-single-branch", url, "all-branches")
+		repo := s.GetRepo()
+		if !hasRef(repo, "refs/remotes/origin/feature") || hasRef(repo, "refs/tags/v1") {
+			t.Error("expected --no-single-branch to keep feature, without the tag on c1")
+		}
+		if shallow := git.ShallowCommits(repo); len(shallow) != 1 || shallow[0] != hashes[1] {
+			t.Errorf("expected c2 to be grafted, got %v", shallow)
+		}
+	})
+
+	t.Run("single-branch, origin name and no-checkout", func(t *testing.T) {
+		s, _ := clone(t, "--single-branch", "-b", "feature", "-o", "upstream", "-n", url, "feat")
+		repo := s.GetRepo()
+		if !hasRef(repo, "refs/remotes/upstream/feature") || hasRef(repo, "refs/remotes/upstream/master") {
+			t.Error("expected only upstream/feature")
+		}
+		if !hasRef(repo, "refs/tags/v1") {
+			t.Error("expected tag pointing into the cloned history")
+		}
+		head, _ := repo.Head()
+		if head.Name() != "refs/heads/feature" || head.Hash() != hashes[1] {
+			t.Errorf("unexpected HEAD %s %s", head.Name(), head.Hash())
+		}
+		if remoteName, _, ok := git.BranchUpstream(repo, "feature"); !ok || remoteName != "upstream" {
+			t.Errorf("expected feature to track upstream, got %q", remoteName)
+		}
+		if _, err := s.Filesystem.Stat("feat/file.txt"); err == nil {
+			t.Error("expected --no-checkout to leave the working tree empty")
+		}
+		cfg, _ := repo.Config()
+		if fetch := cfg.Remotes["upstream"].Fetch; len(fetch) != 1 || fetch[0] != "+refs/heads/feature:refs/remotes/upstream/feature" {
+			t.Errorf("unexpected fetch refspec %v", fetch)
+		}
+	})
+
+	t.Run("bare and mirror", func(t *testing.T) {
+		s, out := clone(t, "--bare", url)
+		if !strings.Contains(out, "bare repository 'variants.git'") {
+			t.Errorf("unexpected output %q", out)
+		}
+		repo := s.Repos["variants.git"]
+		if repo == nil {
+			t.Fatal("bare clone not registered as variants.git")
+		}
+		if _, err := repo.Worktree(); err == nil {
+			t.Error("expected a bare repository")
+		}
+		if !hasRef(repo, "refs/heads/feature") || hasRef(repo, "refs/pull/1/head") {
+			t.Error("expected branches under their own names and no other refs")
+		}
+
+		s, _ = clone(t, "--mirror", url, "mirror")
+		repo = s.Repos["mirror"]
+		if !hasRef(repo, "refs/pull/1/head") {
+			t.Error("expected --mirror to copy every ref")
+		}
+		cfg, _ := repo.Config()
+		if fetch := cfg.Remotes["origin"].Fetch; len(fetch) != 1 || fetch[0] != "+refs/*:refs/*" {
+			t.Errorf("unexpected mirror refspec %v", fetch)
+		}
+	})
+}
@@ -3,15 +3,21 @@ package commands
 // commit.go - Simulated Git Commit Command
 //
 // Records changes to the repository by creating a new commit object.
-// Supports -m (message), --amend, and --allow-empty flags.
+// Supports messages from -m/-F/-C, --amend, -a, pathspecs (--only/--include),
+// --author/--date and the --fixup/--squash commits that rebase --autosquash
+// folds into their targets.
 
 import (
 	"context"
 	"fmt"
+	"io"
 	"strings"
 
 	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/index"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
 	"github.com/kurobon/gitgym/backend/internal/git"
 )
 
@@ -25,9 +31,19 @@ type CommitCommand struct{}
 var _ git.Command = (*CommitCommand)(nil)
 
 type CommitOptions struct {
-	Message    string
+	Messages   []string // One paragraph per -m
+	File       string   // -F: read the message from a file
+	ReuseFrom  string   // -C/-c: reuse the message and authorship of a commit
+	Fixup      string   // --fixup=[amend:|reword:]<commit>
+	Squash     string   // --squash=<commit>
+	Author     string
+	Date       string
+	All        bool
 	Amend      bool
 	AllowEmpty bool
+	Include    bool // --include: stage the pathspecs on top of the index
+	Only       bool // --only: commit just the pathspecs (default with pathspecs)
+	Pathspecs  []string
 }
 
 type commitContext struct {
@@ -35,6 +51,9 @@ type commitContext struct {
 	repo        *gogit.Repository
 	message     string
 	amendCommit *object.Commit
+	author      *object.Signature
+	committer   *object.Signature
+	headOnly    bool // Commit HEAD's tree, ignoring the index (--fixup=reword:)
 }
 
 func (c *CommitCommand) Execute(ctx context.Context, s *git.Session, args []string) (string, error) {
@@ -56,7 +75,7 @@ func (c *CommitCommand) Execute(ctx context.Context, s *git.Session, args []stri
 	}
 
 	// 2. Resolve
-	cCtx, err := c.resolveContext(repo, opts, args)
+	cCtx, err := c.resolveContext(s, repo, opts)
 	if err != nil {
 		return "", err
 	}
@@ -66,46 +85,127 @@ func (c *CommitCommand) Execute(ctx context.Context, s *git.Session, args []stri
 }
 
 func (c *CommitCommand) parseArgs(args []string) (*CommitOptions, error) {
-	opts := &CommitOptions{
-		// Message: "", // Default is empty
+	opts := &CommitOptions{}
+	// value returns the argument of an option given as "--opt=value" or "--opt value"
+	value := func(i *int, arg, name string) (string, error) {
+		if v, ok := strings.CutPrefix(arg, name+"="); ok && strings.HasPrefix(name, "--") {
+			return v, nil
+		}
+		if *i+1 >= len(args) {
+			return "", fmt.Errorf("error: option `%s' requires a value", strings.TrimLeft(name, "-"))
+		}
+		*i++
+		return args[*i], nil
 	}
 
 	for i := 1; i < len(args); i++ {
 		arg := args[i]
-		switch arg {
+		name, _, _ := strings.Cut(arg, "=")
+		var err error
+		switch name {
 		case "-h", "--help":
 			return nil, fmt.Errorf("help requested")
-		case "-m":
-			if i+1 < len(args) {
-				opts.Message = args[i+1]
-				i++
-			}
+		case "-m", "--message":
+			var msg string
+			msg, err = value(&i, arg, name)
+			opts.Messages = append(opts.Messages, msg)
+		case "-am":
+			opts.All = true
+			var msg string
+			msg, err = value(&i, arg, "-m")
+			opts.Messages = append(opts.Messages, msg)
+		case "-F", "--file":
+			opts.File, err = value(&i, arg, name)
+		case "-C", "--reuse-message", "-c", "--reedit-message":
+			opts.ReuseFrom, err = value(&i, arg, name)
+		case "--fixup":
+			opts.Fixup, err = value(&i, arg, name)
+		case "--squash":
+			opts.Squash, err = value(&i, arg, name)
+		case "--author":
+			opts.Author, err = value(&i, arg, name)
+		case "--date":
+			opts.Date, err = value(&i, arg, name)
+		case "-a", "--all":
+			opts.All = true
 		case "--amend":
 			opts.Amend = true
 		case "--allow-empty":
 			opts.AllowEmpty = true
+		case "-i", "--include":
+			opts.Include = true
+		case "-o", "--only":
+			opts.Only = true
 		case "--no-edit":
 			// Shim: In GitGym, amending without -m automatically behaves like --no-edit
 			// We just accept the flag to avoid error.
+		case "--":
+			opts.Pathspecs = append(opts.Pathspecs, args[i+1:]...)
+			i = len(args)
 		default:
-			// Reject positional arguments or unknown flags
-			// Standard git treats positional args as file paths, but we don't fully support that yet.
-			// Even if we did, "git commit --amend <text>" is usually an error (text interpreted as path).
-			return nil, fmt.Errorf("unknown argument or option: '%s'. Did you mean to use -m for message?", arg)
+			switch {
+			case strings.HasPrefix(arg, "-m") && len(arg) > 2:
+				opts.Messages = append(opts.Messages, arg[2:])
+			case strings.HasPrefix(arg, "-"):
+				return nil, fmt.Errorf("error: unknown option `%s'", strings.TrimLeft(arg, "-"))
+			default:
+				opts.Pathspecs = append(opts.Pathspecs, arg)
+			}
+		}
+		if err != nil {
+			return nil, err
 		}
 	}
+
+	// Conflicting sources of the message, as git reports them
+	sources := 0
+	for _, set := range []bool{opts.File != "", opts.ReuseFrom != "", opts.Fixup != ""} {
+		if set {
+			sources++
+		}
+	}
+	switch {
+	case sources > 1:
+		return nil, fmt.Errorf("fatal: only one of -c/-C/-F/--fixup can be used")
+	case len(opts.Messages) > 0 && (opts.File != "" || opts.ReuseFrom != ""):
+		return nil, fmt.Errorf("fatal: option -m cannot be combined with -c/-C/-F")
+	case opts.Fixup != "" && opts.Squash != "":
+		return nil, fmt.Errorf("fatal: options '--squash' and '--fixup' cannot be used together")
+	case opts.Include && opts.Only:
+		return nil, fmt.Errorf("fatal: options '--include' and '--only' cannot be used together")
+	case (opts.Include || opts.Only) && len(opts.Pathspecs) == 0 && !opts.Amend:
+		return nil, fmt.Errorf("fatal: no paths with --include/--only does not make sense")
+	case opts.All && len(opts.Pathspecs) > 0:
+		return nil, fmt.Errorf("fatal: paths '%s ...' with -a does not make sense", opts.Pathspecs[0])
+	case opts.All && (opts.Include || opts.Only):
+		return nil, fmt.Errorf("fatal: options '-a' and '--include/--only' cannot be used together")
+	}
+	if len(opts.Pathspecs) > 0 && !opts.Include {
+		opts.Only = true
+	}
 	return opts, nil
 }
 
-func (c *CommitCommand) resolveContext(repo *gogit.Repository, opts *CommitOptions, originalArgs []string) (*commitContext, error) {
+func (c *CommitCommand) resolveContext(s *git.Session, repo *gogit.Repository, opts *CommitOptions) (*commitContext, error) {
 	w, err := repo.Worktree()
 	if err != nil {
 		return nil, err
 	}
 
+	// Pathspecs must name files git knows about
+	for _, path := range opts.Pathspecs {
+		if !knownPath(repo, w, path) {
+			return nil, fmt.Errorf("error: pathspec '%s' did not match any file(s) known to git\nhint: Did you mean to use -m for message?", path)
+		}
+	}
+
+	committer := s.Signature()
+	author := *committer
 	ctx := &commitContext{
-		w:    w,
-		repo: repo,
+		w:         w,
+		repo:      repo,
+		author:    &author,
+		committer: committer,
 	}
 
 	if opts.Amend {
@@ -118,36 +218,179 @@ func (c *CommitCommand) resolveContext(repo *gogit.Repository, opts *CommitOptio
 			return nil, err
 		}
 		ctx.amendCommit = headCommit
+	}
 
-		// Handle message reuse for amend
-		isMsgProvided := false
-		for _, arg := range originalArgs {
-			if arg == "-m" {
-				isMsgProvided = true
-				break
-			}
+	// Message given on the command line (-m paragraphs, -F file, -C commit)
+	message := strings.Join(opts.Messages, "\n\n")
+	switch {
+	case opts.File != "":
+		content, err := c.readMessageFile(s, opts.File)
+		if err != nil {
+			return nil, err
+		}
+		message = content
+	case opts.ReuseFrom != "":
+		reused, err := c.resolveCommit(repo, opts.ReuseFrom)
+		if err != nil {
+			return nil, err
 		}
+		message = reused.Message
+		author := reused.Author
+		ctx.author = &author
+	}
 
-		if isMsgProvided {
-			ctx.message = opts.Message
-		} else {
-			ctx.message = headCommit.Message
+	switch {
+	case opts.Fixup != "":
+		ctx.message, ctx.headOnly, err = c.fixupMessage(repo, opts.Fixup, message)
+		if err != nil {
+			return nil, err
+		}
+	case opts.Squash != "":
+		target, err := c.resolveCommit(repo, opts.Squash)
+		if err != nil {
+			return nil, err
+		}
+		ctx.message = "squash! " + commitSubject(target)
+		if message != "" {
+			ctx.message += "\n\n" + message
 		}
-	} else {
+	case message != "":
+		ctx.message = message
+	case opts.Amend:
+		ctx.message = ctx.amendCommit.Message
+	default:
 		// Normal Commit: Message is REQUIRED
-		if opts.Message == "" {
-			return nil, fmt.Errorf("message is required. Use -m \"message\"")
+		return nil, fmt.Errorf("message is required. Use -m \"message\"")
+	}
+
+	if opts.Author != "" {
+		author, err := c.resolveAuthor(repo, opts.Author)
+		if err != nil {
+			return nil, err
+		}
+		author.When = ctx.author.When
+		ctx.author = author
+	}
+	if opts.Date != "" {
+		when, err := parseSimulatedDate(opts.Date)
+		if err != nil {
+			return nil, fmt.Errorf("fatal: %v", err)
 		}
-		ctx.message = opts.Message
+		ctx.author.When = when
 	}
 
 	return ctx, nil
 }
 
+// readMessageFile reads -F <file> from the session filesystem ("-" is not
+// supported: there is no stdin). Comment lines and trailing blank lines are
+// dropped like git's default cleanup.
+func (c *CommitCommand) readMessageFile(s *git.Session, name string) (string, error) {
+	f, err := s.Filesystem.Open(sessionFilePath(s, name))
+	if err != nil {
+		return "", fmt.Errorf("fatal: could not read log file '%s': No such file or directory", name)
+	}
+	defer f.Close()
+	content, err := io.ReadAll(f)
+	if err != nil {
+		return "", err
+	}
+	var lines []string
+	for _, line := range strings.Split(string(content), "\n") {
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, strings.TrimRight(line, " \t"))
+		}
+	}
+	message := strings.Trim(strings.Join(lines, "\n"), "\n")
+	if message == "" {
+		return "", fmt.Errorf("Aborting commit due to empty commit message.")
+	}
+	return message, nil
+}
+
+// fixupMessage builds the message of a --fixup commit. "fixup! <subject>"
+// folds the changes into the target; "amend! <subject>" (amend: and reword:)
+// also replaces its message, by default with the target's own.
+func (c *CommitCommand) fixupMessage(repo *gogit.Repository, spec, message string) (string, bool, error) {
+	kind, rev, found := strings.Cut(spec, ":")
+	if !found || (kind != "amend" && kind != "reword") {
+		kind, rev = "", spec
+	}
+	target, err := c.resolveCommit(repo, rev)
+	if err != nil {
+		return "", false, err
+	}
+	if kind == "" {
+		fixup := "fixup! " + commitSubject(target)
+		if message != "" {
+			fixup += "\n\n" + message
+		}
+		return fixup, false, nil
+	}
+	if message == "" {
+		message = strings.TrimRight(target.Message, "\n")
+	}
+	return "amend! " + commitSubject(target) + "\n\n" + message, kind == "reword", nil
+}
+
+func (c *CommitCommand) resolveCommit(repo *gogit.Repository, rev string) (*object.Commit, error) {
+	hash, err := git.ResolveRevision(repo, rev)
+	if err != nil {
+		return nil, fmt.Errorf("fatal: could not lookup commit '%s'", rev)
+	}
+	return repo.CommitObject(*hash)
+}
+
+// resolveAuthor parses --author: "Name <email>", or a pattern matched against
+// the authors of existing commits like git does.
+func (c *CommitCommand) resolveAuthor(repo *gogit.Repository, spec string) (*object.Signature, error) {
+	if name, rest, ok := strings.Cut(spec, "<"); ok && strings.HasSuffix(rest, ">") && strings.TrimSpace(name) != "" {
+		return &object.Signature{Name: strings.TrimSpace(name), Email: strings.TrimSuffix(rest, ">")}, nil
+	}
+
+	var match *object.Signature
+	if iter, err := repo.Log(&gogit.LogOptions{All: true}); err == nil {
+		_ = iter.ForEach(func(commit *object.Commit) error {
+			if strings.Contains(fmt.Sprintf("%s <%s>", commit.Author.Name, commit.Author.Email), spec) {
+				match = &object.Signature{Name: commit.Author.Name, Email: commit.Author.Email}
+				return storer.ErrStop
+			}
+			return nil
+		})
+	}
+	if match == nil {
+		return nil, fmt.Errorf("fatal: --author '%s' is not 'Name <email>' and matches no existing author", spec)
+	}
+	return match, nil
+}
+
+// knownPath reports whether path is in the worktree or the index.
+func knownPath(repo *gogit.Repository, w *gogit.Worktree, path string) bool {
+	if path == "." {
+		return true
+	}
+	if _, err := w.Filesystem.Lstat(path); err == nil {
+		return true
+	}
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return false
+	}
+	prefix := strings.TrimSuffix(path, "/") + "/"
+	for _, e := range idx.Entries {
+		if e.Name == path || strings.HasPrefix(e.Name, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 func (c *CommitCommand) performAction(s *git.Session, ctx *commitContext, opts *CommitOptions) (string, error) {
 	var commitOpts gogit.CommitOptions
-	commitOpts.Author = git.GetDefaultSignature()
-	commitOpts.AllowEmptyCommits = opts.AllowEmpty
+	commitOpts.Author = ctx.author
+	commitOpts.Committer = ctx.committer
+	commitOpts.AllowEmptyCommits = opts.AllowEmpty || ctx.headOnly
+	commitOpts.All = opts.All
 
 	actionLabel := "commit"
 
@@ -158,7 +401,32 @@ func (c *CommitCommand) performAction(s *git.Session, ctx *commitContext, opts *
 		actionLabel = "commit (amend)"
 	}
 
-	commitHash, err := ctx.w.Commit(ctx.message, &commitOpts)
+	var commitHash plumbing.Hash
+	var err error
+	switch {
+	case ctx.headOnly:
+		commitHash, err = c.commitOnly(ctx, nil, &commitOpts)
+	case opts.Only:
+		commitHash, err = c.commitOnly(ctx, opts.Pathspecs, &commitOpts)
+	default:
+		for _, path := range opts.Pathspecs { // --include
+			if _, err := ctx.w.Add(path); err != nil {
+				return "", err
+			}
+		}
+		if len(opts.Pathspecs) > 0 {
+			err = clearIntentToAdd(ctx.repo, opts.Pathspecs)
+		} else if opts.All {
+			err = clearIntentToAdd(ctx.repo, nil) // -a commits intent-to-add files too
+		}
+		if err == nil {
+			err = withoutIntentToAdd(ctx.repo, func() error {
+				var commitErr error
+				commitHash, commitErr = ctx.w.Commit(ctx.message, &commitOpts)
+				return commitErr
+			})
+		}
+	}
 	if err != nil {
 		if strings.Contains(err.Error(), "clean") || strings.Contains(err.Error(), "nothing to commit") {
 			return "", fmt.Errorf("%v\nhint: Use 'git commit --allow-empty -m <message>' to create an empty commit", err)
@@ -168,10 +436,84 @@ func (c *CommitCommand) performAction(s *git.Session, ctx *commitContext, opts *
 
 	s.RecordReflog(fmt.Sprintf("%s: %s", actionLabel, strings.Split(ctx.message, "\n")[0]))
 
+	summary := fmt.Sprintf("Commit created: %s", commitHash.String())
 	if opts.Amend {
-		return fmt.Sprintf("Commit amended: %s", commitHash.String()), nil
+		summary = fmt.Sprintf("Commit amended: %s", commitHash.String())
+	}
+	return joinOutput(summary, renameSummary(ctx.repo, commitHash)), nil
+}
+
+// renameSummary lists the renames a commit recorded the way git's commit
+// summary does: " rename old.txt => new.txt (100%)".
+func renameSummary(repo *gogit.Repository, hash plumbing.Hash) string {
+	commit, err := repo.CommitObject(hash)
+	if err != nil {
+		return ""
+	}
+	changes, err := commitChanges(commit)
+	if err != nil {
+		return ""
+	}
+	var lines []string
+	for _, change := range changes {
+		if change.From != "" {
+			lines = append(lines, fmt.Sprintf(" rename %s => %s (%s%%)", change.From, change.To, strings.TrimLeft(change.Status[1:], "0")))
+		}
 	}
-	return fmt.Sprintf("Commit created: %s", commitHash.String()), nil
+	return strings.Join(lines, "\n")
+}
+
+// commitOnly commits HEAD's tree plus the worktree state of paths (--only),
+// then restores the index so changes staged for other files stay staged.
+func (c *CommitCommand) commitOnly(ctx *commitContext, paths []string, commitOpts *gogit.CommitOptions) (plumbing.Hash, error) {
+	current, err := ctx.repo.Storer.Index()
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	// Storers may hand out the live index: keep a copy of the entries
+	saved := *current
+	saved.Entries = make([]*index.Entry, len(current.Entries))
+	for i, e := range current.Entries {
+		entry := *e
+		saved.Entries[i] = &entry
+	}
+	restore := func() error {
+		if err := ctx.repo.Storer.SetIndex(&saved); err != nil {
+			return err
+		}
+		for _, path := range paths {
+			if _, err := ctx.w.Add(path); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	// Index = HEAD + paths
+	if head, err := ctx.repo.Head(); err == nil {
+		if err := ctx.w.Reset(&gogit.ResetOptions{Commit: head.Hash(), Mode: gogit.MixedReset}); err != nil {
+			return plumbing.ZeroHash, err
+		}
+	} else if err := ctx.repo.Storer.SetIndex(&index.Index{Version: 2}); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	for _, path := range paths {
+		if _, err := ctx.w.Add(path); err != nil {
+			_ = restore()
+			return plumbing.ZeroHash, err
+		}
+	}
+
+	hash, err := ctx.w.Commit(ctx.message, commitOpts)
+	if restoreErr := restore(); err == nil {
+		err = restoreErr
+	}
+	return hash, err
+}
+
+// commitSubject returns the first line of a commit message.
+func commitSubject(commit *object.Commit) string {
+	return strings.SplitN(strings.TrimSpace(commit.Message), "\n", 2)[0]
 }
 
 func (c *CommitCommand) Help() string {
@@ -182,11 +524,25 @@ func (c *CommitCommand) Help() string {
     ・変更内容にメッセージを付けて保存する
 
  📋 SYNOPSIS
-    git commit -m <msg> [--amend] [--allow-empty]
+    git commit [-a] [-m <msg>]... [-F <file>] [-C <commit>] [--amend] [--allow-empty]
+               [--author=<author>] [--date=<date>] [--] [<pathspec>...]
+    git commit --fixup=[amend:|reword:]<commit>
+    git commit --squash=<commit>
 
  ⚙️  COMMON OPTIONS
     -m <msg>
         コミットメッセージを指定します。
+        複数指定すると、それぞれが段落（空行区切り）になります。
+
+    -a, --all
+        変更・削除された追跡済みファイルを自動でステージしてからコミットします。
+        （新規ファイルは含まれません。git add が必要です）
+
+    -F <file>
+        ファイルからコミットメッセージを読み込みます。
+
+    -C <commit>, -c <commit>
+        指定したコミットのメッセージと作者情報を再利用します。
 
     --amend
         直前のコミットを修正します（メッセージの変更や、ファイルの追加忘れ等）。
@@ -195,6 +551,25 @@ func (c *CommitCommand) Help() string {
     --allow-empty
         変更が含まれていなくてもコミットを作成できるようにします。
 
+    --author="Name <email>"
+        作者を指定します。既存のコミットの作者名の一部を指定することもできます。
+
+    --date=<date>
+        作者の日時を指定します（例: 2024-01-01 12:00, @1700000000）。
+
+    --fixup=<commit>
+        指定したコミットを「後で修正する」ためのコミット（fixup! ...）を作ります。
+        git rebase -i --autosquash で対象のコミットに自動で統合されます。
+        amend:<commit> は変更に加えてメッセージも置き換え、
+        reword:<commit> はメッセージだけを置き換えます（amend! ...）。
+
+    --squash=<commit>
+        --fixup と同様ですが、統合時にメッセージも連結します（squash! ...）。
+
+    <pathspec>... (--only)
+        指定したファイルだけをコミットします。他のステージ済みの変更はステージされたまま残ります。
+        -i (--include) を付けると、指定したファイルをステージに追加してからまとめてコミットします。
+
  🛠  PRACTICAL EXAMPLES
     1. 基本: メッセージ付きでコミット
        1コミットにつき1つの論点（変更理由）になるよう意識するのがコツです。
@@ -213,6 +588,16 @@ func (c *CommitCommand) Help() string {
        (メッセージはそのままで良い場合)
        $ git commit --amend --no-edit
 
+    4. 実践: 古いコミットを後から修正する (Recommended)
+       「3つ前のコミットにバグがあった...」という時は、修正をfixupコミットにして
+       rebase でまとめます。履歴をきれいに保つ定番のワークフローです。
+       $ git add bugfix.go
+       $ git commit --fixup=HEAD~2
+       $ git rebase -i --autosquash HEAD~4
+
+    5. 変更されたファイルをまとめてコミット
+       $ git commit -am "fix: update config"
+
  🔗 REFERENCE
     Full documentation: https://git-scm.com/docs/git-commit
 `
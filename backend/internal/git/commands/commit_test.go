@@ -4,8 +4,12 @@ import (
 	"context"
 	"strings"
 	"testing"
+	"time"
 
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/kurobon/gitgym/backend/internal/git"
+	"github.com/kurobon/gitgym/backend/internal/state"
 )
 
 func TestCommitCommand(t *testing.T) {
@@ -90,3 +94,171 @@ func TestCommitCommand(t *testing.T) {
 		}
 	})
 }
+
+func TestCommitCommand_Extensions(t *testing.T) {
+	ctx := context.Background()
+	sm := git.NewSessionManager()
+	s, _ := sm.CreateSession("test-commit-ext")
+	s.InitRepo("testrepo")
+	s.CurrentDir = "/testrepo"
+	repo := s.GetRepo()
+	w, _ := repo.Worktree()
+
+	write := func(name, content string) {
+		f, _ := w.Filesystem.Create(name)
+		f.Write([]byte(content))
+		f.Close()
+	}
+	commit := func(args ...string) *object.Commit {
+		t.Helper()
+		if _, err := git.Dispatch(ctx, s, "commit", append([]string{"commit"}, args...)); err != nil {
+			t.Fatalf("commit %v failed: %v", args, err)
+		}
+		head, _ := repo.Head()
+		c, _ := repo.CommitObject(head.Hash())
+		return c
+	}
+	headFile := func(c *object.Commit, name string) string {
+		f, err := c.File(name)
+		if err != nil {
+			return ""
+		}
+		content, _ := f.Contents()
+		return content
+	}
+
+	write("a.txt", "a1")
+	write("b.txt", "b1")
+	w.Add("a.txt")
+	w.Add("b.txt")
+	first := commit("-m", "subject", "-m", "body paragraph", "--author=Alice <alice@example.com>", "--date=2024-01-02 03:04:05")
+	if first.Message != "subject\n\nbody paragraph" {
+		t.Errorf("unexpected message %q", first.Message)
+	}
+	if first.Author.Name != "Alice" || first.Author.When.Year() != 2024 || first.Committer.Name == "Alice" {
+		t.Errorf("unexpected author %v / committer %v", first.Author, first.Committer)
+	}
+
+	t.Run("-a stages tracked changes", func(t *testing.T) {
+		write("a.txt", "a2")
+		c := commit("-am", "update a")
+		if headFile(c, "a.txt") != "a2" {
+			t.Error("expected -a to commit the modified file")
+		}
+	})
+
+	t.Run("-F, -C and --author pattern", func(t *testing.T) {
+		write("msg.txt", "from file\n# comment\n\nmore\n")
+		write("a.txt", "a3")
+		if c := commit("-a", "-F", "msg.txt"); c.Message != "from file\n\nmore" {
+			t.Errorf("unexpected -F message %q", c.Message)
+		}
+		write("a.txt", "a4")
+		if c := commit("-a", "-C", first.Hash.String()); c.Message != first.Message || c.Author.Email != "alice@example.com" {
+			t.Errorf("expected -C to reuse message and author, got %q by %v", c.Message, c.Author)
+		}
+		write("a.txt", "a5")
+		if c := commit("-a", "-m", "by pattern", "--author=alice@"); c.Author.Name != "Alice" {
+			t.Errorf("expected --author to match Alice, got %v", c.Author)
+		}
+		if _, err := git.Dispatch(ctx, s, "commit", []string{"commit", "-m", "x", "--author=nobody"}); err == nil {
+			t.Error("expected unknown --author pattern to fail")
+		}
+	})
+
+	t.Run("--only and --include", func(t *testing.T) {
+		write("a.txt", "a6")
+		write("b.txt", "b2")
+		w.Add("b.txt")
+		c := commit("-m", "only a", "a.txt")
+		if headFile(c, "a.txt") != "a6" || headFile(c, "b.txt") != "b1" {
+			t.Error("expected only a.txt in the commit")
+		}
+		status, _ := w.Status()
+		if status.File("b.txt").Staging != gogit.Modified {
+			t.Error("expected b.txt to stay staged")
+		}
+
+		write("a.txt", "a7")
+		c = commit("-m", "include a", "--include", "a.txt")
+		if headFile(c, "a.txt") != "a7" || headFile(c, "b.txt") != "b2" {
+			t.Error("expected --include to commit the index and a.txt")
+		}
+
+		if _, err := git.Dispatch(ctx, s, "commit", []string{"commit", "message without -m"}); err == nil || !strings.Contains(err.Error(), "did not match any file") {
+			t.Errorf("expected unknown pathspec error, got %v", err)
+		}
+	})
+
+	t.Run("fixup and squash messages", func(t *testing.T) {
+		write("a.txt", "a8")
+		if c := commit("-a", "--fixup", first.Hash.String()); c.Message != "fixup! subject" {
+			t.Errorf("unexpected fixup message %q", c.Message)
+		}
+		write("a.txt", "a9")
+		if c := commit("-a", "--squash=HEAD", "-m", "more"); c.Message != "squash! fixup! subject\n\nmore" {
+			t.Errorf("unexpected squash message %q", c.Message)
+		}
+		write("a.txt", "a10")
+		c := commit("--fixup=reword:"+first.Hash.String(), "-m", "new subject")
+		if c.Message != "amend! subject\n\nnew subject" {
+			t.Errorf("unexpected reword message %q", c.Message)
+		}
+		parent, _ := c.Parent(0)
+		if c.TreeHash != parent.TreeHash {
+			t.Error("expected reword commit to be empty")
+		}
+	})
+}
+
+func TestCommitCommand_Deterministic(t *testing.T) {
+	script := []string{
+		"git init",
+		"echo 'one' > a.txt",
+		"git add a.txt",
+		"git commit -m first",
+		"git checkout -b feature",
+		"echo 'two' > b.txt",
+		"git add b.txt",
+		"git commit -m second",
+		"git tag -a v1 -m release",
+		"git checkout main",
+		"echo 'three' > c.txt",
+		"git add c.txt",
+		"git commit -m third",
+		"git merge feature",
+	}
+
+	run := func(id string) (string, time.Time) {
+		sm := git.NewSessionManager()
+		s, _ := sm.CreateSession(id)
+		s.SetDeterministic(state.NewVirtualClock(time.Time{}, 0))
+		_ = s.Filesystem.MkdirAll("/project", 0755)
+		s.CurrentDir = "/project"
+		for _, line := range script {
+			name, args := git.ParseCommand(line)
+			if _, err := git.Dispatch(context.Background(), s, name, args); err != nil {
+				t.Fatalf("%s: %v", line, err)
+			}
+		}
+		head, err := s.GetRepo().Head()
+		if err != nil {
+			t.Fatal(err)
+		}
+		commit, err := s.GetRepo().CommitObject(head.Hash())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return head.Hash().String(), commit.Committer.When
+	}
+
+	first, when := run("deterministic-1")
+	second, _ := run("deterministic-2")
+	if first != second {
+		t.Errorf("expected identical hashes, got %s and %s", first, second)
+	}
+	// first, second, tag, third, merge: the merge is the fifth reading
+	if want := state.DefaultClockStart.Add(4 * state.DefaultClockStep); !when.Equal(want) {
+		t.Errorf("expected merge at %v, got %v", want, when)
+	}
+}
@@ -1,10 +1,14 @@
 package commands
 
 import (
+	"bytes"
 	"context"
 	"fmt"
 	"strings"
 
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	format "github.com/go-git/go-git/v5/plumbing/format/config"
 	"github.com/kurobon/gitgym/backend/internal/git"
 )
 
@@ -18,43 +22,122 @@ var _ git.Command = (*ConfigCommand)(nil)
 
 func (c *ConfigCommand) Execute(ctx context.Context, s *git.Session, args []string) (string, error) {
 	// args[0] is "config"
-	if len(args) < 3 {
-		return "", fmt.Errorf("usage: git config <key> <value>")
+	var mode string // "", "get" or "unset"
+	var rest []string
+	for _, arg := range args[1:] {
+		switch arg {
+		case "--global", "--local", "--system":
+			// A session has a single config, whatever the scope
+		case "--get":
+			mode = "get"
+		case "--unset":
+			mode = "unset"
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	if len(rest) == 0 || (mode != "" && len(rest) != 1) {
+		return "", fmt.Errorf("usage: git config [--global] [--get | --unset] <key> [<value>]")
 	}
 
-	key := args[1]
-	value := strings.Join(args[2:], " ")
+	key := rest[0]
+	section, subsection, name, err := splitConfigKey(key)
+	if err != nil {
+		return "", err
+	}
 
 	repo := s.GetRepo()
 	if repo == nil {
 		return "", fmt.Errorf("fatal: not a git repository")
 	}
 
-	// For now, we just support setting user.name and user.email in the local config
 	cfg, err := repo.Config()
 	if err != nil {
 		return "", err
 	}
+	// Typed fields set directly (e.g. upstreams) are not in Raw yet
+	if _, err := cfg.Marshal(); err != nil {
+		return "", err
+	}
+	// Look the key up without creating its section
+	var options format.Options
+	if cfg.Raw.HasSection(section) {
+		sec := cfg.Raw.Section(section)
+		switch {
+		case subsection == "":
+			options = sec.Options
+		case sec.HasSubsection(subsection):
+			options = sec.Subsection(subsection).Options
+		}
+	}
+	if !options.Has(name) && (mode != "" || len(rest) == 1) {
+		// Like git, which exits with a non-zero status
+		return "", fmt.Errorf("error: key '%s' is not set", key)
+	}
 
-	switch key {
-	case "user.name":
-		cfg.User.Name = strings.Trim(value, "'\"")
-	case "user.email":
-		cfg.User.Email = strings.Trim(value, "'\"")
-	default:
-		// Ignore other configs or store in raw config?
-		// go-git Config struct has specific fields.
-		// For generic sections/subsections, it's more complex.
-		// For Mission setup, we only care about identity.
+	switch {
+	case mode == "unset":
+		return "", updateRawConfig(repo, func(raw *format.Config) {
+			if subsection != "" {
+				raw.Section(section).Subsection(subsection).RemoveOption(name)
+			} else {
+				raw.Section(section).RemoveOption(name)
+			}
+		})
+	case len(rest) == 1:
+		// git config [--get] <key>: print the value
+		return options.Get(name), nil
 	}
 
-	if err := repo.Storer.SetConfig(cfg); err != nil {
-		return "", err
+	value := strings.Trim(strings.Join(rest[1:], " "), "'\"")
+	err = updateRawConfig(repo, func(raw *format.Config) {
+		if subsection != "" {
+			raw.Section(section).Subsection(subsection).SetOption(name, value)
+		} else {
+			raw.Section(section).SetOption(name, value)
+		}
+	})
+	return "", err
+}
+
+// updateRawConfig applies fn to the raw config of repo, then re-reads it so
+// the typed fields (user, remotes, branches) pick up the change.
+func updateRawConfig(repo *gogit.Repository, fn func(raw *format.Config)) error {
+	cfg, err := repo.Config()
+	if err != nil {
+		return err
+	}
+	// Typed fields set directly (e.g. upstreams) are not in Raw yet
+	if _, err := cfg.Marshal(); err != nil {
+		return err
+	}
+	fn(cfg.Raw)
+
+	var buf bytes.Buffer
+	if err := format.NewEncoder(&buf).Encode(cfg.Raw); err != nil {
+		return err
+	}
+	updated := config.NewConfig()
+	if err := updated.Unmarshal(buf.Bytes()); err != nil {
+		return err
 	}
+	return repo.Storer.SetConfig(updated)
+}
 
-	return "", nil
+// splitConfigKey splits "section.key" or "section.subsection.key".
+func splitConfigKey(key string) (section, subsection, name string, err error) {
+	first := strings.Index(key, ".")
+	last := strings.LastIndex(key, ".")
+	if first <= 0 || last == len(key)-1 {
+		return "", "", "", fmt.Errorf("error: key does not contain a section: %s", key)
+	}
+	section, name = key[:first], key[last+1:]
+	if first != last {
+		subsection = key[first+1 : last]
+	}
+	return section, subsection, name, nil
 }
 
 func (c *ConfigCommand) Help() string {
-	return "usage: git config <key> <value>"
+	return "usage: git config [--global] [--get | --unset] <key> [<value>]"
 }
@@ -0,0 +1,46 @@
+package commands
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kurobon/gitgym/backend/internal/git"
+)
+
+func TestConfigCommand(t *testing.T) {
+	sm := git.NewSessionManager()
+	s, _ := sm.CreateSession("test-config")
+	s.InitRepo("testrepo")
+	s.CurrentDir = "/testrepo"
+	ctx := context.Background()
+	config := func(args ...string) (string, error) {
+		return git.Dispatch(ctx, s, "config", append([]string{"config"}, args...))
+	}
+
+	// Scopes are accepted: a session has a single config
+	if _, err := config("--global", "pull.rebase", "false"); err != nil {
+		t.Fatalf("config --global failed: %v", err)
+	}
+	if out, err := config("--get", "pull.rebase"); err != nil || out != "false" {
+		t.Errorf("config --get = %q, %v", out, err)
+	}
+	if _, err := config("--local", "branch.main.remote", "origin"); err != nil {
+		t.Fatalf("config with a subsection failed: %v", err)
+	}
+	if out, _ := config("branch.main.remote"); out != "origin" {
+		t.Errorf("config branch.main.remote = %q", out)
+	}
+
+	if _, err := config("--unset", "pull.rebase"); err != nil {
+		t.Fatalf("config --unset failed: %v", err)
+	}
+	if _, err := config("pull.rebase"); err == nil {
+		t.Error("reading a missing key should fail")
+	}
+	if _, err := config("--unset", "pull.rebase"); err == nil {
+		t.Error("unsetting a missing key should fail")
+	}
+	if _, err := config("--get", "branch.other.remote"); err == nil {
+		t.Error("reading a key of a missing subsection should fail")
+	}
+}
@@ -0,0 +1,274 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/kurobon/gitgym/backend/internal/git"
+)
+
+func init() {
+	git.RegisterCommand("describe", func() git.Command { return &DescribeCommand{} })
+}
+
+type DescribeCommand struct{}
+
+// Ensure DescribeCommand implements git.Command
+var _ git.Command = (*DescribeCommand)(nil)
+
+type DescribeOptions struct {
+	Tags       bool     // Also use lightweight tags
+	Long       bool     // Always print <tag>-<n>-g<hash>
+	Dirty      string   // Suffix for a dirty worktree ("" when not requested)
+	Always     bool     // Fall back to the abbreviated hash
+	ExactMatch bool     // Only describe tagged commits
+	Abbrev     int      // Hash length, 0 prints just the tag
+	Match      []string // Only tags matching these patterns
+	Exclude    []string // Never tags matching these patterns
+	Commits    []string
+}
+
+func (c *DescribeCommand) Execute(ctx context.Context, s *git.Session, args []string) (string, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	opts, err := c.parseArgs(args)
+	if err != nil {
+		if err.Error() == "help requested" {
+			return c.Help(), nil
+		}
+		return "", err
+	}
+
+	repo := s.GetRepo()
+	if repo == nil {
+		return "", fmt.Errorf("fatal: not a git repository (or any of the parent directories): .git")
+	}
+
+	if opts.Dirty != "" && len(opts.Commits) > 0 {
+		return "", fmt.Errorf("fatal: option '--dirty' and commit-ishes cannot be used together")
+	}
+	commits := opts.Commits
+	if len(commits) == 0 {
+		commits = []string{"HEAD"}
+	}
+
+	var lines []string
+	for _, rev := range commits {
+		line, err := c.describe(repo, rev, opts)
+		if err != nil {
+			return "", err
+		}
+		lines = append(lines, line)
+	}
+	if opts.Dirty != "" {
+		changes, err := localChanges(repo)
+		if err != nil {
+			return "", err
+		}
+		if len(changes) > 0 {
+			lines[0] += opts.Dirty
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func (c *DescribeCommand) parseArgs(args []string) (*DescribeOptions, error) {
+	opts := &DescribeOptions{Abbrev: 7}
+	cmdArgs := args[1:]
+
+	for i := 0; i < len(cmdArgs); i++ {
+		arg := cmdArgs[i]
+		name, value, hasValue := strings.Cut(arg, "=")
+		switch {
+		case arg == "-h" || arg == "--help":
+			return nil, fmt.Errorf("help requested")
+		case arg == "--tags":
+			opts.Tags = true
+		case arg == "--long":
+			opts.Long = true
+		case arg == "--always":
+			opts.Always = true
+		case arg == "--exact-match":
+			opts.ExactMatch = true
+		case name == "--dirty":
+			opts.Dirty = "-dirty"
+			if hasValue {
+				opts.Dirty = value
+			}
+		case name == "--abbrev":
+			opts.Abbrev = 7
+			if hasValue {
+				n, err := strconv.Atoi(value)
+				if err != nil || n < 0 {
+					return nil, fmt.Errorf("error: option `abbrev' expects a numerical value")
+				}
+				// Like git, a non-zero length is at least 4 and at most a full hash
+				opts.Abbrev = min(max(n, 4), 40)
+				if n == 0 {
+					opts.Abbrev = 0
+				}
+			}
+		case name == "--match" || name == "--exclude":
+			if !hasValue {
+				if i+1 >= len(cmdArgs) {
+					return nil, fmt.Errorf("error: option `%s' requires a value", strings.TrimPrefix(name, "--"))
+				}
+				i++
+				value = cmdArgs[i]
+			}
+			if name == "--match" {
+				opts.Match = append(opts.Match, value)
+			} else {
+				opts.Exclude = append(opts.Exclude, value)
+			}
+		case strings.HasPrefix(arg, "-"):
+			return nil, fmt.Errorf("error: unknown option `%s'", strings.TrimLeft(arg, "-"))
+		default:
+			opts.Commits = append(opts.Commits, arg)
+		}
+	}
+	return opts, nil
+}
+
+// describe names rev after the nearest tag in its history, the one with the
+// fewest commits between it and rev.
+func (c *DescribeCommand) describe(repo *gogit.Repository, rev string, opts *DescribeOptions) (string, error) {
+	hash, err := git.ResolveRevision(repo, rev)
+	if err != nil {
+		return "", fmt.Errorf("fatal: Not a valid object name %s", rev)
+	}
+	tags, err := collectTags(repo)
+	if err != nil {
+		return "", err
+	}
+	var candidates []*tagEntry
+	unannotated := false
+	for _, t := range tags {
+		if t.commit == nil {
+			continue
+		}
+		if len(opts.Match) > 0 && !branchPatternMatches(opts.Match, t.name) {
+			continue
+		}
+		if len(opts.Exclude) > 0 && branchPatternMatches(opts.Exclude, t.name) {
+			continue
+		}
+		if t.tag == nil && !opts.Tags {
+			unannotated = true
+			continue
+		}
+		candidates = append(candidates, t)
+	}
+
+	// Pick the tag with the fewest commits on top of it. Ties go to
+	// annotated tags, then to the most recent one, as in git.
+	var best *tagEntry
+	bestDepth := -1
+	for _, t := range candidates {
+		if ok, err := git.IsFastForward(repo, t.commit.Hash, *hash); err != nil || !ok {
+			continue
+		}
+		between, err := git.CommitsBetween(repo, t.commit.Hash, *hash)
+		if err != nil {
+			return "", err
+		}
+		depth := len(between)
+		if best == nil || depth < bestDepth || depth == bestDepth && betterTag(t, best) {
+			best, bestDepth = t, depth
+		}
+	}
+
+	switch {
+	case best != nil && bestDepth == 0 && !opts.Long:
+		return best.name, nil
+	case best != nil && !opts.ExactMatch:
+		if opts.Abbrev == 0 {
+			return best.name, nil
+		}
+		return fmt.Sprintf("%s-%d-g%s", best.name, bestDepth, hash.String()[:opts.Abbrev]), nil
+	case best != nil && bestDepth == 0:
+		return fmt.Sprintf("%s-0-g%s", best.name, hash.String()[:opts.Abbrev]), nil
+	case opts.ExactMatch:
+		return "", fmt.Errorf("fatal: no tag exactly matches '%s'", hash)
+	case opts.Always:
+		if opts.Abbrev == 0 {
+			return hash.String(), nil
+		}
+		return hash.String()[:opts.Abbrev], nil
+	case len(tags) == 0:
+		return "", fmt.Errorf("fatal: No names found, cannot describe anything.")
+	case unannotated:
+		return "", fmt.Errorf("fatal: No annotated tags can describe '%s'.\nHowever, there were unannotated tags: try --tags.", hash)
+	}
+	return "", fmt.Errorf("fatal: No tags can describe '%s'.\nTry --always, or create some tags.", hash)
+}
+
+// betterTag reports whether a should be preferred over b for the same commit
+// distance.
+func betterTag(a, b *tagEntry) bool {
+	if (a.tag != nil) != (b.tag != nil) {
+		return a.tag != nil
+	}
+	if !a.date().Equal(b.date()) {
+		return a.date().After(b.date())
+	}
+	return a.name < b.name
+}
+
+func (c *DescribeCommand) Help() string {
+	return `📘 GIT-DESCRIBE (1)                                     Git Manual
+
+ 💡 DESCRIPTION
+    コミットに、そこから一番近いタグを使った「人が読める名前」を付けます。
+    例えば v1.0-3-gabc1234 は「v1.0 から 3 コミット進んだ abc1234」という意味です。
+    タグの付いたコミットそのものなら、タグ名だけが表示されます。
+    ビルド番号やバージョン表示によく使われます。
+
+ 📋 SYNOPSIS
+    git describe [--tags] [--long] [--always] [--abbrev=<n>] [--match <pattern>]
+                 [--exclude <pattern>] [--exact-match] [<commit-ish>...]
+    git describe [--tags] [--long] --dirty[=<mark>]
+
+ ⚙️  COMMON OPTIONS
+    --tags
+        注釈付きタグだけでなく、軽量タグも使います。
+
+    --long
+        タグの付いたコミットでも v1.0-0-gabc1234 の形で表示します。
+
+    --dirty[=<mark>]
+        作業ツリーに変更がある場合、末尾に -dirty（または <mark>）を付けます。
+
+    --always
+        使えるタグがない場合、代わりに短縮ハッシュを表示します。
+
+    --abbrev=<n>
+        ハッシュの桁数を指定します（0 でタグ名だけ）。
+
+    --exact-match
+        タグが直接付いているコミットだけを表示します。
+
+    --match <pattern> / --exclude <pattern>
+        パターンに一致する / しないタグだけを使います（例: 'v*'）。
+
+ 🛠  EXAMPLES
+    1. 現在のコミットを表示
+       $ git describe
+       v1.0-3-gabc1234
+
+    2. 未コミットの変更があるかも含めて表示
+       $ git describe --tags --dirty
+       v1.0-3-gabc1234-dirty
+
+ 💡 TIPS
+    既定では注釈付きタグ（git tag -a）しか使いません。
+    軽量タグしかない場合は --tags を付けましょう。
+
+ 🔗 REFERENCE
+    Full documentation: https://git-scm.com/docs/git-describe
+`
+}
@@ -0,0 +1,61 @@
+package commands
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/kurobon/gitgym/backend/internal/git"
+)
+
+func TestDescribeCommand(t *testing.T) {
+	sm := git.NewSessionManager()
+	s, _ := sm.CreateSession("test-describe")
+	s.InitRepo("testrepo")
+	s.CurrentDir = "/testrepo"
+	repo := s.GetRepo()
+	ctx := context.Background()
+	describe := func(args ...string) (string, error) {
+		return git.Dispatch(ctx, s, "describe", append([]string{"describe"}, args...))
+	}
+
+	commitFile(t, repo, "a.txt", "a", "First")
+	if _, err := describe(); err == nil || !strings.Contains(err.Error(), "No names found") {
+		t.Errorf("expected no names error, got %v", err)
+	}
+
+	git.Dispatch(ctx, s, "tag", []string{"tag", "v0.1"})
+	if _, err := describe(); err == nil || !strings.Contains(err.Error(), "try --tags") {
+		t.Errorf("lightweight tags should need --tags, got %v", err)
+	}
+	if out, _ := describe("--tags"); out != "v0.1" {
+		t.Errorf("describe --tags = %q", out)
+	}
+
+	git.Dispatch(ctx, s, "tag", []string{"tag", "-a", "v1.0", "-m", "Release 1.0"})
+	commitFile(t, repo, "b.txt", "b", "Second")
+	commitFile(t, repo, "c.txt", "c", "Third")
+	head, _ := repo.Head()
+	short := head.Hash().String()[:7]
+
+	if out, _ := describe(); out != "v1.0-2-g"+short {
+		t.Errorf("describe = %q", out)
+	}
+	if out, _ := describe("HEAD~2", "--long"); !strings.HasPrefix(out, "v1.0-0-g") {
+		t.Errorf("describe --long = %q", out)
+	}
+	if _, err := describe("--exact-match"); err == nil {
+		t.Error("--exact-match should fail on an untagged commit")
+	}
+
+	w, _ := repo.Worktree()
+	f, _ := w.Filesystem.Create("a.txt")
+	f.Write([]byte("changed"))
+	f.Close()
+	if out, _ := describe("--dirty"); out != "v1.0-2-g"+short+"-dirty" {
+		t.Errorf("describe --dirty = %q", out)
+	}
+	if _, err := describe("--dirty", "HEAD"); err == nil {
+		t.Error("--dirty with a commit-ish should fail")
+	}
+}
@@ -7,10 +7,14 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 
 	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/kurobon/gitgym/backend/internal/git"
 )
@@ -29,7 +33,13 @@ type FetchOptions struct {
 	FetchAll bool
 	Prune    bool
 	Tags     bool
-	Remotes  []string
+	Remote   string
+	Refspecs []string // Command-line refspecs; remote.<name>.fetch when empty
+
+	// Shallow history
+	Depth     int // --depth: keep this many commits from each fetched tip
+	Deepen    int // --deepen: extend the shallow boundary by this many commits
+	Unshallow bool
 }
 
 func (c *FetchCommand) Execute(ctx context.Context, s *git.Session, args []string) (string, error) {
@@ -53,6 +63,12 @@ func (c *FetchCommand) Execute(ctx context.Context, s *git.Session, args []strin
 	// 2. Resolve Targets (List of Remotes)
 	remotes, err := c.resolveFetchTargets(repo, opts)
 	if err != nil {
+		// `git fetch <bundle> [<branch>]` fetches straight from a bundle file into FETCH_HEAD
+		if !opts.FetchAll && opts.Remote != "" {
+			if out, isBundle, bundleErr := c.fetchFromBundle(s, repo, opts); isBundle {
+				return out, bundleErr
+			}
+		}
 		return "", err
 	}
 
@@ -62,9 +78,28 @@ func (c *FetchCommand) Execute(ctx context.Context, s *git.Session, args []strin
 
 func (c *FetchCommand) parseArgs(args []string) (*FetchOptions, error) {
 	opts := &FetchOptions{}
+	var positional []string
 	cmdArgs := args[1:]
-	for i, arg := range cmdArgs {
-		switch arg {
+	// count reads the number of "--opt=<n>" or "--opt <n>"
+	count := func(i *int, arg, name string) (int, error) {
+		v, ok := strings.CutPrefix(arg, name+"=")
+		if !ok {
+			if *i+1 >= len(cmdArgs) {
+				return 0, fmt.Errorf("error: option `%s' requires a value", strings.TrimPrefix(name, "--"))
+			}
+			*i++
+			v = cmdArgs[*i]
+		}
+		n, err := strconv.Atoi(v)
+		if err != nil || n < 1 {
+			return 0, fmt.Errorf("fatal: %s %s is not a positive number", strings.TrimPrefix(name, "--"), v)
+		}
+		return n, nil
+	}
+	for i := 0; i < len(cmdArgs); i++ {
+		arg := cmdArgs[i]
+		name, _, _ := strings.Cut(arg, "=")
+		switch name {
 		case "-n", "--dry-run":
 			opts.DryRun = true
 		case "--all":
@@ -73,20 +108,46 @@ func (c *FetchCommand) parseArgs(args []string) (*FetchOptions, error) {
 			opts.Prune = true
 		case "-t", "--tags":
 			opts.Tags = true
+		case "--depth":
+			n, err := count(&i, arg, name)
+			if err != nil {
+				return nil, err
+			}
+			opts.Depth = n
+		case "--deepen":
+			n, err := count(&i, arg, name)
+			if err != nil {
+				return nil, err
+			}
+			opts.Deepen = n
+		case "--unshallow":
+			opts.Unshallow = true
 		case "-h", "--help":
 			return nil, fmt.Errorf("help requested")
 		default:
+			// "+src:dst" refspecs start with '+', not '-'
 			if strings.HasPrefix(arg, "-") {
 				return nil, fmt.Errorf("unknown flag: %s", arg)
 			}
-			// Only append positional args if not skipping (handled by index loop if manual, but range is safer here if we don't skip)
-			// Wait, mixed flags/args logic in legacy was strict order? No, loop index i:
-			// legacy: for i := 0; i < len(cmdArgs); i++ ...
-			// Here range is fine unless we need to skip next arg (not needed for boolean flags).
-			// If we had value flags, we'd need manual index handling. All current flags are boolean.
-			opts.Remotes = append(opts.Remotes, arg)
+			positional = append(positional, arg)
 		}
-		_ = i
+	}
+
+	switch {
+	case opts.Depth > 0 && opts.Deepen > 0:
+		return nil, fmt.Errorf("fatal: options '--deepen' and '--depth' cannot be used together")
+	case opts.Unshallow && opts.Depth > 0:
+		return nil, fmt.Errorf("fatal: options '--depth' and '--unshallow' cannot be used together")
+	case opts.Unshallow && opts.Deepen > 0:
+		return nil, fmt.Errorf("fatal: options '--deepen' and '--unshallow' cannot be used together")
+	}
+
+	if len(positional) > 0 {
+		if opts.FetchAll {
+			return nil, fmt.Errorf("fatal: fetch --all does not take a repository argument")
+		}
+		opts.Remote = positional[0]
+		opts.Refspecs = positional[1:]
 	}
 	return opts, nil
 }
@@ -100,10 +161,10 @@ func (c *FetchCommand) resolveFetchTargets(repo *gogit.Repository, opts *FetchOp
 		return remotes, nil
 	}
 
-	// Single remote (default origin)
-	remoteName := "origin"
-	if len(opts.Remotes) > 0 {
-		remoteName = opts.Remotes[0]
+	// Single remote (default: the current branch's upstream remote, else origin)
+	remoteName := opts.Remote
+	if remoteName == "" {
+		remoteName = git.DefaultRemote(repo)
 	}
 	rem, err := repo.Remote(remoteName)
 	if err != nil {
@@ -114,226 +175,349 @@ func (c *FetchCommand) resolveFetchTargets(repo *gogit.Repository, opts *FetchOp
 
 func (c *FetchCommand) executeFetch(s *git.Session, repo *gogit.Repository, remotes []*gogit.Remote, opts *FetchOptions) (string, error) {
 	var allResults []string
-	failed := false
 
 	for _, rem := range remotes {
-		res, err := c.fetchRemote(s, repo, rem, opts.DryRun, opts.Tags, opts.Prune)
+		res, err := c.fetchRemote(s, repo, rem, opts)
 		if err != nil {
-			allResults = append(allResults, fmt.Sprintf("error: fetching %s: %v", rem.Config().Name, err))
-			failed = true
-		} else {
-			if res != "" {
-				allResults = append(allResults, res)
+			if len(remotes) == 1 {
+				return "", err
 			}
+			allResults = append(allResults, fmt.Sprintf("error: fetching %s: %v", rem.Config().Name, err))
+		} else if res != "" {
+			allResults = append(allResults, res)
 		}
 	}
 
-	if failed && len(remotes) == 1 {
-		return "", fmt.Errorf("fetch failed") // Return error for single remote failure
-	}
-
 	if len(allResults) == 0 {
-		return "", nil // Standard git is silent if nothing happened? Or "Already up to date."? Git is usually silent if nothing printed.
+		return "", nil // git prints nothing when every ref is up to date
 	}
-
 	return strings.Join(allResults, "\n"), nil
 }
 
-func (c *FetchCommand) resolveSimulatedRemote(s *git.Session, url string) (*gogit.Repository, error) {
-	lookupKey := strings.TrimPrefix(url, "/")
-
-	// Check Session-local Repos
-	if repo, ok := s.Repos[lookupKey]; ok {
-		return repo, nil
+func (c *FetchCommand) resolveSimulatedRemote(s *git.Session, repo *gogit.Repository, url string) (*gogit.Repository, error) {
+	// Bundle files in the session filesystem (prerequisites come from the local repo)
+	if bundleRepo, isBundle, err := openSessionBundleRepo(s, url, repo.Storer); isBundle {
+		return bundleRepo, err
 	}
 
-	// Check Shared Remotes
-	if s.Manager != nil {
-		if repo, ok := s.Manager.SharedRemotes[lookupKey]; ok {
-			return repo, nil
-		}
-		// Fallback: Check using full URL
-		if repo, ok := s.Manager.SharedRemotes[url]; ok {
-			return repo, nil
-		}
-	}
-
-	// FALLBACK: Local filesystem path (persistent remote)
-	repo, err := gogit.PlainOpen(url)
-	if err == nil {
-		return repo, nil
-	}
-	repo, err = gogit.PlainOpen(lookupKey)
-	if err == nil {
-		return repo, nil
-	}
-
-	return nil, fmt.Errorf("remote repository '%s' not found (only local simulation supported)", url)
+	return git.ResolveRemoteURL(s, url)
 }
 
-func (c *FetchCommand) fetchRemote(s *git.Session, repo *gogit.Repository, rem *gogit.Remote, isDryRun bool, fetchTags bool, prune bool) (string, error) {
+// fetchRemote copies the objects the refspecs select from one remote and
+// updates the local refs, printing git's summary table. Rejected updates
+// (non-fast-forward without '+', changed tags) leave their ref untouched.
+func (c *FetchCommand) fetchRemote(s *git.Session, repo *gogit.Repository, rem *gogit.Remote, opts *FetchOptions) (string, error) {
 	cfg := rem.Config()
-	remoteName := cfg.Name
 	if len(cfg.URLs) == 0 {
-		return "", fmt.Errorf("remote %s has no URL defined", remoteName)
+		return "", fmt.Errorf("remote %s has no URL defined", cfg.Name)
 	}
 	url := cfg.URLs[0]
 
 	// Look up simulated remote source
-	srcRepo, err := c.resolveSimulatedRemote(s, url)
+	srcRepo, err := c.resolveSimulatedRemote(s, repo, url)
 	if err != nil {
 		return "", err
 	}
 
-	// Scan remote refs (branches and tags)
-	refs, err := srcRepo.References()
+	updates, err := c.planFetch(repo, srcRepo, cfg, opts)
 	if err != nil {
 		return "", err
 	}
+	if !opts.DryRun {
+		if err := c.fetchShallowHistory(repo, srcRepo, updates, opts); err != nil {
+			return "", err
+		}
+	}
 
-	results := []string{fmt.Sprintf("From %s", url)}
-	updated := 0
-
-	// Filter targets based on refspecs/tags
-	// Map: RemoteBranchName -> TargetLocalRef
-	fetchTargets := make(map[string]plumbing.ReferenceName)
-
-	// Default fetchspec if no refspecs provided: +refs/heads/*:refs/remotes/origin/*
-	// If refspecs provided (e.g. "main"), it usually means fetch that branch and map to FETCH_HEAD,
-	// BUT for simplicity in this simulated env, we will map "main" -> "refs/remotes/origin/main"
-	// unless a full refspec is given (which we honestly support minimally).
-
-	// Build a list of candidate remote branches we care about
-	candidates := make(map[string]*plumbing.Reference)
+	// Objects are needed locally to tell fast-forwards from forced updates;
+	// a dry run gathers them in a quarantine and leaves the repository alone
+	objects := repo
+	if opts.DryRun {
+		if objects, err = git.Quarantine(repo); err != nil {
+			return "", err
+		}
+	}
+	var shown []*refUpdate
+	for _, u := range updates {
+		if err := git.CopyObjectRecursive(srcRepo, objects, u.New); err != nil {
+			return "", err
+		}
+		classifyUpdate(objects, u, "would clobber existing tag")
+		if u.Status != refUpToDate {
+			shown = append(shown, u)
+		}
+	}
 
-	// Track present remote branches for pruning later
-	remoteBranches := make(map[string]bool)
+	if !opts.DryRun && len(git.ShallowCommits(repo)) > 0 {
+		// New objects may have filled in history behind the shallow boundary
+		if err := git.UpdateShallow(repo); err != nil {
+			return "", err
+		}
+	}
 
-	err = refs.ForEach(func(r *plumbing.Reference) error {
-		// 1. Handle Branches
-		if r.Name().IsBranch() {
-			remoteBranches[r.Name().Short()] = true
-			res, count, err := c.handleFetchBranch(repo, srcRepo, r, remoteName, isDryRun)
+	// --prune: tracking refs whose branch is gone from the remote
+	if opts.Prune && len(opts.Refspecs) == 0 {
+		state, err := remoteRefStates(repo, srcRepo, cfg)
+		if err != nil {
+			return "", err
+		}
+		for _, stale := range state.Stale {
+			ref, err := repo.Storer.Reference(stale)
 			if err != nil {
-				return err
-			}
-			if res != "" {
-				results = append(results, res)
+				continue
 			}
-			updated += count
+			shown = append(shown, &refUpdate{Dst: stale, Old: ref.Hash(), Status: refDeleted})
 		}
+	}
 
-		// 2. Handle Tags
-		if fetchTags && r.Name().IsTag() {
-			res, count, err := c.handleFetchTag(repo, srcRepo, r, isDryRun)
-			if err != nil {
-				// Warn but don't fail entire fetch?
-				results = append(results, fmt.Sprintf(" ! [error] %s (copy failed)", r.Name().Short()))
-				return nil
-			}
-			if res != "" {
-				results = append(results, res)
+	if len(shown) == 0 {
+		return "", nil // Nothing to report for this remote if up to date
+	}
+
+	failed := false
+	fetchHeadSet := false
+	for _, u := range shown {
+		if u.Failed() {
+			failed = true
+			continue
+		}
+		if opts.DryRun {
+			continue
+		}
+		switch {
+		case u.Dst == "FETCH_HEAD":
+			// FETCH_HEAD records the first ref fetched without a destination
+			if fetchHeadSet {
+				continue
 			}
-			updated += count
+			fetchHeadSet = true
+			err = repo.Storer.SetReference(plumbing.NewHashReference(u.Dst, u.New))
+		case u.Status == refDeleted:
+			err = repo.Storer.RemoveReference(u.Dst)
+		default:
+			err = repo.Storer.SetReference(plumbing.NewHashReference(u.Dst, u.New))
 		}
-		return nil
-	})
+		if err != nil {
+			return "", err
+		}
+	}
+
+	repoCfg, err := repo.Config()
 	if err != nil {
 		return "", err
 	}
+	out := formatFetchUpdates(remoteDisplayURL(repoCfg, cfg.Name, url), shown)
+	if opts.DryRun {
+		out += "\n[dry-run] no refs were updated"
+	}
+	if failed {
+		return "", errors.New(out)
+	}
+	return out, nil
+}
 
-	// Suppress unused variable warnings
-	_ = fetchTargets
-	_ = candidates
-
-	// 3. Prune Logic
-	// If --prune is set, we remove local remote-tracking branches that no longer exist on remote.
-	if prune {
-		count, res, err := c.pruneRemoteBranches(repo, remoteName, remoteBranches, isDryRun)
-		if err != nil {
-			// Don't fail the whole fetch for prune errors
-			// for now we ignore it
-			_ = err
+// planFetch lists the ref updates a fetch from srcRepo makes: every remote ref
+// matched by remote.<name>.fetch, or the command-line refspecs. A refspec
+// without a destination stores into FETCH_HEAD and, like git, also updates the
+// remote-tracking ref the configured refspecs map it to.
+func (c *FetchCommand) planFetch(repo, srcRepo *gogit.Repository, remote *config.RemoteConfig, opts *FetchOptions) ([]*refUpdate, error) {
+	var updates []*refUpdate
+	seen := make(map[plumbing.ReferenceName]bool)
+	add := func(src, dst plumbing.ReferenceName, hash plumbing.Hash, force bool) {
+		if dst != "FETCH_HEAD" {
+			if seen[dst] {
+				return
+			}
+			seen[dst] = true
 		}
-		if len(res) > 0 {
-			results = append(results, res...)
+		u := &refUpdate{Src: src, Dst: dst, New: hash, Force: force}
+		if dst != "FETCH_HEAD" {
+			if ref, err := repo.Storer.Reference(dst); err == nil {
+				u.Old = ref.Hash()
+			}
+		}
+		updates = append(updates, u)
+	}
+	addConfigured := func(src plumbing.ReferenceName, hash plumbing.Hash) {
+		for _, spec := range remote.Fetch {
+			if spec.Match(src) {
+				add(src, spec.Dst(src), hash, spec.IsForceUpdate())
+			}
 		}
-		updated += count
 	}
 
-	if updated == 0 {
-		return "", nil // Nothing to report for this remote if up to date
+	remoteRefs, err := sortedHashRefs(srcRepo)
+	if err != nil {
+		return nil, err
 	}
 
-	return strings.Join(results, "\n"), nil
-}
+	if len(opts.Refspecs) == 0 {
+		for _, ref := range remoteRefs {
+			addConfigured(ref.Name(), ref.Hash())
+		}
+	}
 
-func (c *FetchCommand) handleFetchBranch(repo, srcRepo *gogit.Repository, r *plumbing.Reference, remoteName string, isDryRun bool) (string, int, error) {
-	branchName := r.Name().Short()
-	localRefName := plumbing.ReferenceName(fmt.Sprintf("refs/remotes/%s/%s", remoteName, branchName))
+	for _, arg := range opts.Refspecs {
+		force, src, dst, err := parseRefSpec(arg)
+		if err != nil {
+			return nil, err
+		}
+		if src == "" {
+			return nil, fmt.Errorf("fatal: invalid refspec '%s'", arg)
+		}
 
-	// Check if update needed
-	currentLocal, errRef := repo.Reference(localRefName, true)
-	if errRef == nil && currentLocal.Hash() == r.Hash() {
-		return "", 0, nil // up to date
-	}
+		if strings.Contains(src, "*") {
+			if !strings.HasPrefix(src, "refs/") || (dst != "" && !strings.HasPrefix(dst, "refs/")) {
+				return nil, fmt.Errorf("fatal: invalid refspec '%s'", arg)
+			}
+			for _, ref := range remoteRefs {
+				spec := config.RefSpec(src + ":" + dst)
+				if dst == "" {
+					spec = config.RefSpec(src + ":" + src)
+				}
+				if !spec.Match(ref.Name()) {
+					continue
+				}
+				if dst == "" {
+					add(ref.Name(), "FETCH_HEAD", ref.Hash(), false)
+				} else {
+					add(ref.Name(), spec.Dst(ref.Name()), ref.Hash(), force)
+				}
+			}
+			continue
+		}
 
-	if isDryRun {
-		return fmt.Sprintf(" * [dry-run] %s -> %s/%s", branchName, remoteName, branchName), 0, nil
-	}
+		name, ok := dwimRef(src, refExists(srcRepo))
+		if !ok {
+			return nil, fmt.Errorf("fatal: couldn't find remote ref %s", src)
+		}
+		ref, err := srcRepo.Reference(name, true)
+		if err != nil {
+			return nil, fmt.Errorf("fatal: couldn't find remote ref %s", src)
+		}
+		if dst == "" {
+			add(name, "FETCH_HEAD", ref.Hash(), false)
+			addConfigured(name, ref.Hash())
+			continue
+		}
 
-	// Copy Objects
-	err := git.CopyCommitRecursive(srcRepo, repo, r.Hash())
-	if err != nil {
-		return "", 0, err
+		dstName := plumbing.ReferenceName(dst)
+		if !strings.HasPrefix(dst, "refs/") {
+			if name.IsTag() {
+				dstName = plumbing.NewTagReferenceName(dst)
+			} else {
+				dstName = plumbing.NewBranchReferenceName(dst)
+			}
+		}
+		if head, err := repo.Storer.Reference(plumbing.HEAD); err == nil && head.Type() == plumbing.SymbolicReference && head.Target() == dstName {
+			return nil, fmt.Errorf("fatal: refusing to fetch into current branch %s of non-bare repository", dstName)
+		}
+		add(name, dstName, ref.Hash(), force)
 	}
 
-	// Update Local Reference
-	newRef := plumbing.NewHashReference(localRefName, r.Hash())
-	err = repo.Storer.SetReference(newRef)
-	if err != nil {
-		return "", 0, err
+	// --tags: refs/tags/*:refs/tags/* (without '+', so moved tags are rejected)
+	if opts.Tags {
+		for _, ref := range remoteRefs {
+			if ref.Name().IsTag() {
+				add(ref.Name(), ref.Name(), ref.Hash(), false)
+			}
+		}
 	}
+	return updates, nil
+}
 
-	status := "updated"
-	if errRef != nil {
-		status = "new branch"
+// fetchShallowHistory handles the options that move the shallow boundary:
+// --depth cuts the fetched history, --deepen extends it behind the grafted
+// commits and --unshallow copies everything behind them.
+func (c *FetchCommand) fetchShallowHistory(repo, srcRepo *gogit.Repository, updates []*refUpdate, opts *FetchOptions) error {
+	if opts.Depth > 0 {
+		var tips []plumbing.Hash
+		for _, u := range updates {
+			tips = append(tips, u.New)
+		}
+		return git.CopyShallowHistory(srcRepo, repo, tips, git.ShallowLimit{Depth: opts.Depth})
+	}
+	if opts.Deepen == 0 && !opts.Unshallow {
+		return nil
 	}
 
-	return fmt.Sprintf(" * [%s] %s -> %s/%s", status, branchName, remoteName, branchName), 1, nil
+	grafted := git.GraftedParents(repo)
+	if len(grafted) == 0 {
+		if opts.Unshallow {
+			return fmt.Errorf("fatal: --unshallow on a complete repository does not make sense")
+		}
+		return nil
+	}
+	return git.CopyShallowHistory(srcRepo, repo, grafted, git.ShallowLimit{Depth: opts.Deepen})
 }
 
-func (c *FetchCommand) handleFetchTag(repo, srcRepo *gogit.Repository, r *plumbing.Reference, isDryRun bool) (string, int, error) {
-	tagName := r.Name().Short()
-	localTagRef := r.Name()
+// sortedHashRefs returns the non-symbolic refs of repo in name order.
+func sortedHashRefs(repo *gogit.Repository) ([]*plumbing.Reference, error) {
+	iter, err := repo.Storer.IterReferences()
+	if err != nil {
+		return nil, err
+	}
+	var refs []*plumbing.Reference
+	err = iter.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() == plumbing.HashReference && ref.Name() != plumbing.HEAD {
+			refs = append(refs, ref)
+		}
+		return nil
+	})
+	sort.Slice(refs, func(i, j int) bool { return refs[i].Name() < refs[j].Name() })
+	return refs, err
+}
 
-	// Check if update needed
-	currentLocal, errRef := repo.Reference(localTagRef, true)
-	if errRef == nil && currentLocal.Hash() == r.Hash() {
-		return "", 0, nil
+// fetchFromBundle handles `git fetch <bundle-file> [<branch>]` where the first
+// argument is not a configured remote. Objects are copied and the fetched tip
+// is recorded in FETCH_HEAD, like fetching from a URL without a refspec.
+func (c *FetchCommand) fetchFromBundle(s *git.Session, repo *gogit.Repository, opts *FetchOptions) (string, bool, error) {
+	bundlePath := opts.Remote
+	srcRepo, isBundle, err := openSessionBundleRepo(s, bundlePath, repo.Storer)
+	if !isBundle {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", true, fmt.Errorf("error: %w", err)
 	}
 
-	if isDryRun {
-		return fmt.Sprintf(" * [dry-run] %s -> %s", tagName, tagName), 0, nil
+	var target *plumbing.Reference
+	label := "HEAD"
+	if len(opts.Refspecs) > 0 {
+		label = opts.Refspecs[0]
+		for _, candidate := range []string{label, "refs/heads/" + label, "refs/tags/" + label} {
+			if ref, refErr := srcRepo.Reference(plumbing.ReferenceName(candidate), true); refErr == nil {
+				target = ref
+				break
+			}
+		}
+		if target == nil {
+			return "", true, fmt.Errorf("fatal: couldn't find remote ref %s", label)
+		}
+	} else {
+		head, headErr := srcRepo.Head()
+		if headErr != nil {
+			return "", true, fmt.Errorf("fatal: couldn't find remote ref HEAD")
+		}
+		target = head
 	}
 
-	// Copy Objects
-	err := git.CopyCommitRecursive(srcRepo, repo, r.Hash())
-	if err != nil {
-		return "", 0, err
+	if opts.DryRun {
+		return fmt.Sprintf("From %s\n * [dry-run] %s -> FETCH_HEAD", bundlePath, label), true, nil
 	}
 
-	newRef := plumbing.NewHashReference(localTagRef, r.Hash())
-	err = repo.Storer.SetReference(newRef)
-	if err != nil {
-		return "", 0, err
+	if err := git.CopyCommitRecursive(srcRepo, repo, target.Hash()); err != nil {
+		return "", true, err
+	}
+	if err := repo.Storer.SetReference(plumbing.NewHashReference("FETCH_HEAD", target.Hash())); err != nil {
+		return "", true, err
 	}
 
-	status := "updated"
-	if errRef != nil {
-		status = "new tag"
+	kind := "branch"
+	if strings.HasPrefix(label, "refs/tags/") || target.Name().IsTag() {
+		kind = "tag"
 	}
-	return fmt.Sprintf(" * [%s] %s -> %s", status, tagName, tagName), 1, nil
+	return fmt.Sprintf("From %s\n * %-18s %-10s -> FETCH_HEAD", bundlePath, kind, label), true, nil
 }
 
 func (c *FetchCommand) Help() string {
@@ -347,9 +531,15 @@ func (c *FetchCommand) Help() string {
     取得した情報は ` + "`" + `git log origin/main` + "`" + ` などで確認できます。
 
  📋 SYNOPSIS
-    git fetch [<remote>] [<branch>]
+    git fetch [<remote>] [<refspec>...]
+    git fetch <bundle-file> [<branch>]
     git fetch --all
     git fetch --prune
+    git fetch --deepen=<n> | --unshallow
+
+    <refspec> は [+]<src>:<dst> の形式です（例: +refs/heads/*:refs/remotes/origin/*）。
+    省略すると remote.<name>.fetch の設定が使われます。
+    先頭の + は、早送りできない更新（強制更新）も許可します。
 
  ⚙️  COMMON OPTIONS
     --all
@@ -365,6 +555,15 @@ func (c *FetchCommand) Help() string {
     --dry-run, -n
         実際にはフェッチを行わず、何が行われるかを表示します。
 
+    --deepen=<n>
+        シャロークローンの履歴を、境界（grafted）からさらに <n> コミット分取得します。
+
+    --unshallow
+        シャロークローンの残りの履歴を全て取得し、通常のリポジトリにします。
+
+    --depth=<n>
+        取得するブランチの先頭から <n> コミット分だけを取得します。
+
  🛠  PRACTICAL EXAMPLES
     1. 基本: originから最新情報を取得
        $ git fetch
@@ -377,40 +576,15 @@ func (c *FetchCommand) Help() string {
        「mainの更新だけ欲しい」という時に。
        $ git fetch origin main
 
+    4. 実践: リモートのブランチを別名のローカルブランチとして取得
+       $ git fetch origin main:review-main
+
+    5. オフライン: バンドルファイルから取得
+       取得したコミットは FETCH_HEAD に記録されます。
+       $ git fetch update.bundle main
+       $ git merge FETCH_HEAD
+
  🔗 REFERENCE
     Full documentation: https://git-scm.com/docs/git-fetch
 `
 }
-func (c *FetchCommand) pruneRemoteBranches(repo *gogit.Repository, remoteName string, remoteBranches map[string]bool, isDryRun bool) (int, []string, error) {
-	var results []string
-	updated := 0
-	localRefs, err := repo.References()
-	if err != nil {
-		return 0, nil, err
-	}
-
-	prefix := fmt.Sprintf("refs/remotes/%s/", remoteName)
-	_ = localRefs.ForEach(func(r *plumbing.Reference) error {
-		name := r.Name().String()
-		if strings.HasPrefix(name, prefix) {
-			// e.g. refs/remotes/origin/main -> branchName = main
-			branchName := strings.TrimPrefix(name, prefix)
-			if !remoteBranches[branchName] {
-				// Stale!
-				if isDryRun {
-					results = append(results, fmt.Sprintf(" - [dry-run] [deleted] (none) -> %s/%s", remoteName, branchName))
-				} else {
-					err := repo.Storer.RemoveReference(r.Name())
-					if err != nil {
-						results = append(results, fmt.Sprintf(" ! [error] %s/%s (prune failed)", remoteName, branchName))
-					} else {
-						results = append(results, fmt.Sprintf(" - [deleted] (none) -> %s/%s", remoteName, branchName))
-						updated++
-					}
-				}
-			}
-		}
-		return nil
-	})
-	return updated, results, nil
-}
@@ -2,6 +2,7 @@ package commands
 
 import (
 	"context"
+	"fmt"
 	"path/filepath"
 	"strings"
 	"testing"
@@ -10,6 +11,7 @@ import (
 	"github.com/go-git/go-billy/v5/memfs"
 	gogit "github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/go-git/go-git/v5/storage/memory"
 	"github.com/kurobon/gitgym/backend/internal/git"
@@ -150,3 +152,138 @@ func TestFetchCommand_All(t *testing.T) {
 		t.Errorf("Missing upstream/master ref")
 	}
 }
+
+func TestFetchCommand_Refspecs(t *testing.T) {
+	sm := git.NewSessionManager()
+	s, _ := sm.CreateSession("test-fetch-refspecs")
+
+	fs := memfs.New()
+	remote, _ := gogit.Init(memory.NewStorage(), fs)
+	rw, _ := remote.Worktree()
+	commit := func(msg string, parents ...plumbing.Hash) plumbing.Hash {
+		f, _ := fs.Create("file.txt")
+		f.Write([]byte(msg))
+		f.Close()
+		rw.Add("file.txt")
+		h, err := rw.Commit(msg, &gogit.CommitOptions{
+			Author:  &object.Signature{Name: "Dev", Email: "dev@example.com", When: time.Now()},
+			Parents: parents,
+		})
+		if err != nil {
+			t.Fatalf("commit failed: %v", err)
+		}
+		return h
+	}
+	base := commit("base")
+	sm.SharedRemotes["central"] = remote
+
+	s.InitRepo("repo")
+	s.CurrentDir = "/repo"
+	repo := s.GetRepo()
+	repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{"central"}})
+
+	cmd := &FetchCommand{}
+	run := func(args ...string) (string, error) {
+		return cmd.Execute(context.Background(), s, append([]string{"fetch"}, args...))
+	}
+	hash := func(name string) plumbing.Hash {
+		ref, err := repo.Reference(plumbing.ReferenceName(name), true)
+		if err != nil {
+			return plumbing.ZeroHash
+		}
+		return ref.Hash()
+	}
+
+	t.Run("Configured refspec", func(t *testing.T) {
+		out, err := run("origin")
+		if err != nil {
+			t.Fatalf("fetch failed: %v", err)
+		}
+		if out != "From central\n * [new branch]      master -> origin/master" {
+			t.Errorf("unexpected output: %q", out)
+		}
+	})
+
+	t.Run("Dry run copies nothing", func(t *testing.T) {
+		next := commit("dry", base)
+		defer remote.Storer.SetReference(plumbing.NewHashReference("refs/heads/master", base))
+
+		out, err := run("--dry-run", "origin")
+		if err != nil {
+			t.Fatalf("fetch --dry-run failed: %v", err)
+		}
+		want := fmt.Sprintf("   %s..%s  master -> origin/master", base.String()[:7], next.String()[:7])
+		if !strings.Contains(out, want) {
+			t.Errorf("dry run should report the fast-forward %q: %s", want, out)
+		}
+		if hash("refs/remotes/origin/master") != base {
+			t.Error("dry run must not move origin/master")
+		}
+		if repo.Storer.HasEncodedObject(next) == nil {
+			t.Error("dry run must not copy objects")
+		}
+	})
+
+	t.Run("src:dst", func(t *testing.T) {
+		out, err := run("origin", "master:review")
+		if err != nil {
+			t.Fatalf("fetch failed: %v", err)
+		}
+		if !strings.Contains(out, " * [new branch]      master -> review") || hash("refs/heads/review") != base {
+			t.Errorf("review branch should be created: %s", out)
+		}
+	})
+
+	t.Run("Branch without destination stores FETCH_HEAD", func(t *testing.T) {
+		next := commit("next", base)
+		out, err := run("origin", "master")
+		if err != nil {
+			t.Fatalf("fetch failed: %v", err)
+		}
+		if !strings.Contains(out, " * branch            master -> FETCH_HEAD") {
+			t.Errorf("unexpected output: %s", out)
+		}
+		want := fmt.Sprintf("   %s..%s  master -> origin/master", base.String()[:7], next.String()[:7])
+		if !strings.Contains(out, want) {
+			t.Errorf("expected opportunistic update %q in %s", want, out)
+		}
+		if hash("FETCH_HEAD") != next || hash("refs/remotes/origin/master") != next {
+			t.Error("FETCH_HEAD and origin/master should point at the new commit")
+		}
+	})
+
+	t.Run("Forced and rejected updates", func(t *testing.T) {
+		if _, err := run("origin", "master:review"); err != nil {
+			t.Fatalf("fast-forward of review failed: %v", err)
+		}
+		rewritten := commit("rewritten", base)
+		old := hash("refs/remotes/origin/master")
+
+		out, err := run("origin")
+		if err != nil {
+			t.Fatalf("fetch failed: %v", err)
+		}
+		want := fmt.Sprintf(" + %s...%s master -> origin/master  (forced update)", old.String()[:7], rewritten.String()[:7])
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in %s", want, out)
+		}
+
+		// Without '+' a non-fast-forward update is refused
+		_, err = run("origin", "master:review")
+		if err == nil || !strings.Contains(err.Error(), " ! [rejected]        master -> review  (non-fast-forward)") {
+			t.Errorf("expected rejection, got %v", err)
+		}
+		if _, err := run("origin", "+master:review"); err != nil || hash("refs/heads/review") != rewritten {
+			t.Errorf("forced refspec should update review: %v", err)
+		}
+	})
+
+	t.Run("Invalid refspecs", func(t *testing.T) {
+		if _, err := run("origin", "missing"); err == nil || !strings.Contains(err.Error(), "couldn't find remote ref missing") {
+			t.Errorf("expected missing ref error, got %v", err)
+		}
+		if _, err := run("origin", "master:main"); err == nil || !strings.Contains(err.Error(), "refusing to fetch into current branch") {
+			t.Errorf("expected refusal for the checked-out branch, got %v", err)
+		}
+	})
+}
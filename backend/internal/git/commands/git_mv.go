@@ -0,0 +1,330 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/util"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/format/index"
+	"github.com/kurobon/gitgym/backend/internal/git"
+)
+
+func init() {
+	git.RegisterCommand("git-mv", func() git.Command { return &GitMvCommand{} })
+}
+
+type GitMvCommand struct{}
+
+// Ensure GitMvCommand implements git.Command
+var _ git.Command = (*GitMvCommand)(nil)
+
+type GitMvOptions struct {
+	Force       bool // -f: overwrite an existing destination
+	SkipErrors  bool // -k: skip moves that would fail
+	DryRun      bool // -n: only report what would happen
+	Verbose     bool // -v: report each rename
+	Sources     []string
+	Destination string
+}
+
+// gitMove is one source moved to its destination.
+type gitMove struct {
+	src, dst string
+	isDir    bool
+}
+
+func (c *GitMvCommand) Execute(ctx context.Context, s *git.Session, args []string) (string, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	opts, err := c.parseArgs(args)
+	if err != nil {
+		if err.Error() == "help requested" {
+			return c.Help(), nil
+		}
+		return "", err
+	}
+
+	repo := s.GetRepo()
+	if repo == nil {
+		return "", fmt.Errorf("fatal: not a git repository (or any of the parent directories): .git")
+	}
+	w, err := repo.Worktree()
+	if err != nil {
+		return "", err
+	}
+
+	moves, err := c.planMoves(repo, w, opts)
+	if err != nil {
+		return "", err
+	}
+
+	var lines []string
+	for _, m := range moves {
+		if opts.DryRun {
+			lines = append(lines, fmt.Sprintf("Checking rename of '%s' to '%s'", m.src, m.dst))
+		}
+		if opts.DryRun || opts.Verbose {
+			lines = append(lines, fmt.Sprintf("Renaming %s to %s", m.src, m.dst))
+		}
+		if opts.DryRun {
+			continue
+		}
+		if err := c.move(repo, w, m); err != nil {
+			return "", err
+		}
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func (c *GitMvCommand) parseArgs(args []string) (*GitMvOptions, error) {
+	opts := &GitMvOptions{}
+	var paths []string
+	cmdArgs := args[1:]
+
+	for i := 0; i < len(cmdArgs); i++ {
+		arg := cmdArgs[i]
+		switch arg {
+		case "-h", "--help":
+			return nil, fmt.Errorf("help requested")
+		case "-f", "--force":
+			opts.Force = true
+		case "-k":
+			opts.SkipErrors = true
+		case "-n", "--dry-run":
+			opts.DryRun = true
+		case "-v", "--verbose":
+			opts.Verbose = true
+		case "--":
+			paths = append(paths, cmdArgs[i+1:]...)
+			i = len(cmdArgs)
+		default:
+			if strings.HasPrefix(arg, "-") {
+				return nil, fmt.Errorf("error: unknown option `%s'", strings.TrimLeft(arg, "-"))
+			}
+			paths = append(paths, arg)
+		}
+	}
+
+	if len(paths) < 2 {
+		return nil, fmt.Errorf("usage: git mv [<options>] <source>... <destination>")
+	}
+	for _, p := range paths {
+		opts.Sources = append(opts.Sources, cleanMvPath(p))
+	}
+	opts.Destination = opts.Sources[len(opts.Sources)-1]
+	opts.Sources = opts.Sources[:len(opts.Sources)-1]
+	return opts, nil
+}
+
+func cleanMvPath(p string) string {
+	p = path.Clean(strings.TrimPrefix(p, "/"))
+	if p == "." {
+		return ""
+	}
+	return p
+}
+
+// planMoves checks every source before anything is moved, so a failing
+// git mv leaves the tree untouched. With -k failing sources are skipped.
+func (c *GitMvCommand) planMoves(repo *gogit.Repository, w *gogit.Worktree, opts *GitMvOptions) ([]gitMove, error) {
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return nil, err
+	}
+	dstInfo, dstErr := w.Filesystem.Stat(opts.Destination)
+	intoDir := dstErr == nil && dstInfo.IsDir()
+	if len(opts.Sources) > 1 && !intoDir {
+		return nil, fmt.Errorf("fatal: destination '%s' is not a directory", opts.Destination)
+	}
+
+	var moves []gitMove
+	planned := map[string]bool{}
+	for _, src := range opts.Sources {
+		dst := opts.Destination
+		if intoDir {
+			dst = path.Join(dst, path.Base(src))
+		}
+		m, err := c.checkMove(w, idx, src, dst, opts.Force)
+		if err == nil && planned[dst] {
+			err = fmt.Errorf("fatal: multiple sources for the same target, source=%s, destination=%s", src, dst)
+		}
+		if err != nil {
+			if opts.SkipErrors {
+				continue
+			}
+			return nil, err
+		}
+		planned[dst] = true
+		moves = append(moves, m)
+	}
+	return moves, nil
+}
+
+func (c *GitMvCommand) checkMove(w *gogit.Worktree, idx *index.Index, src, dst string, force bool) (gitMove, error) {
+	m := gitMove{src: src, dst: dst}
+	fail := func(reason string) (gitMove, error) {
+		return m, fmt.Errorf("fatal: %s, source=%s, destination=%s", reason, src, dst)
+	}
+
+	info, err := w.Filesystem.Lstat(src)
+	if src == "" || err != nil {
+		return fail("bad source")
+	}
+	m.isDir = info.IsDir()
+	if m.isDir && (dst == src || strings.HasPrefix(dst, src+"/")) {
+		return fail("can not move directory into itself")
+	}
+
+	tracked := false
+	for _, e := range idx.Entries {
+		if e.Name != src && !(m.isDir && strings.HasPrefix(e.Name, src+"/")) {
+			continue
+		}
+		if e.Stage != 0 { // See indexFiles: real merged entries have stage 0
+			return fail("conflicted")
+		}
+		tracked = true
+	}
+	if !tracked {
+		if m.isDir {
+			return fail("source directory is empty")
+		}
+		return fail("not under version control")
+	}
+
+	if dstInfo, err := w.Filesystem.Lstat(dst); err == nil {
+		if m.isDir || dstInfo.IsDir() {
+			return fail("destination already exists")
+		}
+		if !force {
+			return fail("destination exists")
+		}
+	}
+	if _, err := w.Filesystem.Stat(path.Dir(dst)); path.Dir(dst) != "." && err != nil {
+		return fail("destination directory does not exist")
+	}
+	return m, nil
+}
+
+// move renames src to dst in the worktree and in the index. Untracked files
+// inside a moved directory go along, as they do with git.
+func (c *GitMvCommand) move(repo *gogit.Repository, w *gogit.Worktree, m gitMove) error {
+	if m.isDir {
+		var files []string
+		err := util.Walk(w.Filesystem, m.src, func(p string, info os.FileInfo, err error) error {
+			if err == nil && !info.IsDir() {
+				files = append(files, p)
+			}
+			return err
+		})
+		if err != nil {
+			return err
+		}
+		for _, f := range files {
+			if err := moveWorktreeFile(w.Filesystem, f, path.Join(m.dst, strings.TrimPrefix(f, m.src+"/"))); err != nil {
+				return err
+			}
+		}
+		if err := util.RemoveAll(w.Filesystem, m.src); err != nil {
+			return err
+		}
+	} else if err := moveWorktreeFile(w.Filesystem, m.src, m.dst); err != nil {
+		return err
+	}
+
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return err
+	}
+	entries := idx.Entries[:0]
+	for _, e := range idx.Entries {
+		if e.Name == m.dst {
+			continue // Overwritten with -f
+		}
+		switch {
+		case e.Name == m.src:
+			e.Name = m.dst
+		case m.isDir && strings.HasPrefix(e.Name, m.src+"/"):
+			e.Name = m.dst + strings.TrimPrefix(e.Name, m.src)
+		}
+		entries = append(entries, e)
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+	idx.Entries = entries
+	return repo.Storer.SetIndex(idx)
+}
+
+// moveWorktreeFile copies a file to its new path and removes the old one.
+// billy's Rename is not used: memfs would also move siblings sharing the
+// name as a prefix ("a" takes "ab" along).
+func moveWorktreeFile(fs billy.Filesystem, src, dst string) error {
+	info, err := fs.Lstat(src)
+	if err != nil {
+		return err
+	}
+	content, err := util.ReadFile(fs, src)
+	if err != nil {
+		return err
+	}
+	if err := fs.MkdirAll(path.Dir(dst), 0755); err != nil {
+		return err
+	}
+	if err := util.WriteFile(fs, dst, content, info.Mode()); err != nil {
+		return err
+	}
+	return fs.Remove(src)
+}
+
+func (c *GitMvCommand) Help() string {
+	return `📘 GIT-MV (1)                                           Git Manual
+
+ 💡 DESCRIPTION
+    ファイルやディレクトリの名前を変更（移動）し、その変更をステージします。
+    mv してから git add / git rm するのと同じ結果になります。
+    Git は「名前の変更」を記録しているわけではなく、内容の似ている
+    削除と追加を組にして「renamed」として表示します。
+
+ 📋 SYNOPSIS
+    git mv [-f] [-k] [-n] [-v] <source> <destination>
+    git mv [-f] [-k] [-n] [-v] <source>... <destination-directory>
+
+ ⚙️  COMMON OPTIONS
+    -f, --force
+        移動先に同名のファイルがあっても上書きします。
+
+    -k
+        移動できないもの（管理外のファイルなど）は飛ばして、残りを移動します。
+
+    -n, --dry-run
+        実際には移動せず、何が行われるかだけを表示します。
+
+    -v, --verbose
+        移動したファイルを表示します。
+
+ 🛠  EXAMPLES
+    1. ファイル名を変更
+       $ git mv old.txt new.txt
+       $ git status
+       renamed:    old.txt -> new.txt
+
+    2. ファイルをディレクトリに移動
+       $ git mv a.txt b.txt docs/
+
+    3. 名前を変えた後も履歴をたどる
+       $ git log --follow -- new.txt
+
+ 💡 TIPS
+    内容も大きく書き換えると、別のファイル（削除と追加）として扱われます。
+    名前の変更と内容の変更は、別々のコミットにすると履歴が追いやすくなります。
+
+ 🔗 REFERENCE
+    Full documentation: https://git-scm.com/docs/git-mv
+`
+}
@@ -0,0 +1,71 @@
+package commands
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/kurobon/gitgym/backend/internal/git"
+)
+
+func TestGitMvCommand(t *testing.T) {
+	sm := git.NewSessionManager()
+	s, _ := sm.CreateSession("test-git-mv")
+	s.InitRepo("testrepo")
+	s.CurrentDir = "/testrepo"
+	repo := s.GetRepo()
+	ctx := context.Background()
+	run := func(line string) string {
+		t.Helper()
+		name, args := git.ParseCommand(line)
+		out, err := git.Dispatch(ctx, s, name, args)
+		if err != nil {
+			t.Fatalf("%s failed: %v", line, err)
+		}
+		return out
+	}
+
+	content := "line 1\nline 2\nline 3\nline 4\n"
+	commitFile(t, repo, "old.txt", content, "Add old.txt")
+	commitFile(t, repo, "src/a.go", "package a\n", "Add src")
+
+	// Errors leave the tree alone
+	for _, line := range []string{"git mv missing.txt x.txt", "git mv old.txt src/a.go x.txt", "git mv old.txt src/a.go", "git mv src src/inner"} {
+		name, args := git.ParseCommand(line)
+		if _, err := git.Dispatch(ctx, s, name, args); err == nil {
+			t.Errorf("%s should fail", line)
+		}
+	}
+
+	if out := run("git mv -n old.txt new.txt"); out != "Checking rename of 'old.txt' to 'new.txt'\nRenaming old.txt to new.txt" {
+		t.Errorf("dry run = %q", out)
+	}
+	run("git mv old.txt new.txt")
+	if out := run("git status -s"); out != "R  old.txt -> new.txt\n" {
+		t.Errorf("status -s = %q", out)
+	}
+	if out := run("git status"); !strings.Contains(out, "renamed:    old.txt -> new.txt") || strings.Contains(out, "deleted:") {
+		t.Errorf("status = %q", out)
+	}
+	if out := run("git commit -m rename"); !strings.Contains(out, " rename old.txt => new.txt (100%)") {
+		t.Errorf("commit = %q", out)
+	}
+	if out := run("git show --name-status"); out != "R100\told.txt\tnew.txt\n" {
+		t.Errorf("show --name-status = %q", out)
+	}
+
+	// A directory moves with its files; log --follow tracks the file back
+	run("git mv src lib")
+	w, _ := repo.Worktree()
+	if _, err := w.Filesystem.Stat("lib/a.go"); err != nil {
+		t.Errorf("lib/a.go missing after moving the directory: %v", err)
+	}
+	run("git commit -m move-dir")
+	out := run("git log --oneline --follow --name-status -- new.txt")
+	if !strings.Contains(out, "rename\nR100\told.txt\tnew.txt") || !strings.Contains(out, "Add old.txt\nA\told.txt") || strings.Contains(out, "move-dir") {
+		t.Errorf("log --follow = %q", out)
+	}
+	if out := run("git log --oneline -- new.txt"); strings.Contains(out, "Add old.txt") {
+		t.Errorf("log without --follow should stop at the rename: %q", out)
+	}
+}
@@ -0,0 +1,366 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-billy/v5/util"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/kurobon/gitgym/backend/internal/git"
+)
+
+func init() {
+	git.RegisterCommand("grep", func() git.Command { return &GrepCommand{} })
+}
+
+type GrepCommand struct{}
+
+// Ensure GrepCommand implements git.Command
+var _ git.Command = (*GrepCommand)(nil)
+
+type GrepOptions struct {
+	LineNumber   bool // -n
+	IgnoreCase   bool // -i
+	WordRegexp   bool // -w
+	Count        bool // -c
+	FilesWith    bool // -l
+	FilesWithout bool // -L
+	Invert       bool // -v
+	Fixed        bool // -F
+	Cached       bool // --cached: search the index instead of the worktree
+	Patterns     []string
+	Trees        []string // Revisions to search instead of the worktree
+	Pathspecs    []string
+
+	args []string // Positional arguments before "--"
+}
+
+// grepFile is one file to search and the name it is reported under.
+type grepFile struct {
+	name    string // As printed, "<rev>:<path>" when searching a tree
+	path    string
+	content string
+}
+
+func (c *GrepCommand) Execute(ctx context.Context, s *git.Session, args []string) (string, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	opts, err := c.parseArgs(args)
+	if err != nil {
+		if err.Error() == "help requested" {
+			return c.Help(), nil
+		}
+		return "", err
+	}
+
+	repo := s.GetRepo()
+	if repo == nil {
+		return "", fmt.Errorf("fatal: not a git repository (or any of the parent directories): .git")
+	}
+
+	// Positional arguments: revisions first, then paths
+	for _, arg := range opts.args {
+		if len(opts.Pathspecs) == 0 {
+			if _, err := git.ResolveRevision(repo, arg); err == nil {
+				opts.Trees = append(opts.Trees, arg)
+				continue
+			}
+		}
+		opts.Pathspecs = append(opts.Pathspecs, arg)
+	}
+	if opts.Cached && len(opts.Trees) > 0 {
+		return "", fmt.Errorf("fatal: --cached cannot be used with a tree")
+	}
+
+	re, err := c.compile(opts)
+	if err != nil {
+		return "", err
+	}
+	files, err := c.collectFiles(repo, opts)
+	if err != nil {
+		return "", err
+	}
+
+	var lines []string
+	for _, f := range files {
+		lines = append(lines, c.search(f, re, opts)...)
+	}
+	// Like git, no match is not an error worth a message
+	return strings.Join(lines, "\n"), nil
+}
+
+func (c *GrepCommand) parseArgs(args []string) (*GrepOptions, error) {
+	opts := &GrepOptions{}
+	cmdArgs := args[1:]
+	flags := map[byte]*bool{
+		'n': &opts.LineNumber, 'i': &opts.IgnoreCase, 'w': &opts.WordRegexp, 'c': &opts.Count,
+		'l': &opts.FilesWith, 'L': &opts.FilesWithout, 'v': &opts.Invert, 'F': &opts.Fixed,
+		'E': new(bool), 'G': new(bool), // Patterns are always regular expressions here
+	}
+
+	for i := 0; i < len(cmdArgs); i++ {
+		arg := cmdArgs[i]
+		switch arg {
+		case "-h", "--help":
+			return nil, fmt.Errorf("help requested")
+		case "--line-number":
+			opts.LineNumber = true
+		case "--ignore-case":
+			opts.IgnoreCase = true
+		case "--word-regexp":
+			opts.WordRegexp = true
+		case "--count":
+			opts.Count = true
+		case "--files-with-matches", "--name-only":
+			opts.FilesWith = true
+		case "--files-without-match":
+			opts.FilesWithout = true
+		case "--invert-match":
+			opts.Invert = true
+		case "--fixed-strings":
+			opts.Fixed = true
+		case "--extended-regexp", "--basic-regexp":
+		case "--cached":
+			opts.Cached = true
+		case "-e":
+			if i+1 >= len(cmdArgs) {
+				return nil, fmt.Errorf("error: switch `e' requires a value")
+			}
+			i++
+			opts.Patterns = append(opts.Patterns, cmdArgs[i])
+		case "--":
+			opts.Pathspecs = append(opts.Pathspecs, cmdArgs[i+1:]...)
+			i = len(cmdArgs)
+		default:
+			if strings.HasPrefix(arg, "-") && len(arg) > 1 {
+				// Combined short flags such as -in
+				for j := 1; j < len(arg); j++ {
+					flag, ok := flags[arg[j]]
+					if !ok {
+						return nil, fmt.Errorf("error: unknown switch `%c'", arg[j])
+					}
+					*flag = true
+				}
+				continue
+			}
+			if len(opts.Patterns) == 0 {
+				opts.Patterns = append(opts.Patterns, arg)
+				continue
+			}
+			opts.args = append(opts.args, arg)
+		}
+	}
+
+	if len(opts.Patterns) == 0 {
+		return nil, fmt.Errorf("fatal: no pattern given")
+	}
+	return opts, nil
+}
+
+// compile joins the patterns into one expression matching any of them.
+func (c *GrepCommand) compile(opts *GrepOptions) (*regexp.Regexp, error) {
+	var alternatives []string
+	for _, p := range opts.Patterns {
+		if opts.Fixed {
+			p = regexp.QuoteMeta(p)
+		}
+		alternatives = append(alternatives, "(?:"+p+")")
+	}
+	expr := strings.Join(alternatives, "|")
+	if opts.WordRegexp {
+		expr = `\b(?:` + expr + `)\b`
+	}
+	if opts.IgnoreCase {
+		expr = "(?i)" + expr
+	}
+	re, err := regexp.Compile(expr)
+	if err != nil {
+		return nil, fmt.Errorf("fatal: invalid regular expression: %s", strings.Join(opts.Patterns, " "))
+	}
+	return re, nil
+}
+
+// collectFiles reads the files to search: the given trees, the index with
+// --cached, or the tracked files of the worktree.
+func (c *GrepCommand) collectFiles(repo *gogit.Repository, opts *GrepOptions) ([]grepFile, error) {
+	var files []grepFile
+	matches := func(name string) bool {
+		if pathMatches(name, opts.Pathspecs) {
+			return true
+		}
+		for _, p := range opts.Pathspecs {
+			if ok, _ := path.Match(p, name); ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(opts.Trees) > 0 {
+		for _, rev := range opts.Trees {
+			hash, err := git.ResolveRevision(repo, rev)
+			if err != nil {
+				return nil, err
+			}
+			commit, err := repo.CommitObject(*hash)
+			if err != nil {
+				return nil, err
+			}
+			tree, err := commit.Tree()
+			if err != nil {
+				return nil, err
+			}
+			err = tree.Files().ForEach(func(f *object.File) error {
+				if !matches(f.Name) {
+					return nil
+				}
+				content, err := f.Contents()
+				if err != nil {
+					return err
+				}
+				files = append(files, grepFile{name: rev + ":" + f.Name, path: f.Name, content: content})
+				return nil
+			})
+			if err != nil {
+				return nil, err
+			}
+		}
+		return files, nil
+	}
+
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return nil, err
+	}
+	w, err := repo.Worktree()
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]bool{}
+	for _, e := range idx.Entries {
+		if seen[e.Name] || !matches(e.Name) {
+			continue
+		}
+		seen[e.Name] = true
+		var content []byte
+		if opts.Cached {
+			blob, err := repo.BlobObject(e.Hash)
+			if err != nil {
+				continue
+			}
+			r, err := blob.Reader()
+			if err != nil {
+				continue
+			}
+			content, err = io.ReadAll(r)
+			r.Close()
+			if err != nil {
+				continue
+			}
+		} else if content, err = util.ReadFile(w.Filesystem, e.Name); err != nil {
+			continue // Deleted in the worktree
+		}
+		files = append(files, grepFile{name: e.Name, path: e.Name, content: string(content)})
+	}
+	sort.Slice(files, func(i, j int) bool { return files[i].name < files[j].name })
+	return files, nil
+}
+
+// search returns the output lines for one file.
+func (c *GrepCommand) search(f grepFile, re *regexp.Regexp, opts *GrepOptions) []string {
+	var hits []string
+	count := 0
+	for i, line := range strings.Split(strings.TrimSuffix(f.content, "\n"), "\n") {
+		if re.MatchString(line) == opts.Invert {
+			continue
+		}
+		count++
+		if opts.LineNumber {
+			hits = append(hits, fmt.Sprintf("%s:%d:%s", f.name, i+1, line))
+		} else {
+			hits = append(hits, fmt.Sprintf("%s:%s", f.name, line))
+		}
+	}
+
+	switch {
+	case opts.FilesWithout:
+		if count == 0 {
+			return []string{f.name}
+		}
+		return nil
+	case count == 0:
+		return nil
+	case opts.FilesWith:
+		return []string{f.name}
+	case opts.Count:
+		return []string{fmt.Sprintf("%s:%d", f.name, count)}
+	case strings.Contains(f.content, "\x00"):
+		return []string{fmt.Sprintf("Binary file %s matches", f.name)}
+	}
+	return hits
+}
+
+func (c *GrepCommand) Help() string {
+	return `📘 GIT-GREP (1)                                         Git Manual
+
+ 💡 DESCRIPTION
+    Git で管理しているファイルの中から、パターン（正規表現）に一致する行を探します。
+    作業ツリーのほか、ステージ済みの内容（--cached）や
+    過去のコミット・ブランチの中身も検索できます。
+
+ 📋 SYNOPSIS
+    git grep [-n] [-i] [-w] [-v] [-c] [-l | -L] [-F] [-e] <pattern>
+             [--cached | <tree-ish>...] [[--] <pathspec>...]
+
+ ⚙️  COMMON OPTIONS
+    -n, --line-number
+        行番号も表示します。
+
+    -i, --ignore-case
+        大文字と小文字を区別しません。
+
+    -w, --word-regexp
+        単語全体として一致するものだけを探します。
+
+    -v, --invert-match
+        一致しない行を表示します。
+
+    -c, --count
+        ファイルごとの一致した行数を表示します。
+
+    -l, --files-with-matches / -L, --files-without-match
+        一致する / しないファイル名だけを表示します。
+
+    -F, --fixed-strings
+        パターンを正規表現ではなく、ただの文字列として扱います。
+
+    -e <pattern>
+        パターンを指定します。複数指定するといずれかに一致する行を探します。
+
+    --cached
+        作業ツリーではなく、ステージ（インデックス）の内容を検索します。
+
+ 🛠  EXAMPLES
+    1. TODO を行番号付きで探す
+       $ git grep -n TODO
+
+    2. 過去のリリースの中を検索
+       $ git grep -i "fixme" v1.0
+
+    3. src ディレクトリの .go ファイルだけを検索
+       $ git grep -w main -- 'src/*.go'
+
+ 💡 TIPS
+    通常の grep と違い、Git の管理外のファイル（.gitignore 対象など）は検索しません。
+
+ 🔗 REFERENCE
+    Full documentation: https://git-scm.com/docs/git-grep
+`
+}
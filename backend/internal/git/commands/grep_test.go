@@ -0,0 +1,55 @@
+package commands
+
+import (
+	"context"
+	"testing"
+
+	"github.com/kurobon/gitgym/backend/internal/git"
+)
+
+func TestGrepCommand(t *testing.T) {
+	sm := git.NewSessionManager()
+	s, _ := sm.CreateSession("test-grep")
+	s.InitRepo("testrepo")
+	s.CurrentDir = "/testrepo"
+	repo := s.GetRepo()
+	ctx := context.Background()
+	grep := func(args ...string) string {
+		t.Helper()
+		out, err := git.Dispatch(ctx, s, "grep", append([]string{"grep"}, args...))
+		if err != nil {
+			t.Fatalf("git grep %v failed: %v", args, err)
+		}
+		return out
+	}
+
+	commitFile(t, repo, "a.txt", "hello world\nTODO: fix\nhelloworld\n", "Add a")
+	commitFile(t, repo, "src/b.go", "// todo later\npackage b\n", "Add b")
+
+	// Worktree edits are searched; the commit still has the old text
+	w, _ := repo.Worktree()
+	f, _ := w.Filesystem.Create("a.txt")
+	f.Write([]byte("hello world\nTODO: fix\nhelloworld\nTODO: more\n"))
+	f.Close()
+
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"-n", "TODO"}, "a.txt:2:TODO: fix\na.txt:4:TODO: more"},
+		{[]string{"-i", "todo", "--", "src"}, "src/b.go:// todo later"},
+		{[]string{"-w", "hello"}, "a.txt:hello world"},
+		{[]string{"-c", "-i", "todo"}, "a.txt:2\nsrc/b.go:1"},
+		{[]string{"-l", "package"}, "src/b.go"},
+		{[]string{"-L", "package"}, "a.txt"},
+		{[]string{"--cached", "-c", "TODO"}, "a.txt:1"},
+		{[]string{"-n", "TODO", "HEAD~1"}, "HEAD~1:a.txt:2:TODO: fix"},
+		{[]string{"-e", "fix", "-e", "package"}, "a.txt:TODO: fix\nsrc/b.go:package b"},
+		{[]string{"nothing-matches"}, ""},
+	}
+	for _, tt := range tests {
+		if got := grep(tt.args...); got != tt.want {
+			t.Errorf("git grep %v = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
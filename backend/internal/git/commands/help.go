@@ -44,16 +44,20 @@ var commandMetadata = map[string]cmdMeta{
 	// Work
 	"add":     {CatWork, "Add file contents to the index"},
 	"clean":   {CatWork, "Remove untracked files from the working tree"},
+	"mv":      {CatWork, "Move or rename a file, a directory, or a symlink"},
 	"restore": {CatWork, "Restore working tree files"},
 	"rm":      {CatWork, "Remove files from the working tree and from the index"},
 
 	// History
-	"blame":  {CatHistory, "Show what revision and author last modified each line of a file"},
-	"diff":   {CatHistory, "Show changes between commits, commit and working tree, etc"},
-	"log":    {CatHistory, "Show commit logs"},
-	"reflog": {CatHistory, "Manage reflog information"},
-	"show":   {CatHistory, "Show various types of objects"},
-	"status": {CatHistory, "Show the working tree status"},
+	"blame":    {CatHistory, "Show what revision and author last modified each line of a file"},
+	"describe": {CatHistory, "Give an object a human readable name based on an available ref"},
+	"diff":     {CatHistory, "Show changes between commits, commit and working tree, etc"},
+	"grep":     {CatHistory, "Print lines matching a pattern"},
+	"log":      {CatHistory, "Show commit logs"},
+	"reflog":   {CatHistory, "Manage reflog information"},
+	"shortlog": {CatHistory, "Summarize 'git log' output"},
+	"show":     {CatHistory, "Show various types of objects"},
+	"status":   {CatHistory, "Show the working tree status"},
 
 	// Grow
 	"branch":      {CatGrow, "List, create, or delete branches"},
@@ -61,17 +65,21 @@ var commandMetadata = map[string]cmdMeta{
 	"cherry-pick": {CatGrow, "Apply the changes introduced by some existing commits"},
 	"commit":      {CatGrow, "Record changes to the repository"},
 	"merge":       {CatGrow, "Join two or more development histories together"},
+	"notes":       {CatGrow, "Add or inspect object notes"},
 	"rebase":      {CatGrow, "Reapply commits on top of another base tip"},
 	"reset":       {CatGrow, "Reset current HEAD to the specified state"},
 	"revert":      {CatGrow, "Revert some existing commits"},
+	"show-branch": {CatGrow, "Show branches and their commits"},
 	"stash":       {CatGrow, "Stash the changes in a dirty working directory away"},
 	"switch":      {CatGrow, "Switch branches"},
 	"tag":         {CatGrow, "Create, list, delete or verify a tag object"},
 
 	// Collab
+	"bundle": {CatCollab, "Move objects and refs by archive"},
 	"fetch":  {CatCollab, "Download objects and refs from another repository"},
 	"pull":   {CatCollab, "Fetch from and integrate with another repository or a local branch"},
 	"push":   {CatCollab, "Update remote refs along with associated objects (simulated)"},
+	"pr":     {CatCollab, "Review pull requests on shared remotes"},
 	"remote": {CatCollab, "Manage set of tracked repositories"},
 
 	// Shell
@@ -101,6 +109,10 @@ func (c *HelpCommand) Execute(ctx context.Context, s *git.Session, args []string
 	if len(args) > 1 {
 		subcmd := args[1]
 		helpStr, err := git.GetCommandHelp(subcmd)
+		if err != nil {
+			// git mv is registered apart from shell commands of the same name
+			helpStr, err = git.GetCommandHelp("git-" + subcmd)
+		}
 		if err != nil {
 			// Fallback if not found in metadata or registry
 			if meta, ok := commandMetadata[subcmd]; ok {
@@ -23,11 +23,15 @@ type LogCommand struct{}
 var _ git.Command = (*LogCommand)(nil)
 
 type LogOptions struct {
-	Oneline bool
-	Graph   bool
-	Limit   int
-	Author  string
-	Args    []string // Revisions or paths
+	Oneline    bool
+	Graph      bool
+	Limit      int
+	Author     string
+	Follow     bool                   // Keep following the one path across renames
+	NameStatus bool                   // List the changed files of each commit
+	NotesRef   plumbing.ReferenceName // Notes shown after each message; "" with --no-notes
+	Args       []string               // Revisions or paths
+	Paths      []string               // Paths after "--"
 }
 
 func (c *LogCommand) Execute(ctx context.Context, s *git.Session, args []string) (string, error) {
@@ -48,7 +52,7 @@ func (c *LogCommand) Execute(ctx context.Context, s *git.Session, args []string)
 }
 
 func (c *LogCommand) parseArgs(args []string) (*LogOptions, error) {
-	opts := &LogOptions{}
+	opts := &LogOptions{NotesRef: git.DefaultNotesRef}
 	cmdArgs := args[1:]
 	for i := 0; i < len(cmdArgs); i++ {
 		arg := cmdArgs[i]
@@ -84,6 +88,19 @@ func (c *LogCommand) parseArgs(args []string) (*LogOptions, error) {
 			}
 		case strings.HasPrefix(arg, "--author="):
 			opts.Author = strings.TrimPrefix(arg, "--author=")
+		case arg == "--follow":
+			opts.Follow = true
+		case arg == "--name-status":
+			opts.NameStatus = true
+		case arg == "--notes":
+			opts.NotesRef = git.DefaultNotesRef
+		case strings.HasPrefix(arg, "--notes="):
+			opts.NotesRef = notesRefName(strings.TrimPrefix(arg, "--notes="))
+		case arg == "--no-notes":
+			opts.NotesRef = ""
+		case arg == "--":
+			opts.Paths = append(opts.Paths, cmdArgs[i+1:]...)
+			return opts, nil
 		default:
 			opts.Args = append(opts.Args, arg)
 		}
@@ -91,6 +108,29 @@ func (c *LogCommand) parseArgs(args []string) (*LogOptions, error) {
 	return opts, nil
 }
 
+// logIter walks history from logOpts.From (HEAD by default). In a shallow
+// repository the walk ends at the grafted commits instead of failing on
+// their missing parents.
+func (c *LogCommand) logIter(repo *gogit.Repository, logOpts *gogit.LogOptions) (object.CommitIter, error) {
+	grafted := git.GraftedParents(repo)
+	if len(grafted) == 0 {
+		return repo.Log(logOpts)
+	}
+	from := logOpts.From
+	if from.IsZero() {
+		head, err := repo.Head()
+		if err != nil {
+			return nil, err
+		}
+		from = head.Hash()
+	}
+	commit, err := repo.CommitObject(from)
+	if err != nil {
+		return nil, err
+	}
+	return object.NewCommitPreorderIter(commit, nil, grafted), nil
+}
+
 func (c *LogCommand) executeLog(_ *git.Session, repo *gogit.Repository, opts *LogOptions) (string, error) {
 	// executeLog performs the log operation with optional graph rendering.
 	// This implementation attempts a simplified ASCII graph.
@@ -99,21 +139,32 @@ func (c *LogCommand) executeLog(_ *git.Session, repo *gogit.Repository, opts *Lo
 		All: false,
 	}
 
-	// Handle arguments (revisions)
-	if len(opts.Args) > 0 {
-		// Try to resolve the first argument as a revision
-		hash, err := repo.ResolveRevision(plumbing.Revision(opts.Args[0]))
-		if err == nil {
-			logOpts.From = *hash
+	// Handle arguments: the first one that resolves is the revision, the
+	// rest are paths limiting the log to commits touching them
+	paths := opts.Paths
+	for _, arg := range opts.Args {
+		if logOpts.From.IsZero() {
+			if hash, err := repo.ResolveRevision(plumbing.Revision(arg)); err == nil {
+				logOpts.From = *hash
+				continue
+			}
 		}
-		// If multiple args or complex pathspecs are needed, we'd need more logic.
-		// For now, support "git log <branch>" simplistic usage.
+		paths = append(paths, arg)
+	}
+	if opts.Follow && len(paths) != 1 {
+		return "", fmt.Errorf("fatal: --follow requires exactly one pathspec")
 	}
 
-	cIter, err := repo.Log(logOpts)
+	cIter, err := c.logIter(repo, logOpts)
 	if err != nil {
 		return "", err
 	}
+	var notes map[plumbing.Hash]plumbing.Hash
+	if opts.NotesRef != "" {
+		if notes, err = git.ReadNotes(repo, opts.NotesRef); err != nil {
+			return "", err
+		}
+	}
 
 	var sb strings.Builder
 
@@ -123,6 +174,37 @@ func (c *LogCommand) executeLog(_ *git.Session, repo *gogit.Repository, opts *Lo
 	var count int
 
 	err = cIter.ForEach(func(c *object.Commit) error {
+		// Changed files, limited to the paths when given
+		var changes []fileChange
+		if len(paths) > 0 || opts.NameStatus {
+			all, err := commitChanges(c)
+			if err != nil {
+				return err
+			}
+			for _, change := range all {
+				if pathMatches(change.To, paths) || change.From != "" && pathMatches(change.From, paths) {
+					changes = append(changes, change)
+				}
+			}
+			if len(paths) > 0 && len(changes) == 0 {
+				return nil
+			}
+			// --follow: older commits know the file by its old name
+			if opts.Follow {
+				for _, change := range changes {
+					if change.From != "" && pathMatches(change.To, paths) {
+						paths = []string{change.From}
+					}
+				}
+			}
+		}
+		nameStatus := ""
+		if opts.NameStatus {
+			for _, change := range changes {
+				nameStatus += change.String() + "\n"
+			}
+		}
+
 		var graphLine string
 		hash := c.Hash.String()
 
@@ -228,6 +310,7 @@ func (c *LogCommand) executeLog(_ *git.Session, repo *gogit.Repository, opts *Lo
 				prefix = graphLine + " "
 			}
 			sb.WriteString(fmt.Sprintf("%s%s %s\n", prefix, hash[:7], msgFirstCheck))
+			sb.WriteString(nameStatus)
 		} else {
 			// Multiline graph is hard to render correctly without line-by-line tracking.
 			// Fallback: Just show graph on first line, indent others.
@@ -252,6 +335,12 @@ func (c *LogCommand) executeLog(_ *git.Session, repo *gogit.Repository, opts *Lo
 				indentStr,
 				strings.TrimSpace(c.Message),
 			))
+			if note := commitNote(repo, notes, opts.NotesRef, c.Hash); note != "" {
+				sb.WriteString(note + "\n")
+			}
+			if nameStatus != "" {
+				sb.WriteString(nameStatus + "\n")
+			}
 		}
 
 		count++
@@ -274,7 +363,7 @@ func (c *LogCommand) Help() string {
     ・プロジェクトの歴史を遡って確認する
 
  📋 SYNOPSIS
-    git log [options] [<revision>]
+    git log [options] [<revision>] [[--] <path>...]
 
  ⚙️  COMMON OPTIONS
     --oneline
@@ -290,6 +379,17 @@ func (c *LogCommand) Help() string {
     --author <pattern>
         指定したパターンに一致する作者のコミットのみ表示します。
 
+    --name-status
+        各コミットで変更されたファイル名と状態（A/M/D/R）も表示します。
+
+    --follow
+        1つのファイルの履歴を、名前が変わる前までさかのぼって表示します。
+        （git mv で移動したファイルの昔の履歴も見られます）
+
+    --notes[=<ref>] / --no-notes
+        git notes で付けたノートを表示します（既定では refs/notes/commits を表示）。
+        --no-notes でノートを表示しません。
+
  🛠  EXAMPLES
     1. 最新の5件を表示
        $ git log -n 5
@@ -300,6 +400,9 @@ func (c *LogCommand) Help() string {
     3. グラフ付きで表示
        $ git log --oneline --graph
 
+    4. 名前を変えたファイルの履歴をすべて表示
+       $ git log --follow --name-status -- new-name.txt
+
  🔗 REFERENCE
     Full documentation: https://git-scm.com/docs/git-log
 `
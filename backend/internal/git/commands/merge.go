@@ -213,10 +213,11 @@ func (c *MergeCommand) performMerge(s *git.Session, repo *gogit.Repository, mCtx
 
 	s.UpdateOrigHead()
 
+	sig := s.Signature()
 	newCommitHash, err := w.Commit(msg, &gogit.CommitOptions{
 		Parents:           parents,
-		Author:            git.GetDefaultSignature(),
-		Committer:         git.GetDefaultSignature(),
+		Author:            sig,
+		Committer:         sig,
 		AllowEmptyCommits: true, // Merge commits should always be created even without tree changes
 	})
 	if err != nil {
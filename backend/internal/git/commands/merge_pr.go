@@ -2,9 +2,11 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
 	"strconv"
+	"strings"
 	"time"
 
 	gogit "github.com/go-git/go-git/v5"
@@ -17,9 +19,21 @@ func init() {
 	git.RegisterCommand("merge-pr", func() git.Command { return &MergePRCommand{} })
 }
 
+// Pull request merge strategies (mirroring the hosted "Merge pull request" button)
+const (
+	MergeStrategyMerge  = "merge"   // Merge commit with two parents
+	MergeStrategySquash = "squash"  // One new commit on top of the base branch
+	MergeStrategyRebase = "rebase"  // Replay each PR commit on top of the base branch
+	MergeStrategyFFOnly = "ff-only" // Move the base branch to the head, no new commits
+)
+
+var mergeBotSignature = object.Signature{Name: "GitGym Merge Bot", Email: "bot@gitgym.com"}
+
 type MergePRCommand struct {
 	prID       int
 	remoteName string
+	strategy   string
+	mergedBy   *object.Signature // Optional: the user pressing "merge" (defaults to the bot)
 
 	pr     *git.PullRequest
 	repo   *gogit.Repository
@@ -41,22 +55,89 @@ func (c *MergePRCommand) Execute(ctx context.Context, s *git.Session, args []str
 }
 
 func (c *MergePRCommand) parseArgs(args []string) error {
-	if len(args) < 3 {
-		return fmt.Errorf("usage: merge-pr <pr-id> <remote-name>")
+	c.strategy = MergeStrategyMerge
+
+	var positional []string
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--merge":
+			c.strategy = MergeStrategyMerge
+		case arg == "--squash":
+			c.strategy = MergeStrategySquash
+		case arg == "--rebase":
+			c.strategy = MergeStrategyRebase
+		case arg == "--ff-only":
+			c.strategy = MergeStrategyFFOnly
+		case arg == "--strategy" || arg == "-s":
+			if i+1 >= len(args) {
+				return fmt.Errorf("option '%s' requires a value", arg)
+			}
+			i++
+			c.strategy = args[i]
+		case strings.HasPrefix(arg, "--strategy="):
+			c.strategy = strings.TrimPrefix(arg, "--strategy=")
+		case arg == "--author":
+			if i+1 >= len(args) {
+				return fmt.Errorf("option '--author' requires a value")
+			}
+			i++
+			sig, err := parseIdentity(args[i])
+			if err != nil {
+				return err
+			}
+			c.mergedBy = sig
+		case strings.HasPrefix(arg, "--author="):
+			sig, err := parseIdentity(strings.TrimPrefix(arg, "--author="))
+			if err != nil {
+				return err
+			}
+			c.mergedBy = sig
+		default:
+			positional = append(positional, arg)
+		}
+	}
+
+	if len(positional) < 2 {
+		return fmt.Errorf("usage: merge-pr <pr-id> <remote-name> [--strategy=merge|squash|rebase|ff-only]")
 	}
 
-	prID, err := strconv.Atoi(args[1])
+	switch c.strategy {
+	case MergeStrategyMerge, MergeStrategySquash, MergeStrategyRebase, MergeStrategyFFOnly:
+	default:
+		return fmt.Errorf("unknown merge strategy %q (expected merge, squash, rebase or ff-only)", c.strategy)
+	}
+
+	prID, err := strconv.Atoi(positional[0])
 	if err != nil {
-		return fmt.Errorf("invalid PR ID %q: %w", args[1], err)
+		return fmt.Errorf("invalid PR ID %q: %w", positional[0], err)
 	}
 
 	c.prID = prID
-	c.remoteName = args[2]
+	c.remoteName = positional[1]
 	return nil
 }
 
+// parseIdentity parses "Name <email>" into a signature; callers stamp its time.
+func parseIdentity(s string) (*object.Signature, error) {
+	open := strings.Index(s, "<")
+	closing := strings.LastIndex(s, ">")
+	if open < 0 || closing < open {
+		return nil, fmt.Errorf("fatal: --author '%s' is not 'Name <email>'", s)
+	}
+	name := strings.TrimSpace(s[:open])
+	email := strings.TrimSpace(s[open+1 : closing])
+	if name == "" || email == "" {
+		return nil, fmt.Errorf("fatal: --author '%s' is not 'Name <email>'", s)
+	}
+	return &object.Signature{Name: name, Email: email}, nil
+}
+
 func (c *MergePRCommand) resolveContext(_ context.Context) error {
 	sm := c.engine.Manager
+	if sm == nil {
+		return fmt.Errorf("pull requests are not available in this session")
+	}
 	sm.RLock()
 	defer sm.RUnlock()
 
@@ -99,11 +180,16 @@ func (c *MergePRCommand) resolveContext(_ context.Context) error {
 }
 
 func (c *MergePRCommand) performAction(_ context.Context) (string, error) {
-	log.Printf("MergePRCommand: Merging PR #%d (%s -> %s) on remote %q", c.prID, c.pr.HeadRef, c.pr.BaseRef, c.remoteName)
+	log.Printf("MergePRCommand: Merging PR #%d (%s -> %s) on remote %q using %s", c.prID, c.pr.HeadRef, c.pr.BaseRef, c.remoteName, c.strategy)
 
 	// Resolve references
 	baseRefName := plumbing.ReferenceName("refs/heads/" + c.pr.BaseRef)
-	headRefName := plumbing.ReferenceName("refs/heads/" + c.pr.HeadRef)
+	headRefName := git.PullRequestHeadRef(c.pr)
+
+	// Cross-repository PRs merge the fork branch as it is right now
+	if err := git.SyncPullRequestHead(c.engine.Manager, c.pr); err != nil {
+		return "", err
+	}
 
 	log.Printf("MergePRCommand: Resolving base ref: %s", baseRefName)
 	baseRef, err := c.repo.Reference(baseRefName, true)
@@ -132,52 +218,302 @@ func (c *MergePRCommand) performAction(_ context.Context) (string, error) {
 		return "", fmt.Errorf("failed to retrieve source commit %s: %w", headRef.Hash(), err)
 	}
 
-	// 3. Create Merge Commit using "Theirs" tree (Head's tree snapshot)
-	mergeCommit := &object.Commit{
-		Author: object.Signature{
-			Name:  "GitGym Merge Bot",
-			Email: "bot@gitgym.com",
-			When:  time.Now(),
-		},
-		Committer: object.Signature{
-			Name:  "GitGym Merge Bot",
-			Email: "bot@gitgym.com",
-			When:  time.Now(),
-		},
-		Message:  fmt.Sprintf("Merge pull request #%d from %s\n\n%s", c.prID, c.pr.HeadRef, c.pr.Title),
-		TreeHash: headCommit.TreeHash,
-		ParentHashes: []plumbing.Hash{
-			baseCommit.Hash,
-			headCommit.Hash,
-		},
-	}
-
-	obj := c.repo.Storer.NewEncodedObject()
-	if err := mergeCommit.Encode(obj); err != nil {
-		return "", fmt.Errorf("failed to encode merge commit: %w", err)
-	}
-
-	newHash, err := c.repo.Storer.SetEncodedObject(obj)
+	// 3. Collect the commits the PR introduces
+	commits, err := git.CommitsBetween(c.repo, baseCommit.Hash, headCommit.Hash)
 	if err != nil {
-		return "", fmt.Errorf("failed to store merge commit: %w", err)
+		return "", fmt.Errorf("failed to list pull request commits: %w", err)
+	}
+	if len(commits) == 0 {
+		return "", fmt.Errorf("pull request #%d has no new commits to merge into %s", c.prID, c.pr.BaseRef)
+	}
+
+	// Reviews count on every base branch; a protection rule only adds required approvals
+	rule := c.engine.Manager.BranchProtectionFor(c.repo, baseRefName)
+	required := 0
+	if rule != nil {
+		required = rule.RequiredApprovals
+	}
+	if err := c.checkReviews(required); err != nil {
+		return "", err
+	}
+	if rule != nil {
+		if rule.RequireLinearHistory && c.strategy == MergeStrategyMerge {
+			return "", fmt.Errorf("merge commits are not allowed on %s (linear history required); use squash or rebase", c.pr.BaseRef)
+		}
+		if err := c.checkStatuses(rule, headCommit.Hash); err != nil {
+			return "", err
+		}
+	}
+
+	// 4-6. Build the new base tip, move the branch and close the PR in one step
+	c.engine.Manager.Lock()
+	newHash, err := c.merge(baseRefName, baseCommit, headCommit, commits)
+	c.engine.Manager.Unlock()
+	if err != nil {
+		return "", err
+	}
+
+	// CI runs on the new base tip, then other open PRs compare against it
+	c.engine.Manager.RunStatusChecks(c.repo, newHash)
+	c.engine.Manager.Bots.NotifyPush(c.repo, baseRefName)
+	git.RefreshPullRequests(c.engine.Manager, c.repo)
+
+	log.Printf("MergePRCommand: PR #%d merged successfully", c.prID)
+	return fmt.Sprintf("Successfully merged PR #%d into %s (%s, %s)", c.prID, c.pr.BaseRef, c.strategy, newHash.String()[:7]), nil
+}
+
+// merge re-checks that the pull request is still open and the base branch has
+// not moved, then records the merge. Caller holds the session manager lock, so
+// a concurrent merge of the same pull request sees it MERGED.
+func (c *MergePRCommand) merge(baseRefName plumbing.ReferenceName, baseCommit, headCommit *object.Commit, commits []*object.Commit) (plumbing.Hash, error) {
+	if c.pr.State != "OPEN" {
+		return plumbing.ZeroHash, fmt.Errorf("pull request #%d is not OPEN (current state: %s)", c.prID, c.pr.State)
+	}
+	baseRef, err := c.repo.Reference(baseRefName, true)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("base branch %q not found in remote: %w", c.pr.BaseRef, err)
+	}
+	if baseRef.Hash() != baseCommit.Hash {
+		return plumbing.ZeroHash, fmt.Errorf("base branch %q moved while merging pull request #%d; try again", c.pr.BaseRef, c.prID)
+	}
+
+	var newHash plumbing.Hash
+	switch c.strategy {
+	case MergeStrategyMerge:
+		newHash, err = c.createMergeCommit(baseCommit, headCommit)
+	case MergeStrategySquash:
+		newHash, err = c.createSquashCommit(baseCommit, headCommit, commits)
+	case MergeStrategyRebase:
+		newHash, err = c.rebaseCommits(baseCommit, commits)
+	case MergeStrategyFFOnly:
+		newHash, err = c.fastForward(baseCommit, headCommit)
+	}
+	if err != nil {
+		return plumbing.ZeroHash, err
 	}
 
-	// 4. Update Remote Reference
 	log.Printf("MergePRCommand: Updating %s to %s", baseRefName, newHash)
 	newRef := plumbing.NewHashReference(baseRefName, newHash)
 	if err := c.repo.Storer.SetReference(newRef); err != nil {
-		return "", fmt.Errorf("failed to update remote branch %q: %w", c.pr.BaseRef, err)
+		return plumbing.ZeroHash, fmt.Errorf("failed to update remote branch %q: %w", c.pr.BaseRef, err)
 	}
 
-	// 5. Update PR State
-	c.engine.Manager.Lock()
 	c.pr.State = "MERGED"
-	c.engine.Manager.Unlock()
+	c.pr.MergeStrategy = c.strategy
+	c.pr.MergeCommit = newHash.String()
+	return newHash, nil
+}
 
-	log.Printf("MergePRCommand: PR #%d merged successfully", c.prID)
-	return fmt.Sprintf("Successfully merged PR #%d into %s", c.prID, c.pr.BaseRef), nil
+// checkReviews refuses a pull request with outstanding change requests or
+// fewer than required approving reviews.
+func (c *MergePRCommand) checkReviews(required int) error {
+	approvedBy, changesRequestedBy := c.engine.Manager.ReviewSummary(c.pr)
+	if len(changesRequestedBy) > 0 {
+		return fmt.Errorf("pull request #%d has changes requested by %s", c.prID, strings.Join(changesRequestedBy, ", "))
+	}
+	if len(approvedBy) < required {
+		return fmt.Errorf("pull request #%d needs %d approving review(s) before merging into %s (has %d)", c.prID, required, c.pr.BaseRef, len(approvedBy))
+	}
+	return nil
+}
+
+// checkStatuses enforces the status checks required by the base branch's protection rule.
+func (c *MergePRCommand) checkStatuses(rule *git.BranchProtection, head plumbing.Hash) error {
+	if len(rule.RequiredStatusChecks) == 0 {
+		return nil
+	}
+	sm := c.engine.Manager
+	sm.RunMissingStatusChecks(c.repo, head)
+
+	reported := make(map[string]*git.CommitStatus)
+	for _, status := range sm.GetCommitStatuses(c.repo, head) {
+		reported[status.Context] = status
+	}
+	for _, context := range rule.RequiredStatusChecks {
+		status, ok := reported[context]
+		switch {
+		case !ok:
+			return fmt.Errorf("pull request #%d is waiting for required status check %q to be reported", c.prID, context)
+		case status.State == git.StatusPending:
+			return fmt.Errorf("required status check %q is still pending on pull request #%d", context, c.prID)
+		case status.State != git.StatusSuccess:
+			return fmt.Errorf("required status check %q failed on pull request #%d: %s", context, c.prID, status.Description)
+		}
+	}
+	return nil
+}
+
+// mergeTree three-way merges head into base at their merge base.
+func (c *MergePRCommand) mergeTree(base, head *object.Commit) (plumbing.Hash, error) {
+	var ancestor *object.Commit
+	if bases, err := base.MergeBase(head); err == nil && len(bases) > 0 {
+		ancestor = bases[0]
+	}
+	tree, err := git.MergeTrees(c.repo.Storer, ancestor, base, head)
+	if err != nil {
+		var conflictErr *git.MergeConflictError
+		if errors.As(err, &conflictErr) {
+			return plumbing.ZeroHash, fmt.Errorf("pull request #%d has conflicts that must be resolved: %s", c.prID, strings.Join(conflictErr.Paths, ", "))
+		}
+		return plumbing.ZeroHash, err
+	}
+	return tree, nil
+}
+
+// createMergeCommit records a two-parent merge commit authored by the merging user.
+func (c *MergePRCommand) createMergeCommit(base, head *object.Commit) (plumbing.Hash, error) {
+	tree, err := c.mergeTree(base, head)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	when := c.engine.Now()
+	return git.StoreObject(c.repo, &object.Commit{
+		Author:       c.merger(when),
+		Committer:    c.committer(when),
+		Message:      fmt.Sprintf("Merge pull request #%d from %s\n\n%s", c.prID, headLabel(c.pr), c.pr.Title),
+		TreeHash:     tree,
+		ParentHashes: []plumbing.Hash{base.Hash, head.Hash},
+	})
+}
+
+// createSquashCommit records a single commit on top of base containing every PR change.
+// The PR's author is kept and other contributors get Co-authored-by trailers.
+func (c *MergePRCommand) createSquashCommit(base, head *object.Commit, commits []*object.Commit) (plumbing.Hash, error) {
+	tree, err := c.mergeTree(base, head)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	var msg strings.Builder
+	if len(commits) == 1 {
+		subject, body, _ := strings.Cut(strings.TrimSpace(commits[0].Message), "\n")
+		fmt.Fprintf(&msg, "%s (#%d)\n", subject, c.prID)
+		if body = strings.TrimSpace(body); body != "" {
+			fmt.Fprintf(&msg, "\n%s\n", body)
+		}
+	} else {
+		title := c.pr.Title
+		if title == "" {
+			title = firstLine(commits[0].Message)
+		}
+		fmt.Fprintf(&msg, "%s (#%d)\n", title, c.prID)
+		for _, commit := range commits {
+			fmt.Fprintf(&msg, "\n* %s\n", strings.TrimSpace(commit.Message))
+		}
+	}
+
+	when := c.engine.Now()
+	author := commits[0].Author
+	author.When = when
+
+	seen := map[string]bool{strings.ToLower(author.Email): true}
+	var coAuthors []string
+	for _, commit := range commits {
+		email := strings.ToLower(commit.Author.Email)
+		if seen[email] {
+			continue
+		}
+		seen[email] = true
+		coAuthors = append(coAuthors, fmt.Sprintf("Co-authored-by: %s <%s>", commit.Author.Name, commit.Author.Email))
+	}
+	if len(coAuthors) > 0 {
+		fmt.Fprintf(&msg, "\n%s\n", strings.Join(coAuthors, "\n"))
+	}
+
+	return git.StoreObject(c.repo, &object.Commit{
+		Author:       author,
+		Committer:    c.committer(when),
+		Message:      msg.String(),
+		TreeHash:     tree,
+		ParentHashes: []plumbing.Hash{base.Hash},
+	})
+}
+
+// rebaseCommits replays each non-merge PR commit onto base, keeping the original
+// authors and messages. Every replayed commit gets a new committer and hash.
+func (c *MergePRCommand) rebaseCommits(base *object.Commit, commits []*object.Commit) (plumbing.Hash, error) {
+	tip := base
+	for _, commit := range commits {
+		if commit.NumParents() > 1 {
+			continue // Merge commits are dropped, as git rebase does
+		}
+
+		var parent *object.Commit
+		if commit.NumParents() == 1 {
+			p, err := commit.Parent(0)
+			if err != nil {
+				return plumbing.ZeroHash, err
+			}
+			parent = p
+		}
+
+		tree, err := git.MergeTrees(c.repo.Storer, parent, tip, commit)
+		if err != nil {
+			var conflictErr *git.MergeConflictError
+			if errors.As(err, &conflictErr) {
+				return plumbing.ZeroHash, fmt.Errorf("pull request #%d cannot be rebased: commit %s conflicts in %s", c.prID, commit.Hash.String()[:7], strings.Join(conflictErr.Paths, ", "))
+			}
+			return plumbing.ZeroHash, err
+		}
+
+		hash, err := git.StoreObject(c.repo, &object.Commit{
+			Author:       commit.Author,
+			Committer:    c.committer(c.engine.Now()),
+			Message:      commit.Message,
+			TreeHash:     tree,
+			ParentHashes: []plumbing.Hash{tip.Hash},
+		})
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+		if tip, err = c.repo.CommitObject(hash); err != nil {
+			return plumbing.ZeroHash, err
+		}
+	}
+	return tip.Hash, nil
+}
+
+// fastForward moves the base branch to head when no merge is required.
+func (c *MergePRCommand) fastForward(base, head *object.Commit) (plumbing.Hash, error) {
+	isFF, err := git.IsFastForward(c.repo, base.Hash, head.Hash)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	if !isFF {
+		return plumbing.ZeroHash, fmt.Errorf("pull request #%d cannot be fast-forwarded: %s has diverged from %s", c.prID, c.pr.BaseRef, c.pr.HeadRef)
+	}
+	return head.Hash, nil
+}
+
+func (c *MergePRCommand) merger(when time.Time) object.Signature {
+	if c.mergedBy != nil {
+		sig := *c.mergedBy
+		sig.When = when
+		return sig
+	}
+	return c.committer(when)
+}
+
+func (c *MergePRCommand) committer(when time.Time) object.Signature {
+	sig := mergeBotSignature
+	sig.When = when
+	return sig
+}
+
+// headLabel names the head branch, prefixed with the fork for cross-repository PRs.
+func headLabel(pr *git.PullRequest) string {
+	if pr.IsCrossRepository() {
+		return pr.HeadRepo + "/" + pr.HeadRef
+	}
+	return pr.HeadRef
+}
+
+func firstLine(msg string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(msg), "\n")
+	return line
 }
 
 func (c *MergePRCommand) Help() string {
-	return "usage: merge-pr <pr-id> <remote-name>"
+	return "usage: merge-pr <pr-id> <remote-name> [--strategy=merge|squash|rebase|ff-only] [--author=\"Name <email>\"]"
 }
@@ -4,12 +4,15 @@ import (
 	"context"
 	"path/filepath"
 	"strconv"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/go-git/go-billy/v5/memfs"
 	gogit "github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
 	"github.com/kurobon/gitgym/backend/internal/git"
 )
 
@@ -105,3 +108,215 @@ func TestMergePRCommand(t *testing.T) {
 		t.Errorf("Expected 2 parents for merge commit, got %d", len(mergeCommit.ParentHashes))
 	}
 }
+
+// setupPRStrategyRemote builds an in-memory shared remote "origin" where master
+// and feature have diverged on different files, and opens a PR feature -> master.
+func setupPRStrategyRemote(t *testing.T) (*git.SessionManager, *git.Session, *gogit.Repository, *git.PullRequest) {
+	t.Helper()
+	sm := git.NewSessionManager()
+	repo, err := gogit.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, _ := repo.Worktree()
+
+	write := func(name, content, msg, author string) {
+		f, _ := w.Filesystem.Create(name)
+		f.Write([]byte(content))
+		f.Close()
+		w.Add(name)
+		if _, err := w.Commit(msg, &gogit.CommitOptions{
+			Author: &object.Signature{Name: author, Email: strings.ToLower(author) + "@example.com", When: time.Now()},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("README.md", "base", "Initial commit", "Dev")
+	w.Checkout(&gogit.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("feature"), Create: true})
+	write("a.txt", "a", "Add a", "Alice")
+	write("b.txt", "b", "Add b\n\nWith details", "Bob")
+	w.Checkout(&gogit.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("master")})
+	write("main.txt", "main", "Main work", "Dev")
+
+	sm.SharedRemotes["origin"] = repo
+	pr, _ := sm.CreatePullRequest("Add letters", "Desc", "feature", "master", "Alice", "origin")
+	session, _ := sm.CreateSession("test-pr-strategy")
+	return sm, session, repo, pr
+}
+
+func TestMergePRCommand_Strategies(t *testing.T) {
+	ctx := context.Background()
+
+	tipOf := func(t *testing.T, repo *gogit.Repository, branch string) *object.Commit {
+		ref, err := repo.Reference(plumbing.NewBranchReferenceName(branch), true)
+		if err != nil {
+			t.Fatal(err)
+		}
+		c, err := repo.CommitObject(ref.Hash())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	hasFiles := func(t *testing.T, c *object.Commit, names ...string) {
+		for _, name := range names {
+			if _, err := c.File(name); err != nil {
+				t.Errorf("expected %s in merged tree: %v", name, err)
+			}
+		}
+	}
+
+	t.Run("Merge commit", func(t *testing.T) {
+		_, s, repo, pr := setupPRStrategyRemote(t)
+		base := tipOf(t, repo, "master")
+
+		_, err := (&MergePRCommand{}).Execute(ctx, s, []string{"merge-pr", strconv.Itoa(pr.ID), "origin", "--author=Maintainer <maint@example.com>"})
+		if err != nil {
+			t.Fatalf("merge failed: %v", err)
+		}
+
+		tip := tipOf(t, repo, "master")
+		if tip.NumParents() != 2 || tip.ParentHashes[0] != base.Hash {
+			t.Errorf("expected merge commit on top of base, got parents %v", tip.ParentHashes)
+		}
+		if !strings.HasPrefix(tip.Message, "Merge pull request #1 from feature") {
+			t.Errorf("unexpected message: %q", tip.Message)
+		}
+		if tip.Author.Name != "Maintainer" {
+			t.Errorf("expected merging user as author, got %s", tip.Author.Name)
+		}
+		hasFiles(t, tip, "main.txt", "a.txt", "b.txt")
+		if pr.State != "MERGED" || pr.MergeStrategy != "merge" || pr.MergeCommit != tip.Hash.String() {
+			t.Errorf("unexpected PR state: %+v", pr)
+		}
+	})
+
+	t.Run("Squash", func(t *testing.T) {
+		_, s, repo, pr := setupPRStrategyRemote(t)
+		base := tipOf(t, repo, "master")
+
+		_, err := (&MergePRCommand{}).Execute(ctx, s, []string{"merge-pr", strconv.Itoa(pr.ID), "origin", "--strategy", "squash"})
+		if err != nil {
+			t.Fatalf("squash failed: %v", err)
+		}
+
+		tip := tipOf(t, repo, "master")
+		if tip.NumParents() != 1 || tip.ParentHashes[0] != base.Hash {
+			t.Errorf("expected single commit on top of base, got parents %v", tip.ParentHashes)
+		}
+		if !strings.HasPrefix(tip.Message, "Add letters (#1)\n") {
+			t.Errorf("unexpected subject: %q", tip.Message)
+		}
+		if !strings.Contains(tip.Message, "* Add a") || !strings.Contains(tip.Message, "* Add b") {
+			t.Errorf("expected commit list in message: %q", tip.Message)
+		}
+		if tip.Author.Name != "Alice" || !strings.Contains(tip.Message, "Co-authored-by: Bob <bob@example.com>") {
+			t.Errorf("unexpected authorship: %s / %q", tip.Author.Name, tip.Message)
+		}
+		hasFiles(t, tip, "main.txt", "a.txt", "b.txt")
+	})
+
+	t.Run("Rebase", func(t *testing.T) {
+		_, s, repo, pr := setupPRStrategyRemote(t)
+		base := tipOf(t, repo, "master")
+		head := tipOf(t, repo, "feature")
+
+		_, err := (&MergePRCommand{}).Execute(ctx, s, []string{"merge-pr", strconv.Itoa(pr.ID), "origin", "--rebase"})
+		if err != nil {
+			t.Fatalf("rebase failed: %v", err)
+		}
+
+		tip := tipOf(t, repo, "master")
+		if tip.Hash == head.Hash {
+			t.Error("rebased commits must get new hashes")
+		}
+		if tip.Message != head.Message || tip.Author.Name != "Bob" || tip.Committer.Name != "GitGym Merge Bot" {
+			t.Errorf("rebased commit should keep message/author: %q by %s (committer %s)", tip.Message, tip.Author.Name, tip.Committer.Name)
+		}
+		parent, _ := tip.Parent(0)
+		if parent.Author.Name != "Alice" || parent.ParentHashes[0] != base.Hash {
+			t.Errorf("expected Alice's commit directly on base, got %s on %v", parent.Author.Name, parent.ParentHashes)
+		}
+		hasFiles(t, tip, "main.txt", "a.txt", "b.txt")
+	})
+
+	t.Run("Fast-forward only", func(t *testing.T) {
+		_, s, repo, pr := setupPRStrategyRemote(t)
+
+		_, err := (&MergePRCommand{}).Execute(ctx, s, []string{"merge-pr", strconv.Itoa(pr.ID), "origin", "--strategy=ff-only"})
+		if err == nil || !strings.Contains(err.Error(), "cannot be fast-forwarded") {
+			t.Fatalf("expected diverged ff-only to fail, got: %v", err)
+		}
+		if pr.State != "OPEN" {
+			t.Errorf("PR should stay open, got %s", pr.State)
+		}
+
+		// Once feature contains master, ff-only just moves the branch
+		base := tipOf(t, repo, "master")
+		_ = repo.Storer.SetReference(plumbing.NewHashReference("refs/heads/master", base.ParentHashes[0]))
+		head := tipOf(t, repo, "feature")
+		if _, err := (&MergePRCommand{}).Execute(ctx, s, []string{"merge-pr", strconv.Itoa(pr.ID), "origin", "--ff-only"}); err != nil {
+			t.Fatalf("ff-only failed: %v", err)
+		}
+		if tipOf(t, repo, "master").Hash != head.Hash {
+			t.Error("master should point at the PR head")
+		}
+	})
+
+	t.Run("Conflict", func(t *testing.T) {
+		_, s, repo, pr := setupPRStrategyRemote(t)
+		w, _ := repo.Worktree()
+		f, _ := w.Filesystem.Create("a.txt")
+		f.Write([]byte("conflicting"))
+		f.Close()
+		w.Add("a.txt")
+		w.Commit("Conflicting a", &gogit.CommitOptions{
+			Author: &object.Signature{Name: "Dev", Email: "dev@example.com", When: time.Now()},
+		})
+
+		_, err := (&MergePRCommand{}).Execute(ctx, s, []string{"merge-pr", strconv.Itoa(pr.ID), "origin", "--squash"})
+		if err == nil || !strings.Contains(err.Error(), "conflicts") || !strings.Contains(err.Error(), "a.txt") {
+			t.Fatalf("expected conflict error, got: %v", err)
+		}
+	})
+
+	t.Run("Unknown strategy", func(t *testing.T) {
+		_, s, _, pr := setupPRStrategyRemote(t)
+		_, err := (&MergePRCommand{}).Execute(ctx, s, []string{"merge-pr", strconv.Itoa(pr.ID), "origin", "--strategy=octopus"})
+		if err == nil || !strings.Contains(err.Error(), "unknown merge strategy") {
+			t.Fatalf("expected unknown strategy error, got: %v", err)
+		}
+	})
+}
+
+func TestMergePRCommand_ConcurrentMerge(t *testing.T) {
+	ctx := context.Background()
+	_, s, repo, pr := setupPRStrategyRemote(t)
+	args := []string{"merge-pr", strconv.Itoa(pr.ID), "origin", "--squash"}
+
+	// The second merge resolves the PR while it is still open...
+	late := &MergePRCommand{engine: s}
+	if err := late.parseArgs(args); err != nil {
+		t.Fatal(err)
+	}
+	if err := late.resolveContext(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// ...and only gets to merging after the first one finished
+	if _, err := (&MergePRCommand{}).Execute(ctx, s, args); err != nil {
+		t.Fatalf("first merge failed: %v", err)
+	}
+	merged, _ := repo.Reference(plumbing.NewBranchReferenceName("master"), true)
+
+	if _, err := late.performAction(ctx); err == nil || !strings.Contains(err.Error(), "not OPEN") {
+		t.Fatalf("expected the second merge to be refused, got %v", err)
+	}
+	if tip, _ := repo.Reference(plumbing.NewBranchReferenceName("master"), true); tip.Hash() != merged.Hash() {
+		t.Errorf("master moved to %s after the refused merge, want %s", tip.Hash(), merged.Hash())
+	}
+	if pr.MergeCommit != merged.Hash().String() {
+		t.Errorf("PR records merge commit %s, want %s", pr.MergeCommit, merged.Hash())
+	}
+}
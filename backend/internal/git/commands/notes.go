@@ -0,0 +1,459 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/kurobon/gitgym/backend/internal/git"
+)
+
+func init() {
+	git.RegisterCommand("notes", func() git.Command { return &NotesCommand{} })
+}
+
+type NotesCommand struct{}
+
+// Ensure NotesCommand implements git.Command
+var _ git.Command = (*NotesCommand)(nil)
+
+type NotesOptions struct {
+	Ref           plumbing.ReferenceName // --ref: notes ref to use
+	Subcommand    string
+	Messages      []string // -m: paragraphs of the note
+	Files         []string // -F: files holding the note
+	Force         bool     // -f: overwrite existing notes
+	IgnoreMissing bool     // --ignore-missing: remove quietly skips objects without notes
+	Args          []string
+}
+
+func (c *NotesCommand) Execute(ctx context.Context, s *git.Session, args []string) (string, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	opts, err := c.parseArgs(args)
+	if err != nil {
+		if err.Error() == "help requested" {
+			return c.Help(), nil
+		}
+		return "", err
+	}
+
+	repo := s.GetRepo()
+	if repo == nil {
+		return "", fmt.Errorf("fatal: not a git repository (or any of the parent directories): .git")
+	}
+
+	switch opts.Subcommand {
+	case "list":
+		return c.list(repo, opts)
+	case "show":
+		return c.show(repo, opts)
+	case "add", "append":
+		return c.add(s, repo, opts)
+	case "copy":
+		return c.copy(s, repo, opts)
+	case "remove":
+		return c.remove(s, repo, opts)
+	case "get-ref":
+		return opts.Ref.String(), nil
+	}
+	return "", fmt.Errorf("error: unknown subcommand: `%s'", opts.Subcommand)
+}
+
+func (c *NotesCommand) parseArgs(args []string) (*NotesOptions, error) {
+	opts := &NotesOptions{Ref: git.DefaultNotesRef, Subcommand: "list"}
+	cmdArgs := args[1:]
+	value := func(i *int, name string) (string, error) {
+		if *i+1 >= len(cmdArgs) {
+			return "", fmt.Errorf("error: switch `%s' requires a value", name)
+		}
+		*i++
+		return cmdArgs[*i], nil
+	}
+
+	subcommandSeen := false
+	for i := 0; i < len(cmdArgs); i++ {
+		arg := cmdArgs[i]
+		switch {
+		case arg == "-h" || arg == "--help":
+			return nil, fmt.Errorf("help requested")
+		case arg == "--ref":
+			ref, err := value(&i, "ref")
+			if err != nil {
+				return nil, err
+			}
+			opts.Ref = notesRefName(ref)
+		case strings.HasPrefix(arg, "--ref="):
+			opts.Ref = notesRefName(strings.TrimPrefix(arg, "--ref="))
+		case arg == "-m" || arg == "--message":
+			msg, err := value(&i, "m")
+			if err != nil {
+				return nil, err
+			}
+			opts.Messages = append(opts.Messages, msg)
+		case strings.HasPrefix(arg, "--message="):
+			opts.Messages = append(opts.Messages, strings.TrimPrefix(arg, "--message="))
+		case strings.HasPrefix(arg, "-m") && len(arg) > 2:
+			opts.Messages = append(opts.Messages, arg[2:])
+		case arg == "-F" || arg == "--file":
+			file, err := value(&i, "F")
+			if err != nil {
+				return nil, err
+			}
+			opts.Files = append(opts.Files, file)
+		case arg == "-f" || arg == "--force":
+			opts.Force = true
+		case arg == "--ignore-missing":
+			opts.IgnoreMissing = true
+		case strings.HasPrefix(arg, "-"):
+			return nil, fmt.Errorf("error: unknown option `%s'", strings.TrimLeft(arg, "-"))
+		case !subcommandSeen:
+			subcommandSeen = true
+			opts.Subcommand = arg
+		default:
+			opts.Args = append(opts.Args, arg)
+		}
+	}
+	return opts, nil
+}
+
+// notesRefName expands --ref the way git does: "foo" and "notes/foo" both
+// name refs/notes/foo.
+func notesRefName(name string) plumbing.ReferenceName {
+	switch {
+	case strings.HasPrefix(name, "refs/"):
+		return plumbing.ReferenceName(name)
+	case strings.HasPrefix(name, "notes/"):
+		return plumbing.ReferenceName("refs/" + name)
+	}
+	return plumbing.ReferenceName("refs/notes/" + name)
+}
+
+// resolveNoteObject resolves the object a note is about, HEAD by default.
+func resolveNoteObject(repo *gogit.Repository, rev string) (plumbing.Hash, error) {
+	if rev == "" {
+		rev = "HEAD"
+	}
+	h, err := git.ResolveRevision(repo, rev)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("error: failed to resolve '%s' as a valid ref.", rev)
+	}
+	return *h, nil
+}
+
+// list prints "<note blob> <object>" for every note, or the note blob of the
+// one object given.
+func (c *NotesCommand) list(repo *gogit.Repository, opts *NotesOptions) (string, error) {
+	notes, err := git.ReadNotes(repo, opts.Ref)
+	if err != nil {
+		return "", err
+	}
+	if len(opts.Args) > 0 {
+		obj, err := resolveNoteObject(repo, opts.Args[0])
+		if err != nil {
+			return "", err
+		}
+		blob, ok := notes[obj]
+		if !ok {
+			return "", fmt.Errorf("error: no note found for object %s.", obj)
+		}
+		return blob.String(), nil
+	}
+
+	objects := make([]plumbing.Hash, 0, len(notes))
+	for obj := range notes {
+		objects = append(objects, obj)
+	}
+	plumbing.HashesSort(objects)
+	var lines []string
+	for _, obj := range objects {
+		lines = append(lines, fmt.Sprintf("%s %s", notes[obj], obj))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func (c *NotesCommand) show(repo *gogit.Repository, opts *NotesOptions) (string, error) {
+	obj, err := resolveNoteObject(repo, firstArg(opts.Args))
+	if err != nil {
+		return "", err
+	}
+	notes, err := git.ReadNotes(repo, opts.Ref)
+	if err != nil {
+		return "", err
+	}
+	blob, ok := notes[obj]
+	if !ok {
+		return "", fmt.Errorf("error: no note found for object %s.", obj)
+	}
+	text, err := git.NoteText(repo, blob)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimRight(text, "\n"), nil
+}
+
+// add writes a new note, or with append adds a paragraph to the existing one.
+func (c *NotesCommand) add(s *git.Session, repo *gogit.Repository, opts *NotesOptions) (string, error) {
+	obj, err := resolveNoteObject(repo, firstArg(opts.Args))
+	if err != nil {
+		return "", err
+	}
+	content, err := c.noteContent(s, opts)
+	if err != nil {
+		return "", err
+	}
+	notes, err := git.ReadNotes(repo, opts.Ref)
+	if err != nil {
+		return "", err
+	}
+
+	var output string
+	existing, exists := notes[obj]
+	switch {
+	case opts.Subcommand == "append" && exists:
+		old, err := git.NoteText(repo, existing)
+		if err != nil {
+			return "", err
+		}
+		content = strings.TrimRight(old, "\n") + "\n\n" + content
+	case exists && !opts.Force:
+		return "", fmt.Errorf("error: Cannot add notes. Found existing notes for object %s. Use '-f' to overwrite existing notes", obj)
+	case exists:
+		output = fmt.Sprintf("Overwriting existing notes for object %s", obj)
+	}
+
+	blob, err := git.StoreBlob(repo, content)
+	if err != nil {
+		return "", err
+	}
+	notes[obj] = blob
+	if err := writeNotes(s, repo, opts.Ref, notes, fmt.Sprintf("Notes added by 'git notes %s'", opts.Subcommand)); err != nil {
+		return "", err
+	}
+	return output, nil
+}
+
+// noteContent joins the -m paragraphs and -F files, separated by blank lines.
+func (c *NotesCommand) noteContent(s *git.Session, opts *NotesOptions) (string, error) {
+	var paragraphs []string
+	for _, msg := range opts.Messages {
+		if msg = strings.Trim(msg, "\n"); msg != "" {
+			paragraphs = append(paragraphs, msg)
+		}
+	}
+	for _, name := range opts.Files {
+		f, err := s.Filesystem.Open(sessionFilePath(s, name))
+		if err != nil {
+			return "", fmt.Errorf("error: could not open or read '%s': No such file or directory", name)
+		}
+		data, err := io.ReadAll(f)
+		f.Close()
+		if err != nil {
+			return "", err
+		}
+		if msg := strings.Trim(string(data), "\n"); msg != "" {
+			paragraphs = append(paragraphs, msg)
+		}
+	}
+	// There is no editor to write the note in
+	if len(paragraphs) == 0 {
+		return "", fmt.Errorf("fatal: please supply the note contents using either -m or -F option")
+	}
+	return strings.Join(paragraphs, "\n\n") + "\n", nil
+}
+
+func (c *NotesCommand) copy(s *git.Session, repo *gogit.Repository, opts *NotesOptions) (string, error) {
+	if len(opts.Args) != 2 {
+		return "", fmt.Errorf("usage: git notes copy [<options>] <from-object> <to-object>")
+	}
+	from, err := resolveNoteObject(repo, opts.Args[0])
+	if err != nil {
+		return "", err
+	}
+	to, err := resolveNoteObject(repo, opts.Args[1])
+	if err != nil {
+		return "", err
+	}
+	notes, err := git.ReadNotes(repo, opts.Ref)
+	if err != nil {
+		return "", err
+	}
+
+	blob, ok := notes[from]
+	if !ok {
+		return "", fmt.Errorf("error: missing notes on source object %s. Cannot copy.", from)
+	}
+	var output string
+	if _, exists := notes[to]; exists {
+		if !opts.Force {
+			return "", fmt.Errorf("error: Cannot copy notes. Found existing notes for object %s. Use '-f' to overwrite existing notes", to)
+		}
+		output = fmt.Sprintf("Overwriting existing notes for object %s", to)
+	}
+	// Both objects now share the same note blob
+	notes[to] = blob
+	if err := writeNotes(s, repo, opts.Ref, notes, "Notes added by 'git notes copy'"); err != nil {
+		return "", err
+	}
+	return output, nil
+}
+
+func (c *NotesCommand) remove(s *git.Session, repo *gogit.Repository, opts *NotesOptions) (string, error) {
+	revs := opts.Args
+	if len(revs) == 0 {
+		revs = []string{"HEAD"}
+	}
+	notes, err := git.ReadNotes(repo, opts.Ref)
+	if err != nil {
+		return "", err
+	}
+
+	var lines []string
+	removed, failed := false, false
+	for _, rev := range revs {
+		obj, err := resolveNoteObject(repo, rev)
+		if err != nil {
+			return "", err
+		}
+		if _, ok := notes[obj]; !ok {
+			if !opts.IgnoreMissing {
+				lines = append(lines, fmt.Sprintf("Object %s has no note", obj))
+				failed = true
+			}
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("Removing note for object %s", obj))
+		delete(notes, obj)
+		removed = true
+	}
+
+	if removed {
+		if err := writeNotes(s, repo, opts.Ref, notes, "Notes removed by 'git notes remove'"); err != nil {
+			return "", err
+		}
+	}
+	if failed {
+		return "", fmt.Errorf("%s", strings.Join(lines, "\n"))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+func firstArg(args []string) string {
+	if len(args) == 0 {
+		return ""
+	}
+	return args[0]
+}
+
+// writeNotes records the notes as a new commit on the notes ref: a tree with
+// one blob per annotated object, named after the object's hash, on top of the
+// previous notes commit.
+func writeNotes(s *git.Session, repo *gogit.Repository, ref plumbing.ReferenceName, notes map[plumbing.Hash]plumbing.Hash, message string) error {
+	files := make(map[string]git.TreeFile, len(notes))
+	for obj, blob := range notes {
+		files[obj.String()] = git.TreeFile{Hash: blob, Mode: filemode.Regular}
+	}
+	treeHash, err := git.WriteTree(repo.Storer, files)
+	if err != nil {
+		return err
+	}
+
+	sig := *s.Signature()
+	commit := &object.Commit{Author: sig, Committer: sig, Message: message + "\n", TreeHash: treeHash}
+	if old, err := repo.Storer.Reference(ref); err == nil {
+		commit.ParentHashes = []plumbing.Hash{old.Hash()}
+	}
+	commitHash, err := git.StoreObject(repo, commit)
+	if err != nil {
+		return err
+	}
+	return repo.Storer.SetReference(plumbing.NewHashReference(ref, commitHash))
+}
+
+// commitNote returns the note of a commit formatted for log and show:
+// "Notes:" and the note indented, or "" when it has none.
+func commitNote(repo *gogit.Repository, notes map[plumbing.Hash]plumbing.Hash, ref plumbing.ReferenceName, commit plumbing.Hash) string {
+	blob, ok := notes[commit]
+	if !ok {
+		return ""
+	}
+	text, err := git.NoteText(repo, blob)
+	if err != nil {
+		return ""
+	}
+	header := "Notes:"
+	if ref != git.DefaultNotesRef {
+		header = fmt.Sprintf("Notes (%s):", strings.TrimPrefix(ref.String(), "refs/notes/"))
+	}
+	var sb strings.Builder
+	sb.WriteString(header + "\n")
+	for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+		sb.WriteString(strings.TrimRight("    "+line, " ") + "\n")
+	}
+	return sb.String()
+}
+
+func (c *NotesCommand) Help() string {
+	return `📘 GIT-NOTES (1)                                        Git Manual
+
+ 💡 DESCRIPTION
+    コミットを書き換えずに、あとからメモ（ノート）を付けます。
+    レビュー結果やテスト結果など、コミットした後に分かった情報を残すのに便利です。
+
+    ノートは refs/notes/commits という専用の ref に保存されます。
+    この ref が指すのは普通のコミットで、そのツリーには
+    「注釈を付けたコミットのハッシュ」という名前のファイルとして
+    ノートの内容が入っています。ノートも「ツリーを指すただの ref」なのです。
+
+ 📋 SYNOPSIS
+    git notes [list [<object>]]
+    git notes add [-f] [-m <msg> | -F <file>] [<object>]
+    git notes append [-m <msg> | -F <file>] [<object>]
+    git notes show [<object>]
+    git notes copy [-f] <from-object> <to-object>
+    git notes remove [--ignore-missing] [<object>...]
+    git notes get-ref
+
+ ⚙️  COMMON OPTIONS
+    -m <msg>
+        ノートの内容を指定します。複数指定すると段落として連結されます。
+
+    -F <file>
+        ファイルの内容をノートにします。
+
+    -f, --force
+        すでにノートがあっても上書きします。
+
+    --ref <ref>
+        refs/notes/commits 以外のノート（例: --ref review → refs/notes/review）を使います。
+
+ 🛠  EXAMPLES
+    1. 最新のコミットにノートを付けて確認
+       $ git notes add -m "Tested on staging"
+       $ git log -1
+       ...
+       Notes:
+           Tested on staging
+
+    2. ノートの実体を見る
+       $ git notes list
+       $ git log --oneline refs/notes/commits
+
+    3. ノートをリモートと共有（明示的な refspec が必要）
+       $ git push origin refs/notes/commits:refs/notes/commits
+       $ git fetch origin refs/notes/commits:refs/notes/commits
+
+ 💡 TIPS
+    ノートはコミットの外に保存されるため、付けても消してもコミットのハッシュは変わりません。
+    git push / git fetch は通常ノートを送受信しないので、refspec を指定して共有します。
+
+ 🔗 REFERENCE
+    Full documentation: https://git-scm.com/docs/git-notes
+`
+}
@@ -0,0 +1,173 @@
+package commands
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/kurobon/gitgym/backend/internal/git"
+	"github.com/kurobon/gitgym/backend/internal/state"
+)
+
+func TestNotesCommand(t *testing.T) {
+	sm := git.NewSessionManager()
+	s, _ := sm.CreateSession("test-notes")
+	s.InitRepo("testrepo")
+	s.CurrentDir = "/testrepo"
+	repo := s.GetRepo()
+	ctx := context.Background()
+	notes := func(args ...string) (string, error) {
+		return git.Dispatch(ctx, s, "notes", append([]string{"notes"}, args...))
+	}
+
+	commitFile(t, repo, "a.txt", "a", "First")
+	first, _ := repo.Head()
+	commitFile(t, repo, "b.txt", "b", "Second")
+	head, _ := repo.Head()
+
+	if _, err := notes("add", "-m", "Tested on staging"); err != nil {
+		t.Fatalf("notes add failed: %v", err)
+	}
+	if out, _ := notes("show"); out != "Tested on staging" {
+		t.Errorf("notes show = %q", out)
+	}
+	if _, err := notes("add", "-m", "Again"); err == nil || !strings.Contains(err.Error(), "Found existing notes") {
+		t.Errorf("adding twice should need -f, got %v", err)
+	}
+	if _, err := notes("append", "-m", "Reviewed"); err != nil {
+		t.Fatalf("notes append failed: %v", err)
+	}
+	if out, _ := notes("show", "HEAD"); out != "Tested on staging\n\nReviewed" {
+		t.Errorf("appended note = %q", out)
+	}
+
+	// The notes ref is an ordinary commit whose tree names the annotated commit
+	ref, err := repo.Reference(git.DefaultNotesRef, true)
+	if err != nil {
+		t.Fatalf("refs/notes/commits should exist: %v", err)
+	}
+	notesCommit, _ := repo.CommitObject(ref.Hash())
+	tree, _ := notesCommit.Tree()
+	if len(tree.Entries) != 1 || tree.Entries[0].Name != head.Hash().String() {
+		t.Errorf("notes tree should hold one entry named after HEAD: %+v", tree.Entries)
+	}
+	if notesCommit.NumParents() != 1 || !strings.HasPrefix(notesCommit.Message, "Notes added by 'git notes append'") {
+		t.Errorf("unexpected notes commit: %q with %d parents", notesCommit.Message, notesCommit.NumParents())
+	}
+	if out, _ := notes("list"); out != tree.Entries[0].Hash.String()+" "+head.Hash().String() {
+		t.Errorf("notes list = %q", out)
+	}
+
+	// log and show print the note after the message
+	log, _ := git.Dispatch(ctx, s, "log", []string{"log", "-n", "1"})
+	if !strings.Contains(log, "    Second\n\nNotes:\n    Tested on staging\n\n    Reviewed\n") {
+		t.Errorf("log should show the note:\n%s", log)
+	}
+	if log, _ := git.Dispatch(ctx, s, "log", []string{"log", "-n", "1", "--no-notes"}); strings.Contains(log, "Notes:") {
+		t.Errorf("--no-notes should hide the note:\n%s", log)
+	}
+	if show, _ := git.Dispatch(ctx, s, "show", []string{"show"}); !strings.Contains(show, "Notes:\n    Tested on staging") {
+		t.Errorf("show should show the note:\n%s", show)
+	}
+
+	// copy and remove
+	if _, err := notes("copy", "HEAD", "HEAD~1"); err != nil {
+		t.Fatalf("notes copy failed: %v", err)
+	}
+	if out, _ := notes("show", first.Hash().String()); out != "Tested on staging\n\nReviewed" {
+		t.Errorf("copied note = %q", out)
+	}
+	if out, err := notes("remove", "HEAD"); err != nil || out != "Removing note for object "+head.Hash().String() {
+		t.Errorf("notes remove = %q, %v", out, err)
+	}
+	if _, err := notes("show"); err == nil || !strings.Contains(err.Error(), "no note found") {
+		t.Errorf("removed note should be gone, got %v", err)
+	}
+	if _, err := notes("remove", "HEAD"); err == nil || !strings.Contains(err.Error(), "has no note") {
+		t.Errorf("removing a missing note should fail, got %v", err)
+	}
+	if _, err := notes("remove", "--ignore-missing", "HEAD"); err != nil {
+		t.Errorf("--ignore-missing should not fail: %v", err)
+	}
+
+	// Other notes refs
+	if _, err := notes("--ref", "review", "add", "-m", "LGTM"); err != nil {
+		t.Fatalf("notes --ref add failed: %v", err)
+	}
+	if _, err := repo.Reference("refs/notes/review", true); err != nil {
+		t.Error("--ref review should write refs/notes/review")
+	}
+	if out, _ := notes("--ref=review", "get-ref"); out != "refs/notes/review" {
+		t.Errorf("get-ref = %q", out)
+	}
+	if log, _ := git.Dispatch(ctx, s, "log", []string{"log", "-n", "1", "--notes=review"}); !strings.Contains(log, "Notes (review):\n    LGTM") {
+		t.Errorf("--notes=review should show the review note:\n%s", log)
+	}
+
+	// The graph annotates commits carrying notes, without showing the notes history
+	graph := state.BuildGraphState(repo, false)
+	for _, c := range graph.Commits {
+		want := ""
+		if c.ID == first.Hash().String() {
+			want = "Tested on staging\n\nReviewed"
+		}
+		if c.Note != want {
+			t.Errorf("commit %s note = %q, want %q", c.ID[:7], c.Note, want)
+		}
+	}
+	if len(graph.Commits) != 2 {
+		t.Errorf("notes commits should not appear in the graph, got %d commits", len(graph.Commits))
+	}
+}
+
+func TestNotesCommand_PushAndFetch(t *testing.T) {
+	sm := git.NewSessionManager()
+	s := setupPushTestSession(t, sm, "test-notes-push")
+	ctx := context.Background()
+	local := s.Repos["localrepo"]
+	remote := sm.SharedRemotes["remoterepo"]
+
+	if _, err := git.Dispatch(ctx, s, "notes", []string{"notes", "add", "-m", "Shared note"}); err != nil {
+		t.Fatalf("notes add failed: %v", err)
+	}
+	notesRef, _ := local.Reference(git.DefaultNotesRef, true)
+
+	// Notes only travel with an explicit refspec
+	if _, err := git.Dispatch(ctx, s, "push", []string{"push", "origin", "master"}); err != nil {
+		t.Fatalf("push failed: %v", err)
+	}
+	if _, err := remote.Reference(git.DefaultNotesRef, true); err == nil {
+		t.Error("a plain push should not send notes")
+	}
+	out, err := git.Dispatch(ctx, s, "push", []string{"push", "origin", "refs/notes/commits:refs/notes/commits"})
+	if err != nil {
+		t.Fatalf("pushing notes failed: %v", err)
+	}
+	if !strings.Contains(out, "[new reference]") {
+		t.Errorf("unexpected push output: %q", out)
+	}
+	if ref, err := remote.Reference(git.DefaultNotesRef, true); err != nil || ref.Hash() != notesRef.Hash() {
+		t.Fatalf("remote notes ref should match the local one: %v", err)
+	}
+
+	// The server view lists every commit object, but not the notes history
+	for _, c := range state.BuildGraphState(remote, true).Commits {
+		if c.ID == notesRef.Hash().String() {
+			t.Error("the notes commit should not appear in the remote graph")
+		}
+	}
+
+	// Drop the local notes and fetch them back
+	local.Storer.RemoveReference(git.DefaultNotesRef)
+	out, err = git.Dispatch(ctx, s, "fetch", []string{"fetch", "origin", "refs/notes/commits:refs/notes/commits"})
+	if err != nil {
+		t.Fatalf("fetching notes failed: %v", err)
+	}
+	if ref, err := local.Reference(plumbing.ReferenceName("refs/notes/commits"), true); err != nil || ref.Hash() != notesRef.Hash() {
+		t.Errorf("fetch should restore refs/notes/commits: %v\n%s", err, out)
+	}
+	if show, _ := git.Dispatch(ctx, s, "notes", []string{"notes", "show"}); show != "Shared note" {
+		t.Errorf("fetched note = %q", show)
+	}
+}
@@ -0,0 +1,462 @@
+package commands
+
+// pr.go - Pull Request Review Command
+//
+// Lets the learner take part in code review from the terminal: list and view
+// pull requests on the shared remotes, read review comment threads, reply to
+// them, comment on diff lines, submit reviews and inspect CI status checks. `--as <name>` acts on behalf
+// of a simulated teammate (otherwise the repository's user.name is used).
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/kurobon/gitgym/backend/internal/git"
+)
+
+func init() {
+	git.RegisterCommand("pr", func() git.Command { return &PRCommand{} })
+}
+
+type PRCommand struct{}
+
+// Ensure PRCommand implements git.Command
+var _ git.Command = (*PRCommand)(nil)
+
+type PROptions struct {
+	SubCmd    string
+	Args      []string
+	Message   string
+	As        string
+	Path      string
+	Position  int
+	State     string // Review state for "review"
+	HasPath   bool
+	HasReview bool
+}
+
+func (c *PRCommand) Execute(ctx context.Context, s *git.Session, args []string) (string, error) {
+	opts, err := c.parseArgs(args)
+	if err != nil {
+		if err.Error() == "help requested" {
+			return c.Help(), nil
+		}
+		return "", err
+	}
+
+	if s.Manager == nil {
+		return "", fmt.Errorf("pull requests are not available in this session")
+	}
+
+	switch opts.SubCmd {
+	case "list":
+		return c.list(s)
+	case "view":
+		return c.view(s, opts)
+	case "checks":
+		return c.checks(s, opts)
+	case "comments":
+		return c.comments(s, opts)
+	case "comment":
+		return c.comment(s, opts)
+	case "reply":
+		return c.reply(s, opts)
+	case "review":
+		return c.review(s, opts)
+	default:
+		return "", fmt.Errorf("unknown subcommand: %s", opts.SubCmd)
+	}
+}
+
+func (c *PRCommand) parseArgs(args []string) (*PROptions, error) {
+	opts := &PROptions{}
+	if len(args) < 2 {
+		return nil, fmt.Errorf("usage: git pr <list|view|comments|comment|reply|review> [<args>]")
+	}
+	opts.SubCmd = args[1]
+
+	value := func(i int, flag string) (string, error) {
+		if i+1 >= len(args) {
+			return "", fmt.Errorf("option '%s' requires a value", flag)
+		}
+		return args[i+1], nil
+	}
+
+	for i := 2; i < len(args); i++ {
+		arg := args[i]
+		switch arg {
+		case "-h", "--help":
+			return nil, fmt.Errorf("help requested")
+		case "-m", "--message", "--body":
+			v, err := value(i, arg)
+			if err != nil {
+				return nil, err
+			}
+			opts.Message = v
+			i++
+		case "--as":
+			v, err := value(i, arg)
+			if err != nil {
+				return nil, err
+			}
+			opts.As = v
+			i++
+		case "--path":
+			v, err := value(i, arg)
+			if err != nil {
+				return nil, err
+			}
+			opts.Path = v
+			opts.HasPath = true
+			i++
+		case "--position", "--line":
+			v, err := value(i, arg)
+			if err != nil {
+				return nil, err
+			}
+			n, err := strconv.Atoi(v)
+			if err != nil {
+				return nil, fmt.Errorf("invalid position %q", v)
+			}
+			opts.Position = n
+			i++
+		case "--approve", "-a":
+			opts.State, opts.HasReview = git.ReviewApproved, true
+		case "--request-changes", "-r":
+			opts.State, opts.HasReview = git.ReviewChangesRequested, true
+		case "--comment", "-c":
+			opts.State, opts.HasReview = git.ReviewCommented, true
+		default:
+			if strings.HasPrefix(arg, "-") {
+				return nil, fmt.Errorf("unknown option: %s", arg)
+			}
+			opts.Args = append(opts.Args, arg)
+		}
+	}
+	return opts, nil
+}
+
+// pullRequest resolves the <pr-id> argument to an existing pull request.
+func (c *PRCommand) pullRequest(s *git.Session, opts *PROptions) (*git.PullRequest, error) {
+	if len(opts.Args) < 1 {
+		return nil, fmt.Errorf("usage: git pr %s <pr-id>", opts.SubCmd)
+	}
+	id, err := strconv.Atoi(strings.TrimPrefix(opts.Args[0], "#"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid PR ID %q", opts.Args[0])
+	}
+	for _, pr := range s.Manager.GetPullRequests() {
+		if pr.ID == id {
+			return pr, nil
+		}
+	}
+	return nil, fmt.Errorf("pull request #%d not found", id)
+}
+
+// actor is the name reviews and comments are recorded under.
+func (c *PRCommand) actor(s *git.Session, opts *PROptions) string {
+	if opts.As != "" {
+		return opts.As
+	}
+	s.Lock()
+	defer s.Unlock()
+	if repo := s.GetRepo(); repo != nil {
+		if cfg, err := repo.Config(); err == nil && cfg.User.Name != "" {
+			return cfg.User.Name
+		}
+	}
+	return s.Identity().Name
+}
+
+func (c *PRCommand) list(s *git.Session) (string, error) {
+	prs := s.Manager.GetPullRequests()
+	if len(prs) == 0 {
+		return "no pull requests", nil
+	}
+
+	sm := s.Manager
+	sm.RLock()
+	defer sm.RUnlock()
+
+	var sb strings.Builder
+	for _, pr := range prs {
+		fmt.Fprintf(&sb, "#%d\t%s\t%s -> %s\t%s", pr.ID, pr.Title, headLabel(pr), pr.BaseRef, pr.State)
+		if pr.State == "OPEN" && pr.MergeableState != "" {
+			fmt.Fprintf(&sb, "\t%s", pr.MergeableState)
+		}
+		if pr.State == "OPEN" && pr.ChecksState != "" {
+			fmt.Fprintf(&sb, "\tchecks: %s", pr.ChecksState)
+		}
+		if pr.ReviewDecision != "" {
+			fmt.Fprintf(&sb, "\t%s", pr.ReviewDecision)
+		}
+		if n := len(pr.Comments); n > 0 {
+			fmt.Fprintf(&sb, "\t%d comment(s)", n)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String(), nil
+}
+
+func (c *PRCommand) view(s *git.Session, opts *PROptions) (string, error) {
+	pr, err := c.pullRequest(s, opts)
+	if err != nil {
+		return "", err
+	}
+
+	sm := s.Manager
+	required := 0
+	var requiredChecks []string
+	if repo, ok := sm.GetSharedRemote(prRemoteName(pr)); ok {
+		if rule := sm.BranchProtectionFor(repo, plumbing.NewBranchReferenceName(pr.BaseRef)); rule != nil {
+			required = rule.RequiredApprovals
+			requiredChecks = rule.RequiredStatusChecks
+		}
+	}
+	approvedBy, changesRequestedBy := sm.ReviewSummary(pr)
+
+	sm.RLock()
+	defer sm.RUnlock()
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "#%d %s\n", pr.ID, pr.Title)
+	fmt.Fprintf(&sb, "%s wants to merge %d commit(s) into %s from %s\n", pr.Creator, pr.AheadBy, pr.BaseRef, headLabel(pr))
+	fmt.Fprintf(&sb, "State: %s\n", pr.State)
+	if pr.StatusMessage != "" {
+		fmt.Fprintf(&sb, "%s\n", pr.StatusMessage)
+	}
+	if pr.Description != "" {
+		fmt.Fprintf(&sb, "\n%s\n", pr.Description)
+	}
+
+	if len(pr.Reviews) > 0 {
+		sb.WriteString("\nReviews:\n")
+		for _, r := range pr.Reviews {
+			fmt.Fprintf(&sb, "  %s %s", r.Author, reviewVerb(r.State))
+			if r.Body != "" {
+				fmt.Fprintf(&sb, ": %s", r.Body)
+			}
+			sb.WriteString("\n")
+		}
+	}
+
+	sb.WriteString("\n")
+	if required > 0 {
+		fmt.Fprintf(&sb, "Approvals: %d of %d required\n", len(approvedBy), required)
+	} else {
+		fmt.Fprintf(&sb, "Approvals: %d\n", len(approvedBy))
+	}
+	if len(changesRequestedBy) > 0 {
+		fmt.Fprintf(&sb, "Changes requested by: %s\n", strings.Join(changesRequestedBy, ", "))
+	}
+	if pr.ChecksState != "" {
+		fmt.Fprintf(&sb, "Checks: %s (see 'git pr checks %d')\n", pr.ChecksState, pr.ID)
+	}
+	if len(requiredChecks) > 0 {
+		fmt.Fprintf(&sb, "Required checks: %s\n", strings.Join(requiredChecks, ", "))
+	}
+	if n := len(pr.Comments); n > 0 {
+		fmt.Fprintf(&sb, "%d review comment(s) (see 'git pr comments %d')\n", n, pr.ID)
+	}
+	return sb.String(), nil
+}
+
+// checks lists the CI statuses of the pull request's head commit, like `gh pr checks`.
+func (c *PRCommand) checks(s *git.Session, opts *PROptions) (string, error) {
+	pr, err := c.pullRequest(s, opts)
+	if err != nil {
+		return "", err
+	}
+
+	sm := s.Manager
+	sm.RLock()
+	defer sm.RUnlock()
+
+	if len(pr.Checks) == 0 {
+		return fmt.Sprintf("no checks reported on pull request #%d", pr.ID), nil
+	}
+	var sb strings.Builder
+	for _, check := range pr.Checks {
+		fmt.Fprintf(&sb, "%s\t%s", check.State, check.Context)
+		if check.Description != "" {
+			fmt.Fprintf(&sb, "\t%s", check.Description)
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String(), nil
+}
+
+func (c *PRCommand) comments(s *git.Session, opts *PROptions) (string, error) {
+	pr, err := c.pullRequest(s, opts)
+	if err != nil {
+		return "", err
+	}
+
+	sm := s.Manager
+	sm.RLock()
+	defer sm.RUnlock()
+
+	if len(pr.Comments) == 0 {
+		return fmt.Sprintf("no review comments on pull request #%d", pr.ID), nil
+	}
+
+	replies := make(map[int][]*git.ReviewComment)
+	for _, cm := range pr.Comments {
+		if cm.InReplyTo != 0 {
+			replies[cm.InReplyTo] = append(replies[cm.InReplyTo], cm)
+		}
+	}
+
+	var sb strings.Builder
+	for _, cm := range pr.Comments {
+		if cm.InReplyTo != 0 {
+			continue
+		}
+		fmt.Fprintf(&sb, "%s (position %d)", cm.Path, cm.Position)
+		if cm.Outdated(pr.HeadSHA) {
+			sb.WriteString(" [outdated]")
+		}
+		fmt.Fprintf(&sb, "\n    %s\n", cm.DiffLine)
+		fmt.Fprintf(&sb, "  #%d %s: %s\n", cm.ID, cm.Author, cm.Body)
+		for _, r := range replies[cm.ID] {
+			fmt.Fprintf(&sb, "    #%d %s: %s\n", r.ID, r.Author, r.Body)
+		}
+		sb.WriteString("\n")
+	}
+	return strings.TrimRight(sb.String(), "\n") + "\n", nil
+}
+
+func (c *PRCommand) comment(s *git.Session, opts *PROptions) (string, error) {
+	pr, err := c.pullRequest(s, opts)
+	if err != nil {
+		return "", err
+	}
+	if !opts.HasPath || opts.Message == "" {
+		return "", fmt.Errorf("usage: git pr comment <pr-id> --path <file> --position <n> -m <message>")
+	}
+
+	// Anchor against the latest diff
+	git.RefreshPullRequests(s.Manager, nil)
+
+	cm, err := s.Manager.AddReviewComment(pr.ID, c.actor(s, opts), opts.Path, opts.Position, opts.Message)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Added comment #%d on %s (position %d) of pull request #%d", cm.ID, cm.Path, cm.Position, pr.ID), nil
+}
+
+func (c *PRCommand) reply(s *git.Session, opts *PROptions) (string, error) {
+	if len(opts.Args) < 2 || opts.Message == "" {
+		return "", fmt.Errorf("usage: git pr reply <pr-id> <comment-id> -m <message>")
+	}
+	pr, err := c.pullRequest(s, opts)
+	if err != nil {
+		return "", err
+	}
+	commentID, err := strconv.Atoi(strings.TrimPrefix(opts.Args[1], "#"))
+	if err != nil {
+		return "", fmt.Errorf("invalid comment ID %q", opts.Args[1])
+	}
+
+	cm, err := s.Manager.ReplyToReviewComment(pr.ID, commentID, c.actor(s, opts), opts.Message)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Replied to comment #%d on pull request #%d (comment #%d)", cm.InReplyTo, pr.ID, cm.ID), nil
+}
+
+func (c *PRCommand) review(s *git.Session, opts *PROptions) (string, error) {
+	pr, err := c.pullRequest(s, opts)
+	if err != nil {
+		return "", err
+	}
+	if !opts.HasReview {
+		return "", fmt.Errorf("usage: git pr review <pr-id> (--approve | --request-changes | --comment) [-m <message>] [--path <file> --position <n>]")
+	}
+
+	git.RefreshPullRequests(s.Manager, nil)
+
+	body := opts.Message
+	var drafts []git.DraftReviewComment
+	if opts.HasPath {
+		// A line anchor turns the message into a line comment of the review
+		drafts = append(drafts, git.DraftReviewComment{Path: opts.Path, Position: opts.Position, Body: opts.Message})
+		body = ""
+	}
+
+	author := c.actor(s, opts)
+	if _, err := s.Manager.SubmitReview(pr.ID, author, opts.State, body, drafts); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%s %s pull request #%d", author, reviewVerb(opts.State), pr.ID), nil
+}
+
+func reviewVerb(state string) string {
+	switch state {
+	case git.ReviewApproved:
+		return "approved"
+	case git.ReviewChangesRequested:
+		return "requested changes on"
+	default:
+		return "commented on"
+	}
+}
+
+func prRemoteName(pr *git.PullRequest) string {
+	if pr.RemoteName == "" {
+		return "origin"
+	}
+	return pr.RemoteName
+}
+
+func (c *PRCommand) Help() string {
+	return `📘 GIT-PR (1)                                           Git Manual
+
+ 💡 DESCRIPTION
+    ・共有リモート上のプルリクエストをターミナルからレビューします
+    ・差分の行へのコメント、返信、承認（Approve）や修正依頼を行えます
+    ・ブランチ保護で必要な承認数が設定されている場合、
+      承認が揃うまでマージできません
+    ・必須のステータスチェック（CI）が失敗している間もマージできません
+
+ 📋 SYNOPSIS
+    git pr list
+    git pr view <pr-id>
+    git pr checks <pr-id>
+    git pr comments <pr-id>
+    git pr comment <pr-id> --path <file> --position <n> -m <message>
+    git pr reply <pr-id> <comment-id> -m <message>
+    git pr review <pr-id> (--approve | --request-changes | --comment) [-m <message>]
+
+ ⚙️  OPTIONS
+    -m, --message <message>
+        コメントやレビューの本文を指定します。
+
+    --path <file> --position <n>
+        コメントを付ける差分の位置を指定します。
+        ファイルの最初の "@@" 行の次の行が position 1 です。
+
+    --as <name>
+        指定したチームメンバーとしてコメント・レビューします。
+        （省略時は user.name が使われます）
+
+ 🛠  PRACTICAL EXAMPLES
+    1. 基本: レビューコメントを読む
+       $ git pr comments 1
+
+    2. 実践: 指摘に返信する
+       $ git pr reply 1 3 -m "修正しました"
+
+    3. 実践: 承認する
+       $ git pr review 1 --approve -m "LGTM"
+
+    4. 実践: CI の結果を確認する
+       $ git pr checks 1
+
+ 🔗 REFERENCE
+    Full documentation: https://cli.github.com/manual/gh_pr_review
+`
+}
@@ -0,0 +1,199 @@
+package commands
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/kurobon/gitgym/backend/internal/git"
+)
+
+func TestPRCommand_Reviews(t *testing.T) {
+	ctx := context.Background()
+	sm, s, _, pr := setupPRStrategyRemote(t)
+	id := strconv.Itoa(pr.ID)
+
+	run := func(args ...string) (string, error) {
+		return (&PRCommand{}).Execute(ctx, s, append([]string{"pr"}, args...))
+	}
+
+	t.Run("Line comment and reply", func(t *testing.T) {
+		out, err := run("comment", id, "--path", "a.txt", "--position", "1", "-m", "Needs a newline", "--as", "Bob")
+		if err != nil {
+			t.Fatalf("comment failed: %v", err)
+		}
+		if !strings.Contains(out, "Added comment #1") {
+			t.Errorf("unexpected output: %s", out)
+		}
+
+		if _, err := run("reply", id, "1", "-m", "Fixed", "--as", "Alice"); err != nil {
+			t.Fatalf("reply failed: %v", err)
+		}
+
+		out, err = run("comments", id)
+		if err != nil {
+			t.Fatalf("comments failed: %v", err)
+		}
+		for _, want := range []string{"a.txt (position 1)", "+a", "#1 Bob: Needs a newline", "    #2 Alice: Fixed"} {
+			if !strings.Contains(out, want) {
+				t.Errorf("expected %q in comments, got:\n%s", want, out)
+			}
+		}
+	})
+
+	t.Run("Invalid anchors are rejected", func(t *testing.T) {
+		if _, err := run("comment", id, "--path", "a.txt", "--position", "99", "-m", "x", "--as", "Bob"); err == nil || !strings.Contains(err.Error(), "outside the diff") {
+			t.Errorf("expected position error, got %v", err)
+		}
+		if _, err := run("comment", id, "--path", "main.txt", "--position", "1", "-m", "x", "--as", "Bob"); err == nil || !strings.Contains(err.Error(), "not part of the pull request diff") {
+			t.Errorf("expected path error, got %v", err)
+		}
+	})
+
+	t.Run("Required approvals gate the merge", func(t *testing.T) {
+		if err := sm.SetBranchProtection("origin", []*git.BranchProtection{{Pattern: "master", RequiredApprovals: 2}}); err != nil {
+			t.Fatal(err)
+		}
+		merge := func() error {
+			_, err := (&MergePRCommand{}).Execute(ctx, s, []string{"merge-pr", id, "origin", "--squash"})
+			return err
+		}
+
+		if err := merge(); err == nil || !strings.Contains(err.Error(), "needs 2 approving review(s)") {
+			t.Fatalf("expected approval gate, got %v", err)
+		}
+
+		if _, err := run("review", id, "--approve", "--as", "Alice"); err == nil {
+			t.Error("expected the PR author to be unable to approve")
+		}
+
+		if _, err := run("review", id, "--request-changes", "-m", "Please split", "--as", "Bob"); err != nil {
+			t.Fatalf("request changes failed: %v", err)
+		}
+		if _, err := run("review", id, "--approve", "--as", "Carol"); err != nil {
+			t.Fatalf("approve failed: %v", err)
+		}
+		if err := merge(); err == nil || !strings.Contains(err.Error(), "changes requested by Bob") {
+			t.Fatalf("expected change request to block, got %v", err)
+		}
+
+		// A later approval replaces Bob's change request
+		if _, err := run("review", id, "--approve", "-m", "LGTM", "--as", "Bob"); err != nil {
+			t.Fatalf("approve failed: %v", err)
+		}
+		out, err := run("view", id)
+		if err != nil {
+			t.Fatalf("view failed: %v", err)
+		}
+		if !strings.Contains(out, "Approvals: 2 of 2 required") || !strings.Contains(out, "Bob approved: LGTM") {
+			t.Errorf("unexpected view output:\n%s", out)
+		}
+
+		if err := merge(); err != nil {
+			t.Fatalf("merge failed after approvals: %v", err)
+		}
+	})
+}
+
+func TestPRCommand_ChangesRequestedWithoutProtection(t *testing.T) {
+	ctx := context.Background()
+	_, s, _, pr := setupPRStrategyRemote(t)
+	id := strconv.Itoa(pr.ID)
+
+	if _, err := (&PRCommand{}).Execute(ctx, s, []string{"pr", "review", id, "--request-changes", "-m", "Not yet", "--as", "Bob"}); err != nil {
+		t.Fatalf("request changes failed: %v", err)
+	}
+	_, err := (&MergePRCommand{}).Execute(ctx, s, []string{"merge-pr", id, "origin", "--squash"})
+	if err == nil || !strings.Contains(err.Error(), "changes requested by Bob") {
+		t.Fatalf("expected change request to block an unprotected base, got %v", err)
+	}
+}
+
+func TestPRCommand_NoSessionManager(t *testing.T) {
+	s := &git.Session{}
+	if _, err := (&PRCommand{}).Execute(context.Background(), s, []string{"pr", "list"}); err == nil {
+		t.Error("expected pr to fail without a session manager")
+	}
+}
+
+func TestPRCommand_StatusChecks(t *testing.T) {
+	ctx := context.Background()
+	sm, s, repo, pr := setupPRStrategyRemote(t)
+	id := strconv.Itoa(pr.ID)
+
+	if err := sm.SetStatusChecks("origin", []*git.StatusCheck{
+		{Context: "ci/tests", Type: "file-exists", Path: "tests/pass"},
+		{Context: "lint", Type: "no-conflict-markers"},
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if err := sm.SetBranchProtection("origin", []*git.BranchProtection{{Pattern: "master", RequiredStatusChecks: []string{"ci/tests"}}}); err != nil {
+		t.Fatal(err)
+	}
+	merge := func() error {
+		_, err := (&MergePRCommand{}).Execute(ctx, s, []string{"merge-pr", id, "origin", "--squash"})
+		return err
+	}
+	// commitOnFeature adds a file to the PR branch, as a push would, and runs CI on it
+	commitOnFeature := func(name, content string) {
+		w, _ := repo.Worktree()
+		if err := w.Checkout(&gogit.CheckoutOptions{Branch: plumbing.NewBranchReferenceName("feature")}); err != nil {
+			t.Fatal(err)
+		}
+		f, _ := w.Filesystem.Create(name)
+		f.Write([]byte(content))
+		f.Close()
+		w.Add(name)
+		hash, err := w.Commit("Add "+name, &gogit.CommitOptions{Author: &object.Signature{Name: "Alice", Email: "alice@example.com", When: time.Now()}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		sm.RunStatusChecks(repo, hash)
+		git.RefreshPullRequests(sm, repo)
+	}
+
+	// Rules added after the push still report on the PR head
+	git.RefreshPullRequests(sm, repo)
+	if pr.ChecksState != git.StatusFailure {
+		t.Fatalf("expected failing checks, got %q", pr.ChecksState)
+	}
+	out, err := (&PRCommand{}).Execute(ctx, s, []string{"pr", "checks", id})
+	if err != nil {
+		t.Fatalf("pr checks failed: %v", err)
+	}
+	for _, want := range []string{"failure\tci/tests\ttests/pass is missing", "success\tlint"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in checks, got:\n%s", want, out)
+		}
+	}
+	if err := merge(); err == nil || !strings.Contains(err.Error(), `required status check "ci/tests" failed`) {
+		t.Fatalf("expected failing check to block the merge, got %v", err)
+	}
+
+	commitOnFeature("conflicted.txt", "<<<<<<< HEAD\nours\n=======\ntheirs\n>>>>>>> feature\n")
+	if pr.ChecksState != git.StatusFailure {
+		t.Fatalf("expected failing checks, got %q", pr.ChecksState)
+	}
+	for _, c := range pr.Checks {
+		if c.Context == "lint" && (c.State != git.StatusFailure || !strings.Contains(c.Description, "conflicted.txt")) {
+			t.Errorf("expected lint to report conflicted.txt, got %s: %s", c.State, c.Description)
+		}
+	}
+
+	// Only ci/tests is required, so a red lint does not block once tests pass
+	commitOnFeature("tests/pass", "ok")
+	if err := merge(); err != nil {
+		t.Fatalf("merge failed after required check passed: %v", err)
+	}
+
+	// The merge commit is checked on the base branch too
+	tip, _ := repo.Reference(plumbing.NewBranchReferenceName("master"), true)
+	if summary := sm.CommitStatusSummary(repo); summary[tip.Hash().String()] != git.StatusFailure {
+		t.Errorf("expected the merged tip to carry lint's failure, got %q", summary[tip.Hash().String()])
+	}
+}
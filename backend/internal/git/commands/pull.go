@@ -3,16 +3,24 @@ package commands
 // pull.go - Simulated Git Pull Command
 //
 // Fetches from and integrates with another repository or a local branch.
-// This is equivalent to git fetch + git merge in simulation.
+// This is equivalent to git fetch + git merge (or git rebase) in simulation.
+// The integration mode comes from the command line, branch.<name>.rebase,
+// pull.rebase and pull.ff, like git.
 // IMPORTANT: No actual network operations are performed.
 
 import (
 	"context"
 	"fmt"
+	"os"
+	"slices"
+	"sort"
 	"strings"
 
+	"github.com/go-git/go-billy/v5/util"
 	gogit "github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/index"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/kurobon/gitgym/backend/internal/git"
 )
 
@@ -26,9 +34,13 @@ type PullCommand struct{}
 var _ git.Command = (*PullCommand)(nil)
 
 type PullOptions struct {
-	DryRun bool
-	Remote string
-	Branch string // Optional
+	DryRun    bool
+	Remote    string
+	Branch    string // Optional
+	Rebase    string // "true", "merges" or "false"; "" defers to config
+	FF        string // "only", "true" (--ff) or "false" (--no-ff); "" defers to config
+	Squash    bool
+	Autostash string // "true" or "false"; "" defers to rebase.autoStash / merge.autoStash
 }
 
 type pullContext struct {
@@ -37,8 +49,27 @@ type pullContext struct {
 	HeadRef      *plumbing.Reference
 	MergeRef     *plumbing.Reference // The remote ref to merge
 	MergeRefName string
+	Rebase       string // Effective mode: "true", "merges" or "false"
+	FF           string // Effective pull.ff
+	Configured   bool   // Whether the user chose how to reconcile divergent branches
+	Autostash    bool
 }
 
+// divergentBranchesError is git's refusal to pick merge or rebase by itself.
+const divergentBranchesError = `hint: You have divergent branches and need to specify how to reconcile them.
+hint: You can do so by running one of the following commands sometime before
+hint: your next pull:
+hint:
+hint:   git config pull.rebase false  # merge
+hint:   git config pull.rebase true   # rebase
+hint:   git config pull.ff only       # fast-forward only
+hint:
+hint: You can replace "git config" with "git config --global" to set a default
+hint: preference for all repositories. You can also pass --rebase, --no-rebase,
+hint: or --ff-only on the command line to override the configured default per
+hint: invocation.
+fatal: Need to specify how to reconcile divergent branches.`
+
 func (c *PullCommand) Execute(ctx context.Context, s *git.Session, args []string) (string, error) {
 	// 1. Parse Args
 	opts, err := c.parseArgs(args)
@@ -49,6 +80,11 @@ func (c *PullCommand) Execute(ctx context.Context, s *git.Session, args []string
 		return "", err
 	}
 
+	// Without arguments, pull from the current branch's upstream
+	if opts.Remote == "" {
+		c.resolveUpstream(s, opts)
+	}
+
 	// 2. Fetch (Delegate to FetchCommand)
 	fetchOutput, err := c.executeFetch(ctx, s, opts)
 	if err != nil {
@@ -59,20 +95,25 @@ func (c *PullCommand) Execute(ctx context.Context, s *git.Session, args []string
 		return fmt.Sprintf("%s\n[dry-run] Pull would continue with merge/rebase.", fetchOutput), nil
 	}
 
-	// 3. Resolve Context (Identify Merge Target)
+	s.Lock()
+	defer s.Unlock()
+
+	// 3. Resolve Context (Identify Merge Target and Mode)
 	pCtx, err := c.resolveContext(s, opts, fetchOutput)
 	if err != nil {
 		return "", err
 	}
 
-	// 4. Perform Merge
-	return c.performPullMerge(s, pCtx)
+	// 4. Integrate (fast-forward, merge, squash or rebase)
+	out, err := c.integrate(ctx, s, pCtx, opts)
+	if err != nil {
+		return "", err
+	}
+	return joinOutput(pCtx.FetchOutput, out), nil
 }
 
 func (c *PullCommand) parseArgs(args []string) (*PullOptions, error) {
-	opts := &PullOptions{
-		Remote: "origin",
-	}
+	opts := &PullOptions{}
 	var cleanArgs []string
 	cmdArgs := args[1:]
 
@@ -80,9 +121,32 @@ func (c *PullCommand) parseArgs(args []string) (*PullOptions, error) {
 		switch arg {
 		case "-n", "--dry-run":
 			opts.DryRun = true
+		case "-r", "--rebase", "--rebase=true":
+			opts.Rebase = "true"
+		case "--rebase=merges":
+			opts.Rebase = "merges"
+		case "--no-rebase", "--rebase=false":
+			opts.Rebase = "false"
+		case "--ff-only":
+			opts.FF = "only"
+		case "--ff":
+			opts.FF = "true"
+		case "--no-ff":
+			opts.FF = "false"
+		case "--squash":
+			opts.Squash = true
+		case "--no-squash":
+			opts.Squash = false
+		case "--autostash":
+			opts.Autostash = "true"
+		case "--no-autostash":
+			opts.Autostash = "false"
 		case "-h", "--help":
 			return nil, fmt.Errorf("help requested")
 		default:
+			if strings.HasPrefix(arg, "--rebase=") {
+				return nil, fmt.Errorf("fatal: invalid value for '--rebase': '%s'", strings.TrimPrefix(arg, "--rebase="))
+			}
 			if strings.HasPrefix(arg, "-") {
 				// ignore
 			} else {
@@ -91,6 +155,10 @@ func (c *PullCommand) parseArgs(args []string) (*PullOptions, error) {
 		}
 	}
 
+	if opts.Squash && opts.FF == "false" {
+		return nil, fmt.Errorf("fatal: options '--squash' and '--no-ff.' cannot be used together")
+	}
+
 	if len(cleanArgs) > 0 {
 		opts.Remote = cleanArgs[0]
 	}
@@ -100,6 +168,26 @@ func (c *PullCommand) parseArgs(args []string) (*PullOptions, error) {
 	return opts, nil
 }
 
+// resolveUpstream fills in the remote and branch configured as the current
+// branch's upstream, or origin and the same-named branch when there is none.
+func (c *PullCommand) resolveUpstream(s *git.Session, opts *PullOptions) {
+	s.Lock()
+	defer s.Unlock()
+
+	opts.Remote = "origin"
+	repo := s.GetRepo()
+	if repo == nil {
+		return
+	}
+	head, err := repo.Head()
+	if err != nil || !head.Name().IsBranch() {
+		return
+	}
+	if remote, merge, ok := git.BranchUpstream(repo, head.Name().Short()); ok {
+		opts.Remote, opts.Branch = remote, merge.Short()
+	}
+}
+
 func (c *PullCommand) executeFetch(ctx context.Context, s *git.Session, opts *PullOptions) (string, error) {
 	fetchArgs := []string{"fetch"}
 	if opts.DryRun {
@@ -112,10 +200,10 @@ func (c *PullCommand) executeFetch(ctx context.Context, s *git.Session, opts *Pu
 	return fetchCmd.Execute(ctx, s, fetchArgs)
 }
 
+// resolveContext finds the remote-tracking ref to integrate and the mode to
+// integrate it with. Command-line options win over branch.<name>.rebase, which
+// wins over pull.rebase; pull.ff applies when no --ff option is given.
 func (c *PullCommand) resolveContext(s *git.Session, opts *PullOptions, fetchOutput string) (*pullContext, error) {
-	s.Lock()
-	defer s.Unlock()
-
 	repo := s.GetRepo()
 	if repo == nil {
 		return nil, fmt.Errorf("fatal: not a git repository")
@@ -143,63 +231,414 @@ func (c *PullCommand) resolveContext(s *git.Session, opts *PullOptions, fetchOut
 	// Verify merge ref exists
 	mergeRef, err := repo.Reference(plumbing.ReferenceName(mergeRefName), true)
 	if err != nil {
-		// If dry run, we might not care, but we are past dry run here.
 		return nil, fmt.Errorf("ref %s not found (fetch might have failed to update it?)", mergeRefName)
 	}
 
+	cfg, err := repo.Config()
+	if err != nil {
+		return nil, err
+	}
+	rebase := opts.Rebase
+	if rebase == "" {
+		if b, ok := cfg.Branches[headRef.Name().Short()]; ok && b.Rebase != "" {
+			rebase = b.Rebase
+		} else {
+			rebase = cfg.Raw.Section("pull").Option("rebase")
+		}
+	}
+	ff := opts.FF
+	if ff == "" {
+		ff = cfg.Raw.Section("pull").Option("ff")
+	}
+	configured := rebase != "" || ff != ""
+	switch rebase {
+	case "", "false":
+		rebase = "false"
+	case "merges", "interactive", "i":
+		rebase = "merges"
+	default:
+		rebase = "true"
+	}
+
+	autostash := opts.Autostash
+	if autostash == "" {
+		section := "merge"
+		if rebase != "false" {
+			section = "rebase"
+		}
+		autostash = cfg.Raw.Section(section).Option("autoStash")
+	}
+
 	return &pullContext{
 		FetchOutput:  fetchOutput,
 		Repo:         repo,
 		HeadRef:      headRef,
 		MergeRef:     mergeRef,
 		MergeRefName: mergeRefName,
+		Rebase:       rebase,
+		FF:           ff,
+		Configured:   configured,
+		Autostash:    autostash == "true",
 	}, nil
 }
 
-func (c *PullCommand) performPullMerge(_ *git.Session, pCtx *pullContext) (string, error) {
-	// Need lock for repo operations?
-	// s.GetRepo() returns pointer. Operations on repo are usually thread-safe or s is locked?
-	// Legacy Execute locked s during resolve. Here we unlocked.
-	// Should lock again or locking is fine?
-	// Standard practice: if interacting with Session state, lock. Repo state might have its own locks.
-	// But simple read/write to repo is fine.
-
+// integrate brings the fetched branch into HEAD. Local changes are refused
+// when they would be overwritten, unless --autostash stashes them around the
+// operation.
+func (c *PullCommand) integrate(ctx context.Context, s *git.Session, pCtx *pullContext, opts *PullOptions) (string, error) {
 	repo := pCtx.Repo
-	headRef := pCtx.HeadRef
-	mergeRef := pCtx.MergeRef
+	headHash := pCtx.HeadRef.Hash()
+	targetHash := pCtx.MergeRef.Hash()
 
-	headHash := headRef.Hash()
-	targetHash := mergeRef.Hash()
+	// Already contains the fetched commits
+	if upToDate, err := git.IsFastForward(repo, targetHash, headHash); err == nil && upToDate {
+		if pCtx.Rebase != "false" && !opts.Squash {
+			return fmt.Sprintf("Current branch %s is up to date.", pCtx.HeadRef.Name().Short()), nil
+		}
+		return "Already up to date.", nil
+	}
+	canFF, err := git.IsFastForward(repo, headHash, targetHash)
+	if err != nil {
+		return "", err
+	}
+
+	type step func() (string, error)
+	var run step
+	rebasing, merging := false, false
+	switch {
+	case opts.Squash:
+		merging = true
+		run = func() (string, error) { return c.performPullSquash(pCtx) }
+	case canFF && (pCtx.FF != "false" || pCtx.Rebase != "false"):
+		run = func() (string, error) { return c.performPullFastForward(s, pCtx) }
+	case pCtx.Rebase != "false" && !(pCtx.FF == "only" && opts.Rebase == ""):
+		rebasing = true
+		run = func() (string, error) { return c.performPullRebase(ctx, s, pCtx) }
+	case pCtx.FF == "only":
+		return "", fmt.Errorf("fatal: Not possible to fast-forward, aborting.")
+	case !canFF && !pCtx.Configured:
+		return "", fmt.Errorf("%s", divergentBranchesError)
+	default:
+		merging = true
+		run = func() (string, error) { return c.performPullMerge(s, pCtx) }
+	}
 
-	// Check Fast-Forward
-	isFF, err := git.IsFastForward(repo, headHash, targetHash)
+	// Local changes
+	dirty, err := localChanges(repo)
 	if err != nil {
 		return "", err
 	}
+	if len(dirty) == 0 {
+		return run()
+	}
+	if pCtx.Autostash {
+		return c.runWithAutostash(s, repo, run)
+	}
 
-	if isFF {
-		// FF Update
-		newRef := plumbing.NewHashReference(headRef.Name(), targetHash)
-		err = repo.Storer.SetReference(newRef)
+	if rebasing {
+		return "", fmt.Errorf("error: cannot pull with rebase: You have unstaged changes.\nerror: Please commit or stash them.")
+	}
+	overwritten, err := overwrittenByUpdate(repo, headHash, targetHash, dirty)
+	if err != nil {
+		return "", err
+	}
+	if merging {
+		// A merge commits the index, so staged changes are in the way too
+		staged, err := stagedChanges(repo)
 		if err != nil {
 			return "", err
 		}
+		overwritten = append(overwritten, staged...)
+	}
+	if len(overwritten) > 0 {
+		sort.Strings(overwritten)
+		return "", fmt.Errorf("error: Your local changes to the following files would be overwritten by merge:\n\t%s\nPlease commit your changes or stash them before you merge.\nAborting", strings.Join(slices.Compact(overwritten), "\n\t"))
+	}
 
-		w, wErr := repo.Worktree()
-		if wErr != nil {
-			return "", wErr
+	// The update does not touch the changed files: put them back as they were
+	restore, err := saveLocalChanges(repo, dirty)
+	if err != nil {
+		return "", err
+	}
+	out, err := run()
+	if err != nil {
+		return "", err
+	}
+	if err := restore(); err != nil {
+		return "", err
+	}
+	return out, nil
+}
+
+// runWithAutostash carries local changes across the update in a stash entry.
+// When the update stops on a conflict or fails, the entry is kept, as git
+// does, instead of being applied to the half-finished operation.
+func (c *PullCommand) runWithAutostash(s *git.Session, repo *gogit.Repository, run func() (string, error)) (string, error) {
+	stash := &StashCommand{}
+	if _, err := stash.executePush(s, repo, nil); err != nil {
+		return "", err
+	}
+	stashRef, err := repo.Reference(plumbing.ReferenceName(StashRefName), true)
+	if err != nil {
+		return "", err
+	}
+	created := fmt.Sprintf("Created autostash: %s", stashRef.Hash().String()[:7])
+	const kept = "Your changes are safe in the stash.\nYou can run \"git stash pop\" or \"git stash drop\" at any time."
+
+	out, runErr := run()
+	if runErr != nil {
+		return "", fmt.Errorf("%s\n%w\n%s", created, runErr, kept)
+	}
+	if strings.Contains(out, "CONFLICT (") {
+		return joinOutput(created, out, kept), nil
+	}
+
+	popOut, err := stash.executePop(repo)
+	if err != nil {
+		return "", err
+	}
+	applied := "Applied autostash."
+	if strings.HasPrefix(popOut, "error:") {
+		applied = "Applying autostash resulted in conflicts.\n" + kept
+	}
+	return joinOutput(created, out, applied), nil
+}
+
+// stagedChanges lists the files whose index entry differs from HEAD.
+func stagedChanges(repo *gogit.Repository) ([]string, error) {
+	w, err := repo.Worktree()
+	if err != nil {
+		return nil, err
+	}
+	status, err := w.Status()
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for path, st := range status {
+		if st.Staging != gogit.Unmodified && st.Staging != gogit.Untracked {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// savedChange is a locally changed file: its index entry and worktree
+// contents, either of which may be missing (deleted).
+type savedChange struct {
+	entry   *index.Entry
+	content []byte
+	mode    os.FileMode
+	exists  bool
+}
+
+// saveLocalChanges records the index entries and worktree contents of paths
+// and returns a function putting them back after HEAD has moved.
+func saveLocalChanges(repo *gogit.Repository, paths []string) (func() error, error) {
+	w, err := repo.Worktree()
+	if err != nil {
+		return nil, err
+	}
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return nil, err
+	}
+	saved := make(map[string]*savedChange, len(paths))
+	for _, p := range paths {
+		change := &savedChange{}
+		if e, err := idx.Entry(p); err == nil {
+			copied := *e
+			change.entry = &copied
 		}
-		err = w.Reset(&gogit.ResetOptions{
-			Commit: targetHash,
-			Mode:   gogit.HardReset,
-		})
+		if info, err := w.Filesystem.Lstat(p); err == nil {
+			if change.content, err = util.ReadFile(w.Filesystem, p); err != nil {
+				return nil, err
+			}
+			change.mode, change.exists = info.Mode(), true
+		}
+		saved[p] = change
+	}
+
+	return func() error {
+		idx, err := repo.Storer.Index()
 		if err != nil {
-			return "", fmt.Errorf("failed to update worktree: %w", err)
+			return err
+		}
+		entries := idx.Entries[:0]
+		for _, e := range idx.Entries {
+			if _, ok := saved[e.Name]; !ok {
+				entries = append(entries, e)
+			}
+		}
+		for p, change := range saved {
+			if change.entry != nil {
+				entries = append(entries, change.entry)
+			}
+			if !change.exists {
+				if err := w.Filesystem.Remove(p); err != nil && !os.IsNotExist(err) {
+					return err
+				}
+				continue
+			}
+			if err := util.WriteFile(w.Filesystem, p, change.content, change.mode); err != nil {
+				return err
+			}
+		}
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Name < entries[j].Name })
+		idx.Entries = entries
+		return repo.Storer.SetIndex(idx)
+	}, nil
+}
+
+// localChanges lists tracked files that differ from HEAD in the index or worktree.
+func localChanges(repo *gogit.Repository) ([]string, error) {
+	w, err := repo.Worktree()
+	if err != nil {
+		return nil, err
+	}
+	status, err := w.Status()
+	if err != nil {
+		return nil, err
+	}
+	var paths []string
+	for path, st := range status {
+		if st.Worktree == gogit.Untracked && st.Staging == gogit.Untracked {
+			continue
+		}
+		if st.Worktree != gogit.Unmodified || st.Staging != gogit.Unmodified {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// overwrittenByUpdate returns the dirty paths that also change between from and to.
+func overwrittenByUpdate(repo *gogit.Repository, from, to plumbing.Hash, dirty []string) ([]string, error) {
+	fromCommit, err := repo.CommitObject(from)
+	if err != nil {
+		return nil, err
+	}
+	toCommit, err := repo.CommitObject(to)
+	if err != nil {
+		return nil, err
+	}
+	fromTree, err := fromCommit.Tree()
+	if err != nil {
+		return nil, err
+	}
+	toTree, err := toCommit.Tree()
+	if err != nil {
+		return nil, err
+	}
+	changes, err := object.DiffTree(fromTree, toTree)
+	if err != nil {
+		return nil, err
+	}
+	changed := make(map[string]bool)
+	for _, ch := range changes {
+		changed[ch.From.Name] = true
+		changed[ch.To.Name] = true
+	}
+	var overwritten []string
+	for _, path := range dirty {
+		if changed[path] {
+			overwritten = append(overwritten, path)
+		}
+	}
+	return overwritten, nil
+}
+
+// joinOutput joins the non-empty parts of a command's output with newlines.
+func joinOutput(parts ...string) string {
+	var lines []string
+	for _, p := range parts {
+		if p != "" {
+			lines = append(lines, p)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (c *PullCommand) performPullFastForward(s *git.Session, pCtx *pullContext) (string, error) {
+	repo := pCtx.Repo
+	headHash := pCtx.HeadRef.Hash()
+	targetHash := pCtx.MergeRef.Hash()
+
+	s.UpdateOrigHead()
+	w, err := repo.Worktree()
+	if err != nil {
+		return "", err
+	}
+	err = w.Reset(&gogit.ResetOptions{
+		Commit: targetHash,
+		Mode:   gogit.HardReset,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to update worktree: %w", err)
+	}
+
+	return fmt.Sprintf("Updating %s..%s\nFast-forward", headHash.String()[:7], targetHash.String()[:7]), nil
+}
+
+// performPullRebase replays the local commits onto the fetched branch.
+func (c *PullCommand) performPullRebase(ctx context.Context, s *git.Session, pCtx *pullContext) (string, error) {
+	rebase := &RebaseCommand{}
+	rbOpts := &RebaseOptions{Upstream: pCtx.MergeRefName, Preserve: pCtx.Rebase == "merges"}
+
+	s.UpdateOrigHead()
+	rbCtx, err := rebase.prepareRebaseContext(pCtx.Repo, rbOpts)
+	if err != nil {
+		if err == ErrUpToDate {
+			return fmt.Sprintf("Current branch %s is up to date.", pCtx.HeadRef.Name().Short()), nil
 		}
+		return "", err
+	}
+	return rebase.performRebase(ctx, s, pCtx.Repo, rbCtx, rbOpts.Preserve)
+}
 
-		return fmt.Sprintf("%s\nUpdating %s..%s\nFast-forward", pCtx.FetchOutput, headHash.String()[:7], targetHash.String()[:7]), nil
+// performPullSquash applies the fetched changes to the worktree and index
+// without committing or moving HEAD.
+func (c *PullCommand) performPullSquash(pCtx *pullContext) (string, error) {
+	repo := pCtx.Repo
+	headCommit, err := repo.CommitObject(pCtx.HeadRef.Hash())
+	if err != nil {
+		return "", err
+	}
+	targetCommit, err := repo.CommitObject(pCtx.MergeRef.Hash())
+	if err != nil {
+		return "", err
+	}
+	mergeBases, err := headCommit.MergeBase(targetCommit)
+	if err != nil {
+		return "", fmt.Errorf("failed to calculate merge base: %w", err)
+	}
+	if len(mergeBases) == 0 {
+		return "", fmt.Errorf("fatal: refusing to merge unrelated histories")
 	}
 
+	w, err := repo.Worktree()
+	if err != nil {
+		return "", err
+	}
+	if err := git.Merge3Way(w, mergeBases[0], headCommit, targetCommit); err != nil {
+		if err == git.ErrConflict {
+			return "Squash commit -- not updating HEAD\nCONFLICT (content): Merge conflict detected.\nAutomatic merge failed; fix conflicts and then commit the result.", nil
+		}
+		return "", fmt.Errorf("merge failed: %w", err)
+	}
+	return "Squash commit -- not updating HEAD\nAutomatic merge went well; stopped before committing as requested", nil
+}
+
+func (c *PullCommand) performPullMerge(s *git.Session, pCtx *pullContext) (string, error) {
+	repo := pCtx.Repo
+	headRef := pCtx.HeadRef
+	mergeRef := pCtx.MergeRef
+
+	headHash := headRef.Hash()
+	targetHash := mergeRef.Hash()
+
 	// 3-Way Merge
 	headCommit, err := repo.CommitObject(headHash)
 	if err != nil {
@@ -224,32 +663,30 @@ func (c *PullCommand) performPullMerge(_ *git.Session, pCtx *pullContext) (strin
 		return "", err
 	}
 
+	s.UpdateOrigHead()
 	err = git.Merge3Way(w, baseCommit, headCommit, targetCommit)
 	if err != nil {
 		if err == git.ErrConflict {
-			return fmt.Sprintf("%s\nCONFLICT (content): Merge conflict detected.\nAutomatic merge failed; fix conflicts and then commit the result.", pCtx.FetchOutput), nil
+			return "CONFLICT (content): Merge conflict detected.\nAutomatic merge failed; fix conflicts and then commit the result.", nil
 		}
 		return "", fmt.Errorf("merge failed: %w", err)
 	}
 
-	// Stage changes (simplified)
-	_, err = w.Add(".")
-	if err != nil {
-		return "", fmt.Errorf("failed to stage changes: %w", err)
-	}
-
+	// Merge3Way staged what it changed; local changes stay out of the commit
 	message := fmt.Sprintf("Merge branch '%s' into %s", pCtx.MergeRefName, headRef.Name().Short())
 
+	sig := s.Signature()
 	mergeCommit, err := w.Commit(message, &gogit.CommitOptions{
-		Parents:   []plumbing.Hash{headHash, targetHash},
-		Author:    git.GetDefaultSignature(),
-		Committer: git.GetDefaultSignature(),
+		Parents:           []plumbing.Hash{headHash, targetHash},
+		Author:            sig,
+		Committer:         sig,
+		AllowEmptyCommits: true, // --no-ff merges of a fast-forwardable branch change no files
 	})
 	if err != nil {
 		return "", fmt.Errorf("failed to create merge commit: %w", err)
 	}
 
-	return fmt.Sprintf("%s\nMerge made by the 'ort' strategy.\n%s", pCtx.FetchOutput, mergeCommit.String()[:7]), nil
+	return fmt.Sprintf("Merge made by the 'ort' strategy.\n%s", mergeCommit.String()[:7]), nil
 }
 
 func (c *PullCommand) Help() string {
@@ -257,15 +694,44 @@ func (c *PullCommand) Help() string {
 
  💡 DESCRIPTION
     ・リモートリポジトリから最新の変更をダウンロードする（fetch）
-    ・ダウンロードした変更を現在のブランチに取り込む（merge）
-    （fetch と merge を一度に行うコマンドです）
+    ・ダウンロードした変更を現在のブランチに取り込む（merge または rebase）
+    （fetch と merge/rebase を一度に行うコマンドです）
+
+    ローカルとリモートの両方に新しいコミットがある（分岐している）場合、
+    取り込み方を指定しないとエラーになります:
+      hint: You have divergent branches and need to specify how to reconcile them.
+    --rebase / --no-rebase / --ff-only を付けるか、git config pull.rebase で既定を設定してください。
 
  📋 SYNOPSIS
-    git pull [<remote>] [<branch>] [--rebase]
+    git pull [--rebase[=merges] | --no-rebase] [--ff | --no-ff | --ff-only]
+             [--squash] [--autostash] [<remote>] [<branch>]
 
  ⚙️  COMMON OPTIONS
-    --rebase
-        (現在未実装) マージコミットを作らずに、履歴を一直線にして取り込みます。
+    -r, --rebase[=merges]
+        マージコミットを作らずに、自分のコミットを取得したブランチの先頭に付け替えます（リベース）。
+        履歴が一直線になります。
+
+    --no-rebase
+        マージで取り込みます。
+
+    --ff-only
+        早送り（Fast-forward）できる場合だけ取り込みます。分岐していたら何もせずに中止します。
+
+    --no-ff
+        早送りできる場合でもマージコミットを作成します。
+
+    --squash
+        変更内容をワーキングツリーとインデックスにだけ取り込み、コミットはしません。
+
+    --autostash
+        作業中の変更を一時的に stash してから取り込み、終わったら元に戻します。
+        コンフリクトで止まった時は stash に残るので、解決後に git stash pop します。
+
+ ⚙️  CONFIGURATION
+    pull.rebase        true / false / merges  （--rebase を省略した時の既定）
+    pull.ff            only / false           （--ff-only / --no-ff を省略した時の既定）
+    branch.<name>.rebase  ブランチごとの pull.rebase
+    rebase.autoStash   リベース時に常に --autostash する
 
  🛠  PRACTICAL EXAMPLES
     1. 基本: リモートの更新を取り込む
@@ -276,9 +742,14 @@ func (c *PullCommand) Help() string {
        そんな時はリベースを使います。履歴をきれいに一直線に保てます。
        $ git pull --rebase
 
+    3. 実践: チームの方針を設定しておく
+       $ git config pull.rebase true
+       $ git config pull.ff only
+
+    4. 実践: 作業中の変更があっても取り込む
+       $ git pull --rebase --autostash
+
  🔗 REFERENCE
     Full documentation: https://git-scm.com/docs/git-pull
 `
 }
-
-// isFastForward moved to utils.go
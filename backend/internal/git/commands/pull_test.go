@@ -8,6 +8,7 @@ import (
 
 	"github.com/go-git/go-billy/v5/memfs"
 	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/go-git/go-git/v5/storage/memory"
 	"github.com/kurobon/gitgym/backend/internal/git"
@@ -88,7 +89,7 @@ func TestPull_Merge(t *testing.T) {
 
 	// 4. Pull
 	cmd := &PullCommand{}
-	output, err := cmd.Execute(context.Background(), session, []string{"pull", "origin"}) // default merges origin/master
+	output, err := cmd.Execute(context.Background(), session, []string{"pull", "--no-rebase", "origin"}) // merges origin/master
 	if err != nil {
 		t.Fatalf("pull failed: %v", err)
 	}
@@ -149,7 +150,7 @@ func TestPull_Conflict(t *testing.T) {
 
 	// 4. Pull
 	cmd := &PullCommand{}
-	output, err := cmd.Execute(context.Background(), session, []string{"pull"})
+	output, err := cmd.Execute(context.Background(), session, []string{"pull", "--no-rebase"})
 	if err != nil {
 		t.Fatalf("pull execution returned error (should handle conflict gracefully?): %v", err)
 	}
@@ -171,3 +172,206 @@ func TestPull_Conflict(t *testing.T) {
 		t.Errorf("Conflict markers missing in file.txt: %s", fileStr)
 	}
 }
+
+func TestPull_Modes(t *testing.T) {
+	ctx := context.Background()
+	setup := func(t *testing.T, name string) (*git.Session, *gogit.Repository, *gogit.Repository) {
+		remoteRepo, _ := gogit.Init(memory.NewStorage(), memfs.New())
+		commitFile(t, remoteRepo, "base.txt", "base\n", "Initial commit")
+
+		sm := git.NewSessionManager()
+		sm.DataDir = t.TempDir()
+		remoteURL := "https://example.com/" + name + ".git"
+		sm.SharedRemotes[remoteURL] = remoteRepo
+		session, _ := sm.CreateSession("test-pull-" + name)
+		if _, err := (&CloneCommand{}).Execute(ctx, session, []string{"clone", remoteURL}); err != nil {
+			t.Fatalf("setup: clone failed: %v", err)
+		}
+		return session, session.GetRepo(), remoteRepo
+	}
+	headCommit := func(repo *gogit.Repository) *object.Commit {
+		head, _ := repo.Head()
+		c, _ := repo.CommitObject(head.Hash())
+		return c
+	}
+	readFile := func(repo *gogit.Repository, name string) string {
+		w, _ := repo.Worktree()
+		f, err := w.Filesystem.Open(name)
+		if err != nil {
+			return ""
+		}
+		defer f.Close()
+		buf := make([]byte, 256)
+		n, _ := f.Read(buf)
+		return string(buf[:n])
+	}
+
+	t.Run("divergent branches need a mode", func(t *testing.T) {
+		session, local, remote := setup(t, "divergent")
+		commitFile(t, remote, "remote.txt", "remote\n", "Remote commit")
+		commitFile(t, local, "local.txt", "local\n", "Local commit")
+
+		_, err := (&PullCommand{}).Execute(ctx, session, []string{"pull"})
+		if err == nil || !strings.Contains(err.Error(), "hint: You have divergent branches") {
+			t.Fatalf("expected divergent branches hint, got %v", err)
+		}
+
+		_, err = (&PullCommand{}).Execute(ctx, session, []string{"pull", "--ff-only"})
+		if err == nil || !strings.Contains(err.Error(), "Not possible to fast-forward") {
+			t.Fatalf("expected --ff-only to refuse, got %v", err)
+		}
+	})
+
+	t.Run("pull.rebase rebases local commits", func(t *testing.T) {
+		session, local, remote := setup(t, "rebase")
+		commitFile(t, remote, "remote.txt", "remote\n", "Remote commit")
+		commitFile(t, local, "local.txt", "local\n", "Local commit")
+
+		if _, err := git.Dispatch(ctx, session, "config", []string{"config", "pull.rebase", "true"}); err != nil {
+			t.Fatalf("config failed: %v", err)
+		}
+		if _, err := (&PullCommand{}).Execute(ctx, session, []string{"pull"}); err != nil {
+			t.Fatalf("pull failed: %v", err)
+		}
+		head := headCommit(local)
+		if head.Message != "Local commit" || head.NumParents() != 1 {
+			t.Fatalf("expected rebased local commit on top, got %q with %d parents", head.Message, head.NumParents())
+		}
+		parent, _ := head.Parent(0)
+		if parent.Message != "Remote commit" {
+			t.Errorf("expected linear history over the remote commit, got parent %q", parent.Message)
+		}
+	})
+
+	t.Run("no-ff creates a merge commit", func(t *testing.T) {
+		session, local, remote := setup(t, "noff")
+		commitFile(t, remote, "remote.txt", "remote\n", "Remote commit")
+
+		if _, err := (&PullCommand{}).Execute(ctx, session, []string{"pull", "--no-ff"}); err != nil {
+			t.Fatalf("pull failed: %v", err)
+		}
+		if n := headCommit(local).NumParents(); n != 2 {
+			t.Errorf("expected a merge commit, got %d parents", n)
+		}
+	})
+
+	t.Run("squash leaves HEAD alone", func(t *testing.T) {
+		session, local, remote := setup(t, "squash")
+		commitFile(t, remote, "remote.txt", "remote\n", "Remote commit")
+		before := headCommit(local).Hash
+
+		out, err := (&PullCommand{}).Execute(ctx, session, []string{"pull", "--squash"})
+		if err != nil {
+			t.Fatalf("pull failed: %v", err)
+		}
+		if !strings.Contains(out, "Squash commit -- not updating HEAD") {
+			t.Errorf("unexpected output: %s", out)
+		}
+		if headCommit(local).Hash != before {
+			t.Errorf("HEAD moved on --squash")
+		}
+		if readFile(local, "remote.txt") != "remote\n" {
+			t.Errorf("squashed changes missing from worktree")
+		}
+	})
+
+	t.Run("autostash carries local changes", func(t *testing.T) {
+		session, local, remote := setup(t, "autostash")
+		commitFile(t, remote, "remote.txt", "remote\n", "Remote commit")
+		commitFile(t, local, "local.txt", "local\n", "Local commit")
+		w, _ := local.Worktree()
+		f, _ := w.Filesystem.Create("base.txt")
+		f.Write([]byte("work in progress\n"))
+		f.Close()
+
+		_, err := (&PullCommand{}).Execute(ctx, session, []string{"pull", "--rebase"})
+		if err == nil || !strings.Contains(err.Error(), "cannot pull with rebase") {
+			t.Fatalf("expected dirty worktree to be refused, got %v", err)
+		}
+
+		out, err := (&PullCommand{}).Execute(ctx, session, []string{"pull", "--rebase", "--autostash"})
+		if err != nil {
+			t.Fatalf("pull failed: %v", err)
+		}
+		if !strings.Contains(out, "Created autostash: ") || !strings.Contains(out, "Applied autostash.") {
+			t.Errorf("unexpected output: %s", out)
+		}
+		if readFile(local, "base.txt") != "work in progress\n" {
+			t.Errorf("local changes lost: %q", readFile(local, "base.txt"))
+		}
+		if headCommit(local).Message != "Local commit" {
+			t.Errorf("expected rebased local commit at HEAD")
+		}
+	})
+
+	t.Run("local changes stay put without autostash", func(t *testing.T) {
+		session, local, remote := setup(t, "dirty")
+		commitFile(t, remote, "remote.txt", "remote\n", "Remote commit")
+		w, _ := local.Worktree()
+		f, _ := w.Filesystem.Create("base.txt")
+		f.Write([]byte("work in progress\n"))
+		f.Close()
+
+		// Fast-forward keeps the unstaged change
+		if _, err := (&PullCommand{}).Execute(ctx, session, []string{"pull"}); err != nil {
+			t.Fatalf("pull failed: %v", err)
+		}
+		if readFile(local, "base.txt") != "work in progress\n" || readFile(local, "remote.txt") != "remote\n" {
+			t.Errorf("fast-forward lost local changes or missed the update")
+		}
+		if ref, _ := local.Reference(plumbing.ReferenceName(StashRefName), true); ref != nil {
+			t.Error("pull without --autostash should not stash")
+		}
+
+		// A merge commit leaves the unstaged change out
+		commitFile(t, remote, "remote2.txt", "more\n", "Second remote commit")
+		if _, err := (&PullCommand{}).Execute(ctx, session, []string{"pull", "--no-ff"}); err != nil {
+			t.Fatalf("pull --no-ff failed: %v", err)
+		}
+		head := headCommit(local)
+		if head.NumParents() != 2 {
+			t.Fatalf("expected a merge commit, got %d parents", head.NumParents())
+		}
+		if f, _ := head.File("base.txt"); f == nil {
+			t.Fatal("base.txt missing from the merge")
+		} else if content, _ := f.Contents(); content != "base\n" {
+			t.Errorf("merge commit picked up the local change: %q", content)
+		}
+		if readFile(local, "base.txt") != "work in progress\n" {
+			t.Errorf("local change lost by the merge")
+		}
+
+		// Staged changes are in the way of a merge
+		w.Add("base.txt")
+		commitFile(t, remote, "remote3.txt", "again\n", "Third remote commit")
+		_, err := (&PullCommand{}).Execute(ctx, session, []string{"pull", "--no-ff"})
+		if err == nil || !strings.Contains(err.Error(), "would be overwritten by merge:\n\tbase.txt") {
+			t.Errorf("expected staged changes to block the merge, got %v", err)
+		}
+	})
+
+	t.Run("autostash is kept when the merge conflicts", func(t *testing.T) {
+		session, local, remote := setup(t, "autostash-conflict")
+		commitFile(t, remote, "base.txt", "remote\n", "Remote commit")
+		commitFile(t, local, "base.txt", "local\n", "Local commit")
+		w, _ := local.Worktree()
+		f, _ := w.Filesystem.Create("notes.txt")
+		f.Write([]byte("untracked work\n"))
+		f.Close()
+		w.Add("notes.txt")
+
+		out, err := (&PullCommand{}).Execute(ctx, session, []string{"pull", "--no-rebase", "--autostash"})
+		if err != nil {
+			t.Fatalf("pull failed: %v", err)
+		}
+		if !strings.Contains(out, "CONFLICT") || !strings.Contains(out, "Your changes are safe in the stash.") {
+			t.Errorf("unexpected output: %s", out)
+		}
+		if ref, _ := local.Reference(plumbing.ReferenceName(StashRefName), true); ref == nil {
+			t.Error("the autostash entry should be kept")
+		}
+		if readFile(local, "notes.txt") != "" {
+			t.Error("the stash should not be applied to the conflicted merge")
+		}
+	})
+}
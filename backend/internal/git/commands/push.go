@@ -11,12 +11,13 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
 	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
 	"github.com/go-git/go-git/v5/plumbing"
-	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/kurobon/gitgym/backend/internal/git"
 )
 
@@ -30,17 +31,34 @@ type PushCommand struct{}
 var _ git.Command = (*PushCommand)(nil)
 
 type PushOptions struct {
-	Remote  string
-	Refspec string
-	Force   bool
-	DryRun  bool
+	Remote         string
+	Refspecs       []string
+	Force          bool
+	DryRun         bool
+	SetUpstream    bool
+	Delete         bool // Refspecs name remote refs to delete
+	Tags           bool // Push all refs/tags/*
+	FollowTags     bool // Push annotated tags that point into the pushed history
+	All            bool // Push all branches
+	ForceWithLease bool
+	Leases         []pushLease
+	Atomic         bool
+}
+
+// pushLease is one --force-with-lease=<ref>[:<expect>]. Without an explicit
+// expectation the remote-tracking ref is the expected value.
+type pushLease struct {
+	Ref       string
+	Expect    string
+	HasExpect bool
 }
 
 type pushContext struct {
 	TargetRepo *gogit.Repository
 	RemoteName string
-	RemoteURL  string
-	Ref        *plumbing.Reference // The local ref to push (HEAD or specific branch/tag)
+	RemoteURL  string // As shown to the user
+	Remote     *config.RemoteConfig
+	Updates    []*refUpdate
 }
 
 func (c *PushCommand) Execute(ctx context.Context, s *git.Session, args []string) (string, error) {
@@ -61,44 +79,50 @@ func (c *PushCommand) Execute(ctx context.Context, s *git.Session, args []string
 		return "", err
 	}
 
-	// if opts.DryRun {
-	// 	// Quick check before resolution? Or resolved dry run?
-	// 	// Logic below does full resolution then prints matches.
-	// }
-
-	// 2. Resolve Context (Remote, TargetRepo, RefToPush)
+	// 2. Resolve Context (Remote, TargetRepo, ref updates)
 	pCtx, err := c.resolveContext(s, repo, opts)
 	if err != nil {
 		return "", err
 	}
 
 	// 3. Execution (Perform Push)
-	return c.performPush(repo, pCtx, opts)
+	return c.performPush(s, repo, pCtx, opts)
 }
 
 func (c *PushCommand) parseArgs(args []string) (*PushOptions, error) {
-	opts := &PushOptions{
-		Remote: "origin", // Default
-	}
+	opts := &PushOptions{}
 	var positional []string
 
 	cmdArgs := args[1:]
 	for _, arg := range cmdArgs {
-		switch arg {
-		case "-f", "--force":
+		switch {
+		case arg == "-f" || arg == "--force":
 			opts.Force = true
-		case "-n", "--dry-run":
+		case arg == "-n" || arg == "--dry-run":
 			opts.DryRun = true
-		case "-h", "--help":
+		case arg == "-u" || arg == "--set-upstream":
+			opts.SetUpstream = true
+		case arg == "-d" || arg == "--delete":
+			opts.Delete = true
+		case arg == "--tags":
+			opts.Tags = true
+		case arg == "--follow-tags":
+			opts.FollowTags = true
+		case arg == "--all" || arg == "--branches":
+			opts.All = true
+		case arg == "--atomic":
+			opts.Atomic = true
+		case arg == "--force-with-lease":
+			opts.ForceWithLease = true
+		case strings.HasPrefix(arg, "--force-with-lease="):
+			ref, expect, hasExpect := strings.Cut(strings.TrimPrefix(arg, "--force-with-lease="), ":")
+			opts.Leases = append(opts.Leases, pushLease{Ref: ref, Expect: expect, HasExpect: hasExpect})
+		case arg == "-h" || arg == "--help":
 			return nil, fmt.Errorf("help requested")
+		case strings.HasPrefix(arg, "-"):
+			// Other flags are accepted and ignored
 		default:
-			if strings.HasPrefix(arg, "-") {
-				// Ignore unknown flags or error? Legacy ignored.
-				// Let's stick to simple "ignore" or strict?
-				// Legacy: switch default: if strings.HasPrefix(arg, "-") { ignore } else { positional... }
-			} else {
-				positional = append(positional, arg)
-			}
+			positional = append(positional, arg)
 		}
 	}
 
@@ -106,186 +130,530 @@ func (c *PushCommand) parseArgs(args []string) (*PushOptions, error) {
 		opts.Remote = positional[0]
 	}
 	if len(positional) > 1 {
-		opts.Refspec = positional[1]
+		opts.Refspecs = positional[1:]
 	}
 
+	switch {
+	case opts.All && (opts.Tags || opts.Delete || len(opts.Refspecs) > 0):
+		return nil, fmt.Errorf("fatal: options '--all' and '--tags', '--delete' or refspecs cannot be used together")
+	case opts.Delete && len(opts.Refspecs) == 0:
+		return nil, fmt.Errorf("fatal: --delete doesn't make sense without any refs")
+	case opts.Delete && opts.Tags:
+		return nil, fmt.Errorf("fatal: options '--delete' and '--tags' cannot be used together")
+	}
 	return opts, nil
 }
 
 func (c *PushCommand) resolveContext(s *git.Session, repo *gogit.Repository, opts *PushOptions) (*pushContext, error) {
+	// Default: the current branch's upstream remote, else origin
+	if opts.Remote == "" {
+		opts.Remote = git.DefaultRemote(repo)
+	}
+
 	// Resolve Remote URL
 	rem, err := repo.Remote(opts.Remote)
 	if err != nil {
 		return nil, fmt.Errorf("fatal: '%s' does not appear to be a git repository", opts.Remote)
 	}
 
-	cfg := rem.Config()
-	if len(cfg.URLs) == 0 {
+	repoCfg, err := repo.Config()
+	if err != nil {
+		return nil, err
+	}
+	remoteCfg := rem.Config()
+	// remote.<name>.pushurl takes precedence over the fetch URL
+	urls := remotePushURLs(repoCfg, remoteCfg)
+	if len(urls) == 0 {
 		return nil, fmt.Errorf("remote %s has no URL defined", opts.Remote)
 	}
-	url := cfg.URLs[0]
+	url := urls[0]
 
-	// Resolve local simulated remote path
-	lookupKey := strings.TrimPrefix(url, "/")
+	targetRepo, err := git.ResolveRemoteURL(s, url)
+	if err != nil {
+		return nil, err
+	}
 
-	var targetRepo *gogit.Repository
-	var ok bool
+	displayURL := url
+	if len(remoteCfg.URLs) > 0 && url == remoteCfg.URLs[0] {
+		displayURL = remoteDisplayURL(repoCfg, opts.Remote, url)
+	}
 
-	// Check Session-local Repos
-	targetRepo, ok = s.Repos[lookupKey]
-	if !ok && s.Manager != nil {
-		// Check Shared Remotes
-		targetRepo, ok = s.Manager.SharedRemotes[lookupKey] // e.g. "repo.git"
+	pCtx := &pushContext{
+		TargetRepo: targetRepo,
+		RemoteName: opts.Remote,
+		RemoteURL:  displayURL,
+		Remote:     remoteCfg,
+	}
+	if err := c.planPush(repo, pCtx, opts); err != nil {
+		return nil, err
+	}
+	return pCtx, nil
+}
 
-		// Fallback: Check using full URL
-		if !ok {
-			targetRepo, ok = s.Manager.SharedRemotes[url]
+// planPush lists the ref updates the push makes on the remote.
+func (c *PushCommand) planPush(repo *gogit.Repository, pCtx *pushContext, opts *PushOptions) error {
+	target := pCtx.TargetRepo
+	seen := make(map[plumbing.ReferenceName]bool)
+	add := func(src, dst plumbing.ReferenceName, hash plumbing.Hash, force bool) {
+		if seen[dst] {
+			return
 		}
+		seen[dst] = true
+		u := &refUpdate{Src: src, Dst: dst, New: hash, Force: force || opts.Force}
+		if ref, err := target.Storer.Reference(dst); err == nil {
+			u.Old = ref.Hash()
+		}
+		pCtx.Updates = append(pCtx.Updates, u)
+	}
+	remoteRef := func(name string) (plumbing.ReferenceName, bool) {
+		return dwimRef(name, refExists(target))
 	}
 
-	if !ok {
-		// FALLBACK: Local filesystem path (persistent remote)
-		targetRepo, err = gogit.PlainOpen(url)
-		if err == nil {
-			ok = true
-		} else {
-			targetRepo, err = gogit.PlainOpen(lookupKey)
-			if err == nil {
-				ok = true
+	localRefs, err := sortedHashRefs(repo)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case opts.All:
+		for _, ref := range localRefs {
+			if ref.Name().IsBranch() {
+				add(ref.Name(), ref.Name(), ref.Hash(), false)
 			}
 		}
-	}
 
-	if !ok {
-		return nil, fmt.Errorf("remote repository '%s' not found (only local simulation supported)", url)
+	case opts.Delete:
+		for _, name := range opts.Refspecs {
+			dst, ok := remoteRef(name)
+			if !ok {
+				return fmt.Errorf("error: unable to delete '%s': remote ref does not exist\nerror: failed to push some refs to '%s'", name, pCtx.RemoteURL)
+			}
+			add("", dst, plumbing.ZeroHash, false)
+		}
+
+	case len(opts.Refspecs) > 0:
+		for _, arg := range opts.Refspecs {
+			if err := c.planRefSpec(repo, pCtx, arg, add, remoteRef, localRefs); err != nil {
+				return err
+			}
+		}
+
+	case !opts.Tags:
+		// Default: the current branch to its upstream on this remote, else the same name
+		head, err := repo.Head()
+		if err != nil {
+			return fmt.Errorf("failed to get HEAD: %w", err)
+		}
+		if !head.Name().IsBranch() {
+			return fmt.Errorf("fatal: You are not currently on a branch.")
+		}
+		dst := head.Name()
+		if remote, merge, ok := git.BranchUpstream(repo, head.Name().Short()); ok && remote == pCtx.RemoteName {
+			dst = merge
+		}
+		add(head.Name(), dst, head.Hash(), false)
 	}
 
-	// Determined Ref to Push
-	var refToPush *plumbing.Reference
+	// --tags: refs/tags/*:refs/tags/*
+	if opts.Tags {
+		for _, ref := range localRefs {
+			if ref.Name().IsTag() {
+				add(ref.Name(), ref.Name(), ref.Hash(), false)
+			}
+		}
+	}
 
-	if opts.Refspec != "" {
-		// Try to resolve refspec (Branch or Tag)
-		ref, refErr := repo.Reference(plumbing.ReferenceName(opts.Refspec), true)
-		if refErr == nil {
-			refToPush = ref
-		} else {
-			// Try refs/heads/
-			ref, err = repo.Reference(plumbing.ReferenceName("refs/heads/"+opts.Refspec), true)
-			if err == nil {
-				refToPush = ref
-			} else {
-				// Try refs/tags/
-				ref, err = repo.Reference(plumbing.ReferenceName("refs/tags/"+opts.Refspec), true)
-				if err == nil {
-					refToPush = ref
-				} else {
-					return nil, fmt.Errorf("src refspec '%s' does not match any", opts.Refspec)
+	// --follow-tags: annotated tags pointing at pushed commits the remote lacks
+	if opts.FollowTags {
+		for _, ref := range localRefs {
+			if !ref.Name().IsTag() || seen[ref.Name()] {
+				continue
+			}
+			if _, err := target.Storer.Reference(ref.Name()); err == nil {
+				continue
+			}
+			if _, err := repo.TagObject(ref.Hash()); err != nil {
+				continue
+			}
+			tagged, ok := git.PeelToCommit(repo, ref.Hash())
+			if !ok {
+				continue
+			}
+			for _, u := range pCtx.Updates {
+				if u.New.IsZero() || !u.Dst.IsBranch() {
+					continue
+				}
+				if ff, err := git.IsFastForward(repo, tagged, u.New); err == nil && ff {
+					add(ref.Name(), ref.Name(), ref.Hash(), false)
+					break
 				}
 			}
 		}
-	} else {
-		// Default: Push HEAD
-		headRef, headErr := repo.Head()
-		if headErr != nil {
-			return nil, fmt.Errorf("failed to get HEAD: %w", headErr)
+	}
+	return nil
+}
+
+// planRefSpec adds the updates of one command-line refspec: "[+]src[:dst]",
+// ":dst" to delete, or a pattern like "refs/heads/*:refs/heads/*".
+func (c *PushCommand) planRefSpec(repo *gogit.Repository, pCtx *pushContext, arg string,
+	add func(src, dst plumbing.ReferenceName, hash plumbing.Hash, force bool),
+	remoteRef func(string) (plumbing.ReferenceName, bool), localRefs []*plumbing.Reference) error {
+
+	force, src, dst, err := parseRefSpec(arg)
+	if err != nil {
+		return err
+	}
+
+	// ":dst" deletes the remote ref
+	if src == "" {
+		name, ok := remoteRef(dst)
+		if !ok {
+			return fmt.Errorf("error: unable to delete '%s': remote ref does not exist\nerror: failed to push some refs to '%s'", dst, pCtx.RemoteURL)
+		}
+		add("", name, plumbing.ZeroHash, false)
+		return nil
+	}
+
+	if strings.Contains(src, "*") {
+		if dst == "" {
+			dst = src
 		}
-		if !headRef.Name().IsBranch() {
-			return nil, fmt.Errorf("HEAD is not on a branch (detached?)")
+		if !strings.HasPrefix(src, "refs/") || !strings.HasPrefix(dst, "refs/") {
+			return fmt.Errorf("fatal: invalid refspec '%s'", arg)
 		}
-		refToPush = headRef
+		spec := config.RefSpec(src + ":" + dst)
+		for _, ref := range localRefs {
+			if spec.Match(ref.Name()) {
+				add(ref.Name(), spec.Dst(ref.Name()), ref.Hash(), force)
+			}
+		}
+		return nil
 	}
 
-	return &pushContext{
-		TargetRepo: targetRepo,
-		RemoteName: opts.Remote,
-		RemoteURL:  url,
-		Ref:        refToPush,
-	}, nil
+	// Source: a local ref, else any revision (HEAD, a commit id)
+	var srcName plumbing.ReferenceName
+	var hash plumbing.Hash
+	if name, ok := dwimRef(src, refExists(repo)); ok {
+		ref, err := repo.Reference(name, true)
+		if err != nil {
+			return err
+		}
+		srcName, hash = name, ref.Hash()
+	} else if h, err := repo.ResolveRevision(plumbing.Revision(src)); err == nil {
+		srcName, hash = plumbing.ReferenceName(src), *h
+		if src == "HEAD" {
+			if head, err := repo.Head(); err == nil && head.Name().IsBranch() {
+				srcName = head.Name()
+			}
+		}
+	} else {
+		return fmt.Errorf("error: src refspec %s does not match any\nerror: failed to push some refs to '%s'", src, pCtx.RemoteURL)
+	}
+
+	// Destination: as given, an existing remote ref, or the source's kind
+	var dstName plumbing.ReferenceName
+	switch {
+	case dst == "" && (srcName.IsBranch() || srcName.IsTag() || strings.HasPrefix(srcName.String(), "refs/")):
+		dstName = srcName
+	case dst == "":
+		return fmt.Errorf("error: The destination you provided is not a full refname (i.e.,\nstarting with \"refs/\"). Specify it as '%s:refs/heads/<name>'.", src)
+	case strings.HasPrefix(dst, "refs/"):
+		dstName = plumbing.ReferenceName(dst)
+	default:
+		if name, ok := remoteRef(dst); ok && (name.IsBranch() || name.IsTag()) {
+			dstName = name
+		} else if srcName.IsTag() {
+			dstName = plumbing.NewTagReferenceName(dst)
+		} else {
+			dstName = plumbing.NewBranchReferenceName(dst)
+		}
+	}
+	add(srcName, dstName, hash, force)
+	return nil
 }
 
-func (c *PushCommand) performPush(repo *gogit.Repository, pCtx *pushContext, opts *PushOptions) (string, error) {
-	refName := pCtx.Ref.Name()
-	targetRepo := pCtx.TargetRepo
+// leaseFor returns the value --force-with-lease expects dst to have on the
+// remote, if the push is protected by a lease for it.
+func (c *PushCommand) leaseFor(repo *gogit.Repository, pCtx *pushContext, opts *PushOptions, dst plumbing.ReferenceName) (plumbing.Hash, bool, error) {
+	lease := pushLease{}
+	found := opts.ForceWithLease
+	for _, l := range opts.Leases {
+		if l.Ref == dst.String() || l.Ref == dst.Short() {
+			lease, found = l, true
+		}
+	}
+	if !found {
+		return plumbing.ZeroHash, false, nil
+	}
+
+	if lease.HasExpect {
+		if lease.Expect == "" {
+			return plumbing.ZeroHash, true, nil // The ref must not exist yet
+		}
+		h, err := repo.ResolveRevision(plumbing.Revision(lease.Expect))
+		if err != nil {
+			return plumbing.ZeroHash, false, fmt.Errorf("error: cannot parse expected object name '%s'", lease.Expect)
+		}
+		return *h, true, nil
+	}
+
+	// The remote-tracking ref records what we last saw on the remote
+	for _, spec := range pCtx.Remote.Fetch {
+		if !spec.Match(dst) {
+			continue
+		}
+		if ref, err := repo.Storer.Reference(spec.Dst(dst)); err == nil {
+			return ref.Hash(), true, nil
+		}
+	}
+	return plumbing.ZeroHash, true, nil
+}
 
-	// Check Fast-Forward (only for branches)
-	if refName.IsBranch() && !opts.Force {
-		targetRef, targetErr := targetRepo.Reference(refName, true)
-		if targetErr == nil {
-			isFF, gitErr := git.IsFastForward(repo, targetRef.Hash(), pCtx.Ref.Hash())
-			if gitErr != nil {
-				return "", gitErr
+func (c *PushCommand) performPush(s *git.Session, repo *gogit.Repository, pCtx *pushContext, opts *PushOptions) (string, error) {
+	// Client-side checks: leases (deletions included), fast-forwards, existing tags
+	var shown, upToDate []*refUpdate
+	for _, u := range pCtx.Updates {
+		expect, leased, err := c.leaseFor(repo, pCtx, opts, u.Dst)
+		if err != nil {
+			return "", err
+		}
+		if leased {
+			if u.Old != expect {
+				u.reject("stale info")
+				shown = append(shown, u)
+				continue
 			}
-			if !isFF {
-				return "", fmt.Errorf("non-fast-forward update rejected (use --force to override)")
+			u.Force = true
+		}
+		classifyUpdate(repo, u, "already exists")
+		if u.Status == refTagUpdate {
+			u.Status = refForced // push reports moved tags as forced updates
+		}
+		if u.Status == refUpToDate {
+			upToDate = append(upToDate, u)
+		} else {
+			shown = append(shown, u)
+		}
+	}
+	if len(shown) == 0 {
+		out := "Everything up-to-date"
+		if opts.SetUpstream && !opts.DryRun {
+			tracking, err := setUpstreams(repo, pCtx, upToDate)
+			if err != nil {
+				return "", err
 			}
+			out += tracking
+		}
+		return out, nil
+	}
+
+	// Server side: the update is received under the lock that other pushes,
+	// bots and simulated commits take to write to shared remotes
+	var remoteMessages string
+	if opts.DryRun {
+		rejectAtomically(shown, opts.Atomic)
+	} else {
+		if s.Manager != nil {
+			s.Manager.Lock()
 		}
-	} else if refName.IsTag() {
-		_, tagRefErr := targetRepo.Reference(refName, true)
-		if tagRefErr == nil && !opts.Force {
-			return "", fmt.Errorf("tag '%s' already exists (use --force to override)", refName.Short())
+		var err error
+		remoteMessages, err = c.receive(s, repo, pCtx, shown, opts.Atomic)
+		if s.Manager != nil {
+			s.Manager.Unlock()
 		}
+		if err != nil {
+			return "", err
+		}
+		c.afterUpdates(s, repo, pCtx, shown)
 	}
 
+	failed := false
+	for _, u := range shown {
+		failed = failed || u.Failed()
+	}
+	out := remoteMessages + formatPushUpdates(pCtx.RemoteURL, shown)
 	if opts.DryRun {
-		return fmt.Sprintf("[dry-run] Would push %s to %s at %s", refName.Short(), pCtx.RemoteName, pCtx.RemoteURL), nil
+		out += "\n[dry-run] no refs were updated"
+	}
+
+	if opts.SetUpstream && !opts.DryRun {
+		tracking, err := setUpstreams(repo, pCtx, append(shown, upToDate...))
+		if err != nil {
+			return "", err
+		}
+		out += tracking
 	}
 
-	// SIMULATE PUSH: Copy Objects + Update Ref
-	hashToSync := pCtx.Ref.Hash()
+	if failed {
+		return "", errors.New(out + pushFailureHints(pCtx, shown))
+	}
+	return out, nil
+}
 
-	// Check object type
-	obj, err := repo.Storer.EncodedObject(plumbing.AnyObject, hashToSync)
+// receive is the server side of a push: objects wait in quarantine while
+// branch protection rules decide, and only accepted updates bring theirs into
+// the remote. It returns the messages of the remote's hooks.
+func (c *PushCommand) receive(s *git.Session, repo *gogit.Repository, pCtx *pushContext, updates []*refUpdate, atomic bool) (string, error) {
+	targetRepo := pCtx.TargetRepo
+	quarantine, err := git.Quarantine(targetRepo)
 	if err != nil {
 		return "", err
 	}
 
-	if obj.Type() == plumbing.TagObject {
-		// Annotated tag logic
-		if !git.HasObject(targetRepo, hashToSync) {
-			_, err = targetRepo.Storer.SetEncodedObject(obj)
-			if err != nil {
+	var remoteMessages strings.Builder
+	for _, u := range updates {
+		if u.Failed() {
+			continue
+		}
+		if !u.New.IsZero() {
+			if err := git.CopyObjectRecursive(repo, quarantine, u.New); err != nil {
 				return "", err
 			}
 		}
-		// Decode tag to find target commit
-		tagObj, decodeErr := object.DecodeTag(repo.Storer, obj)
-		if decodeErr != nil {
-			return "", decodeErr
+		if s.Manager == nil {
+			continue
 		}
-		if copyErr := git.CopyCommitRecursive(repo, targetRepo, tagObj.Target); copyErr != nil {
-			return "", copyErr
-		}
-	} else if obj.Type() == plumbing.CommitObject {
-		if copyErr := git.CopyCommitRecursive(repo, targetRepo, hashToSync); copyErr != nil {
-			return "", copyErr
+		update := git.RefUpdate{Name: u.Dst, Old: u.Old, New: u.New, Pusher: s.Identity(), Quarantine: quarantine}
+		if hookErr := s.Manager.CheckRefUpdateLocked(targetRepo, update); hookErr != nil {
+			var protErr *git.ProtectionError
+			if errors.As(hookErr, &protErr) {
+				remoteMessages.WriteString(protErr.RemoteMessages())
+			}
+			u.Status, u.Reason = refRemoteRejected, hookErr.Error()
 		}
-	} else {
-		return "", fmt.Errorf("unsupported object type to push: %s", obj.Type())
 	}
+	rejectAtomically(updates, atomic)
 
-	// Update Remote Reference
-	err = targetRepo.Storer.SetReference(pCtx.Ref)
-	if err != nil {
+	for _, u := range updates {
+		if u.Failed() || u.New.IsZero() {
+			continue
+		}
+		if err := git.CopyObjectRecursive(quarantine, targetRepo, u.New); err != nil {
+			return "", err
+		}
+	}
+	if err := c.applyUpdates(repo, pCtx, updates); err != nil {
 		return "", err
 	}
+	return remoteMessages.String(), nil
+}
 
-	// Update Local Remote-Tracking Reference (ONLY for branches)
-	if refName.IsBranch() {
-		localRemoteRefName := plumbing.ReferenceName(fmt.Sprintf("refs/remotes/%s/%s", pCtx.RemoteName, refName.Short()))
-		newLocalRemoteRef := plumbing.NewHashReference(localRemoteRefName, hashToSync)
-		_ = repo.Storer.SetReference(newLocalRemoteRef)
+// rejectAtomically handles --atomic: one refused ref refuses them all.
+func rejectAtomically(updates []*refUpdate, atomic bool) {
+	if !atomic {
+		return
+	}
+	failed := false
+	for _, u := range updates {
+		failed = failed || u.Failed()
 	}
+	if !failed {
+		return
+	}
+	for _, u := range updates {
+		if !u.Failed() {
+			u.reject("atomic push failed")
+		}
+	}
+}
+
+// setUpstreams handles -u: each pushed branch, including those already up to
+// date, now tracks its counterpart on this remote.
+func setUpstreams(repo *gogit.Repository, pCtx *pushContext, updates []*refUpdate) (string, error) {
+	var out string
+	for _, u := range updates {
+		if u.Failed() || u.Status == refDeleted || !u.Src.IsBranch() || !u.Dst.IsBranch() {
+			continue
+		}
+		if err := git.SetBranchUpstream(repo, u.Src.Short(), pCtx.RemoteName, u.Dst); err != nil {
+			return "", err
+		}
+		out += fmt.Sprintf("\nbranch '%s' set up to track '%s/%s'.", u.Src.Short(), pCtx.RemoteName, u.Dst.Short())
+	}
+	return out, nil
+}
+
+// applyUpdates moves the accepted refs on the remote and the matching
+// remote-tracking refs locally.
+func (c *PushCommand) applyUpdates(repo *gogit.Repository, pCtx *pushContext, updates []*refUpdate) error {
+	targetRepo := pCtx.TargetRepo
+	for _, u := range updates {
+		if u.Failed() {
+			continue
+		}
+
+		if u.Status == refDeleted {
+			if err := targetRepo.Storer.RemoveReference(u.Dst); err != nil {
+				return err
+			}
+		} else if err := targetRepo.Storer.SetReference(plumbing.NewHashReference(u.Dst, u.New)); err != nil {
+			return err
+		}
+
+		// Update Local Remote-Tracking Reference (via the remote's fetch refspecs)
+		for _, spec := range pCtx.Remote.Fetch {
+			if !spec.Match(u.Dst) {
+				continue
+			}
+			tracking := spec.Dst(u.Dst)
+			if u.Status == refDeleted {
+				_ = repo.Storer.RemoveReference(tracking)
+			} else {
+				_ = repo.Storer.SetReference(plumbing.NewHashReference(tracking, u.New))
+			}
+		}
+	}
+	return nil
+}
 
-	// Get old hash for display (if updating existing ref)
-	oldHashStr := "0000000"
-	if refName.IsBranch() {
-		existingRef, refErr := targetRepo.Reference(refName, true)
-		if refErr == nil {
-			oldHashStr = existingRef.Hash().String()[:7]
+// afterUpdates runs what a push triggers on the server once the remote lock is
+// released: simulated CI on each pushed branch tip, bots waiting for the push,
+// and the comparison of open pull requests against the new tips.
+func (c *PushCommand) afterUpdates(s *git.Session, repo *gogit.Repository, pCtx *pushContext, updates []*refUpdate) {
+	targetRepo := pCtx.TargetRepo
+	applied := false
+	for _, u := range updates {
+		if u.Failed() {
+			continue
+		}
+		applied = true
+		if s.Manager != nil && u.Dst.IsBranch() && u.Status != refDeleted {
+			if _, err := repo.CommitObject(u.New); err == nil {
+				s.Manager.RunStatusChecks(targetRepo, u.New)
+				s.Manager.Bots.NotifyPush(targetRepo, u.Dst)
+			}
 		}
 	}
+	if applied {
+		git.RefreshPullRequests(s.Manager, targetRepo)
+	}
+}
 
-	return fmt.Sprintf("To %s\n   %s..%s  %s -> %s/%s", pCtx.RemoteURL, oldHashStr, hashToSync.String()[:7], refName.Short(), pCtx.RemoteName, refName.Short()), nil
+// pushFailureHints returns git's trailer for a push with refused refs.
+func pushFailureHints(pCtx *pushContext, updates []*refUpdate) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "\nerror: failed to push some refs to '%s'", pCtx.RemoteURL)
+	for _, u := range updates {
+		switch u.Reason {
+		case "non-fast-forward":
+			sb.WriteString("\nhint: Updates were rejected because the tip of your current branch is behind" +
+				"\nhint: its remote counterpart. If you want to integrate the remote changes," +
+				"\nhint: use 'git pull' before pushing again." +
+				"\nhint: See the 'Note about fast-forwards' in 'git push --help' for details.")
+			return sb.String()
+		case "fetch first":
+			sb.WriteString("\nhint: Updates were rejected because the remote contains work that you do not" +
+				"\nhint: have locally. This is usually caused by another repository pushing to" +
+				"\nhint: the same ref. If you want to integrate the remote changes, use" +
+				"\nhint: 'git pull' before pushing again." +
+				"\nhint: See the 'Note about fast-forwards' in 'git push --help' for details.")
+			return sb.String()
+		case "already exists":
+			sb.WriteString("\nhint: Updates were rejected because the tag already exists in the remote.")
+			return sb.String()
+		}
+	}
+	return sb.String()
 }
 
 func (c *PushCommand) Help() string {
@@ -296,19 +664,56 @@ func (c *PushCommand) Help() string {
     ・ローカルのブランチをリモートに公開する
     
     ※ GitGymではシミュレーションであり、実際のネットワーク送信は行われません。
+    ※ 共有リモートにブランチ保護ルールがある場合、サーバー側で拒否されることがあります。
+       例: ! [remote rejected] main -> main (protected branch hook declined)
 
  📋 SYNOPSIS
-    git push [<remote>] [<branch>] [--force] [--force-with-lease]
+    git push [-u] [--force | --force-with-lease[=<ref>[:<expect>]]] [--atomic]
+             [--tags | --follow-tags] [<remote> [<refspec>...]]
+    git push --all [<remote>]
+    git push --delete <remote> <ref>...
+
+    <refspec> は [+]<src>:<dst> の形式です。
+      main              ローカルの main をリモートの main へ
+      main:release      ローカルの main をリモートの release へ
+      +main             強制的に（早送りでなくても）更新
+      :old-branch       リモートの old-branch を削除
 
  ⚙️  COMMON OPTIONS
     -u, --set-upstream
-        (現在未実装) リモートブランチとローカルブランチの関連付け(追跡設定)を行います。
+        リモートブランチとローカルブランチの関連付け(追跡設定)を行います。
+        以降は引数なしの git push / git pull / git fetch がこのリモートを使います。
 
     -f, --force
         強制的にプッシュします（リモートの履歴を上書きするので注意）。
 
-    --force-with-lease
-        (現在未実装) より安全な強制プッシュです。他人の更新がないか確認してから上書きします。
+    --force-with-lease[=<ref>[:<expect>]]
+        より安全な強制プッシュです。リモートのブランチが、最後にフェッチした時
+        （リモート追跡ブランチ）または <expect> と同じ場合だけ上書きします。
+        他人の更新があれば "(stale info)" で拒否されます。
+
+    -d, --delete
+        指定したリモートのブランチやタグを削除します。
+
+    --tags
+        全てのタグをプッシュします。
+
+    --follow-tags
+        プッシュするコミットを指す注釈付きタグも一緒にプッシュします。
+
+    --all
+        全てのローカルブランチをプッシュします。
+
+    --atomic
+        全ての参照の更新が成功する場合だけ更新します（1つでも拒否されたら何も更新しません）。
+
+ 💡 TIPS
+    プッシュの結果は参照ごとに1行で表示されます。
+      * [new branch]      feature -> feature         新規作成
+        1a2b3c4..5d6e7f8  main -> main               早送り更新
+      + 1a2b3c4...9f8e7d6 main -> main (forced update)  強制更新
+      - [deleted]         old-branch                 削除
+      ! [rejected]        main -> main (non-fast-forward)  拒否
 
  🛠  PRACTICAL EXAMPLES
     1. 基本: リモートに送信
@@ -319,6 +724,12 @@ func (c *PushCommand) Help() string {
        しかし --force は危険なので、現場では「競合がない時だけ強制する」このオプションを使います。
        $ git push --force-with-lease
 
+    3. 実践: マージ済みのリモートブランチを削除
+       $ git push origin --delete feature
+
+    4. 実践: リリースタグと一緒にプッシュ
+       $ git push --follow-tags origin main
+
  🔗 REFERENCE
     Full documentation: https://git-scm.com/docs/git-push
 `
@@ -2,6 +2,7 @@ package commands
 
 import (
 	"context"
+	"fmt"
 	"strings"
 	"testing"
 	"time"
@@ -9,9 +10,11 @@ import (
 	"github.com/go-git/go-billy/v5/memfs"
 	gogit "github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/go-git/go-git/v5/storage/memory"
 	"github.com/kurobon/gitgym/backend/internal/git"
+	"github.com/kurobon/gitgym/backend/internal/state"
 )
 
 // setupPushTestSession creates a session with:
@@ -160,3 +163,400 @@ func TestPushCommand_NoRemote(t *testing.T) {
 		t.Error("Expected error for missing remote")
 	}
 }
+
+func TestPushCommand_BranchProtection(t *testing.T) {
+	sm := git.NewSessionManager()
+	s := setupPushTestSession(t, sm, "test-push-protection")
+	ctx := context.Background()
+	cmd := &PushCommand{}
+
+	if _, err := cmd.Execute(ctx, s, []string{"push", "origin", "master"}); err != nil {
+		t.Fatalf("initial push failed: %v", err)
+	}
+
+	local := s.Repos["localrepo"]
+	remote := sm.SharedRemotes["remoterepo"]
+	w, _ := local.Worktree()
+	commit := func(msg, author string, parents ...plumbing.Hash) plumbing.Hash {
+		f, _ := w.Filesystem.Create("file.txt")
+		f.Write([]byte(msg))
+		f.Close()
+		w.Add("file.txt")
+		h, err := w.Commit(msg, &gogit.CommitOptions{
+			Author:  &object.Signature{Name: author, Email: strings.ToLower(author) + "@example.com", When: time.Now()},
+			Parents: parents,
+		})
+		if err != nil {
+			t.Fatalf("commit failed: %v", err)
+		}
+		return h
+	}
+	remoteHead := func() plumbing.Hash {
+		ref, err := remote.Reference("refs/heads/master", true)
+		if err != nil {
+			t.Fatalf("remote master missing: %v", err)
+		}
+		return ref.Hash()
+	}
+	protect := func(rule *git.BranchProtection) {
+		if err := sm.SetBranchProtection("remoterepo", []*git.BranchProtection{rule}); err != nil {
+			t.Fatalf("SetBranchProtection failed: %v", err)
+		}
+	}
+
+	t.Run("Require pull request", func(t *testing.T) {
+		protect(&git.BranchProtection{Pattern: "master", RequirePullRequest: true})
+		before := remoteHead()
+		commit("direct", "Dev")
+
+		_, err := cmd.Execute(ctx, s, []string{"push", "origin", "master"})
+		if err == nil {
+			t.Fatal("expected push to protected branch to be rejected")
+		}
+		if !strings.Contains(err.Error(), "! [remote rejected] master -> master (protected branch hook declined)") {
+			t.Errorf("unexpected rejection output: %s", err)
+		}
+		if !strings.Contains(err.Error(), "remote: error: Changes must be made through a pull request.") {
+			t.Errorf("expected reason in output: %s", err)
+		}
+		if remoteHead() != before {
+			t.Error("remote ref must not move on rejection")
+		}
+	})
+
+	t.Run("Unprotected branch accepted", func(t *testing.T) {
+		protect(&git.BranchProtection{Pattern: "release/*", RequirePullRequest: true})
+		if _, err := cmd.Execute(ctx, s, []string{"push", "origin", "master"}); err != nil {
+			t.Fatalf("push to unprotected branch failed: %v", err)
+		}
+	})
+
+	t.Run("No force push", func(t *testing.T) {
+		protect(&git.BranchProtection{Pattern: "master"})
+		head, _ := local.Head()
+		c, _ := local.CommitObject(head.Hash())
+		rewritten := commit("rewritten", "Dev", c.ParentHashes...)
+		_ = local.Storer.SetReference(plumbing.NewHashReference("refs/heads/master", rewritten))
+
+		_, err := cmd.Execute(ctx, s, []string{"push", "-f", "origin", "master"})
+		if err == nil || !strings.Contains(err.Error(), "Cannot force-push to this branch.") {
+			t.Fatalf("expected force-push rejection, got: %v", err)
+		}
+
+		protect(&git.BranchProtection{Pattern: "master", AllowForcePushes: true})
+		if _, err := cmd.Execute(ctx, s, []string{"push", "-f", "origin", "master"}); err != nil {
+			t.Fatalf("force push with allowForcePushes failed: %v", err)
+		}
+	})
+
+	t.Run("Allowed pushers", func(t *testing.T) {
+		protect(&git.BranchProtection{Pattern: "master", AllowedPushers: []string{"alice"}})
+		// The session pushes as User; a commit authored by Alice does not help
+		forged := commit("by alice", "Alice")
+		_, err := cmd.Execute(ctx, s, []string{"push", "origin", "master"})
+		if err == nil || !strings.Contains(err.Error(), "User <user@example.com> is not allowed to push to this branch.") {
+			t.Fatalf("expected pusher rejection, got: %v", err)
+		}
+		if remote.Storer.HasEncodedObject(forged) == nil {
+			t.Error("a rejected push must not leave its objects in the remote")
+		}
+
+		protect(&git.BranchProtection{Pattern: "master", AllowedPushers: []string{"alice", "user@example.com"}})
+		if _, err := cmd.Execute(ctx, s, []string{"push", "origin", "master"}); err != nil {
+			t.Fatalf("push by allowed pusher failed: %v", err)
+		}
+		if remote.Storer.HasEncodedObject(forged) != nil {
+			t.Error("an accepted push should bring its objects into the remote")
+		}
+	})
+
+	t.Run("Allowed pushers cover deletions", func(t *testing.T) {
+		protect(&git.BranchProtection{Pattern: "master", AllowDeletions: true, AllowedPushers: []string{"alice"}})
+		before := remoteHead()
+		_, err := cmd.Execute(ctx, s, []string{"push", "origin", "--delete", "master"})
+		if err == nil || !strings.Contains(err.Error(), "User <user@example.com> is not allowed to push to this branch.") {
+			t.Fatalf("expected a disallowed pusher's deletion to be rejected, got: %v", err)
+		}
+		if remoteHead() != before {
+			t.Error("a rejected deletion must keep the branch")
+		}
+	})
+
+	t.Run("Require linear history", func(t *testing.T) {
+		protect(&git.BranchProtection{Pattern: "master", RequireLinearHistory: true})
+		base := remoteHead()
+		side := commit("side", "Dev", base)
+		merge := commit("merge", "Dev", base, side)
+		_ = local.Storer.SetReference(plumbing.NewHashReference("refs/heads/master", merge))
+
+		_, err := cmd.Execute(ctx, s, []string{"push", "origin", "master"})
+		if err == nil || !strings.Contains(err.Error(), "must not contain merge commits") {
+			t.Fatalf("expected linear history rejection, got: %v", err)
+		}
+	})
+}
+
+func TestPushCommand_Refspecs(t *testing.T) {
+	sm := git.NewSessionManager()
+	s := setupPushTestSession(t, sm, "test-push-refspecs")
+	ctx := context.Background()
+	cmd := &PushCommand{}
+
+	local := s.Repos["localrepo"]
+	remote := sm.SharedRemotes["remoterepo"]
+	w, _ := local.Worktree()
+	commit := func(msg string, parents ...plumbing.Hash) plumbing.Hash {
+		f, _ := w.Filesystem.Create("file.txt")
+		f.Write([]byte(msg))
+		f.Close()
+		w.Add("file.txt")
+		h, err := w.Commit(msg, &gogit.CommitOptions{
+			Author:  &object.Signature{Name: "Dev", Email: "dev@example.com", When: time.Now()},
+			Parents: parents,
+		})
+		if err != nil {
+			t.Fatalf("commit failed: %v", err)
+		}
+		return h
+	}
+	remoteHash := func(name string) plumbing.Hash {
+		ref, err := remote.Reference(plumbing.ReferenceName(name), true)
+		if err != nil {
+			return plumbing.ZeroHash
+		}
+		return ref.Hash()
+	}
+	tagger := &gogit.CreateTagOptions{Tagger: &object.Signature{Name: "Dev", Email: "dev@example.com", When: time.Now()}, Message: "release"}
+
+	t.Run("New branch and src:dst", func(t *testing.T) {
+		out, err := cmd.Execute(ctx, s, []string{"push", "origin", "master"})
+		if err != nil {
+			t.Fatalf("push failed: %v", err)
+		}
+		if out != "To /remoterepo\n * [new branch]      master -> master" {
+			t.Errorf("unexpected output: %q", out)
+		}
+
+		out, err = cmd.Execute(ctx, s, []string{"push", "origin", "master:release"})
+		if err != nil {
+			t.Fatalf("push master:release failed: %v", err)
+		}
+		if !strings.Contains(out, " * [new branch]      master -> release") || remoteHash("refs/heads/release").IsZero() {
+			t.Errorf("release should be created on the remote: %s", out)
+		}
+		if _, err := local.Reference("refs/remotes/origin/release", true); err != nil {
+			t.Error("push should update the remote-tracking ref")
+		}
+
+		out, _ = cmd.Execute(ctx, s, []string{"push", "origin", "master"})
+		if out != "Everything up-to-date" {
+			t.Errorf("unexpected output for a no-op push: %q", out)
+		}
+
+		// -u still records the upstream when there is nothing to push
+		out, _ = cmd.Execute(ctx, s, []string{"push", "-u", "origin", "master"})
+		if out != "Everything up-to-date\nbranch 'master' set up to track 'origin/master'." {
+			t.Errorf("unexpected output for push -u: %q", out)
+		}
+		cfg, _ := local.Config()
+		if b := cfg.Branches["master"]; b == nil || b.Remote != "origin" || b.Merge != "refs/heads/master" {
+			t.Errorf("push -u should set the upstream of master: %+v", b)
+		}
+	})
+
+	t.Run("Tags", func(t *testing.T) {
+		head, _ := local.Head()
+		local.CreateTag("v1", head.Hash(), nil)
+		local.CreateTag("v2", head.Hash(), tagger)
+
+		out, err := cmd.Execute(ctx, s, []string{"push", "--tags", "origin"})
+		if err != nil {
+			t.Fatalf("push --tags failed: %v", err)
+		}
+		if !strings.Contains(out, " * [new tag]         v1 -> v1") || !strings.Contains(out, " * [new tag]         v2 -> v2") {
+			t.Errorf("unexpected output: %s", out)
+		}
+		if _, err := remote.TagObject(remoteHash("refs/tags/v2")); err != nil {
+			t.Errorf("annotated tag object should be pushed: %v", err)
+		}
+	})
+
+	t.Run("Follow tags", func(t *testing.T) {
+		head, _ := local.Head()
+		next := commit("next", head.Hash())
+		local.CreateTag("v3", next, tagger)
+		local.CreateTag("light", next, nil)
+
+		out, err := cmd.Execute(ctx, s, []string{"push", "--follow-tags", "origin", "master"})
+		if err != nil {
+			t.Fatalf("push --follow-tags failed: %v", err)
+		}
+		if !strings.Contains(out, "master -> master") || !strings.Contains(out, " * [new tag]         v3 -> v3") {
+			t.Errorf("unexpected output: %s", out)
+		}
+		if strings.Contains(out, "light") {
+			t.Errorf("lightweight tags are not followed: %s", out)
+		}
+	})
+
+	t.Run("Non-fast-forward is rejected", func(t *testing.T) {
+		head, _ := local.Head()
+		c, _ := local.CommitObject(head.Hash())
+		rewritten := commit("rewritten", c.ParentHashes...)
+		before := remoteHash("refs/heads/master")
+
+		_, err := cmd.Execute(ctx, s, []string{"push", "origin", "master"})
+		if err == nil || !strings.Contains(err.Error(), " ! [rejected]        master -> master (non-fast-forward)") {
+			t.Fatalf("expected non-fast-forward rejection, got %v", err)
+		}
+		if !strings.Contains(err.Error(), "hint: Updates were rejected because the tip of your current branch is behind") {
+			t.Errorf("expected hint: %v", err)
+		}
+		if remoteHash("refs/heads/master") != before {
+			t.Error("remote must not move")
+		}
+
+		out, err := cmd.Execute(ctx, s, []string{"push", "origin", "+master"})
+		if err != nil {
+			t.Fatalf("forced push failed: %v", err)
+		}
+		want := fmt.Sprintf(" + %s...%s master -> master (forced update)", before.String()[:7], rewritten.String()[:7])
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in %s", want, out)
+		}
+	})
+
+	t.Run("Force with lease", func(t *testing.T) {
+		head, _ := local.Head()
+		// A teammate pushed after our last fetch
+		teammate := commit("teammate", head.Hash())
+		_ = git.CopyCommitRecursive(local, remote, teammate)
+		_ = remote.Storer.SetReference(plumbing.NewHashReference("refs/heads/master", teammate))
+		mine := commit("mine", head.Hash())
+
+		_, err := cmd.Execute(ctx, s, []string{"push", "--force-with-lease", "origin", "master"})
+		if err == nil || !strings.Contains(err.Error(), " ! [rejected]        master -> master (stale info)") {
+			t.Fatalf("expected stale info rejection, got %v", err)
+		}
+		if remoteHash("refs/heads/master") != teammate {
+			t.Error("lease must protect the teammate's commit")
+		}
+
+		out, err := cmd.Execute(ctx, s, []string{"push", "--force-with-lease=master:" + teammate.String(), "origin", "master"})
+		if err != nil {
+			t.Fatalf("push with explicit lease failed: %v", err)
+		}
+		if !strings.Contains(out, "(forced update)") || remoteHash("refs/heads/master") != mine {
+			t.Errorf("explicit lease should allow the overwrite: %s", out)
+		}
+	})
+
+	t.Run("Atomic", func(t *testing.T) {
+		head, _ := local.Head()
+		_ = local.DeleteTag("v1")
+		local.CreateTag("v1", head.Hash(), nil) // moved locally, exists on the remote
+
+		_, err := cmd.Execute(ctx, s, []string{"push", "--atomic", "origin", "master:staging", "v1"})
+		if err == nil {
+			t.Fatal("expected atomic push to fail")
+		}
+		for _, want := range []string{
+			" ! [rejected]        master -> staging (atomic push failed)",
+			" ! [rejected]        v1 -> v1 (already exists)",
+		} {
+			if !strings.Contains(err.Error(), want) {
+				t.Errorf("missing %q in %s", want, err)
+			}
+		}
+		if !remoteHash("refs/heads/staging").IsZero() {
+			t.Error("atomic push must not create staging")
+		}
+
+		// Without --atomic the valid update goes through
+		if _, err := cmd.Execute(ctx, s, []string{"push", "origin", "master:staging", "v1"}); err == nil {
+			t.Fatal("expected the tag to be rejected")
+		}
+		if remoteHash("refs/heads/staging").IsZero() {
+			t.Error("staging should be created without --atomic")
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		out, err := cmd.Execute(ctx, s, []string{"push", "--delete", "origin", "release"})
+		if err != nil {
+			t.Fatalf("push --delete failed: %v", err)
+		}
+		if !strings.Contains(out, " - [deleted]         release") || !remoteHash("refs/heads/release").IsZero() {
+			t.Errorf("release should be deleted: %s", out)
+		}
+		if _, err := local.Reference("refs/remotes/origin/release", true); err == nil {
+			t.Error("remote-tracking ref should be removed")
+		}
+
+		out, err = cmd.Execute(ctx, s, []string{"push", "origin", ":staging"})
+		if err != nil || !strings.Contains(out, "[deleted]         staging") {
+			t.Fatalf("push :staging failed: %v %s", err, out)
+		}
+
+		_, err = cmd.Execute(ctx, s, []string{"push", "origin", ":missing"})
+		if err == nil || !strings.Contains(err.Error(), "unable to delete 'missing': remote ref does not exist") {
+			t.Errorf("deleting a missing ref should fail, got %v", err)
+		}
+	})
+
+	t.Run("Force with lease on deletion", func(t *testing.T) {
+		if _, err := cmd.Execute(ctx, s, []string{"push", "origin", "master:hotfix"}); err != nil {
+			t.Fatalf("push master:hotfix failed: %v", err)
+		}
+		// A teammate moved hotfix after our last fetch
+		head, _ := local.Head()
+		c, _ := local.CommitObject(head.Hash())
+		_ = remote.Storer.SetReference(plumbing.NewHashReference("refs/heads/hotfix", c.ParentHashes[0]))
+
+		_, err := cmd.Execute(ctx, s, []string{"push", "--force-with-lease", "origin", ":hotfix"})
+		if err == nil || !strings.Contains(err.Error(), " ! [rejected]        hotfix (stale info)") {
+			t.Fatalf("expected stale info rejection, got %v", err)
+		}
+		if remoteHash("refs/heads/hotfix") != c.ParentHashes[0] {
+			t.Error("lease must protect the teammate's branch")
+		}
+
+		out, err := cmd.Execute(ctx, s, []string{"push", "--force-with-lease=hotfix:" + c.ParentHashes[0].String(), "origin", ":hotfix"})
+		if err != nil || !remoteHash("refs/heads/hotfix").IsZero() {
+			t.Errorf("explicit lease should allow the deletion: %v %s", err, out)
+		}
+	})
+}
+
+func TestPushCommand_KeepsDeterministicClock(t *testing.T) {
+	sm := git.NewSessionManager()
+	s := setupPushTestSession(t, sm, "test-push-clock")
+	s.SetDeterministic(state.NewVirtualClock(time.Time{}, 0))
+	ctx := context.Background()
+	run := func(args ...string) {
+		t.Helper()
+		if _, err := git.Dispatch(ctx, s, args[0], args); err != nil {
+			t.Fatalf("%v: %v", args, err)
+		}
+	}
+	commitTime := func() time.Time {
+		head, _ := s.GetRepo().Head()
+		c, err := s.GetRepo().CommitObject(head.Hash())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c.Committer.When
+	}
+
+	run("commit", "--allow-empty", "-m", "first")
+	first := commitTime()
+	// Neither a push nor a review creates an object, so neither reads the clock
+	run("push", "origin", "master")
+	pr, _ := sm.CreatePullRequest("Title", "", "master", "master", "Dev", "remoterepo")
+	run("pr", "review", fmt.Sprint(pr.ID), "--comment", "-m", "Looks fine")
+	run("commit", "--allow-empty", "-m", "second")
+
+	if got := commitTime().Sub(first); got != state.DefaultClockStep {
+		t.Errorf("commits should be one clock step apart, got %v", got)
+	}
+}
@@ -22,17 +22,34 @@ type RebaseCommand struct{}
 var _ git.Command = (*RebaseCommand)(nil)
 
 type RebaseOptions struct {
-	Upstream string
-	Branch   string
-	Onto     string
-	Root     bool
-	Preserve bool
+	Upstream    string
+	Branch      string
+	Onto        string
+	Root        bool
+	Preserve    bool
+	Interactive bool   // -i: the todo list is taken as is (no editor in GitGym)
+	Autosquash  string // "true"/"false" from --[no-]autosquash; "" defers to rebase.autoSquash
+}
+
+// rebaseAction is what a todo line does with its commit.
+type rebaseAction string
+
+const (
+	rebasePick   rebaseAction = "pick"
+	rebaseFixup  rebaseAction = "fixup"    // Fold into the previous commit, keep its message
+	rebaseSquash rebaseAction = "squash"   // Fold in and append the message
+	rebaseAmend  rebaseAction = "fixup -C" // Fold in and use this message (amend!)
+)
+
+type rebaseStep struct {
+	Action rebaseAction
+	Commit *object.Commit
 }
 
 type rebaseContext struct {
-	targetHash      *plumbing.Hash
-	commitsToReplay []*object.Commit
-	headRef         *plumbing.Reference // Needed for success message
+	targetHash *plumbing.Hash
+	todo       []rebaseStep
+	headRef    *plumbing.Reference // Needed for success message
 }
 
 func (c *RebaseCommand) Execute(ctx context.Context, s *git.Session, args []string) (string, error) {
@@ -69,6 +86,11 @@ func (c *RebaseCommand) Execute(ctx context.Context, s *git.Session, args []stri
 		return "", err
 	}
 
+	// fixup!/squash!/amend! commits move behind the commits they fix
+	if c.autosquashEnabled(repo, opts) {
+		rbCtx.todo = autosquashTodo(rbCtx.todo)
+	}
+
 	// 4. Perform Rebase
 	return c.performRebase(ctx, s, repo, rbCtx, opts.Preserve)
 }
@@ -91,6 +113,12 @@ func (c *RebaseCommand) parseArgs(args []string) (*RebaseOptions, error) {
 			opts.Preserve = true
 		case "--root":
 			opts.Root = true
+		case "-i", "--interactive":
+			opts.Interactive = true
+		case "--autosquash":
+			opts.Autosquash = "true"
+		case "--no-autosquash":
+			opts.Autosquash = "false"
 		case "-h", "--help":
 			// Handled by calling Help() at higher level usually, but here checking arg
 			return nil, fmt.Errorf("help requested") // Should effectively show help if strictly followed, but standard is different. Logic in Execute handles it? No, Execute returns string/error.
@@ -213,8 +241,8 @@ func (c *RebaseCommand) prepareRebaseContext(repo *gogit.Repository, opts *Rebas
 		}
 		base := mergeBases[0]
 
-		// Check for up-to-date
-		if opts.Onto == "" {
+		// Check for up-to-date (an interactive rebase still rewrites the commits)
+		if opts.Onto == "" && !opts.Interactive && opts.Autosquash != "true" {
 			if base.Hash == upstreamCommit.Hash {
 				return nil, ErrUpToDate
 			}
@@ -236,18 +264,123 @@ func (c *RebaseCommand) prepareRebaseContext(repo *gogit.Repository, opts *Rebas
 	}
 
 	// Reverse to replay oldest first
-	for i, j := 0, len(commitsToReplay)-1; i < j; i, j = i+1, j-1 {
-		commitsToReplay[i], commitsToReplay[j] = commitsToReplay[j], commitsToReplay[i]
+	todo := make([]rebaseStep, len(commitsToReplay))
+	for i, commit := range commitsToReplay {
+		todo[len(todo)-1-i] = rebaseStep{Action: rebasePick, Commit: commit}
 	}
 
 	return &rebaseContext{
 		targetHash: targetHash,
-
-		commitsToReplay: commitsToReplay,
-		headRef:         headRef,
+		todo:       todo,
+		headRef:    headRef,
 	}, nil
 }
 
+// autosquashEnabled applies --autosquash, or rebase.autoSquash for -i.
+func (c *RebaseCommand) autosquashEnabled(repo *gogit.Repository, opts *RebaseOptions) bool {
+	if opts.Autosquash != "" {
+		return opts.Autosquash == "true"
+	}
+	if !opts.Interactive {
+		return false
+	}
+	cfg, err := repo.Config()
+	return err == nil && cfg.Raw.Section("rebase").Option("autoSquash") == "true"
+}
+
+// autosquashTodo moves each "fixup! X", "squash! X" and "amend! X" commit
+// right after the commit X names (by subject or hash prefix), after earlier
+// fixups of the same commit, and marks it fixup, squash or fixup -C.
+func autosquashTodo(todo []rebaseStep) []rebaseStep {
+	var result []rebaseStep
+	// Fixups waiting to follow the pick at the same index of result
+	followers := make(map[int][]rebaseStep)
+	findTarget := func(ref string) (int, bool) {
+		for i, step := range result {
+			if step.Action != rebasePick {
+				continue
+			}
+			if commitSubject(step.Commit) == ref || (len(ref) >= 4 && strings.HasPrefix(step.Commit.Hash.String(), ref)) {
+				return i, true
+			}
+		}
+		for i, step := range result {
+			if step.Action == rebasePick && strings.HasPrefix(commitSubject(step.Commit), ref) {
+				return i, true
+			}
+		}
+		return 0, false
+	}
+
+	for _, step := range todo {
+		action, ref := autosquashTarget(commitSubject(step.Commit))
+		if action != rebasePick {
+			if i, ok := findTarget(ref); ok {
+				followers[i] = append(followers[i], rebaseStep{Action: action, Commit: step.Commit})
+				continue
+			}
+		}
+		result = append(result, step)
+	}
+
+	var ordered []rebaseStep
+	for i, step := range result {
+		ordered = append(ordered, step)
+		ordered = append(ordered, followers[i]...)
+	}
+	return ordered
+}
+
+// autosquashTarget parses a "fixup! ", "squash! " or "amend! " subject into
+// its action and the subject it targets, skipping repeated prefixes
+// ("fixup! fixup! X" targets X).
+func autosquashTarget(subject string) (rebaseAction, string) {
+	action := rebasePick
+	for {
+		switch {
+		case strings.HasPrefix(subject, "fixup! "):
+			subject = strings.TrimPrefix(subject, "fixup! ")
+			if action == rebasePick {
+				action = rebaseFixup
+			}
+		case strings.HasPrefix(subject, "squash! "):
+			subject = strings.TrimPrefix(subject, "squash! ")
+			if action == rebasePick {
+				action = rebaseSquash
+			}
+		case strings.HasPrefix(subject, "amend! "):
+			subject = strings.TrimPrefix(subject, "amend! ")
+			if action == rebasePick {
+				action = rebaseAmend
+			}
+		default:
+			return action, subject
+		}
+	}
+}
+
+// squashedMessage combines messages for squash and fixup -C: squash appends
+// the body of the squash! commit; amend! replaces the message with its body.
+func squashedMessage(action rebaseAction, current string, commit *object.Commit) string {
+	body := ""
+	if _, rest, ok := strings.Cut(strings.TrimSpace(commit.Message), "\n"); ok {
+		body = strings.TrimSpace(rest)
+	}
+	switch action {
+	case rebaseSquash:
+		if body == "" {
+			return current
+		}
+		return strings.TrimRight(current, "\n") + "\n\n" + body
+	case rebaseAmend:
+		if body == "" {
+			return current
+		}
+		return body
+	}
+	return current
+}
+
 func (c *RebaseCommand) performRebase(_ context.Context, s *git.Session, repo *gogit.Repository, rbCtx *rebaseContext, _ bool) (string, error) {
 	// Hard Reset to Target (NewBase)
 	w, _ := repo.Worktree()
@@ -257,7 +390,9 @@ func (c *RebaseCommand) performRebase(_ context.Context, s *git.Session, repo *g
 
 	// Replay Commits
 	replayedCount := 0
-	for _, c := range rbCtx.commitsToReplay {
+	var last *object.Commit // Commit the next fixup/squash folds into
+	for _, step := range rbCtx.todo {
+		c := step.Commit
 		if applyErr := git.ApplyCommitChanges(w, c); applyErr != nil {
 			return "", fmt.Errorf("failed to apply commit %s: %v", c.Hash.String()[:7], applyErr)
 		}
@@ -265,13 +400,25 @@ func (c *RebaseCommand) performRebase(_ context.Context, s *git.Session, repo *g
 		// Ensure timestamp distinctness
 		time.Sleep(10 * time.Millisecond)
 
-		_, err := w.Commit(c.Message, &gogit.CommitOptions{
-			Author:            git.GetDefaultSignature(),
+		message := c.Message
+		commitOpts := &gogit.CommitOptions{
+			Author:            &c.Author,
+			Committer:         s.Signature(),
 			AllowEmptyCommits: true,
-		})
+		}
+		if step.Action != rebasePick && last != nil {
+			// Rewrite the previous commit with this one's changes
+			message = squashedMessage(step.Action, last.Message, c)
+			commitOpts.Author = &last.Author
+			commitOpts.Parents = last.ParentHashes
+		}
+		hash, err := w.Commit(message, commitOpts)
 		if err != nil {
 			return "", fmt.Errorf("failed to commit replayed change: %v", err)
 		}
+		if last, err = repo.CommitObject(hash); err != nil {
+			return "", err
+		}
 		replayedCount++
 	}
 
@@ -290,7 +437,7 @@ func (c *RebaseCommand) Help() string {
     ⚠️ 注意: 既に公開（プッシュ）したコミットをリベースすることは推奨されません。
 
  📋 SYNOPSIS
-    git rebase [--onto <newbase>] <upstream> [<branch>]
+    git rebase [-i] [--autosquash] [--onto <newbase>] <upstream> [<branch>]
     git rebase --root
 
  ⚙️  COMMON OPTIONS
@@ -300,10 +447,26 @@ func (c *RebaseCommand) Help() string {
     --root
         ルートコミット（最初のコミット）まで遡ってリベースします。
 
+    -i, --interactive
+        インタラクティブリベース。GitGymにはエディタがないため、
+        TODOリスト（pick の並び）はそのまま実行されます。
+
+    --autosquash
+        git commit --fixup / --squash で作った "fixup! ..." "squash! ..." "amend! ..."
+        コミットを対象のコミットの直後に移動し、自動で統合します。
+          fixup!  変更だけを統合（メッセージは対象のまま）
+          squash! 変更を統合し、メッセージを連結
+          amend!  変更を統合し、メッセージを置き換え
+        git config rebase.autoSquash true にすると -i の時に常に有効になります。
+
  🛠  EXAMPLES
     1. 現在のブランチをmainの最新に追従させる
        $ git rebase main
 
+    2. fixupコミットを対象のコミットにまとめる
+       $ git commit --fixup=HEAD~1
+       $ git rebase -i --autosquash HEAD~3
+
  🔗 REFERENCE
     Full documentation: https://git-scm.com/docs/git-rebase
 `
@@ -203,3 +203,60 @@ func TestRebaseDisjoint(t *testing.T) {
 	_, err = fs.Stat("b.txt")
 	assert.NoError(t, err)
 }
+
+func TestRebase_Autosquash(t *testing.T) {
+	ctx := context.Background()
+	sm := git.NewSessionManager()
+	s, _ := sm.CreateSession("test-rebase-autosquash")
+	s.InitRepo("repo")
+	s.CurrentDir = "/repo"
+	repo := s.GetRepo()
+	w, _ := repo.Worktree()
+
+	run := func(args ...string) {
+		t.Helper()
+		if _, err := git.Dispatch(ctx, s, args[0], args); err != nil {
+			t.Fatalf("%v failed: %v", args, err)
+		}
+	}
+	write := func(name, content string) {
+		f, _ := w.Filesystem.Create(name)
+		f.Write([]byte(content))
+		f.Close()
+		w.Add(name)
+	}
+
+	write("base.txt", "base")
+	run("commit", "-m", "base")
+	write("a.txt", "a")
+	run("commit", "-m", "add a")
+	write("b.txt", "b")
+	run("commit", "-m", "add b")
+	write("a.txt", "a fixed")
+	run("commit", "--fixup=HEAD~1")
+	write("b.txt", "b more")
+	run("commit", "--squash=HEAD~1", "-m", "b details")
+	write("c.txt", "c")
+	run("commit", "-m", "add c")
+	run("commit", "--fixup=reword:HEAD", "-m", "add c, reworded")
+
+	run("rebase", "-i", "--autosquash", "HEAD~6")
+
+	var messages []string
+	head, _ := repo.Head()
+	commit, _ := repo.CommitObject(head.Hash())
+	for commit.NumParents() > 0 {
+		messages = append(messages, commit.Message)
+		commit, _ = commit.Parent(0)
+	}
+	assert.Equal(t, []string{"add c, reworded", "add b\n\nb details", "add a"}, messages)
+
+	headCommit, _ := repo.CommitObject(head.Hash())
+	for name, want := range map[string]string{"a.txt": "a fixed", "b.txt": "b more", "c.txt": "c"} {
+		f, err := headCommit.File(name)
+		if assert.NoError(t, err) {
+			content, _ := f.Contents()
+			assert.Equal(t, want, content)
+		}
+	}
+}
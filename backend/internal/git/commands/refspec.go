@@ -0,0 +1,211 @@
+package commands
+
+// refspec.go - Refspecs and Ref Update Summaries
+//
+// Shared by fetch and push: resolving the short names of command-line
+// refspecs, classifying each ref update (new, fast-forward, forced, rejected)
+// and printing git's per-ref summary table.
+
+import (
+	"fmt"
+	"strings"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/kurobon/gitgym/backend/internal/git"
+)
+
+type refUpdateStatus int
+
+const (
+	refUpToDate refUpdateStatus = iota
+	refNew
+	refFastForward
+	refForced
+	refTagUpdate
+	refDeleted
+	refRejected
+	refRemoteRejected
+)
+
+// refUpdate is one line of the summary table: a ref moving from Old to New.
+type refUpdate struct {
+	Src    plumbing.ReferenceName // Source ref ("" when deleting)
+	Dst    plumbing.ReferenceName // Ref being updated (FETCH_HEAD for fetches without a destination)
+	Old    plumbing.Hash          // ZeroHash when creating
+	New    plumbing.Hash          // ZeroHash when deleting
+	Force  bool
+	Status refUpdateStatus
+	Reason string // Why the update was rejected
+}
+
+// Failed reports whether the update was refused locally or by the remote.
+func (u *refUpdate) Failed() bool {
+	return u.Status == refRejected || u.Status == refRemoteRejected
+}
+
+func (u *refUpdate) reject(reason string) {
+	u.Status = refRejected
+	u.Reason = reason
+}
+
+// parseRefSpec splits a command-line refspec into its force flag, source and
+// destination. A missing ":" leaves dst empty; ":dst" has an empty source.
+func parseRefSpec(spec string) (force bool, src, dst string, err error) {
+	force = strings.HasPrefix(spec, "+")
+	spec = strings.TrimPrefix(spec, "+")
+	src, dst, _ = strings.Cut(spec, ":")
+	if strings.Contains(dst, ":") || (src == "" && dst == "") {
+		return false, "", "", fmt.Errorf("fatal: invalid refspec '%s'", spec)
+	}
+	if src != "" && dst != "" && strings.Contains(src, "*") != strings.Contains(dst, "*") {
+		return false, "", "", fmt.Errorf("fatal: invalid refspec '%s'", spec)
+	}
+	return force, src, dst, nil
+}
+
+// dwimRef expands a short ref name the way git does (name, refs/<name>,
+// refs/tags/<name>, refs/heads/<name>, refs/remotes/<name>), returning the
+// first candidate exists accepts.
+func dwimRef(name string, exists func(plumbing.ReferenceName) bool) (plumbing.ReferenceName, bool) {
+	candidates := []string{name, "refs/" + name, "refs/tags/" + name, "refs/heads/" + name, "refs/remotes/" + name}
+	if strings.HasPrefix(name, "refs/") {
+		candidates = candidates[:1]
+	}
+	for _, candidate := range candidates {
+		if exists(plumbing.ReferenceName(candidate)) {
+			return plumbing.ReferenceName(candidate), true
+		}
+	}
+	return "", false
+}
+
+// refExists returns a lookup for dwimRef over the refs of repo.
+func refExists(repo *gogit.Repository) func(plumbing.ReferenceName) bool {
+	return func(name plumbing.ReferenceName) bool {
+		_, err := repo.Storer.Reference(name)
+		return err == nil
+	}
+}
+
+// classifyUpdate decides how Dst moves from Old to New. repo must contain both
+// commits to tell a fast-forward from a forced update; tags may only be
+// created unless forced.
+func classifyUpdate(repo *gogit.Repository, u *refUpdate, tagRejectReason string) {
+	switch {
+	case u.New.IsZero():
+		u.Status = refDeleted
+	case u.Old == u.New:
+		u.Status = refUpToDate
+	case u.Old.IsZero():
+		u.Status = refNew
+	case u.Dst.IsTag():
+		if u.Force {
+			u.Status = refTagUpdate
+		} else {
+			u.reject(tagRejectReason)
+		}
+	default:
+		oldCommit, oldOK := git.PeelToCommit(repo, u.Old)
+		newCommit, newOK := git.PeelToCommit(repo, u.New)
+		ff := false
+		if oldOK && newOK {
+			ff, _ = git.IsFastForward(repo, oldCommit, newCommit)
+		}
+		switch {
+		case ff:
+			u.Status = refFastForward
+		case u.Force:
+			u.Status = refForced
+		case !oldOK:
+			u.reject("fetch first")
+		default:
+			u.reject("non-fast-forward")
+		}
+	}
+}
+
+// newRefLabel names a created ref by its kind: [new branch], [new tag] or [new ref].
+func newRefLabel(name plumbing.ReferenceName, ref string) string {
+	switch {
+	case name.IsBranch():
+		return "[new branch]"
+	case name.IsTag():
+		return "[new tag]"
+	}
+	return "[new " + ref + "]"
+}
+
+// summary returns the flag, summary column and trailing note of a table line.
+// kind is the ref whose type labels a creation (the source for fetch, the
+// destination for push).
+func (u *refUpdate) summary(kind plumbing.ReferenceName, newRef string) (byte, string, string) {
+	switch u.Status {
+	case refUpToDate:
+		return '=', "[up to date]", ""
+	case refNew:
+		return '*', newRefLabel(kind, newRef), ""
+	case refFastForward:
+		return ' ', u.Old.String()[:7] + ".." + u.New.String()[:7], ""
+	case refForced:
+		return '+', u.Old.String()[:7] + "..." + u.New.String()[:7], "forced update"
+	case refTagUpdate:
+		return 't', "[tag update]", ""
+	case refDeleted:
+		return '-', "[deleted]", ""
+	case refRemoteRejected:
+		return '!', "[remote rejected]", u.Reason
+	}
+	return '!', "[rejected]", u.Reason
+}
+
+// formatFetchUpdates prints fetch's table, aligning the source column.
+func formatFetchUpdates(url string, updates []*refUpdate) string {
+	width := 0
+	for _, u := range updates {
+		width = max(width, len(fetchSourceName(u)))
+	}
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "From %s", url)
+	for _, u := range updates {
+		flag, summary, note := u.summary(u.Src, "ref")
+		if u.Dst == "FETCH_HEAD" {
+			flag, summary = '*', "branch"
+			if u.Src.IsTag() {
+				summary = "tag"
+			} else if !u.Src.IsBranch() {
+				summary = ""
+			}
+		}
+		fmt.Fprintf(&sb, "\n %c %-17s %-*s -> %s", flag, summary, width, fetchSourceName(u), u.Dst.Short())
+		if note != "" {
+			fmt.Fprintf(&sb, "  (%s)", note)
+		}
+	}
+	return sb.String()
+}
+
+func fetchSourceName(u *refUpdate) string {
+	if u.Src == "" {
+		return "(none)"
+	}
+	return u.Src.Short()
+}
+
+// formatPushUpdates prints push's table: "To <url>" and one line per ref.
+func formatPushUpdates(url string, updates []*refUpdate) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "To %s", url)
+	for _, u := range updates {
+		flag, summary, note := u.summary(u.Dst, "reference")
+		if u.New.IsZero() {
+			fmt.Fprintf(&sb, "\n %c %-17s %s", flag, summary, u.Dst.Short()) // Deletions have no source
+		} else {
+			fmt.Fprintf(&sb, "\n %c %-17s %s -> %s", flag, summary, u.Src.Short(), u.Dst.Short())
+		}
+		if note != "" {
+			fmt.Fprintf(&sb, " (%s)", note)
+		}
+	}
+	return sb.String()
+}
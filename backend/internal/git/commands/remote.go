@@ -1,12 +1,22 @@
 package commands
 
+// remote.go - Simulated Git Remote Command
+//
+// Manages the remotes of the session repository: add, rename, remove, URL
+// changes, and inspection against the simulated remote (show, prune,
+// set-head). Remotes resolve through git.ResolveRemoteURL, so the remote side
+// is read from shared remotes without any network access.
+
 import (
 	"context"
 	"fmt"
+	"regexp"
+	"sort"
 	"strings"
 
 	gogit "github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/kurobon/gitgym/backend/internal/git"
 )
 
@@ -21,15 +31,21 @@ var _ git.Command = (*RemoteCommand)(nil)
 
 type RemoteOptions struct {
 	SubCmd  string
-	Name    string
-	URL     string
+	Args    []string // Positional arguments after the subcommand
 	Verbose bool
+
+	Fetch  bool     // add -f
+	Track  []string // add -t <branch>
+	Master string   // add -m <branch>
+	Push   bool     // set-url/get-url --push
+	Add    bool     // set-url --add
+	Delete bool     // set-url --delete, set-head -d
+	All    bool     // get-url --all
+	Auto   bool     // set-head -a
+	NoAct  bool     // prune --dry-run, show -n
 }
 
 func (c *RemoteCommand) Execute(ctx context.Context, s *git.Session, args []string) (string, error) {
-	s.Lock()
-	defer s.Unlock()
-
 	opts, err := c.parseArgs(args)
 	if err != nil {
 		if err.Error() == "help requested" {
@@ -38,11 +54,33 @@ func (c *RemoteCommand) Execute(ctx context.Context, s *git.Session, args []stri
 		return "", err
 	}
 
+	out, err := c.executeLocked(s, opts)
+	if err != nil {
+		return "", err
+	}
+
+	// add -f fetches the new remote right away (fetch takes the session lock itself)
+	if opts.SubCmd == "add" && opts.Fetch {
+		fetchOut, err := (&FetchCommand{}).Execute(ctx, s, []string{"fetch", opts.Args[0]})
+		if err != nil {
+			return "", err
+		}
+		out = "Updating " + opts.Args[0]
+		if fetchOut != "" {
+			out += "\n" + fetchOut
+		}
+	}
+	return out, nil
+}
+
+func (c *RemoteCommand) executeLocked(s *git.Session, opts *RemoteOptions) (string, error) {
+	s.Lock()
+	defer s.Unlock()
+
 	repo := s.GetRepo()
 	if repo == nil {
 		return "", fmt.Errorf("fatal: not a git repository")
 	}
-
 	return c.executeRemote(s, repo, opts)
 }
 
@@ -50,181 +88,759 @@ func (c *RemoteCommand) parseArgs(args []string) (*RemoteOptions, error) {
 	opts := &RemoteOptions{}
 	cmdArgs := args[1:]
 
-	// Pre-scan structure: git remote [-v] [subcmd [args]]
+	// Structure: git remote [-v] [subcmd [options] [args]]
 	var positional []string
-	for _, arg := range cmdArgs {
-		if arg == "-v" || arg == "--verbose" {
+	for i := 0; i < len(cmdArgs); i++ {
+		arg := cmdArgs[i]
+		switch arg {
+		case "-v", "--verbose":
 			opts.Verbose = true
-		} else if arg == "-h" || arg == "--help" {
+		case "-h", "--help":
 			return nil, fmt.Errorf("help requested")
-		} else if !strings.HasPrefix(arg, "-") {
+		case "-f", "--fetch":
+			opts.Fetch = true
+		case "-t", "--track", "-m", "--master":
+			if i+1 >= len(cmdArgs) {
+				return nil, fmt.Errorf("error: switch `%s' requires a value", strings.TrimLeft(arg, "-"))
+			}
+			i++
+			if arg == "-t" || arg == "--track" {
+				opts.Track = append(opts.Track, cmdArgs[i])
+			} else {
+				opts.Master = cmdArgs[i]
+			}
+		case "--push":
+			opts.Push = true
+		case "--add":
+			opts.Add = true
+		case "-d", "--delete":
+			opts.Delete = true
+		case "--all":
+			opts.All = true
+		case "-a", "--auto":
+			opts.Auto = true
+		case "-n", "--dry-run":
+			opts.NoAct = true
+		default:
+			if strings.HasPrefix(arg, "-") {
+				return nil, fmt.Errorf("error: unknown option `%s'", strings.TrimLeft(arg, "-"))
+			}
 			positional = append(positional, arg)
 		}
 	}
 
 	if len(positional) > 0 {
 		opts.SubCmd = positional[0]
+		opts.Args = positional[1:]
+	}
+	return opts, nil
+}
+
+func (c *RemoteCommand) executeRemote(s *git.Session, repo *gogit.Repository, opts *RemoteOptions) (string, error) {
+	args := opts.Args
+	switch opts.SubCmd {
+	case "":
+		return listRemotes(repo, opts.Verbose)
+	case "add":
+		if len(args) != 2 {
+			return "", fmt.Errorf("usage: git remote add [-f] [-t <branch>] [-m <master>] <name> <url>")
+		}
+		return "", addRemote(repo, args[0], args[1], opts.Track, opts.Master)
+	case "remove", "rm":
+		if len(args) != 1 {
+			return "", fmt.Errorf("usage: git remote remove <name>")
+		}
+		return "", removeRemote(repo, args[0])
+	case "rename":
+		if len(args) != 2 {
+			return "", fmt.Errorf("usage: git remote rename <old> <new>")
+		}
+		return "", renameRemote(repo, args[0], args[1])
+	case "set-url":
+		if len(args) < 2 || len(args) > 3 || ((opts.Add || opts.Delete) && len(args) != 2) {
+			return "", fmt.Errorf("usage: git remote set-url [--push] <name> <newurl> [<oldurl>]\n   or: git remote set-url --add [--push] <name> <newurl>\n   or: git remote set-url --delete [--push] <name> <url>")
+		}
+		oldURL := ""
+		if len(args) == 3 {
+			oldURL = args[2]
+		}
+		return "", setRemoteURL(repo, args[0], args[1], oldURL, opts)
+	case "get-url":
+		if len(args) != 1 {
+			return "", fmt.Errorf("usage: git remote get-url [--push] [--all] <name>")
+		}
+		return getRemoteURL(repo, args[0], opts.Push, opts.All)
+	case "show":
+		if len(args) == 0 {
+			return listRemotes(repo, opts.Verbose)
+		}
+		var blocks []string
+		for _, name := range args {
+			out, err := showRemote(s, repo, name, opts.NoAct)
+			if err != nil {
+				return "", err
+			}
+			blocks = append(blocks, out)
+		}
+		return strings.Join(blocks, "\n"), nil
+	case "prune":
+		if len(args) == 0 {
+			return "", fmt.Errorf("usage: git remote prune [-n | --dry-run] <name>...")
+		}
+		var blocks []string
+		for _, name := range args {
+			out, err := pruneRemote(s, repo, name, opts.NoAct)
+			if err != nil {
+				return "", err
+			}
+			blocks = append(blocks, out)
+		}
+		return strings.Join(blocks, "\n"), nil
+	case "set-head":
+		if len(args) == 0 || len(args) > 2 || (len(args) == 2) == (opts.Auto || opts.Delete) {
+			return "", fmt.Errorf("usage: git remote set-head <name> (-a | --auto | -d | --delete | <branch>)")
+		}
+		branch := ""
+		if len(args) == 2 {
+			branch = args[1]
+		}
+		return setRemoteHead(s, repo, args[0], branch, opts)
 	}
-	if len(positional) > 1 {
-		opts.Name = positional[1]
+
+	return "", fmt.Errorf("error: unknown subcommand: `%s'", opts.SubCmd)
+}
+
+// addRemote configures name to fetch url's branches into refs/remotes/<name>/,
+// or only the branches given with -t. -m sets refs/remotes/<name>/HEAD.
+// Each remote resolves to its own shared remote, so origin, upstream and a
+// teammate's fork can be fetched and pushed independently.
+func addRemote(repo *gogit.Repository, name, url string, track []string, master string) error {
+	if err := git.ValidateRemoteName(name); err != nil {
+		return err
 	}
-	if len(positional) > 2 {
-		opts.URL = positional[2]
+	if _, err := repo.Remote(name); err == nil {
+		return fmt.Errorf("error: remote %s already exists.", name)
 	}
 
-	// For debugging: if it's 'add' but URL is missing, log the positional args
-	// if opts.SubCmd == "add" && opts.URL == "" {
-	// 	fmt.Printf("DEBUG: remote add detected with missing URL. Positional: %v\n", positional)
-	// }
+	remote := &config.RemoteConfig{Name: name, URLs: []string{url}}
+	for _, branch := range track {
+		remote.Fetch = append(remote.Fetch, config.RefSpec(fmt.Sprintf("+refs/heads/%s:refs/remotes/%s/%s", branch, name, branch)))
+	}
+	if _, err := repo.CreateRemote(remote); err != nil {
+		return err
+	}
 
-	return opts, nil
+	if master != "" {
+		head := plumbing.NewSymbolicReference(plumbing.NewRemoteHEADReferenceName(name), plumbing.NewRemoteReferenceName(name, master))
+		return repo.Storer.SetReference(head)
+	}
+	return nil
 }
 
-func (c *RemoteCommand) executeRemote(_ *git.Session, repo *gogit.Repository, opts *RemoteOptions) (string, error) {
-	if opts.SubCmd == "" {
-		return listRemotes(repo, opts.Verbose)
+// removeRemote deletes the remote, its remote-tracking refs and the upstream
+// settings of branches that tracked it.
+func removeRemote(repo *gogit.Repository, name string) error {
+	cfg, err := repo.Config()
+	if err != nil {
+		return err
+	}
+	if _, ok := cfg.Remotes[name]; !ok {
+		return fmt.Errorf("error: No such remote: '%s'", name)
 	}
 
-	if opts.SubCmd == "add" {
-		if opts.Name == "" || opts.URL == "" {
-			return "", fmt.Errorf("usage: git remote add <name> <url>")
+	delete(cfg.Remotes, name)
+	for _, b := range cfg.Branches {
+		if b.Remote == name {
+			b.Remote, b.Merge = "", ""
 		}
-		_, err := repo.CreateRemote(&config.RemoteConfig{
-			Name: opts.Name,
-			URLs: []string{opts.URL},
-		})
-		if err != nil {
-			return "", err
+	}
+	if err := repo.Storer.SetConfig(cfg); err != nil {
+		return err
+	}
+
+	refs, err := remoteTrackingRefs(repo, name)
+	if err != nil {
+		return err
+	}
+	for _, ref := range refs {
+		if err := repo.Storer.RemoveReference(ref.Name()); err != nil {
+			return err
 		}
-		return "", nil
 	}
+	return nil
+}
 
-	if opts.SubCmd == "remove" || opts.SubCmd == "rm" {
-		if opts.Name == "" {
-			return "", fmt.Errorf("usage: git remote remove <name>")
+// renameRemote renames the remote and moves everything that refers to it: its
+// remote-tracking refs, default fetch refspecs and the upstream of branches.
+func renameRemote(repo *gogit.Repository, oldName, newName string) error {
+	cfg, err := repo.Config()
+	if err != nil {
+		return err
+	}
+	remote, ok := cfg.Remotes[oldName]
+	if !ok {
+		return fmt.Errorf("error: No such remote: '%s'", oldName)
+	}
+	if err := git.ValidateRemoteName(newName); err != nil {
+		return err
+	}
+	if _, exists := cfg.Remotes[newName]; exists {
+		return fmt.Errorf("error: remote %s already exists.", newName)
+	}
+
+	oldPrefix := "refs/remotes/" + oldName + "/"
+	newPrefix := "refs/remotes/" + newName + "/"
+	renameRef := func(name string) string {
+		if rest, ok := strings.CutPrefix(name, oldPrefix); ok {
+			return newPrefix + rest
 		}
-		err := repo.DeleteRemote(opts.Name)
+		return name
+	}
+
+	// The subsection keeps its other options (e.g. displayurl) under the new name
+	remote.Name = newName
+	for i, spec := range remote.Fetch {
+		src, dst, _ := strings.Cut(strings.TrimPrefix(spec.String(), "+"), ":")
+		if spec.IsForceUpdate() {
+			src = "+" + src
+		}
+		remote.Fetch[i] = config.RefSpec(src + ":" + renameRef(dst))
+	}
+	delete(cfg.Remotes, oldName)
+	cfg.Remotes[newName] = remote
+	for _, b := range cfg.Branches {
+		if b.Remote == oldName {
+			b.Remote = newName
+		}
+	}
+	if err := repo.Storer.SetConfig(cfg); err != nil {
+		return err
+	}
+
+	refs, err := remoteTrackingRefs(repo, oldName)
+	if err != nil {
+		return err
+	}
+	for _, ref := range refs {
+		var moved *plumbing.Reference
+		if ref.Type() == plumbing.SymbolicReference {
+			moved = plumbing.NewSymbolicReference(plumbing.ReferenceName(renameRef(ref.Name().String())), plumbing.ReferenceName(renameRef(ref.Target().String())))
+		} else {
+			moved = plumbing.NewHashReference(plumbing.ReferenceName(renameRef(ref.Name().String())), ref.Hash())
+		}
+		if err := repo.Storer.SetReference(moved); err != nil {
+			return err
+		}
+		if err := repo.Storer.RemoveReference(ref.Name()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// setRemoteURL changes the fetch URLs of the remote, or its push URLs with
+// --push. Without --add/--delete it replaces the first URL matching the oldURL
+// regex (the first URL when oldURL is empty), keeping refspecs and other URLs.
+func setRemoteURL(repo *gogit.Repository, name, newURL, oldURL string, opts *RemoteOptions) error {
+	cfg, err := repo.Config()
+	if err != nil {
+		return err
+	}
+	remote, ok := cfg.Remotes[name]
+	if !ok {
+		return fmt.Errorf("error: No such remote '%s'", name)
+	}
+	raw := cfg.Raw.Section("remote").Subsection(name)
+
+	urls := remote.URLs
+	if opts.Push {
+		urls = raw.Options.GetAll("pushurl")
+	}
+
+	switch {
+	case opts.Add:
+		urls = append(urls, newURL)
+	case opts.Delete:
+		// With --delete the second argument is the regex of URLs to remove
+		re, err := regexp.Compile(newURL)
 		if err != nil {
-			return "", err
+			return fmt.Errorf("fatal: Invalid old URL pattern: %s", newURL)
+		}
+		var kept []string
+		for _, u := range urls {
+			if !re.MatchString(u) {
+				kept = append(kept, u)
+			}
+		}
+		if len(kept) == len(urls) {
+			return fmt.Errorf("fatal: No such URL found: %s", newURL)
+		}
+		if len(kept) == 0 && !opts.Push {
+			return fmt.Errorf("fatal: Will not delete all non-push URLs")
+		}
+		urls = kept
+	default:
+		index := -1
+		if oldURL == "" {
+			if len(urls) > 0 {
+				index = 0
+			}
+		} else {
+			re, err := regexp.Compile(oldURL)
+			if err != nil {
+				return fmt.Errorf("fatal: Invalid old URL pattern: %s", oldURL)
+			}
+			for i, u := range urls {
+				if re.MatchString(u) {
+					index = i
+					break
+				}
+			}
+			if index < 0 {
+				return fmt.Errorf("fatal: No such URL found: %s", oldURL)
+			}
 		}
+		if index < 0 {
+			urls = append(urls, newURL)
+		} else {
+			urls[index] = newURL
+		}
+	}
+
+	if opts.Push {
+		if len(urls) == 0 {
+			raw.RemoveOption("pushurl")
+		} else {
+			raw.SetOption("pushurl", urls...)
+		}
+	} else {
+		remote.URLs = urls
+		// A display URL recorded by clone described the old location
+		raw.RemoveOption("displayurl")
+	}
+	return repo.Storer.SetConfig(cfg)
+}
+
+// getRemoteURL prints the first fetch URL of the remote, or all of them with
+// --all; --push prints the push URLs instead.
+func getRemoteURL(repo *gogit.Repository, name string, push, all bool) (string, error) {
+	cfg, err := repo.Config()
+	if err != nil {
+		return "", err
+	}
+	remote, ok := cfg.Remotes[name]
+	if !ok {
+		return "", fmt.Errorf("error: No such remote '%s'", name)
+	}
+	urls := remote.URLs
+	if push {
+		urls = remotePushURLs(cfg, remote)
+	}
+	if len(urls) == 0 {
 		return "", nil
 	}
+	if !all {
+		urls = urls[:1]
+	}
+	return strings.Join(urls, "\n"), nil
+}
 
-	if opts.SubCmd == "rename" {
-		if opts.Name == "" || opts.URL == "" {
-			return "", fmt.Errorf("usage: git remote rename <old> <new>")
+// remotePushURLs returns remote.<name>.pushurl, or the fetch URLs when none is set.
+func remotePushURLs(cfg *config.Config, remote *config.RemoteConfig) []string {
+	if pushURLs := cfg.Raw.Section("remote").Subsection(remote.Name).Options.GetAll("pushurl"); len(pushURLs) > 0 {
+		return pushURLs
+	}
+	return remote.URLs
+}
+
+// remoteDisplayURL is the URL shown for the remote: the friendly URL clone
+// recorded (remote.<name>.displayurl) instead of the internal path, if any.
+func remoteDisplayURL(cfg *config.Config, name, url string) string {
+	if display := cfg.Raw.Section("remote").Subsection(name).Option("displayurl"); display != "" {
+		return display
+	}
+	return url
+}
+
+// remoteTrackingRefs lists the refs under refs/remotes/<name>/ without resolving them.
+func remoteTrackingRefs(repo *gogit.Repository, name string) ([]*plumbing.Reference, error) {
+	iter, err := repo.Storer.IterReferences()
+	if err != nil {
+		return nil, err
+	}
+	prefix := "refs/remotes/" + name + "/"
+	var refs []*plumbing.Reference
+	err = iter.ForEach(func(ref *plumbing.Reference) error {
+		if strings.HasPrefix(ref.Name().String(), prefix) {
+			refs = append(refs, ref)
 		}
-		oldName := opts.Name
-		newName := opts.URL // URL field holds the new name in this context
+		return nil
+	})
+	return refs, err
+}
 
-		// Get old remote config
-		remote, err := repo.Remote(oldName)
-		if err != nil {
-			return "", fmt.Errorf("error: No such remote: '%s'", oldName)
+// remoteRefState compares the branches of a remote with their remote-tracking refs.
+type remoteRefState struct {
+	Tracked []string                 // Remote branches with a tracking ref
+	New     []string                 // Remote branches the next fetch will store
+	Stale   []plumbing.ReferenceName // Tracking refs whose branch is gone from the remote
+	HEAD    string                   // Branch the remote's HEAD points to
+	Heads   map[string]plumbing.Hash // Remote branches by short name
+}
+
+// openRemote resolves the configured remote name to its config and simulated repository.
+func openRemote(s *git.Session, repo *gogit.Repository, name string) (*config.Config, *config.RemoteConfig, *gogit.Repository, error) {
+	cfg, err := repo.Config()
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	remote, ok := cfg.Remotes[name]
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("fatal: '%s' does not appear to be a git repository", name)
+	}
+	if len(remote.URLs) == 0 {
+		return nil, nil, nil, fmt.Errorf("fatal: remote %s has no URL defined", name)
+	}
+	remoteRepo, err := git.ResolveRemoteURL(s, remote.URLs[0])
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("fatal: %w", err)
+	}
+	return cfg, remote, remoteRepo, nil
+}
+
+// remoteRefStates classifies the remote's branches by the remote's fetch refspecs.
+func remoteRefStates(repo, remoteRepo *gogit.Repository, remote *config.RemoteConfig) (*remoteRefState, error) {
+	state := &remoteRefState{Heads: make(map[string]plumbing.Hash)}
+	if head, err := remoteRepo.Storer.Reference(plumbing.HEAD); err == nil && head.Type() == plumbing.SymbolicReference {
+		state.HEAD = head.Target().Short()
+	}
+
+	refs, err := remoteRepo.References()
+	if err != nil {
+		return nil, err
+	}
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if !ref.Name().IsBranch() || ref.Type() != plumbing.HashReference {
+			return nil
 		}
-		cfg := remote.Config()
+		state.Heads[ref.Name().Short()] = ref.Hash()
+		for _, spec := range remote.Fetch {
+			if !spec.Match(ref.Name()) {
+				continue
+			}
+			if _, err := repo.Storer.Reference(spec.Dst(ref.Name())); err == nil {
+				state.Tracked = append(state.Tracked, ref.Name().Short())
+			} else {
+				state.New = append(state.New, ref.Name().Short())
+			}
+			break
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
 
-		// Create new remote with same URLs
-		_, err = repo.CreateRemote(&config.RemoteConfig{
-			Name: newName,
-			URLs: cfg.URLs,
-		})
-		if err != nil {
+	tracking, err := remoteTrackingRefs(repo, remote.Name)
+	if err != nil {
+		return nil, err
+	}
+	for _, ref := range tracking {
+		if ref.Type() != plumbing.HashReference {
+			continue
+		}
+		for _, spec := range remote.Fetch {
+			// Reverse keeps a leading '+' on what becomes the destination
+			reverse := config.RefSpec(strings.TrimPrefix(spec.String(), "+")).Reverse()
+			if !reverse.Match(ref.Name()) {
+				continue
+			}
+			if src := reverse.Dst(ref.Name()); src.IsBranch() {
+				if _, ok := state.Heads[src.Short()]; !ok {
+					state.Stale = append(state.Stale, ref.Name())
+				}
+			}
+			break
+		}
+	}
+
+	sort.Strings(state.Tracked)
+	sort.Strings(state.New)
+	sort.Slice(state.Stale, func(i, j int) bool { return state.Stale[i] < state.Stale[j] })
+	return state, nil
+}
+
+// showRemote describes a remote the way `git remote show <name>` does. With
+// noQuery the remote itself is not consulted.
+func showRemote(s *git.Session, repo *gogit.Repository, name string, noQuery bool) (string, error) {
+	var (
+		cfg        *config.Config
+		remote     *config.RemoteConfig
+		remoteRepo *gogit.Repository
+		err        error
+	)
+	if noQuery {
+		if cfg, err = repo.Config(); err != nil {
 			return "", err
 		}
+		var ok bool
+		if remote, ok = cfg.Remotes[name]; !ok {
+			return "", fmt.Errorf("fatal: '%s' does not appear to be a git repository", name)
+		}
+	} else if cfg, remote, remoteRepo, err = openRemote(s, repo, name); err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "* remote %s\n", name)
+	fetchURL := ""
+	if len(remote.URLs) > 0 {
+		fetchURL = remoteDisplayURL(cfg, name, remote.URLs[0])
+	}
+	fmt.Fprintf(&sb, "  Fetch URL: %s\n", fetchURL)
+	for _, u := range remotePushURLs(cfg, remote) {
+		fmt.Fprintf(&sb, "  Push  URL: %s\n", remoteDisplayURL(cfg, name, u))
+	}
 
-		// Delete old remote
-		err = repo.DeleteRemote(oldName)
+	var state *remoteRefState
+	if noQuery {
+		sb.WriteString("  HEAD branch: (not queried)\n")
+		tracking, err := remoteTrackingRefs(repo, name)
 		if err != nil {
 			return "", err
 		}
+		var branches []string
+		for _, ref := range tracking {
+			if ref.Type() == plumbing.HashReference {
+				branches = append(branches, strings.TrimPrefix(ref.Name().String(), "refs/remotes/"+name+"/"))
+			}
+		}
+		sort.Strings(branches)
+		if len(branches) > 0 {
+			fmt.Fprintf(&sb, "  %s (status not queried)\n", plural(len(branches), "Remote branch:", "Remote branches:"))
+			for _, b := range branches {
+				fmt.Fprintf(&sb, "    %s\n", b)
+			}
+		}
+	} else {
+		if state, err = remoteRefStates(repo, remoteRepo, remote); err != nil {
+			return "", err
+		}
+		head := state.HEAD
+		if head == "" {
+			head = "(unknown)"
+		}
+		fmt.Fprintf(&sb, "  HEAD branch: %s\n", head)
 
-		return "", nil
+		type line struct{ name, status string }
+		var lines []line
+		for _, b := range state.Tracked {
+			lines = append(lines, line{b, "tracked"})
+		}
+		for _, b := range state.New {
+			lines = append(lines, line{b, fmt.Sprintf("new (next fetch will store in remotes/%s)", name)})
+		}
+		for _, ref := range state.Stale {
+			lines = append(lines, line{ref.String(), "stale (use 'git remote prune' to remove)"})
+		}
+		sort.Slice(lines, func(i, j int) bool { return lines[i].name < lines[j].name })
+		if len(lines) > 0 {
+			width := 0
+			for _, l := range lines {
+				width = max(width, len(l.name))
+			}
+			fmt.Fprintf(&sb, "  %s\n", plural(len(lines), "Remote branch:", "Remote branches:"))
+			for _, l := range lines {
+				fmt.Fprintf(&sb, "    %-*s %s\n", width, l.name, l.status)
+			}
+		}
 	}
 
-	if opts.SubCmd == "set-url" {
-		if opts.Name == "" || opts.URL == "" {
-			return "", fmt.Errorf("usage: git remote set-url <name> <newurl>")
+	// Branches that pull from this remote
+	var pulls []*config.Branch
+	for _, b := range cfg.Branches {
+		if b.Remote == name && b.Merge != "" {
+			pulls = append(pulls, b)
 		}
-
-		// Get current remote config
-		remote, err := repo.Remote(opts.Name)
-		if err != nil {
-			return "", fmt.Errorf("error: No such remote '%s'", opts.Name)
+	}
+	sort.Slice(pulls, func(i, j int) bool { return pulls[i].Name < pulls[j].Name })
+	if len(pulls) > 0 {
+		width := 0
+		for _, b := range pulls {
+			width = max(width, len(b.Name))
+		}
+		fmt.Fprintf(&sb, "  %s\n", plural(len(pulls), "Local branch configured for 'git pull':", "Local branches configured for 'git pull':"))
+		for _, b := range pulls {
+			verb := "merges with"
+			if b.Rebase == "true" || b.Rebase == "merges" || b.Rebase == "interactive" {
+				verb = "rebases onto"
+			}
+			fmt.Fprintf(&sb, "    %-*s %s remote %s\n", width, b.Name, verb, b.Merge.Short())
 		}
-		oldCfg := remote.Config()
+	}
 
-		// Delete and recreate with new URL
-		err = repo.DeleteRemote(opts.Name)
-		if err != nil {
-			return "", err
+	// Matching branches that `git push` would update
+	if noQuery {
+		sb.WriteString("  Local ref configured for 'git push' (status not queried):\n")
+		sb.WriteString("    (matching) pushes to (matching)\n")
+		return strings.TrimSuffix(sb.String(), "\n"), nil
+	}
+	pushes, err := pushStates(repo, remoteRepo, state)
+	if err != nil {
+		return "", err
+	}
+	if len(pushes) > 0 {
+		width := 0
+		for _, p := range pushes {
+			width = max(width, len(p[0]))
 		}
+		fmt.Fprintf(&sb, "  %s\n", plural(len(pushes), "Local ref configured for 'git push':", "Local refs configured for 'git push':"))
+		for _, p := range pushes {
+			fmt.Fprintf(&sb, "    %-*s pushes to %-*s (%s)\n", width, p[0], width, p[0], p[1])
+		}
+	}
+	return strings.TrimSuffix(sb.String(), "\n"), nil
+}
 
-		newURLs := []string{opts.URL}
-		// Keep additional URLs if any
-		if len(oldCfg.URLs) > 1 {
-			newURLs = append(newURLs, oldCfg.URLs[1:]...)
+// pushStates pairs each local branch that also exists on the remote with the
+// state a matching push would find it in.
+func pushStates(repo, remoteRepo *gogit.Repository, state *remoteRefState) ([][2]string, error) {
+	branches, err := repo.Branches()
+	if err != nil {
+		return nil, err
+	}
+	var pushes [][2]string
+	err = branches.ForEach(func(ref *plumbing.Reference) error {
+		remoteHash, ok := state.Heads[ref.Name().Short()]
+		if !ok {
+			return nil
 		}
+		status := "local out of date"
+		switch {
+		case remoteHash == ref.Hash():
+			status = "up to date"
+		case git.HasObject(repo, remoteHash):
+			if ff, err := git.IsFastForward(repo, remoteHash, ref.Hash()); err == nil && ff {
+				status = "fast-forwardable"
+			}
+		}
+		pushes = append(pushes, [2]string{ref.Name().Short(), status})
+		return nil
+	})
+	sort.Slice(pushes, func(i, j int) bool { return pushes[i][0] < pushes[j][0] })
+	return pushes, err
+}
 
-		_, err = repo.CreateRemote(&config.RemoteConfig{
-			Name: opts.Name,
-			URLs: newURLs,
-		})
-		if err != nil {
+// pruneRemote deletes remote-tracking refs whose branch no longer exists on the remote.
+func pruneRemote(s *git.Session, repo *gogit.Repository, name string, dryRun bool) (string, error) {
+	cfg, remote, remoteRepo, err := openRemote(s, repo, name)
+	if err != nil {
+		return "", err
+	}
+	state, err := remoteRefStates(repo, remoteRepo, remote)
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Pruning %s\n", name)
+	fmt.Fprintf(&sb, "URL: %s\n", remoteDisplayURL(cfg, name, remote.URLs[0]))
+	for _, ref := range state.Stale {
+		if dryRun {
+			fmt.Fprintf(&sb, " * [would prune] %s\n", ref.Short())
+			continue
+		}
+		if err := repo.Storer.RemoveReference(ref); err != nil {
 			return "", err
 		}
+		fmt.Fprintf(&sb, " * [pruned] %s\n", ref.Short())
+	}
 
-		return "", nil
+	// refs/remotes/<name>/HEAD must not dangle after its branch was pruned
+	if !dryRun {
+		headName := plumbing.NewRemoteHEADReferenceName(name)
+		if head, err := repo.Storer.Reference(headName); err == nil && head.Type() == plumbing.SymbolicReference {
+			if _, err := repo.Storer.Reference(head.Target()); err != nil {
+				_ = repo.Storer.RemoveReference(headName)
+			}
+		}
 	}
+	return strings.TrimSuffix(sb.String(), "\n"), nil
+}
 
-	if opts.SubCmd == "get-url" {
-		if opts.Name == "" {
-			return "", fmt.Errorf("usage: git remote get-url <name>")
+// setRemoteHead sets refs/remotes/<name>/HEAD to branch, to the remote's HEAD
+// branch with --auto, or deletes it with --delete.
+func setRemoteHead(s *git.Session, repo *gogit.Repository, name, branch string, opts *RemoteOptions) (string, error) {
+	headName := plumbing.NewRemoteHEADReferenceName(name)
+	if opts.Delete {
+		if _, err := repo.Storer.Reference(headName); err != nil {
+			return "", fmt.Errorf("error: Could not delete %s", headName)
 		}
-		remote, err := repo.Remote(opts.Name)
+		return "", repo.Storer.RemoveReference(headName)
+	}
+
+	if opts.Auto {
+		_, remote, remoteRepo, err := openRemote(s, repo, name)
 		if err != nil {
-			return "", fmt.Errorf("error: No such remote '%s'", opts.Name)
+			return "", err
 		}
-		cfg := remote.Config()
-		if len(cfg.URLs) > 0 {
-			return cfg.URLs[0], nil
+		state, err := remoteRefStates(repo, remoteRepo, remote)
+		if err != nil {
+			return "", err
 		}
-		return "", nil
+		if state.HEAD == "" {
+			return "", fmt.Errorf("error: Cannot determine remote HEAD")
+		}
+		branch = state.HEAD
+	} else if _, err := repo.Remote(name); err != nil {
+		return "", fmt.Errorf("error: No such remote '%s'", name)
+	}
+
+	target := plumbing.NewRemoteReferenceName(name, branch)
+	if _, err := repo.Storer.Reference(target); err != nil {
+		return "", fmt.Errorf("error: Not a valid ref: %s", target)
+	}
+	if err := repo.Storer.SetReference(plumbing.NewSymbolicReference(headName, target)); err != nil {
+		return "", err
+	}
+	if opts.Auto {
+		return fmt.Sprintf("%s/HEAD set to %s", name, branch), nil
 	}
+	return "", nil
+}
 
-	return "", fmt.Errorf("unknown subcommand: %s", opts.SubCmd)
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return one
+	}
+	return many
 }
 
+// listRemotes prints remote names, or with -v each remote's fetch URL and its
+// push URLs like git.
 func listRemotes(repo *gogit.Repository, verbose bool) (string, error) {
-	remotes, err := repo.Remotes()
+	cfg, err := repo.Config()
 	if err != nil {
 		return "", err
 	}
-
-	// Prepare config lookup for displayurl overrides
-	repoCfg, _ := repo.Config()
+	names := make([]string, 0, len(cfg.Remotes))
+	for name := range cfg.Remotes {
+		names = append(names, name)
+	}
+	sort.Strings(names)
 
 	var sb strings.Builder
-	for _, r := range remotes {
-		cfg := r.Config()
-
-		// Check override
-		displayURL := ""
-		if repoCfg != nil {
-			displayURL = repoCfg.Raw.Section("remote").Subsection(cfg.Name).Option("displayurl")
+	for _, name := range names {
+		if !verbose {
+			sb.WriteString(fmt.Sprintf("%s\n", name))
+			continue
 		}
-
-		if verbose {
-			for _, url := range cfg.URLs {
-				showURL := url
-				if displayURL != "" {
-					showURL = displayURL
-				}
-				sb.WriteString(fmt.Sprintf("%s\t%s (fetch)\n", cfg.Name, showURL))
-				sb.WriteString(fmt.Sprintf("%s\t%s (push)\n", cfg.Name, showURL))
-			}
-		} else {
-			sb.WriteString(fmt.Sprintf("%s\n", cfg.Name))
+		remote := cfg.Remotes[name]
+		if len(remote.URLs) > 0 {
+			sb.WriteString(fmt.Sprintf("%s\t%s (fetch)\n", name, remoteDisplayURL(cfg, name, remote.URLs[0])))
+		}
+		for _, url := range remotePushURLs(cfg, remote) {
+			sb.WriteString(fmt.Sprintf("%s\t%s (push)\n", name, remoteDisplayURL(cfg, name, url)))
 		}
 	}
 	return sb.String(), nil
@@ -239,20 +855,43 @@ func (c *RemoteCommand) Help() string {
     ・新しい接続先を追加する（add）
     ・不要な接続先を削除する（remove）
     ・接続先の名前を変更する（rename）
-    ・接続先のURLを変更する（set-url）
+    ・接続先のURLを変更する（set-url / get-url）
+    ・接続先の詳細（HEADブランチ、追跡状況）を表示する（show）
+    ・リモートで削除されたブランチの追跡ブランチを掃除する（prune）
+    ・origin/HEAD を設定する（set-head）
+
+    origin・upstream・チームメイトのフォークなど、複数のリモートを同時に登録できます。
+    rename / remove はリモート追跡ブランチ（refs/remotes/<name>/*）と追跡設定も更新します。
 
  📋 SYNOPSIS
     git remote [-v]
-    git remote add <name> <url>
+    git remote add [-f] [-t <branch>] [-m <master>] <name> <url>
     git remote remove <name>
     git remote rename <old> <new>
-    git remote set-url <name> <newurl>
-    git remote get-url <name>
+    git remote set-url [--push] <name> <newurl> [<oldurl>]
+    git remote set-url --add [--push] <name> <newurl>
+    git remote set-url --delete [--push] <name> <url>
+    git remote get-url [--push] [--all] <name>
+    git remote show [-n] <name>
+    git remote prune [-n | --dry-run] <name>
+    git remote set-head <name> (-a | --auto | -d | --delete | <branch>)
 
  ⚙️  COMMON OPTIONS
     -v, --verbose
         URLも含めて詳細に表示します。
 
+    add -f
+        追加した直後にフェッチします。
+
+    add -t <branch>
+        指定したブランチだけを追跡するようにフェッチ設定を作ります。
+
+    set-url --push
+        プッシュ先のURL（pushurl）だけを変更します。フェッチ元はそのままです。
+
+    prune -n, --dry-run
+        実際には削除せず、削除される追跡ブランチを表示します。
+
  🛠  EXAMPLES
     1. リモート一覧を表示
        $ git remote -v
@@ -266,6 +905,13 @@ func (c *RemoteCommand) Help() string {
     4. リモートのURLを変更
        $ git remote set-url origin https://github.com/user/new-repo.git
 
+    5. フォーク元を upstream として追加し、それぞれからフェッチ
+       $ git remote add -f upstream https://github.com/original/repo.git
+
+    6. リモートの状態を確認し、消えたブランチを掃除
+       $ git remote show origin
+       $ git remote prune origin
+
  🔗 REFERENCE
     Full documentation: https://git-scm.com/docs/git-remote
 `
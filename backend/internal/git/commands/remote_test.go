@@ -131,3 +131,300 @@ func TestFetchCommand(t *testing.T) {
 		}
 	})
 }
+
+func TestRemoteCommand_MultipleRemotes(t *testing.T) {
+	sm := git.NewSessionManager()
+	s, _ := sm.CreateSession("test-multi-remote")
+
+	newShared := func(name string) *gogit.Repository {
+		fs := memfs.New()
+		repo, _ := gogit.Init(memory.NewStorage(), fs)
+		w, _ := repo.Worktree()
+		f, _ := fs.Create(name + ".txt")
+		f.Close()
+		w.Add(name + ".txt")
+		if _, err := w.Commit("Commit on "+name, &gogit.CommitOptions{Author: &object.Signature{Name: name, When: time.Now()}}); err != nil {
+			t.Fatalf("setup commit failed: %v", err)
+		}
+		if err := sm.IngestRepository(name, name, func(string) (*gogit.Repository, error) { return repo, nil }); err != nil {
+			t.Fatal(err)
+		}
+		return repo
+	}
+	upstream := newShared("team-upstream")
+	fork := newShared("team-fork")
+
+	s.InitRepo("repo")
+	s.CurrentDir = "/repo"
+	repo := s.GetRepo()
+
+	run := func(args ...string) (string, error) {
+		return git.Dispatch(context.Background(), s, args[0], args)
+	}
+	hasRef := func(name string) bool {
+		_, err := repo.Storer.Reference(plumbing.ReferenceName(name))
+		return err == nil
+	}
+
+	t.Run("Add several remotes", func(t *testing.T) {
+		for _, args := range [][]string{{"remote", "add", "upstream", "team-upstream"}, {"remote", "add", "fork", "team-fork"}} {
+			if _, err := run(args...); err != nil {
+				t.Fatalf("%v failed: %v", args, err)
+			}
+		}
+		if _, err := run("remote", "add", "fork", "team-upstream"); err == nil || !strings.Contains(err.Error(), "remote fork already exists") {
+			t.Errorf("duplicate add should fail, got %v", err)
+		}
+		if _, err := run("remote", "add", "bad..name", "team-upstream"); err == nil {
+			t.Error("invalid remote name should be rejected")
+		}
+	})
+
+	t.Run("Fetch each remote independently", func(t *testing.T) {
+		if _, err := run("fetch", "upstream"); err != nil {
+			t.Fatalf("fetch upstream failed: %v", err)
+		}
+		if !hasRef("refs/remotes/upstream/master") || hasRef("refs/remotes/fork/master") {
+			t.Fatal("fetching upstream should only update upstream's tracking refs")
+		}
+
+		state, err := sm.GetGraphState(s.ID, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		attached := map[string]string{}
+		for _, r := range state.Remotes {
+			attached[r.Name] = r.SharedRemote
+		}
+		if attached["upstream"] != "team-upstream" || attached["fork"] != "team-fork" {
+			t.Errorf("unexpected shared remotes: %v", attached)
+		}
+	})
+
+	t.Run("Push -u sets the default remote", func(t *testing.T) {
+		upstreamHead, _ := upstream.Head()
+		_ = repo.Storer.SetReference(plumbing.NewHashReference("refs/heads/feature", upstreamHead.Hash()))
+		_ = repo.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, "refs/heads/feature"))
+
+		out, err := run("push", "-u", "fork", "feature")
+		if err != nil {
+			t.Fatalf("push failed: %v", err)
+		}
+		if !strings.Contains(out, "branch 'feature' set up to track 'fork/feature'.") {
+			t.Errorf("unexpected push output: %s", out)
+		}
+		if _, err := fork.Reference("refs/heads/feature", true); err != nil {
+			t.Error("feature should exist on the fork")
+		}
+		if _, err := upstream.Reference("refs/heads/feature", true); err == nil {
+			t.Error("upstream must not receive the push")
+		}
+
+		// Without arguments fetch talks to the branch's remote
+		if _, err := run("fetch"); err != nil {
+			t.Fatalf("fetch failed: %v", err)
+		}
+		if !hasRef("refs/remotes/fork/master") {
+			t.Error("plain fetch should use the upstream remote of the current branch")
+		}
+	})
+
+	t.Run("Rename moves tracking refs and upstream config", func(t *testing.T) {
+		if _, err := run("remote", "rename", "fork", "teammate"); err != nil {
+			t.Fatalf("rename failed: %v", err)
+		}
+		if hasRef("refs/remotes/fork/feature") || !hasRef("refs/remotes/teammate/feature") {
+			t.Error("tracking refs should move to refs/remotes/teammate/")
+		}
+		cfg, _ := repo.Config()
+		if cfg.Branches["feature"].Remote != "teammate" {
+			t.Errorf("branch upstream not renamed: %s", cfg.Branches["feature"].Remote)
+		}
+		if got := cfg.Remotes["teammate"].Fetch[0].String(); got != "+refs/heads/*:refs/remotes/teammate/*" {
+			t.Errorf("fetch refspec not renamed: %s", got)
+		}
+		if _, err := run("remote", "rename", "fork", "other"); err == nil || !strings.Contains(err.Error(), "No such remote: 'fork'") {
+			t.Errorf("renaming a missing remote should fail, got %v", err)
+		}
+	})
+
+	t.Run("Set-url keeps refspecs", func(t *testing.T) {
+		if _, err := run("remote", "set-url", "teammate", "team-upstream"); err != nil {
+			t.Fatalf("set-url failed: %v", err)
+		}
+		cfg, _ := repo.Config()
+		remote := cfg.Remotes["teammate"]
+		if remote.URLs[0] != "team-upstream" || remote.Fetch[0].String() != "+refs/heads/*:refs/remotes/teammate/*" {
+			t.Errorf("unexpected remote config: %v %v", remote.URLs, remote.Fetch)
+		}
+	})
+
+	t.Run("Remove drops tracking refs and upstream config", func(t *testing.T) {
+		if _, err := run("remote", "remove", "teammate"); err != nil {
+			t.Fatalf("remove failed: %v", err)
+		}
+		if hasRef("refs/remotes/teammate/feature") {
+			t.Error("tracking refs of a removed remote should be deleted")
+		}
+		if !hasRef("refs/remotes/upstream/master") {
+			t.Error("other remotes must be untouched")
+		}
+		cfg, _ := repo.Config()
+		if b := cfg.Branches["feature"]; b != nil && b.Remote != "" {
+			t.Errorf("upstream of feature should be unset, got %s", b.Remote)
+		}
+		if _, err := run("remote", "remove", "teammate"); err == nil || !strings.Contains(err.Error(), "No such remote: 'teammate'") {
+			t.Errorf("removing twice should fail, got %v", err)
+		}
+	})
+}
+
+func TestRemoteCommand_ShowPruneSetHead(t *testing.T) {
+	sm := git.NewSessionManager()
+	s, _ := sm.CreateSession("test-remote-show")
+
+	fs := memfs.New()
+	central, _ := gogit.Init(memory.NewStorage(), fs)
+	w, _ := central.Worktree()
+	f, _ := fs.Create("README.md")
+	f.Close()
+	w.Add("README.md")
+	hash, err := w.Commit("Initial", &gogit.CommitOptions{Author: &object.Signature{Name: "central", When: time.Now()}})
+	if err != nil {
+		t.Fatalf("setup commit failed: %v", err)
+	}
+	_ = central.Storer.SetReference(plumbing.NewHashReference("refs/heads/topic", hash))
+	sm.SharedRemotes["central"] = central
+	sm.SharedRemotePaths["central"] = "/remotes/central"
+
+	s.InitRepo("repo")
+	s.CurrentDir = "/repo"
+	repo := s.GetRepo()
+
+	run := func(args ...string) (string, error) {
+		return git.Dispatch(context.Background(), s, args[0], args)
+	}
+	hasRef := func(name string) bool {
+		_, err := repo.Storer.Reference(plumbing.ReferenceName(name))
+		return err == nil
+	}
+
+	t.Run("Add -f -m fetches and sets HEAD", func(t *testing.T) {
+		out, err := run("remote", "add", "-f", "-m", "master", "origin", "central")
+		if err != nil {
+			t.Fatalf("remote add -f failed: %v", err)
+		}
+		if !strings.HasPrefix(out, "Updating origin") {
+			t.Errorf("unexpected output: %s", out)
+		}
+		if !hasRef("refs/remotes/origin/master") || !hasRef("refs/remotes/origin/topic") {
+			t.Error("add -f should fetch the remote branches")
+		}
+		head, err := repo.Storer.Reference("refs/remotes/origin/HEAD")
+		if err != nil || head.Target() != "refs/remotes/origin/master" {
+			t.Errorf("add -m should point origin/HEAD at master, got %v", head)
+		}
+	})
+
+	t.Run("Add -t tracks only the given branches", func(t *testing.T) {
+		if _, err := run("remote", "add", "-t", "topic", "narrow", "central"); err != nil {
+			t.Fatalf("remote add -t failed: %v", err)
+		}
+		cfg, _ := repo.Config()
+		fetch := cfg.Remotes["narrow"].Fetch
+		if len(fetch) != 1 || fetch[0].String() != "+refs/heads/topic:refs/remotes/narrow/topic" {
+			t.Errorf("unexpected fetch refspecs: %v", fetch)
+		}
+	})
+
+	t.Run("Show lists tracked, new and stale branches", func(t *testing.T) {
+		_ = central.Storer.RemoveReference("refs/heads/topic")
+		_ = central.Storer.SetReference(plumbing.NewHashReference("refs/heads/next", hash))
+
+		out, err := run("remote", "show", "origin")
+		if err != nil {
+			t.Fatalf("remote show failed: %v", err)
+		}
+		for _, want := range []string{
+			"* remote origin",
+			"  Fetch URL: central",
+			"  HEAD branch: master",
+			"  Remote branches:",
+			"    master                    tracked",
+			"    next                      new (next fetch will store in remotes/origin)",
+			"    refs/remotes/origin/topic stale (use 'git remote prune' to remove)",
+		} {
+			if !strings.Contains(out, want) {
+				t.Errorf("show output missing %q:\n%s", want, out)
+			}
+		}
+
+		out, err = run("remote", "show", "-n", "origin")
+		if err != nil {
+			t.Fatalf("remote show -n failed: %v", err)
+		}
+		if !strings.Contains(out, "HEAD branch: (not queried)") || !strings.Contains(out, "Remote branches: (status not queried)") {
+			t.Errorf("unexpected show -n output:\n%s", out)
+		}
+	})
+
+	t.Run("Prune removes stale tracking refs", func(t *testing.T) {
+		out, err := run("remote", "prune", "--dry-run", "origin")
+		if err != nil {
+			t.Fatalf("prune --dry-run failed: %v", err)
+		}
+		if !strings.Contains(out, " * [would prune] origin/topic") || !hasRef("refs/remotes/origin/topic") {
+			t.Errorf("dry run should only report: %s", out)
+		}
+
+		out, err = run("remote", "prune", "origin")
+		if err != nil {
+			t.Fatalf("prune failed: %v", err)
+		}
+		if out != "Pruning origin\nURL: central\n * [pruned] origin/topic" {
+			t.Errorf("unexpected prune output: %q", out)
+		}
+		if hasRef("refs/remotes/origin/topic") || !hasRef("refs/remotes/origin/master") {
+			t.Error("only the stale ref should be pruned")
+		}
+	})
+
+	t.Run("Set-head", func(t *testing.T) {
+		if _, err := run("remote", "set-head", "origin", "-d"); err != nil || hasRef("refs/remotes/origin/HEAD") {
+			t.Fatalf("set-head -d failed: %v", err)
+		}
+		out, err := run("remote", "set-head", "origin", "--auto")
+		if err != nil || out != "origin/HEAD set to master" {
+			t.Fatalf("set-head --auto: %q, %v", out, err)
+		}
+		if _, err := run("remote", "set-head", "origin", "next"); err == nil || !strings.Contains(err.Error(), "Not a valid ref: refs/remotes/origin/next") {
+			t.Errorf("set-head to an unfetched branch should fail, got %v", err)
+		}
+	})
+
+	t.Run("Push URLs", func(t *testing.T) {
+		if _, err := run("remote", "set-url", "--push", "origin", "team-push"); err != nil {
+			t.Fatalf("set-url --push failed: %v", err)
+		}
+		if _, err := run("remote", "set-url", "--add", "--push", "origin", "team-mirror"); err != nil {
+			t.Fatalf("set-url --add --push failed: %v", err)
+		}
+		if out, _ := run("remote", "get-url", "origin"); out != "central" {
+			t.Errorf("fetch URL should be unchanged, got %q", out)
+		}
+		if out, _ := run("remote", "get-url", "--push", "--all", "origin"); out != "team-push\nteam-mirror" {
+			t.Errorf("unexpected push URLs: %q", out)
+		}
+		out, _ := run("remote", "-v")
+		if !strings.Contains(out, "origin\tcentral (fetch)\norigin\tteam-push (push)\norigin\tteam-mirror (push)\n") {
+			t.Errorf("unexpected verbose listing:\n%s", out)
+		}
+
+		if _, err := run("remote", "set-url", "--delete", "origin", "central"); err == nil || !strings.Contains(err.Error(), "Will not delete all non-push URLs") {
+			t.Errorf("deleting the only fetch URL should fail, got %v", err)
+		}
+		if _, err := run("remote", "set-url", "origin", "elsewhere", "no-such"); err == nil || !strings.Contains(err.Error(), "No such URL found: no-such") {
+			t.Errorf("unknown old URL should fail, got %v", err)
+		}
+	})
+}
@@ -0,0 +1,238 @@
+package commands
+
+// rename.go - Rename detection shared by status, commit, show and log.
+//
+// Git does not record renames: a moved file is a deletion plus an addition.
+// Like git, we pair deleted and added files back up by how similar their
+// contents are.
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/utils/merkletrie"
+)
+
+// renameThreshold is the minimum similarity (percent) for a rename, git's default.
+const renameThreshold = 50
+
+// renamePair is a file that moved from From to To with Score percent similar contents.
+type renamePair struct {
+	From, To string
+	Score    int
+}
+
+// detectRenames pairs deleted files with added files at least renameThreshold
+// percent similar. Identical files are paired first, then the best scores win.
+// The maps hold file contents by path.
+func detectRenames(deleted, added map[string]string) []renamePair {
+	type candidate struct {
+		renamePair
+		exact bool
+	}
+	var candidates []candidate
+	for from, a := range deleted {
+		for to, b := range added {
+			// Like git, empty files are never paired up
+			if a == "" || b == "" {
+				continue
+			}
+			if a == b {
+				candidates = append(candidates, candidate{renamePair{from, to, 100}, true})
+				continue
+			}
+			if score := similarityScore(a, b); score >= renameThreshold {
+				candidates = append(candidates, candidate{renamePair{from, to, score}, false})
+			}
+		}
+	}
+	sort.Slice(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		switch {
+		case a.exact != b.exact:
+			return a.exact
+		case a.Score != b.Score:
+			return a.Score > b.Score
+		case a.From != b.From:
+			return a.From < b.From
+		}
+		return a.To < b.To
+	})
+
+	usedFrom, usedTo := map[string]bool{}, map[string]bool{}
+	var pairs []renamePair
+	for _, c := range candidates {
+		if usedFrom[c.From] || usedTo[c.To] {
+			continue
+		}
+		usedFrom[c.From], usedTo[c.To] = true, true
+		pairs = append(pairs, c.renamePair)
+	}
+	sort.Slice(pairs, func(i, j int) bool { return pairs[i].To < pairs[j].To })
+	return pairs
+}
+
+// similarityScore rates how alike two file contents are, from 0 to 100: the
+// bytes of the lines they share over the size of the larger one, as git's
+// similarity index does.
+func similarityScore(a, b string) int {
+	if a == b {
+		return 100
+	}
+	size := max(len(a), len(b))
+	lines := map[string]int{}
+	for _, line := range strings.SplitAfter(a, "\n") {
+		lines[line]++
+	}
+	common := 0
+	for _, line := range strings.SplitAfter(b, "\n") {
+		if lines[line] > 0 {
+			lines[line]--
+			common += len(line)
+		}
+	}
+	return common * 100 / size
+}
+
+// fileChange is one line of --name-status output.
+type fileChange struct {
+	Status   string // A, M, D or R<score>
+	From, To string // From is only set for renames
+}
+
+func (f fileChange) String() string {
+	if f.From != "" {
+		return fmt.Sprintf("%s\t%s\t%s", f.Status, f.From, f.To)
+	}
+	return fmt.Sprintf("%s\t%s", f.Status, f.To)
+}
+
+// commitChanges lists the files a commit changed against its first parent,
+// with renames detected.
+func commitChanges(commit *object.Commit) ([]fileChange, error) {
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, err
+	}
+	var parentTree *object.Tree
+	if commit.NumParents() > 0 {
+		parent, err := commit.Parent(0)
+		if err != nil {
+			return nil, err
+		}
+		if parentTree, err = parent.Tree(); err != nil {
+			return nil, err
+		}
+	}
+	return treeChanges(parentTree, tree)
+}
+
+// treeChanges lists the files that differ between two trees (from may be nil
+// for a root commit), with renames detected.
+func treeChanges(from, to *object.Tree) ([]fileChange, error) {
+	deleted, added := map[string]string{}, map[string]string{}
+	var changes []fileChange
+
+	if from == nil {
+		err := to.Files().ForEach(func(f *object.File) error {
+			changes = append(changes, fileChange{Status: "A", To: f.Name})
+			return nil
+		})
+		return changes, err
+	}
+
+	// Tree.Diff pairs renames itself but without a score, so diff plainly
+	diff, err := object.DiffTreeWithOptions(context.Background(), from, to, nil)
+	if err != nil {
+		return nil, err
+	}
+	for _, change := range diff {
+		action, err := change.Action()
+		if err != nil {
+			continue
+		}
+		switch action {
+		case merkletrie.Insert:
+			added[change.To.Name] = changeContents(to, change.To.Name)
+		case merkletrie.Delete:
+			deleted[change.From.Name] = changeContents(from, change.From.Name)
+		default:
+			changes = append(changes, fileChange{Status: "M", To: change.To.Name})
+		}
+	}
+
+	for _, r := range detectRenames(deleted, added) {
+		delete(deleted, r.From)
+		delete(added, r.To)
+		changes = append(changes, fileChange{Status: fmt.Sprintf("R%03d", r.Score), From: r.From, To: r.To})
+	}
+	for name := range added {
+		changes = append(changes, fileChange{Status: "A", To: name})
+	}
+	for name := range deleted {
+		changes = append(changes, fileChange{Status: "D", To: name})
+	}
+	sort.Slice(changes, func(i, j int) bool { return changes[i].To < changes[j].To })
+	return changes, nil
+}
+
+func changeContents(tree *object.Tree, name string) string {
+	f, err := tree.File(name)
+	if err != nil {
+		return ""
+	}
+	content, _ := f.Contents()
+	return content
+}
+
+// stagedRenames finds renames among the files staged as deleted and added,
+// keyed by the new path.
+func stagedRenames(repo *gogit.Repository, status gogit.Status) map[string]renamePair {
+	deleted, added := map[string]string{}, map[string]string{}
+	for path, st := range status {
+		switch st.Staging {
+		case gogit.Deleted:
+			deleted[path] = ""
+		case gogit.Added:
+			added[path] = ""
+		}
+	}
+	if len(deleted) == 0 || len(added) == 0 {
+		return nil
+	}
+
+	if head, err := repo.Head(); err == nil {
+		if commit, err := repo.CommitObject(head.Hash()); err == nil {
+			if tree, err := commit.Tree(); err == nil {
+				for path := range deleted {
+					deleted[path] = changeContents(tree, path)
+				}
+			}
+		}
+	}
+	if idx, err := repo.Storer.Index(); err == nil {
+		for _, e := range idx.Entries {
+			if _, ok := added[e.Name]; !ok {
+				continue
+			}
+			if blob, err := repo.BlobObject(e.Hash); err == nil {
+				if r, err := blob.Reader(); err == nil {
+					content, _ := io.ReadAll(r)
+					r.Close()
+					added[e.Name] = string(content)
+				}
+			}
+		}
+	}
+
+	renames := map[string]renamePair{}
+	for _, r := range detectRenames(deleted, added) {
+		renames[r.To] = r
+	}
+	return renames
+}
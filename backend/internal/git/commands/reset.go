@@ -1,11 +1,24 @@
 package commands
 
+// reset.go - Simulated Git Reset Command
+//
+// Moves the current branch to a commit and resets the index (and with --hard,
+// --keep or --merge, the working tree) to it. With paths it only copies their
+// entries from a tree-ish into the index, leaving HEAD alone.
+
 import (
 	"context"
 	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
 
 	gogit "github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/format/index"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/kurobon/gitgym/backend/internal/git"
 )
 
@@ -18,9 +31,31 @@ type ResetCommand struct{}
 // Ensure ResetCommand implements git.Command
 var _ git.Command = (*ResetCommand)(nil)
 
+// Reset modes
+const (
+	resetSoft  = "soft"
+	resetMixed = "mixed"
+	resetHard  = "hard"
+	resetKeep  = "keep"
+	resetMerge = "merge"
+)
+
 type ResetOptions struct {
-	Mode   gogit.ResetMode
-	Target string
+	Mode        string // soft, mixed (default), hard, keep or merge
+	Target      string
+	Paths       []string // Pathspecs: reset only these index entries
+	IntentToAdd bool     // -N: keep removed paths as intent-to-add
+	Patch       bool     // -p: every hunk is selected (there is no terminal to ask)
+	Quiet       bool
+
+	args     []string // Positional arguments before "--"
+	dashDash bool
+}
+
+type resetContext struct {
+	repo   *gogit.Repository
+	w      *gogit.Worktree
+	target *object.Commit // nil when resetting paths in a repository without commits
 }
 
 func (c *ResetCommand) Execute(ctx context.Context, s *git.Session, args []string) (string, error) {
@@ -39,23 +74,21 @@ func (c *ResetCommand) Execute(ctx context.Context, s *git.Session, args []strin
 	}
 
 	// 2. Resolve Context
-	targetHash, err := repo.ResolveRevision(plumbing.Revision(opts.Target))
-	if err != nil {
-		return "", err
-	}
-
-	w, err := repo.Worktree()
+	rCtx, err := c.resolveContext(repo, opts)
 	if err != nil {
 		return "", err
 	}
 
 	// 3. Execution
-	return c.executeReset(s, w, targetHash, opts)
+	if len(opts.Paths) > 0 || opts.Patch {
+		return c.resetPaths(rCtx, opts)
+	}
+	return c.executeReset(s, rCtx, opts)
 }
 
 func (c *ResetCommand) parseArgs(args []string) (*ResetOptions, error) {
 	opts := &ResetOptions{
-		Mode:   gogit.MixedReset,
+		Mode:   resetMixed,
 		Target: "HEAD",
 	}
 	cmdArgs := args[1:]
@@ -63,34 +96,598 @@ func (c *ResetCommand) parseArgs(args []string) (*ResetOptions, error) {
 	for i := 0; i < len(cmdArgs); i++ {
 		arg := cmdArgs[i]
 		switch arg {
-		case "--soft":
-			opts.Mode = gogit.SoftReset
-		case "--mixed":
-			opts.Mode = gogit.MixedReset
-		case "--hard":
-			opts.Mode = gogit.HardReset
+		case "--soft", "--mixed", "--hard", "--keep", "--merge":
+			opts.Mode = strings.TrimPrefix(arg, "--")
+		case "-N", "--intent-to-add":
+			opts.IntentToAdd = true
+		case "-p", "--patch":
+			opts.Patch = true
+		case "-q", "--quiet":
+			opts.Quiet = true
 		case "-h", "--help":
 			return nil, fmt.Errorf("help requested")
+		case "--":
+			opts.dashDash = true
+			opts.Paths = append(opts.Paths, cmdArgs[i+1:]...)
+			i = len(cmdArgs)
 		default:
-			opts.Target = arg
+			if strings.HasPrefix(arg, "-") && arg != "-" {
+				return nil, fmt.Errorf("error: unknown option `%s'", strings.TrimLeft(arg, "-"))
+			}
+			opts.args = append(opts.args, arg)
 		}
 	}
+
+	if opts.IntentToAdd && opts.Mode != resetMixed {
+		return nil, fmt.Errorf("fatal: -N can only be used with --mixed")
+	}
 	return opts, nil
 }
 
-func (c *ResetCommand) executeReset(s *git.Session, w *gogit.Worktree, targetHash *plumbing.Hash, opts *ResetOptions) (string, error) {
+// resolveContext splits the positional arguments into the target and the
+// paths like git: without "--" the first argument is a revision if it
+// resolves to one, and every other argument must name a known file.
+func (c *ResetCommand) resolveContext(repo *gogit.Repository, opts *ResetOptions) (*resetContext, error) {
+	w, err := repo.Worktree()
+	if err != nil {
+		return nil, err
+	}
+	rCtx := &resetContext{repo: repo, w: w}
+
+	isRevision := func(rev string) bool {
+		_, err := git.ResolveRevision(repo, rev)
+		return err == nil
+	}
+	ambiguous := func(arg string) error {
+		return fmt.Errorf("fatal: ambiguous argument '%s': unknown revision or path not in the working tree.\nUse '--' to separate paths from revisions, like this:\n'git <command> [<revision>...] -- [<file>...]'", arg)
+	}
+
+	switch {
+	case opts.dashDash:
+		if len(opts.args) > 1 {
+			return nil, fmt.Errorf("fatal: only one revision allowed, got '%s'", strings.Join(opts.args, "' '"))
+		}
+		if len(opts.args) == 1 {
+			opts.Target = opts.args[0]
+		}
+	case len(opts.args) > 0:
+		first := opts.args[0]
+		rest := opts.args[1:]
+		switch {
+		case isRevision(first) && len(rest) == 0 && first != "HEAD" && knownPath(repo, w, first):
+			return nil, fmt.Errorf("fatal: ambiguous argument '%s': both revision and filename\nUse '--' to separate paths from revisions, like this:\n'git <command> [<revision>...] -- [<file>...]'", first)
+		case isRevision(first):
+			opts.Target = first
+		case knownPath(repo, w, first):
+			rest = opts.args
+		default:
+			return nil, ambiguous(first)
+		}
+		for _, path := range rest {
+			if !knownPath(repo, w, path) {
+				return nil, ambiguous(path)
+			}
+		}
+		opts.Paths = rest
+	}
+
+	if (len(opts.Paths) > 0 || opts.Patch) && opts.Mode != resetMixed {
+		return nil, fmt.Errorf("fatal: Cannot do %s reset with paths.", opts.Mode)
+	}
+
+	hash, err := git.ResolveRevision(repo, opts.Target)
+	if err != nil {
+		if opts.Target == "HEAD" && len(opts.Paths) > 0 {
+			return rCtx, nil // Unborn branch: unstaging removes the entries
+		}
+		return nil, fmt.Errorf("fatal: ambiguous argument '%s': unknown revision or path not in the working tree.", opts.Target)
+	}
+	if rCtx.target, err = repo.CommitObject(*hash); err != nil {
+		return nil, fmt.Errorf("fatal: Could not parse object '%s'.", opts.Target)
+	}
+	return rCtx, nil
+}
+
+func (c *ResetCommand) executeReset(s *git.Session, rCtx *resetContext, opts *ResetOptions) (string, error) {
+	target := rCtx.target.Hash
+
+	// --keep and --merge refuse before touching anything
+	var update []string
+	var err error
+	switch opts.Mode {
+	case resetKeep:
+		update, err = c.keepUpdates(rCtx, opts)
+	case resetMerge:
+		update, err = c.mergeUpdates(rCtx, opts)
+	}
+	if err != nil {
+		return "", err
+	}
+
+	// --keep leaves local changes staged as they were; the mixed reset below
+	// would unstage them. keepUpdates refused if any of them is in update.
+	var restoreKept func() error
+	if opts.Mode == resetKeep {
+		dirty, err := localChanges(rCtx.repo)
+		if err != nil {
+			return "", err
+		}
+		if restoreKept, err = saveLocalChanges(rCtx.repo, dirty); err != nil {
+			return "", err
+		}
+	}
+
+	var removed []string
+	if opts.IntentToAdd {
+		if removed, err = c.removedPaths(rCtx); err != nil {
+			return "", err
+		}
+	}
+
 	// Update ORIG_HEAD before reset
 	s.UpdateOrigHead()
 
-	if err := w.Reset(&gogit.ResetOptions{
-		Commit: *targetHash,
-		Mode:   opts.Mode,
-	}); err != nil {
+	mode := gogit.MixedReset
+	switch opts.Mode {
+	case resetSoft:
+		mode = gogit.SoftReset
+	case resetHard:
+		mode = gogit.HardReset
+	}
+	if err := rCtx.w.Reset(&gogit.ResetOptions{Commit: target, Mode: mode}); err != nil {
+		return "", err
+	}
+	if err := c.checkoutPaths(rCtx, update); err != nil {
+		return "", err
+	}
+	if restoreKept != nil {
+		if err := restoreKept(); err != nil {
+			return "", err
+		}
+	}
+	if err := addIntentToAdd(rCtx.repo, removed); err != nil {
 		return "", err
 	}
 	s.RecordReflog(fmt.Sprintf("reset: moving to %s", opts.Target))
 
-	return fmt.Sprintf("HEAD is now at %s", targetHash.String()[:7]), nil
+	if opts.Quiet {
+		return "", nil
+	}
+	var unstaged string
+	if opts.Mode == resetMixed {
+		if unstaged, err = unstagedAfterReset(rCtx.repo); err != nil {
+			return "", err
+		}
+	}
+	return joinOutput(unstaged, fmt.Sprintf("HEAD is now at %s %s", target.String()[:7], commitSubject(rCtx.target))), nil
+}
+
+// keepUpdates checks --keep: files that differ between HEAD and the target
+// must have no local changes. It returns those files, which the reset
+// checks out; local changes to other files are kept.
+func (c *ResetCommand) keepUpdates(rCtx *resetContext, opts *ResetOptions) ([]string, error) {
+	head, err := rCtx.repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("fatal: Cannot do a keep reset without a current commit.")
+	}
+	dirty, err := localChanges(rCtx.repo)
+	if err != nil {
+		return nil, err
+	}
+	if overwritten, err := overwrittenByUpdate(rCtx.repo, head.Hash(), rCtx.target.Hash, dirty); err != nil {
+		return nil, err
+	} else if len(overwritten) > 0 {
+		return nil, fmt.Errorf("error: Entry '%s' not uptodate. Cannot merge.\nfatal: Could not reset index file to revision '%s'.", overwritten[0], opts.Target)
+	}
+
+	headFiles, err := treeFiles(rCtx.repo, head.Hash())
+	if err != nil {
+		return nil, err
+	}
+	targetFiles, err := treeFiles(rCtx.repo, rCtx.target.Hash)
+	if err != nil {
+		return nil, err
+	}
+	status, err := rCtx.w.Status()
+	if err != nil {
+		return nil, err
+	}
+
+	var update []string
+	for path := range unionKeys(headFiles, targetFiles) {
+		if headFiles[path] == targetFiles[path] {
+			continue
+		}
+		if st, ok := status[path]; ok && st.Staging == gogit.Untracked {
+			return nil, fmt.Errorf("error: Untracked working tree file '%s' would be overwritten by merge.\nfatal: Could not reset index file to revision '%s'.", path, opts.Target)
+		}
+		update = append(update, path)
+	}
+	sort.Strings(update)
+	return update, nil
+}
+
+// mergeUpdates checks --merge: a file whose index entry differs from the
+// target must not have unstaged changes. It returns those files, which the
+// reset checks out; files with only unstaged changes keep them. Files still
+// holding conflict markers count as unmerged and are reset too, so
+// "git reset --merge" backs out of a conflicted merge.
+func (c *ResetCommand) mergeUpdates(rCtx *resetContext, opts *ResetOptions) ([]string, error) {
+	targetFiles, err := treeFiles(rCtx.repo, rCtx.target.Hash)
+	if err != nil {
+		return nil, err
+	}
+	indexFiles, err := indexFiles(rCtx.repo)
+	if err != nil {
+		return nil, err
+	}
+	status, err := rCtx.w.Status()
+	if err != nil {
+		return nil, err
+	}
+
+	var update []string
+	for path := range unionKeys(indexFiles, targetFiles) {
+		st, changed := status[path]
+		changed = changed && st.Worktree != gogit.Unmodified
+		if changed && hasConflictMarkers(rCtx.w, path) {
+			update = append(update, path)
+			continue
+		}
+		if indexFiles[path] == targetFiles[path] {
+			continue // Unstaged changes survive
+		}
+		if changed {
+			if st.Staging == gogit.Untracked {
+				return nil, fmt.Errorf("error: Untracked working tree file '%s' would be overwritten by merge.\nfatal: Could not reset index file to revision '%s'.", path, opts.Target)
+			}
+			return nil, fmt.Errorf("error: Entry '%s' would be overwritten by merge. Cannot merge.\nfatal: Could not reset index file to revision '%s'.", path, opts.Target)
+		}
+		update = append(update, path)
+	}
+	sort.Strings(update)
+	return update, nil
+}
+
+// removedPaths lists the index entries the target does not have: -N keeps
+// them as intent-to-add.
+func (c *ResetCommand) removedPaths(rCtx *resetContext) ([]string, error) {
+	targetFiles, err := treeFiles(rCtx.repo, rCtx.target.Hash)
+	if err != nil {
+		return nil, err
+	}
+	indexFiles, err := indexFiles(rCtx.repo)
+	if err != nil {
+		return nil, err
+	}
+	var removed []string
+	for path := range indexFiles {
+		if _, ok := targetFiles[path]; !ok {
+			removed = append(removed, path)
+		}
+	}
+	sort.Strings(removed)
+	return removed, nil
+}
+
+// checkoutPaths writes the target's version of paths to the working tree,
+// deleting the ones the target does not have.
+func (c *ResetCommand) checkoutPaths(rCtx *resetContext, paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	tree, err := rCtx.target.Tree()
+	if err != nil {
+		return err
+	}
+	for _, path := range paths {
+		file, err := tree.File(path)
+		if err != nil {
+			if err := rCtx.w.Filesystem.Remove(path); err != nil && !os.IsNotExist(err) {
+				return err
+			}
+			continue
+		}
+		content, err := file.Contents()
+		if err != nil {
+			return err
+		}
+		if err := writeWorktreeFile(rCtx.w, path, content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// resetPaths copies the entries of paths from the target into the index
+// (removing those the target lacks) without moving HEAD. -p has no terminal
+// to ask about hunks, so it behaves as if every hunk was selected.
+func (c *ResetCommand) resetPaths(rCtx *resetContext, opts *ResetOptions) (string, error) {
+	idx, err := rCtx.repo.Storer.Index()
+	if err != nil {
+		return "", err
+	}
+	var targetFiles map[string]treeEntry
+	if rCtx.target != nil {
+		if targetFiles, err = treeFiles(rCtx.repo, rCtx.target.Hash); err != nil {
+			return "", err
+		}
+	}
+
+	entries := make([]*index.Entry, 0, len(idx.Entries))
+	for _, e := range idx.Entries {
+		if !pathMatches(e.Name, opts.Paths) { // -p without paths: everything
+			entries = append(entries, e)
+			continue
+		}
+		if _, ok := targetFiles[e.Name]; !ok && opts.IntentToAdd && e.Stage == 0 {
+			entries = append(entries, intentToAddEntry(e.Name))
+		}
+	}
+	for path, f := range targetFiles {
+		if pathMatches(path, opts.Paths) {
+			entries = append(entries, &index.Entry{Name: path, Hash: f.hash, Mode: f.mode})
+		}
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Name != entries[j].Name {
+			return entries[i].Name < entries[j].Name
+		}
+		return entries[i].Stage < entries[j].Stage
+	})
+
+	updated := *idx
+	updated.Entries = entries
+	if opts.IntentToAdd && updated.Version < 3 {
+		updated.Version = 3
+	}
+	if err := rCtx.repo.Storer.SetIndex(&updated); err != nil {
+		return "", err
+	}
+
+	if opts.Quiet {
+		return "", nil
+	}
+	return unstagedAfterReset(rCtx.repo)
+}
+
+// unstagedAfterReset lists the tracked files whose working tree differs from
+// the index, the way git reports them after a mixed reset.
+func unstagedAfterReset(repo *gogit.Repository) (string, error) {
+	w, err := repo.Worktree()
+	if err != nil {
+		return "", err
+	}
+	status, err := w.Status()
+	if err != nil {
+		return "", err
+	}
+	markIntentToAdd(repo, status)
+
+	var lines []string
+	for path, st := range status {
+		if st.Worktree != gogit.Unmodified && st.Worktree != gogit.Untracked {
+			lines = append(lines, fmt.Sprintf("%c\t%s", getStatusCodeChar(st.Worktree), path))
+		}
+	}
+	if len(lines) == 0 {
+		return "", nil
+	}
+	sort.Slice(lines, func(i, j int) bool { return lines[i][2:] < lines[j][2:] })
+	return "Unstaged changes after reset:\n" + strings.Join(lines, "\n"), nil
+}
+
+// treeEntry is a file of a tree: its blob and mode.
+type treeEntry struct {
+	hash plumbing.Hash
+	mode filemode.FileMode
+}
+
+// treeFiles flattens the tree of a commit into path -> entry.
+func treeFiles(repo *gogit.Repository, commitHash plumbing.Hash) (map[string]treeEntry, error) {
+	commit, err := repo.CommitObject(commitHash)
+	if err != nil {
+		return nil, err
+	}
+	iter, err := commit.Files()
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]treeEntry)
+	err = iter.ForEach(func(f *object.File) error {
+		files[f.Name] = treeEntry{f.Hash, f.Mode}
+		return nil
+	})
+	return files, err
+}
+
+// indexFiles returns the merged entries of the index as path -> entry. They
+// have stage 0 (go-git's index.Merged constant is 1, which is wrong here).
+func indexFiles(repo *gogit.Repository) (map[string]treeEntry, error) {
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return nil, err
+	}
+	files := make(map[string]treeEntry, len(idx.Entries))
+	for _, e := range idx.Entries {
+		if e.Stage == 0 {
+			files[e.Name] = treeEntry{e.Hash, e.Mode}
+		}
+	}
+	return files, nil
+}
+
+func unionKeys(a, b map[string]treeEntry) map[string]bool {
+	keys := make(map[string]bool, len(a)+len(b))
+	for k := range a {
+		keys[k] = true
+	}
+	for k := range b {
+		keys[k] = true
+	}
+	return keys
+}
+
+// hasConflictMarkers reports whether a working tree file still contains the
+// markers of an unresolved merge.
+func hasConflictMarkers(w *gogit.Worktree, path string) bool {
+	f, err := w.Filesystem.Open(path)
+	if err != nil {
+		return false
+	}
+	defer f.Close()
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return false
+	}
+	content := string(data)
+	return strings.HasPrefix(content, "<<<<<<< ") || strings.Contains(content, "\n<<<<<<< ")
+}
+
+func writeWorktreeFile(w *gogit.Worktree, path, content string) error {
+	f, err := w.Filesystem.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write([]byte(content))
+	return err
+}
+
+// Intent-to-add entries (reset -N) record that a path will be added later.
+// They carry the empty blob, show up as unstaged new files and are left out
+// of commits until the file is added.
+
+func intentToAddEntry(name string) *index.Entry {
+	return &index.Entry{
+		Name:        name,
+		Hash:        plumbing.ComputeHash(plumbing.BlobObject, nil),
+		Mode:        filemode.Regular,
+		IntentToAdd: true,
+	}
+}
+
+// addIntentToAdd adds intent-to-add entries for paths missing from the index.
+func addIntentToAdd(repo *gogit.Repository, paths []string) error {
+	if len(paths) == 0 {
+		return nil
+	}
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return err
+	}
+	present := make(map[string]bool, len(idx.Entries))
+	for _, e := range idx.Entries {
+		present[e.Name] = true
+	}
+	for _, path := range paths {
+		if !present[path] {
+			idx.Entries = append(idx.Entries, intentToAddEntry(path))
+		}
+	}
+	sort.Slice(idx.Entries, func(i, j int) bool { return idx.Entries[i].Name < idx.Entries[j].Name })
+	if idx.Version < 3 {
+		idx.Version = 3
+	}
+	return repo.Storer.SetIndex(idx)
+}
+
+// intentToAddPaths returns the paths of the intent-to-add entries.
+func intentToAddPaths(repo *gogit.Repository) map[string]bool {
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return nil
+	}
+	paths := make(map[string]bool)
+	for _, e := range idx.Entries {
+		if e.IntentToAdd {
+			paths[e.Name] = true
+		}
+	}
+	return paths
+}
+
+// markIntentToAdd shows intent-to-add entries as git does: nothing staged,
+// a new file in the working tree.
+func markIntentToAdd(repo *gogit.Repository, status gogit.Status) {
+	for path := range intentToAddPaths(repo) {
+		st := status.File(path)
+		st.Staging = gogit.Unmodified
+		st.Worktree = gogit.Added
+	}
+}
+
+// clearIntentToAdd turns the intent-to-add entries matching paths (all of
+// them when paths is empty) into ordinary entries, once their content has
+// been added.
+func clearIntentToAdd(repo *gogit.Repository, paths []string) error {
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return err
+	}
+	changed := false
+	for i, e := range idx.Entries {
+		if !e.IntentToAdd || !pathMatches(e.Name, paths) {
+			continue
+		}
+		entry := *e
+		entry.IntentToAdd = false
+		idx.Entries[i] = &entry
+		changed = true
+	}
+	if !changed {
+		return nil
+	}
+	return repo.Storer.SetIndex(idx)
+}
+
+// withoutIntentToAdd runs fn with the intent-to-add entries taken out of the
+// index, then puts them back: they are not content to commit.
+func withoutIntentToAdd(repo *gogit.Repository, fn func() error) error {
+	idx, err := repo.Storer.Index()
+	if err != nil {
+		return err
+	}
+	var kept, pending []*index.Entry
+	for _, e := range idx.Entries {
+		if e.IntentToAdd {
+			pending = append(pending, e)
+		} else {
+			kept = append(kept, e)
+		}
+	}
+	if len(pending) == 0 {
+		return fn()
+	}
+
+	idx.Entries = kept
+	if err := repo.Storer.SetIndex(idx); err != nil {
+		return err
+	}
+	fnErr := fn()
+	var paths []string
+	for _, e := range pending {
+		paths = append(paths, e.Name)
+	}
+	if err := addIntentToAdd(repo, paths); err != nil && fnErr == nil {
+		fnErr = err
+	}
+	return fnErr
+}
+
+// pathMatches reports whether name is one of paths or inside one of them;
+// no paths (or ".") match everything.
+func pathMatches(name string, paths []string) bool {
+	if len(paths) == 0 {
+		return true
+	}
+	for _, p := range paths {
+		p = strings.TrimSuffix(strings.TrimPrefix(p, "./"), "/")
+		if p == "." || p == "" || name == p || strings.HasPrefix(name, p+"/") {
+			return true
+		}
+	}
+	return false
 }
 
 func (c *ResetCommand) Help() string {
@@ -101,9 +698,12 @@ func (c *ResetCommand) Help() string {
     ・ステージングした変更を取り消す（Unstage）
     ・作業中の変更をすべて破棄して元に戻す（Hard Reset）
     オプションによって、インデックスやワーキングツリーの状態をどう扱うかが変わります。
+    パスを指定した場合は HEAD を動かさず、そのファイルのインデックスだけを戻します。
 
  📋 SYNOPSIS
-    git reset [--soft | --mixed | --hard] <commit>
+    git reset [--soft | --mixed [-N] | --hard | --keep | --merge] [-q] [<commit>]
+    git reset [-q] [<tree-ish>] [--] <pathspec>...
+    git reset -p [<tree-ish>] [--] [<pathspec>...]
 
  ⚙️  COMMON OPTIONS
     --soft
@@ -113,11 +713,35 @@ func (c *ResetCommand) Help() string {
     --mixed (default)
         HEADとインデックスを移動します。ワーキングツリーは変更しません。
         （戻った分のコミット内容は「未ステージ」として残ります）
+        残った未ステージの変更は "Unstaged changes after reset:" として一覧表示されます。
 
     --hard
         HEAD、インデックス、ワーキングツリーすべてを強制的に移動します。
         未コミットの変更はすべて破棄されます。
 
+    --keep
+        --hard と同様に戻しますが、ローカルの変更は保持します。
+        HEAD と <commit> の間で変わるファイルに変更がある場合は中止します。
+
+    --merge
+        インデックスを戻し、<commit> と異なるファイルを更新します。未ステージの変更は保持します。
+        コンフリクトしたマージを取り消すときに使います。
+        未ステージの変更があるファイルを上書きする必要がある場合は中止します。
+
+    -N, --intent-to-add
+        --mixed で取り除かれたファイルを「後で追加する予定」(intent-to-add) として残します。
+        git status では未ステージの新規ファイルとして表示され、コミットには含まれません。
+
+    <tree-ish> -- <pathspec>...
+        指定したファイルのインデックスを <tree-ish> (既定は HEAD) の内容に戻します。
+        HEAD もワーキングツリーも変わりません（git restore --staged と同じ効果）。
+
+    -p, --patch
+        端末で hunk を選択する代わりに、すべての hunk を選択したものとして扱います。
+
+    -q, --quiet
+        出力を抑制します。
+
  🛠  EXAMPLES
     1. 直前のコミットを取り消す（変更はそのまま残す）
        $ git reset HEAD~1
@@ -125,6 +749,22 @@ func (c *ResetCommand) Help() string {
     2. 全てを強制的に以前の状態に戻す（危険）
        $ git reset --hard HEAD~1
 
+    3. 特定のファイルだけステージングを取り消す
+       $ git reset -- src/main.go
+
+    4. 別のコミットの内容をインデックスに戻す
+       $ git reset HEAD~2 -- config.yml
+
+    5. ローカルの変更を残したままコミットを取り消す
+       $ git reset --keep HEAD~1
+
+    6. コンフリクトしたマージをやめる
+       $ git reset --merge
+
+ 💡 TIPS
+    --keep と --merge は、上書きされると困るローカル変更があれば何もせずに中止するので、
+    --hard より安全です。
+
  🔗 REFERENCE
     Full documentation: https://git-scm.com/docs/git-reset
 `
@@ -70,3 +70,178 @@ func TestResetCommand(t *testing.T) {
 		}
 	})
 }
+
+func TestResetCommand_Extensions(t *testing.T) {
+	sm := git.NewSessionManager()
+	s, _ := sm.CreateSession("test-reset-ext")
+	s.InitRepo("testrepo")
+	s.CurrentDir = "/testrepo"
+	repo := s.GetRepo()
+	w, _ := repo.Worktree()
+
+	write := func(name, content string) {
+		f, _ := w.Filesystem.Create(name)
+		f.Write([]byte(content))
+		f.Close()
+	}
+	read := func(name string) string {
+		f, err := w.Filesystem.Open(name)
+		if err != nil {
+			return "<missing>"
+		}
+		defer f.Close()
+		buf := make([]byte, 256)
+		n, _ := f.Read(buf)
+		return string(buf[:n])
+	}
+	run := func(args ...string) (string, error) {
+		return git.Dispatch(context.Background(), s, args[0], args)
+	}
+	headMessage := func() string {
+		head, _ := repo.Head()
+		c, _ := repo.CommitObject(head.Hash())
+		return c.Message
+	}
+	short := func() string {
+		out, _ := run("status", "-s")
+		return out
+	}
+
+	commitFile(t, repo, "a.txt", "a1", "first")
+	commitFile(t, repo, "b.txt", "b1", "second")
+	commitFile(t, repo, "a.txt", "a2", "third")
+
+	t.Run("Paths", func(t *testing.T) {
+		write("a.txt", "a3")
+		write("b.txt", "b2")
+		w.Add("a.txt")
+		w.Add("b.txt")
+
+		out, err := run("reset", "a.txt")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if out != "Unstaged changes after reset:\nM\ta.txt" {
+			t.Errorf("unexpected output: %q", out)
+		}
+		if headMessage() != "third" {
+			t.Error("path reset must not move HEAD")
+		}
+		if got := short(); got != " M a.txt\nM  b.txt\n" {
+			t.Errorf("unexpected status: %q", got)
+		}
+
+		// From another commit: the index gets first's a.txt
+		if _, err := run("reset", "HEAD~2", "--", "a.txt"); err != nil {
+			t.Fatal(err)
+		}
+		if got := short(); got != "MM a.txt\nM  b.txt\n" {
+			t.Errorf("unexpected status: %q", got)
+		}
+
+		if _, err := run("reset", "--hard", "--", "a.txt"); err == nil || !strings.Contains(err.Error(), "Cannot do hard reset with paths") {
+			t.Errorf("expected hard reset with paths to fail, got %v", err)
+		}
+		if _, err := run("reset", "nothing-here"); err == nil || !strings.Contains(err.Error(), "ambiguous argument") {
+			t.Errorf("expected ambiguous argument error, got %v", err)
+		}
+		run("reset", "--hard")
+	})
+
+	t.Run("Keep", func(t *testing.T) {
+		// a.txt changes between HEAD~1 and HEAD: a local change there blocks --keep
+		write("a.txt", "local")
+		if _, err := run("reset", "--keep", "HEAD~1"); err == nil || !strings.Contains(err.Error(), "Entry 'a.txt' not uptodate") {
+			t.Fatalf("expected --keep to refuse, got %v", err)
+		}
+		if headMessage() != "third" {
+			t.Fatal("refused reset must not move HEAD")
+		}
+
+		// A local change to b.txt (unchanged between them) survives
+		run("reset", "--hard")
+		write("b.txt", "local b")
+		if _, err := run("reset", "--keep", "HEAD~1"); err != nil {
+			t.Fatal(err)
+		}
+		if headMessage() != "second" || read("a.txt") != "a1" || read("b.txt") != "local b" {
+			t.Errorf("unexpected state: HEAD=%q a=%q b=%q", headMessage(), read("a.txt"), read("b.txt"))
+		}
+
+		// Staged changes stay staged
+		run("reset", "--hard")
+		commitFile(t, repo, "a.txt", "a2", "third")
+		write("b.txt", "staged b")
+		run("add", "b.txt")
+		write("c.txt", "new c")
+		run("add", "c.txt")
+		if _, err := run("reset", "--keep", "HEAD~1"); err != nil {
+			t.Fatal(err)
+		}
+		status, _ := run("status", "--short")
+		if !strings.Contains(status, "M  b.txt") || !strings.Contains(status, "A  c.txt") {
+			t.Errorf("--keep should keep staged changes:\n%s", status)
+		}
+		if headMessage() != "second" || read("a.txt") != "a1" {
+			t.Errorf("unexpected state: HEAD=%q a=%q", headMessage(), read("a.txt"))
+		}
+		run("reset", "--hard")
+		run("clean", "-f")
+		commitFile(t, repo, "a.txt", "a2", "third")
+	})
+
+	t.Run("Merge", func(t *testing.T) {
+		// Conflict markers left by a failed merge are reset
+		write("a.txt", "<<<<<<< HEAD\na2\n=======\nother\n>>>>>>> 1234567\n")
+		write("b.txt", "unstaged b")
+		if _, err := run("reset", "--merge"); err != nil {
+			t.Fatal(err)
+		}
+		if read("a.txt") != "a2" || read("b.txt") != "unstaged b" {
+			t.Errorf("unexpected files: a=%q b=%q", read("a.txt"), read("b.txt"))
+		}
+
+		// b.txt differs in HEAD~2 (absent) and has unstaged changes: refuse
+		if _, err := run("reset", "--merge", "HEAD~2"); err == nil || !strings.Contains(err.Error(), "Entry 'b.txt' would be overwritten by merge") {
+			t.Errorf("expected --merge to refuse, got %v", err)
+		}
+		run("reset", "--hard")
+	})
+
+	t.Run("IntentToAdd", func(t *testing.T) {
+		write("c.txt", "new file")
+		w.Add("c.txt")
+		run("commit", "-m", "fourth")
+
+		out, err := run("reset", "-N", "HEAD~1")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(out, "Unstaged changes after reset:\nA\tc.txt") {
+			t.Errorf("unexpected output: %q", out)
+		}
+		if got := short(); got != " A c.txt\n" {
+			t.Errorf("unexpected status: %q", got)
+		}
+
+		// Intent-to-add files are not committed until added
+		write("a.txt", "a4")
+		w.Add("a.txt")
+		if _, err := run("commit", "-m", "only a"); err != nil {
+			t.Fatal(err)
+		}
+		head, _ := repo.Head()
+		c, _ := repo.CommitObject(head.Hash())
+		if _, err := c.File("c.txt"); err == nil {
+			t.Error("intent-to-add file must not be committed")
+		}
+		if got := short(); got != " A c.txt\n" {
+			t.Errorf("expected c.txt to stay intent-to-add, got %q", got)
+		}
+
+		run("add", "c.txt")
+		if got := short(); got != "A  c.txt\n" {
+			t.Errorf("expected c.txt staged, got %q", got)
+		}
+	})
+}
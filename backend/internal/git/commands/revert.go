@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"strings"
-	"time"
 
 	gogit "github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing/object"
@@ -156,7 +155,7 @@ func (c *RevertCommand) Execute(ctx context.Context, s *git.Session, args []stri
 		Author: &object.Signature{
 			Name:  authorName,
 			Email: authorEmail,
-			When:  time.Now(),
+			When:  s.Now(),
 		},
 	})
 	if err != nil {
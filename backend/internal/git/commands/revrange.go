@@ -0,0 +1,61 @@
+package commands
+
+// revrange.go - Revision Ranges
+//
+// Shared by shortlog and bundle: splitting "A..B" and "A...B" arguments and
+// finding the merge bases a symmetric range leaves out.
+
+import (
+	"strings"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// revRange is a "From..To" (or "From...To") argument. An empty side means HEAD.
+type revRange struct {
+	From, To string
+	// Symmetric ranges ("A...B") cover the commits reachable from either side
+	// but not from both.
+	Symmetric bool
+}
+
+// parseRevRange splits a range argument. ok is false for a plain revision.
+func parseRevRange(arg string) (r revRange, ok bool) {
+	sep := ".."
+	if strings.Contains(arg, "...") {
+		sep = "..."
+	}
+	from, to, found := strings.Cut(arg, sep)
+	if !found {
+		return revRange{}, false
+	}
+	if from == "" {
+		from = "HEAD"
+	}
+	if to == "" {
+		to = "HEAD"
+	}
+	return revRange{From: from, To: to, Symmetric: sep == "..."}, true
+}
+
+// mergeBases returns the best common ancestors of a and b.
+func mergeBases(repo *gogit.Repository, a, b plumbing.Hash) ([]plumbing.Hash, error) {
+	ca, err := repo.CommitObject(a)
+	if err != nil {
+		return nil, err
+	}
+	cb, err := repo.CommitObject(b)
+	if err != nil {
+		return nil, err
+	}
+	bases, err := ca.MergeBase(cb)
+	if err != nil {
+		return nil, err
+	}
+	hashes := make([]plumbing.Hash, 0, len(bases))
+	for _, base := range bases {
+		hashes = append(hashes, base.Hash)
+	}
+	return hashes, nil
+}
@@ -0,0 +1,295 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/kurobon/gitgym/backend/internal/git"
+)
+
+func init() {
+	git.RegisterCommand("shortlog", func() git.Command { return &ShortlogCommand{} })
+}
+
+type ShortlogCommand struct{}
+
+// Ensure ShortlogCommand implements git.Command
+var _ git.Command = (*ShortlogCommand)(nil)
+
+type ShortlogOptions struct {
+	Summary   bool // -s: only the commit counts
+	Numbered  bool // -n: sort by number of commits
+	Email     bool // -e: show email addresses
+	Committer bool // -c: group by committer instead of author
+	Revisions []string
+	Paths     []string
+}
+
+func (c *ShortlogCommand) Execute(ctx context.Context, s *git.Session, args []string) (string, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	opts, err := c.parseArgs(args)
+	if err != nil {
+		if err.Error() == "help requested" {
+			return c.Help(), nil
+		}
+		return "", err
+	}
+
+	repo := s.GetRepo()
+	if repo == nil {
+		return "", fmt.Errorf("fatal: not a git repository (or any of the parent directories): .git")
+	}
+
+	includes, excludes, err := c.resolveRange(repo, opts.Revisions)
+	if err != nil {
+		return "", err
+	}
+	commits, err := reachableCommits(repo, includes, excludes)
+	if err != nil {
+		return "", err
+	}
+
+	// Group subjects by person, in walk order (newest first)
+	groups := map[string][]string{}
+	for _, commit := range commits {
+		if len(opts.Paths) > 0 {
+			changes, err := commitChanges(commit)
+			if err != nil {
+				return "", err
+			}
+			touched := false
+			for _, change := range changes {
+				touched = touched || pathMatches(change.To, opts.Paths) || change.From != "" && pathMatches(change.From, opts.Paths)
+			}
+			if !touched {
+				continue
+			}
+		}
+		sig := commit.Author
+		if opts.Committer {
+			sig = commit.Committer
+		}
+		who := sig.Name
+		if opts.Email {
+			who = fmt.Sprintf("%s <%s>", sig.Name, sig.Email)
+		}
+		groups[who] = append(groups[who], commitSubject(commit))
+	}
+
+	people := make([]string, 0, len(groups))
+	for who := range groups {
+		people = append(people, who)
+	}
+	sort.Slice(people, func(i, j int) bool {
+		a, b := people[i], people[j]
+		if opts.Numbered && len(groups[a]) != len(groups[b]) {
+			return len(groups[a]) > len(groups[b])
+		}
+		return a < b
+	})
+
+	var sb strings.Builder
+	for _, who := range people {
+		subjects := groups[who]
+		if opts.Summary {
+			fmt.Fprintf(&sb, "%6d\t%s\n", len(subjects), who)
+			continue
+		}
+		fmt.Fprintf(&sb, "%s (%d):\n", who, len(subjects))
+		// Oldest first, like git
+		for i := len(subjects) - 1; i >= 0; i-- {
+			fmt.Fprintf(&sb, "      %s\n", subjects[i])
+		}
+		sb.WriteString("\n")
+	}
+	return strings.TrimRight(sb.String(), "\n"), nil
+}
+
+func (c *ShortlogCommand) parseArgs(args []string) (*ShortlogOptions, error) {
+	opts := &ShortlogOptions{}
+	cmdArgs := args[1:]
+	flags := map[byte]*bool{'s': &opts.Summary, 'n': &opts.Numbered, 'e': &opts.Email, 'c': &opts.Committer}
+
+	for i := 0; i < len(cmdArgs); i++ {
+		arg := cmdArgs[i]
+		switch arg {
+		case "-h", "--help":
+			return nil, fmt.Errorf("help requested")
+		case "-s", "--summary":
+			opts.Summary = true
+		case "-n", "--numbered":
+			opts.Numbered = true
+		case "-e", "--email":
+			opts.Email = true
+		case "-c", "--committer":
+			opts.Committer = true
+		case "--":
+			opts.Paths = append(opts.Paths, cmdArgs[i+1:]...)
+			i = len(cmdArgs)
+		default:
+			if strings.HasPrefix(arg, "--") {
+				return nil, fmt.Errorf("error: unknown option `%s'", strings.TrimLeft(arg, "-"))
+			}
+			if strings.HasPrefix(arg, "-") {
+				// Combined short flags such as -sn
+				for j := 1; j < len(arg); j++ {
+					flag, ok := flags[arg[j]]
+					if !ok {
+						return nil, fmt.Errorf("error: unknown switch `%c'", arg[j])
+					}
+					*flag = true
+				}
+				continue
+			}
+			opts.Revisions = append(opts.Revisions, arg)
+		}
+	}
+	return opts, nil
+}
+
+// resolveRange turns "A..B", "A...B", "^A" and plain revisions into the commits to
+// start from and the commits whose history is left out. HEAD is the default.
+func (c *ShortlogCommand) resolveRange(repo *gogit.Repository, revisions []string) (includes, excludes []plumbing.Hash, err error) {
+	resolve := func(rev string) (plumbing.Hash, error) {
+		if rev == "" {
+			rev = "HEAD"
+		}
+		h, err := git.ResolveRevision(repo, rev)
+		if err != nil {
+			return plumbing.ZeroHash, fmt.Errorf("fatal: ambiguous argument '%s': unknown revision or path not in the working tree.", rev)
+		}
+		return *h, nil
+	}
+
+	for _, rev := range revisions {
+		switch {
+		case strings.HasPrefix(rev, "^"):
+			h, err := resolve(rev[1:])
+			if err != nil {
+				return nil, nil, err
+			}
+			excludes = append(excludes, h)
+		case strings.Contains(rev, ".."):
+			r, _ := parseRevRange(rev)
+			fromHash, err := resolve(r.From)
+			if err != nil {
+				return nil, nil, err
+			}
+			toHash, err := resolve(r.To)
+			if err != nil {
+				return nil, nil, err
+			}
+			includes = append(includes, toHash)
+			if !r.Symmetric {
+				excludes = append(excludes, fromHash)
+				continue
+			}
+			// A...B: both sides, minus what they share
+			includes = append(includes, fromHash)
+			bases, err := mergeBases(repo, fromHash, toHash)
+			if err != nil {
+				return nil, nil, err
+			}
+			excludes = append(excludes, bases...)
+		default:
+			h, err := resolve(rev)
+			if err != nil {
+				return nil, nil, err
+			}
+			includes = append(includes, h)
+		}
+	}
+	if len(includes) == 0 {
+		h, err := resolve("HEAD")
+		if err != nil {
+			return nil, nil, fmt.Errorf("fatal: your current branch does not have any commits yet")
+		}
+		includes = append(includes, h)
+	}
+	return includes, excludes, nil
+}
+
+// reachableCommits returns the commits reachable from includes but not from
+// excludes, newest first.
+func reachableCommits(repo *gogit.Repository, includes, excludes []plumbing.Hash) ([]*object.Commit, error) {
+	excluded := map[plumbing.Hash]bool{}
+	queue := append([]plumbing.Hash(nil), excludes...)
+	for len(queue) > 0 {
+		h := queue[0]
+		queue = queue[1:]
+		if excluded[h] {
+			continue
+		}
+		excluded[h] = true
+		if commit, err := repo.CommitObject(h); err == nil {
+			queue = append(queue, commit.ParentHashes...)
+		}
+	}
+
+	var commits []*object.Commit
+	seen := map[plumbing.Hash]bool{}
+	queue = append([]plumbing.Hash(nil), includes...)
+	for len(queue) > 0 {
+		h := queue[0]
+		queue = queue[1:]
+		if seen[h] || excluded[h] {
+			continue
+		}
+		seen[h] = true
+		commit, err := repo.CommitObject(h)
+		if err != nil {
+			continue // Beyond a shallow boundary
+		}
+		commits = append(commits, commit)
+		queue = append(queue, commit.ParentHashes...)
+	}
+	sort.SliceStable(commits, func(i, j int) bool {
+		return commits[i].Committer.When.After(commits[j].Committer.When)
+	})
+	return commits, nil
+}
+
+func (c *ShortlogCommand) Help() string {
+	return `📘 GIT-SHORTLOG (1)                                     Git Manual
+
+ 💡 DESCRIPTION
+    コミット履歴を「誰が何をしたか」で人ごとにまとめて表示します。
+    リリースノートの作成や、貢献者の一覧づくりに便利です。
+
+ 📋 SYNOPSIS
+    git shortlog [-s] [-n] [-e] [-c] [<revision-range>] [[--] <path>...]
+
+ ⚙️  COMMON OPTIONS
+    -s, --summary
+        コミットの件名を省略し、人ごとのコミット数だけを表示します。
+
+    -n, --numbered
+        名前順ではなく、コミット数の多い順に並べます。
+
+    -e, --email
+        メールアドレスも表示します。
+
+    -c, --committer
+        作者（author）ではなくコミッター（committer）でまとめます。
+
+    <revision-range>
+        v1.0..main のように範囲を指定すると、その間のコミットだけを集計します。
+
+ 🛠  EXAMPLES
+    1. 貢献者をコミット数の多い順に表示
+       $ git shortlog -sn
+
+    2. v1.0 以降の変更を人ごとに表示（リリースノート用）
+       $ git shortlog v1.0..HEAD
+
+ 🔗 REFERENCE
+    Full documentation: https://git-scm.com/docs/git-shortlog
+`
+}
@@ -0,0 +1,73 @@
+package commands
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/kurobon/gitgym/backend/internal/git"
+)
+
+func TestShortlogCommand(t *testing.T) {
+	sm := git.NewSessionManager()
+	s, _ := sm.CreateSession("test-shortlog")
+	s.InitRepo("testrepo")
+	s.CurrentDir = "/testrepo"
+	repo := s.GetRepo()
+	ctx := context.Background()
+	w, _ := repo.Worktree()
+
+	when := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	commitAs := func(name, msg string) {
+		f, _ := w.Filesystem.Create(msg + ".txt")
+		f.Write([]byte(msg))
+		f.Close()
+		w.Add(msg + ".txt")
+		when = when.Add(time.Minute)
+		sig := &object.Signature{Name: name, Email: name + "@example.com", When: when}
+		if _, err := w.Commit(msg, &gogit.CommitOptions{Author: sig, Committer: sig}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	commitAs("Bob", "first")
+	git.Dispatch(ctx, s, "tag", []string{"tag", "v1"})
+	commitAs("Alice", "second")
+	commitAs("Bob", "third")
+	commitAs("Bob", "fourth")
+
+	shortlog := func(args ...string) string {
+		t.Helper()
+		out, err := git.Dispatch(ctx, s, "shortlog", append([]string{"shortlog"}, args...))
+		if err != nil {
+			t.Fatalf("git shortlog %v failed: %v", args, err)
+		}
+		return out
+	}
+
+	if out := shortlog(); out != "Alice (1):\n      second\n\nBob (3):\n      first\n      third\n      fourth" {
+		t.Errorf("shortlog = %q", out)
+	}
+	if out := shortlog("-sn"); out != "     3\tBob\n     1\tAlice" {
+		t.Errorf("shortlog -sn = %q", out)
+	}
+	if out := shortlog("-s", "-e", "v1..HEAD"); out != "     1\tAlice <Alice@example.com>\n     2\tBob <Bob@example.com>" {
+		t.Errorf("shortlog -se v1..HEAD = %q", out)
+	}
+	if out := shortlog("-s", "v1.."); out != "     1\tAlice\n     2\tBob" {
+		t.Errorf("shortlog -s v1.. = %q", out)
+	}
+
+	// A...B covers both sides of a fork, but not what they share
+	if _, err := git.Dispatch(ctx, s, "checkout", []string{"checkout", "-b", "side", "v1"}); err != nil {
+		t.Fatal(err)
+	}
+	commitAs("Carol", "fifth")
+	if out := shortlog("-s", "main...side"); out != "     1\tAlice\n     2\tBob\n     1\tCarol" {
+		t.Errorf("shortlog -s main...side = %q", out)
+	}
+	if out := shortlog("-s", "main..."); out != "     1\tAlice\n     2\tBob\n     1\tCarol" {
+		t.Errorf("shortlog -s main... = %q", out)
+	}
+}
@@ -8,7 +8,6 @@ import (
 	gogit "github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
-	"github.com/go-git/go-git/v5/utils/merkletrie"
 	"github.com/kurobon/gitgym/backend/internal/git"
 )
 
@@ -24,6 +23,7 @@ var _ git.Command = (*ShowCommand)(nil)
 type ShowOptions struct {
 	NameStatus bool
 	CommitID   string
+	NotesRef   plumbing.ReferenceName // Notes shown after the message; "" with --no-notes
 }
 
 func (c *ShowCommand) Execute(ctx context.Context, s *git.Session, args []string) (string, error) {
@@ -46,11 +46,18 @@ func (c *ShowCommand) Execute(ctx context.Context, s *git.Session, args []string
 func (c *ShowCommand) parseArgs(args []string) (*ShowOptions, error) {
 	opts := &ShowOptions{
 		CommitID: "HEAD", // Default
+		NotesRef: git.DefaultNotesRef,
 	}
 	cmdArgs := args[1:]
 	for _, arg := range cmdArgs {
 		if arg == "--name-status" {
 			opts.NameStatus = true
+		} else if arg == "--no-notes" {
+			opts.NotesRef = ""
+		} else if arg == "--notes" {
+			opts.NotesRef = git.DefaultNotesRef
+		} else if strings.HasPrefix(arg, "--notes=") {
+			opts.NotesRef = notesRefName(strings.TrimPrefix(arg, "--notes="))
 		} else if strings.HasPrefix(arg, "--format=") {
 			// ignore
 		} else if arg == "-h" || arg == "--help" {
@@ -112,6 +119,15 @@ func (c *ShowCommand) executeShow(_ *git.Session, repo *gogit.Repository, opts *
 		var sb strings.Builder
 		sb.WriteString(commit.String())
 		sb.WriteString("\n")
+		if opts.NotesRef != "" {
+			notes, err := git.ReadNotes(repo, opts.NotesRef)
+			if err != nil {
+				return "", err
+			}
+			if note := commitNote(repo, notes, opts.NotesRef, commit.Hash); note != "" {
+				sb.WriteString(note + "\n")
+			}
+		}
 
 		// Calculate Diff with Parent for Patch
 		var parentTree *object.Tree
@@ -157,62 +173,15 @@ func (c *ShowCommand) executeShow(_ *git.Session, repo *gogit.Repository, opts *
 		return sb.String(), nil
 	}
 
-	// Calculate Diff with Parent
-	var parentTree *object.Tree
-	if commit.NumParents() > 0 {
-		parent, err := commit.Parent(0)
-		if err != nil {
-			return "", err
-		}
-		parentTree, err = parent.Tree()
-		if err != nil {
-			return "", err
-		}
-	}
-
-	currentTree, err := commit.Tree()
-	if err != nil {
-		return "", err
-	}
-
-	var changes object.Changes
-	if parentTree != nil {
-		changes, err = parentTree.Diff(currentTree)
-	} else {
-		// Root diff
-		return listRootChanges(currentTree)
-	}
-
+	// Name-status against the first parent, with renames as R<score>
+	changes, err := commitChanges(commit)
 	if err != nil {
 		return "", err
 	}
 
 	var sb strings.Builder
 	for _, change := range changes {
-		action, err := change.Action()
-		if err != nil {
-			continue
-		}
-
-		var status string
-		var path string
-
-		switch action {
-		case merkletrie.Insert:
-			status = "A"
-			path = change.To.Name
-		case merkletrie.Delete:
-			status = "D"
-			path = change.From.Name
-		case merkletrie.Modify:
-			status = "M"
-			path = change.To.Name
-		default:
-			status = "M"
-			path = change.To.Name
-		}
-
-		sb.WriteString(fmt.Sprintf("%s\t%s\n", status, path))
+		sb.WriteString(change.String() + "\n")
 	}
 
 	return sb.String(), nil
@@ -226,11 +195,15 @@ func (c *ShowCommand) Help() string {
     ・コミットの内容を詳細に確認する
 
  📋 SYNOPSIS
-    git show [<commit>] [--name-status]
+    git show [<commit>] [--name-status] [--notes[=<ref>] | --no-notes]
 
  ⚙️  COMMON OPTIONS
     --name-status
         変更内容の差分テキストではなく、変更されたファイル名と状態（A/M/D）のみを表示します。
+        ファイル名の変更は R100 old.txt new.txt のように表示されます（数字は内容の類似度 %）。
+
+    --notes[=<ref>] / --no-notes
+        git notes で付けたノートを表示する / しないを切り替えます（既定では表示）。
 
  🛠  EXAMPLES
     1. 最新のコミットを表示
@@ -243,12 +216,3 @@ func (c *ShowCommand) Help() string {
     Full documentation: https://git-scm.com/docs/git-show
 `
 }
-
-func listRootChanges(tree *object.Tree) (string, error) {
-	var sb strings.Builder
-	err := tree.Files().ForEach(func(f *object.File) error {
-		sb.WriteString(fmt.Sprintf("A\t%s\n", f.Name))
-		return nil
-	})
-	return sb.String(), err
-}
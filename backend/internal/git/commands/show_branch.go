@@ -0,0 +1,384 @@
+package commands
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/kurobon/gitgym/backend/internal/git"
+)
+
+func init() {
+	git.RegisterCommand("show-branch", func() git.Command { return &ShowBranchCommand{} })
+}
+
+type ShowBranchCommand struct{}
+
+// Ensure ShowBranchCommand implements git.Command
+var _ git.Command = (*ShowBranchCommand)(nil)
+
+// showBranchMax is git's limit on the number of columns.
+const showBranchMax = 26
+
+type ShowBranchOptions struct {
+	All     bool // -a: local and remote-tracking branches
+	Remotes bool // -r: remote-tracking branches
+	List    bool // --list: only the branch lines
+	More    int  // --more=<n>: commits to show past the common ancestor
+	Refs    []string
+}
+
+// showBranchTip is one column of the matrix.
+type showBranchTip struct {
+	name    string
+	commit  *object.Commit
+	current bool
+}
+
+func (c *ShowBranchCommand) Execute(ctx context.Context, s *git.Session, args []string) (string, error) {
+	s.Lock()
+	defer s.Unlock()
+
+	opts, err := c.parseArgs(args)
+	if err != nil {
+		if err.Error() == "help requested" {
+			return c.Help(), nil
+		}
+		return "", err
+	}
+
+	repo := s.GetRepo()
+	if repo == nil {
+		return "", fmt.Errorf("fatal: not a git repository (or any of the parent directories): .git")
+	}
+
+	tips, err := c.collectTips(repo, opts)
+	if err != nil {
+		return "", err
+	}
+	if len(tips) == 0 {
+		return "", nil
+	}
+	if len(tips) > showBranchMax {
+		return "", fmt.Errorf("fatal: cannot handle more than %d refs", showBranchMax)
+	}
+
+	// A single branch is just its name and subject
+	if len(tips) == 1 {
+		return fmt.Sprintf("[%s] %s", tips[0].name, commitSubject(tips[0].commit)), nil
+	}
+
+	var lines []string
+	for i, t := range tips {
+		marker := "!"
+		if t.current {
+			marker = "*"
+		}
+		lines = append(lines, fmt.Sprintf("%s%s [%s] %s", strings.Repeat(" ", i), marker, t.name, commitSubject(t.commit)))
+	}
+	if opts.List {
+		return strings.Join(lines, "\n"), nil
+	}
+	lines = append(lines, strings.Repeat("-", len(tips)))
+
+	rows, err := c.matrix(repo, tips, opts.More)
+	if err != nil {
+		return "", err
+	}
+	return strings.Join(append(lines, rows...), "\n"), nil
+}
+
+func (c *ShowBranchCommand) parseArgs(args []string) (*ShowBranchOptions, error) {
+	opts := &ShowBranchOptions{}
+	for _, arg := range args[1:] {
+		name, value, hasValue := strings.Cut(arg, "=")
+		switch {
+		case arg == "-h" || arg == "--help":
+			return nil, fmt.Errorf("help requested")
+		case arg == "-a" || arg == "--all":
+			opts.All = true
+		case arg == "-r" || arg == "--remotes":
+			opts.Remotes = true
+		case arg == "--list":
+			opts.List = true
+		case name == "--more":
+			opts.More = 1
+			if hasValue {
+				if _, err := fmt.Sscanf(value, "%d", &opts.More); err != nil {
+					return nil, fmt.Errorf("error: option `more' expects a numerical value")
+				}
+			}
+		case strings.HasPrefix(arg, "-"):
+			return nil, fmt.Errorf("error: unknown option `%s'", strings.TrimLeft(arg, "-"))
+		default:
+			opts.Refs = append(opts.Refs, arg)
+		}
+	}
+	return opts, nil
+}
+
+// collectTips resolves the named refs, or lists the branches in ref order.
+func (c *ShowBranchCommand) collectTips(repo *gogit.Repository, opts *ShowBranchOptions) ([]*showBranchTip, error) {
+	var current plumbing.ReferenceName
+	if head, err := repo.Storer.Reference(plumbing.HEAD); err == nil && head.Type() == plumbing.SymbolicReference {
+		current = head.Target()
+	}
+
+	var tips []*showBranchTip
+	add := func(name string, refName plumbing.ReferenceName, hash plumbing.Hash) error {
+		commit, err := repo.CommitObject(hash)
+		if err != nil {
+			return fmt.Errorf("fatal: bad sha1 reference %s", name)
+		}
+		tips = append(tips, &showBranchTip{name: name, commit: commit, current: refName != "" && refName == current})
+		return nil
+	}
+
+	if len(opts.Refs) > 0 {
+		for _, name := range opts.Refs {
+			hash, err := git.ResolveRevision(repo, name)
+			if err != nil {
+				return nil, fmt.Errorf("fatal: bad sha1 reference %s", name)
+			}
+			refName := plumbing.NewBranchReferenceName(name)
+			if name == "HEAD" {
+				refName = current
+			}
+			if err := add(name, refName, *hash); err != nil {
+				return nil, err
+			}
+		}
+		return tips, nil
+	}
+
+	refs, err := repo.References()
+	if err != nil {
+		return nil, err
+	}
+	var names []plumbing.ReferenceName
+	err = refs.ForEach(func(r *plumbing.Reference) error {
+		switch {
+		case r.Type() != plumbing.HashReference:
+		case r.Name().IsBranch() && (!opts.Remotes || opts.All):
+			names = append(names, r.Name())
+		case r.Name().IsRemote() && (opts.Remotes || opts.All):
+			names = append(names, r.Name())
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Slice(names, func(i, j int) bool { return names[i] < names[j] })
+	for _, name := range names {
+		ref, err := repo.Reference(name, true)
+		if err != nil {
+			continue
+		}
+		if err := add(name.Short(), name, ref.Hash()); err != nil {
+			return nil, err
+		}
+	}
+	return tips, nil
+}
+
+// matrix renders one row per commit, newest first: a column per tip showing
+// whether the commit is in its history ('+', '*' for the current branch, '-'
+// for merges), then the commit's name relative to a tip. It stops once
+// commits common to all tips are reached, plus --more rows.
+func (c *ShowBranchCommand) matrix(repo *gogit.Repository, tips []*showBranchTip, more int) ([]string, error) {
+	// Which tips reach each commit
+	reach := map[plumbing.Hash][]bool{}
+	commits := map[plumbing.Hash]*object.Commit{}
+	for i, t := range tips {
+		queue := []*object.Commit{t.commit}
+		for len(queue) > 0 {
+			commit := queue[0]
+			queue = queue[1:]
+			mask, ok := reach[commit.Hash]
+			if !ok {
+				mask = make([]bool, len(tips))
+				reach[commit.Hash] = mask
+				commits[commit.Hash] = commit
+			}
+			if mask[i] {
+				continue
+			}
+			mask[i] = true
+			for _, p := range commit.ParentHashes {
+				if parent, err := repo.CommitObject(p); err == nil {
+					queue = append(queue, parent)
+				}
+			}
+		}
+	}
+
+	order := make([]*object.Commit, 0, len(commits))
+	for _, commit := range commits {
+		order = append(order, commit)
+	}
+	sortTopological(order)
+	names := showBranchNames(repo, tips)
+
+	var rows []string
+	remaining := -1
+	for _, commit := range order {
+		mask := reach[commit.Hash]
+		var sb strings.Builder
+		common := true
+		for i, t := range tips {
+			switch {
+			case !mask[i]:
+				sb.WriteByte(' ')
+				common = false
+			case commit.NumParents() > 1:
+				sb.WriteByte('-')
+			case t.current:
+				sb.WriteByte('*')
+			default:
+				sb.WriteByte('+')
+			}
+		}
+		fmt.Fprintf(&sb, " [%s] %s", names[commit.Hash], commitSubject(commit))
+		rows = append(rows, sb.String())
+
+		if remaining > 0 {
+			remaining--
+		}
+		if common && remaining < 0 {
+			remaining = more
+		}
+		if remaining == 0 {
+			break
+		}
+	}
+	return rows, nil
+}
+
+// sortTopological orders commits newest first, never listing a parent
+// before its children.
+func sortTopological(commits []*object.Commit) {
+	sort.Slice(commits, func(i, j int) bool {
+		a, b := commits[i], commits[j]
+		if !a.Committer.When.Equal(b.Committer.When) {
+			return a.Committer.When.After(b.Committer.When)
+		}
+		return a.Hash.String() < b.Hash.String()
+	})
+	index := map[plumbing.Hash]int{}
+	for i, commit := range commits {
+		index[commit.Hash] = i
+	}
+	// Children still waiting to be listed, per commit
+	pending := map[plumbing.Hash]int{}
+	for _, commit := range commits {
+		for _, p := range commit.ParentHashes {
+			if _, ok := index[p]; ok {
+				pending[p]++
+			}
+		}
+	}
+	var sorted []*object.Commit
+	done := map[plumbing.Hash]bool{}
+	for len(sorted) < len(commits) {
+		for _, commit := range commits {
+			if done[commit.Hash] || pending[commit.Hash] > 0 {
+				continue
+			}
+			done[commit.Hash] = true
+			sorted = append(sorted, commit)
+			for _, p := range commit.ParentHashes {
+				pending[p]--
+			}
+			break
+		}
+	}
+	copy(commits, sorted)
+}
+
+// showBranchNames names commits after the first tip that reaches them:
+// "main", "main^", "main~2", "main^2" for a second parent, and so on.
+func showBranchNames(repo *gogit.Repository, tips []*showBranchTip) map[plumbing.Hash]string {
+	names := map[plumbing.Hash]string{}
+	type step struct {
+		hash       plumbing.Hash
+		base       string
+		generation int
+	}
+	name := func(s step) string {
+		switch s.generation {
+		case 0:
+			return s.base
+		case 1:
+			return s.base + "^"
+		}
+		return fmt.Sprintf("%s~%d", s.base, s.generation)
+	}
+	for _, t := range tips {
+		queue := []step{{t.commit.Hash, t.name, 0}}
+		for len(queue) > 0 {
+			s := queue[0]
+			queue = queue[1:]
+			if _, ok := names[s.hash]; ok {
+				continue
+			}
+			names[s.hash] = name(s)
+			commit, err := repo.CommitObject(s.hash)
+			if err != nil {
+				continue
+			}
+			for i, p := range commit.ParentHashes {
+				if i == 0 {
+					queue = append(queue, step{p, s.base, s.generation + 1})
+				} else {
+					queue = append(queue, step{p, fmt.Sprintf("%s^%d", name(s), i+1), 0})
+				}
+			}
+		}
+	}
+	return names
+}
+
+func (c *ShowBranchCommand) Help() string {
+	return `📘 GIT-SHOW-BRANCH (1)                                  Git Manual
+
+ 💡 DESCRIPTION
+    複数のブランチと、それぞれに含まれるコミットを表（マトリクス）で表示します。
+    どのコミットがどのブランチに入っているかが一目でわかります。
+
+    上部にブランチの一覧、--- の下にコミットが新しい順に並びます。
+    各列がブランチに対応し、そのブランチに含まれるコミットには
+    + （現在のブランチは *、マージコミットは -）が付きます。
+    全ブランチ共通のコミットまで表示すると止まります。
+
+ 📋 SYNOPSIS
+    git show-branch [-a | -r] [--list] [--more=<n>] [<rev>...]
+
+ ⚙️  COMMON OPTIONS
+    -a, --all / -r, --remotes
+        リモート追跡ブランチも含めて / だけを表示します。
+
+    --list
+        ブランチの一覧だけを表示します。
+
+    --more=<n>
+        共通のコミットからさらに <n> 個さかのぼって表示します。
+
+ 🛠  EXAMPLES
+    1. main と feature の違いを見る
+       $ git show-branch main feature
+       * [main] Fix typo
+        ! [feature] Add login
+       --
+       *  [main] Fix typo
+        + [feature] Add login
+       *+ [main^] Initial commit
+
+ 🔗 REFERENCE
+    Full documentation: https://git-scm.com/docs/git-show-branch
+`
+}
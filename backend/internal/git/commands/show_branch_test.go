@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/kurobon/gitgym/backend/internal/git"
+)
+
+func TestShowBranchCommand(t *testing.T) {
+	sm := git.NewSessionManager()
+	s, _ := sm.CreateSession("test-show-branch")
+	s.InitRepo("testrepo")
+	s.CurrentDir = "/testrepo"
+	repo := s.GetRepo()
+	ctx := context.Background()
+	w, _ := repo.Worktree()
+
+	when := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	commit := func(msg string) plumbing.Hash {
+		f, _ := w.Filesystem.Create(msg + ".txt")
+		f.Write([]byte(msg))
+		f.Close()
+		w.Add(msg + ".txt")
+		when = when.Add(time.Minute)
+		h, err := w.Commit(msg, &gogit.CommitOptions{Author: &object.Signature{Name: "Me", When: when}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return h
+	}
+
+	commit("base")
+	base := commit("shared")
+	commit("main-work")
+	repo.Storer.SetReference(plumbing.NewHashReference("refs/heads/feature", base))
+	w.Checkout(&gogit.CheckoutOptions{Branch: "refs/heads/feature"})
+	commit("feature-one")
+	commit("feature-two")
+	w.Checkout(&gogit.CheckoutOptions{Branch: "refs/heads/main"})
+
+	out, err := git.Dispatch(ctx, s, "show-branch", []string{"show-branch"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `! [feature] feature-two
+ * [main] main-work
+--
++  [feature] feature-two
++  [feature^] feature-one
+ * [main] main-work
++* [feature~2] shared`
+	if out != want {
+		t.Errorf("show-branch =\n%s\nwant\n%s", out, want)
+	}
+
+	out, _ = git.Dispatch(ctx, s, "show-branch", []string{"show-branch", "--more=1", "main"})
+	if out != "[main] main-work" {
+		t.Errorf("single branch = %q", out)
+	}
+	out, _ = git.Dispatch(ctx, s, "show-branch", []string{"show-branch", "--list"})
+	if out != "! [feature] feature-two\n * [main] main-work" {
+		t.Errorf("--list = %q", out)
+	}
+}
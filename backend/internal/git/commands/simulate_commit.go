@@ -3,11 +3,10 @@ package commands
 import (
 	"context"
 	"fmt"
-	"os"
+	"strconv"
+	"strings"
 	"time"
 
-	gogit "github.com/go-git/go-git/v5"
-	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/kurobon/gitgym/backend/internal/git"
 )
 
@@ -20,106 +19,148 @@ type SimulateCommitCommand struct{}
 // Ensure SimulateCommitCommand implements git.Command
 var _ git.Command = (*SimulateCommitCommand)(nil)
 
-func (c *SimulateCommitCommand) Execute(ctx context.Context, s *git.Session, args []string) (string, error) {
-	// Usage: simulate-commit <remote-name> <message> [<author-name> <author-email>]
-	if len(args) < 3 {
-		return "", fmt.Errorf("usage: simulate-commit <remote-name> <message> [<author-name> <author-email>]")
-	}
+const simulateCommitUsage = "usage: simulate-commit <remote-name> <message> [<author-name> <author-email>] [-b <branch>] [--parent <rev>]... [--merge <rev>]... [--write <path>=<content>]... [--append <path>=<content>]... [--delete <path>]... [--rename <old>=<new>]... [--author \"Name <email>\"] [--date <date>] [--tag <name>] [--tag-message <msg>]"
 
-	remoteName := args[1]
-	message := args[2]
-	authorName := "Simulated User"
-	authorEmail := "simulated@example.com"
-
-	if len(args) >= 5 {
-		authorName = args[3]
-		authorEmail = args[4]
+func (c *SimulateCommitCommand) Execute(ctx context.Context, s *git.Session, args []string) (string, error) {
+	spec, remoteName, err := parseSimulateCommitArgs(args)
+	if err != nil {
+		return "", err
 	}
 
-	sm := s.Manager
-	sm.Lock()
-	defer sm.Unlock()
-
-	// repo var not needed if using temp clone
-	_, ok := sm.SharedRemotes[remoteName]
+	repo, ok := s.Manager.GetSharedRemote(remoteName)
 	if !ok {
 		return "", fmt.Errorf("remote %s not found", remoteName)
 	}
-
-	// Handle Bare Repos by cloning to temp, committing, and pushing back
-	// This is heavier but robust for simulation on bare shared repos.
-
-	// 1. Create Temp Dir
-	tempDir, err := os.MkdirTemp("", "gitgym-sim-*")
-	if err != nil {
-		return "", fmt.Errorf("failed to create temp dir: %w", err)
-	}
-	defer os.RemoveAll(tempDir) // Clean up
-
-	// 2. Clone (Local)
-	// We clone from the SharedRemote path
-	remotePath, ok := sm.SharedRemotePaths[remoteName]
-	if !ok {
-		// Fallback to iterating if key mismatch, but should match
-		return "", fmt.Errorf("remote path for %s not found", remoteName)
+	if spec.Date.IsZero() {
+		spec.Date = s.Now()
 	}
 
-	tempRepo, err := gogit.PlainClone(tempDir, false, &gogit.CloneOptions{
-		URL: remotePath,
-	})
+	sm := s.Manager
+	sm.Lock()
+	hash, branch, err := git.WriteRemoteCommit(sm, repo, spec)
+	sm.Unlock()
 	if err != nil {
-		return "", fmt.Errorf("failed to clone for simulation: %w", err)
+		return "", err
 	}
 
-	w, err := tempRepo.Worktree()
-	if err != nil {
-		return "", fmt.Errorf("temp worktree error: %v", err)
-	}
+	// CI checks the teammate's commit, bots react to it, then open PRs pick it up
+	sm.RunStatusChecks(repo, hash)
+	sm.Bots.NotifyPush(repo, branch)
+	git.RefreshPullRequests(sm, repo)
 
-	// 3. Make Change
-	filename := fmt.Sprintf("simulated_%d.txt", time.Now().Unix())
-	file, err := w.Filesystem.Create(filename)
-	if err != nil {
-		return "", err
+	out := fmt.Sprintf("Simulated commit created: %s", hash.String())
+	if spec.Branch != "" {
+		out += fmt.Sprintf(" on %s", branch.Short())
 	}
-	if _, writeErr := file.Write([]byte("Simulated content")); writeErr != nil {
-		return "", writeErr
+	if spec.Tag != "" {
+		out += fmt.Sprintf(" (tag %s)", spec.Tag)
 	}
-	_ = file.Close()
+	return out, nil
+}
 
-	if _, addErr := w.Add(filename); addErr != nil {
-		return "", addErr
+func parseSimulateCommitArgs(args []string) (*git.RemoteCommit, string, error) {
+	spec := &git.RemoteCommit{}
+
+	var positional []string
+	for i := 1; i < len(args); i++ {
+		arg := args[i]
+		name, value, inline := strings.Cut(arg, "=")
+		if !strings.HasPrefix(arg, "-") {
+			positional = append(positional, arg)
+			continue
+		}
+		if !inline {
+			name = arg
+			switch name {
+			case "-b", "--branch", "--parent", "--merge", "--write", "--append", "--delete", "--rename", "--author", "--date", "--tag", "--tag-message":
+				if i+1 >= len(args) {
+					return nil, "", fmt.Errorf("option '%s' requires a value", name)
+				}
+				i++
+				value = args[i]
+			}
+		}
+
+		switch name {
+		case "-b", "--branch":
+			spec.Branch = value
+		case "--parent":
+			spec.Parents = append(spec.Parents, value)
+		case "--merge":
+			spec.Merge = append(spec.Merge, value)
+		case "--write", "--append":
+			path, content, ok := strings.Cut(value, "=")
+			if !ok {
+				return nil, "", fmt.Errorf("option '%s' expects <path>=<content>", name)
+			}
+			op := git.FileWrite
+			if name == "--append" {
+				op = git.FileAppend
+			}
+			spec.Changes = append(spec.Changes, git.FileChange{Op: op, Path: path, Content: unescapeContent(content)})
+		case "--delete":
+			spec.Changes = append(spec.Changes, git.FileChange{Op: git.FileDelete, Path: value})
+		case "--rename":
+			from, to, ok := strings.Cut(value, "=")
+			if !ok {
+				return nil, "", fmt.Errorf("option '--rename' expects <old>=<new>")
+			}
+			spec.Changes = append(spec.Changes, git.FileChange{Op: git.FileRename, Path: from, To: to})
+		case "--author":
+			sig, err := parseIdentity(value)
+			if err != nil {
+				return nil, "", err
+			}
+			spec.Author, spec.Email = sig.Name, sig.Email
+		case "--date":
+			when, err := parseSimulatedDate(value)
+			if err != nil {
+				return nil, "", err
+			}
+			spec.Date = when
+		case "--tag":
+			spec.Tag = value
+		case "--tag-message":
+			spec.TagMessage = value
+		default:
+			return nil, "", fmt.Errorf("unknown option: %s", arg)
+		}
 	}
 
-	if authorName == "" {
-		authorName = "Simulated User"
+	if len(positional) < 2 {
+		return nil, "", fmt.Errorf("%s", simulateCommitUsage)
 	}
-	if authorEmail == "" {
-		authorEmail = "simulated@example.com"
+	spec.Message = positional[1]
+	if len(positional) >= 4 {
+		spec.Author, spec.Email = positional[2], positional[3]
 	}
+	return spec, positional[0], nil
+}
 
-	hash, err := w.Commit(message, &gogit.CommitOptions{
-		Author: &object.Signature{
-			Name:  authorName,
-			Email: authorEmail,
-			When:  time.Now(),
-		},
-	})
-	if err != nil {
-		return "", err
-	}
+// unescapeContent turns "\n" and "\t" typed on the command line into real
+// newlines and tabs so multi-line files can be written from one argument.
+func unescapeContent(s string) string {
+	return strings.NewReplacer(`\n`, "\n", `\t`, "\t").Replace(s)
+}
 
-	// 4. Push back to Shared Remote
-	err = tempRepo.Push(&gogit.PushOptions{
-		RemoteName: "origin",
-	})
-	if err != nil {
-		return "", fmt.Errorf("failed to push simulation: %w", err)
+// parseSimulatedDate accepts RFC 3339, "YYYY-MM-DD[ HH:MM[:SS]]" (UTC) or
+// "@<unix-seconds>", like git's --date.
+func parseSimulatedDate(s string) (time.Time, error) {
+	if secs, ok := strings.CutPrefix(s, "@"); ok {
+		n, err := strconv.ParseInt(secs, 10, 64)
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid date '%s'", s)
+		}
+		return time.Unix(n, 0).UTC(), nil
 	}
-
-	return fmt.Sprintf("Simulated commit created: %s", hash.String()), nil
+	for _, layout := range []string{time.RFC3339, "2006-01-02T15:04:05", "2006-01-02 15:04:05", "2006-01-02 15:04", "2006-01-02"} {
+		if t, err := time.Parse(layout, s); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid date '%s'", s)
 }
 
 func (c *SimulateCommitCommand) Help() string {
-	return "usage: simulate-commit <remote-name> <message> [<author-name> <author-email>]"
+	return simulateCommitUsage
 }
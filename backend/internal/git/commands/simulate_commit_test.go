@@ -2,12 +2,17 @@ package commands
 
 import (
 	"context"
+	"errors"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/go-git/go-billy/v5/memfs"
 	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
 	"github.com/kurobon/gitgym/backend/internal/git"
 )
 
@@ -60,3 +65,156 @@ func TestSimulateCommitCommand(t *testing.T) {
 		t.Errorf("Expected message 'Test Commit', got '%s'", commit.Message)
 	}
 }
+
+func TestSimulateCommitCommand_Options(t *testing.T) {
+	ctx := context.Background()
+	sm := git.NewSessionManager()
+	repo, err := gogit.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	wt, _ := repo.Worktree()
+	for name, content := range map[string]string{"a.txt": "base\n", "b.txt": "keep\n"} {
+		f, _ := wt.Filesystem.Create(name)
+		_, _ = f.Write([]byte(content))
+		_ = f.Close()
+		_, _ = wt.Add(name)
+	}
+	initial, err := wt.Commit("Initial", &gogit.CommitOptions{Author: &object.Signature{Name: "Me", Email: "me@me.com", When: time.Now()}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	sm.SharedRemotes["team"] = repo
+	session, _ := sm.CreateSession("test-session")
+
+	run := func(args ...string) (string, error) {
+		return (&SimulateCommitCommand{}).Execute(ctx, session, append([]string{"simulate-commit", "team"}, args...))
+	}
+	tip := func(branch string) *object.Commit {
+		t.Helper()
+		ref, err := repo.Reference(plumbing.NewBranchReferenceName(branch), true)
+		if err != nil {
+			t.Fatalf("branch %s: %v", branch, err)
+		}
+		c, err := repo.CommitObject(ref.Hash())
+		if err != nil {
+			t.Fatal(err)
+		}
+		return c
+	}
+	contents := func(c *object.Commit, path string) string {
+		t.Helper()
+		f, err := c.File(path)
+		if err != nil {
+			return "<missing>"
+		}
+		s, _ := f.Contents()
+		return s
+	}
+
+	t.Run("New branch with edits, author and date", func(t *testing.T) {
+		out, err := run("Rework files", "-b", "feature",
+			"--write", "src/new.txt=line 1\\nline 2", "--append", "a.txt=more", "--rename", "b.txt=docs/b.txt",
+			"--author", "Alice <alice@example.com>", "--date", "2026-01-02 03:04:05")
+		if err != nil {
+			t.Fatalf("simulate-commit failed: %v", err)
+		}
+		if !strings.Contains(out, "on feature") {
+			t.Errorf("unexpected output: %s", out)
+		}
+
+		c := tip("feature")
+		if len(c.ParentHashes) != 1 || c.ParentHashes[0] != initial {
+			t.Errorf("new branch should start at HEAD, parents: %v", c.ParentHashes)
+		}
+		if c.Author.Name != "Alice" || c.Author.Email != "alice@example.com" {
+			t.Errorf("unexpected author: %v", c.Author)
+		}
+		if want := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC); !c.Author.When.Equal(want) {
+			t.Errorf("expected date %v, got %v", want, c.Author.When)
+		}
+		for path, want := range map[string]string{"src/new.txt": "line 1\nline 2", "a.txt": "base\nmore", "docs/b.txt": "keep\n", "b.txt": "<missing>"} {
+			if got := contents(c, path); got != want {
+				t.Errorf("%s: expected %q, got %q", path, want, got)
+			}
+		}
+		if tip("master").Hash != initial {
+			t.Error("master should not move")
+		}
+	})
+
+	t.Run("Conflicting merge needs a resolution", func(t *testing.T) {
+		if _, err := run("Edit a on master", "--write", "a.txt=master version"); err != nil {
+			t.Fatal(err)
+		}
+		before := tip("master").Hash
+
+		_, err := run("Merge feature", "--merge", "feature")
+		if !errors.Is(err, git.ErrConflict) || !strings.Contains(err.Error(), "a.txt") {
+			t.Fatalf("expected conflict on a.txt, got %v", err)
+		}
+		if tip("master").Hash != before {
+			t.Error("failed merge must not move the branch")
+		}
+
+		if _, err := run("Merge feature", "--merge", "feature", "--write", "a.txt=resolved", "--tag", "v1.0", "--tag-message", "First release"); err != nil {
+			t.Fatalf("resolved merge failed: %v", err)
+		}
+		c := tip("master")
+		if len(c.ParentHashes) != 2 || c.ParentHashes[0] != before || c.ParentHashes[1] != tip("feature").Hash {
+			t.Errorf("unexpected merge parents: %v", c.ParentHashes)
+		}
+		if got := contents(c, "a.txt"); got != "resolved" {
+			t.Errorf("expected resolved a.txt, got %q", got)
+		}
+		if got := contents(c, "src/new.txt"); got != "line 1\nline 2" {
+			t.Errorf("merge should bring in feature files, got %q", got)
+		}
+
+		ref, err := repo.Reference(plumbing.NewTagReferenceName("v1.0"), false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		tag, err := repo.TagObject(ref.Hash())
+		if err != nil {
+			t.Fatalf("expected annotated tag: %v", err)
+		}
+		if tag.Target != c.Hash || tag.Message != "First release" {
+			t.Errorf("unexpected tag: %+v", tag)
+		}
+	})
+
+	t.Run("Explicit parents and deletes", func(t *testing.T) {
+		if _, err := run("Hotfix from initial", "-b", "hotfix", "--parent", initial.String(), "--delete", "a.txt"); err != nil {
+			t.Fatal(err)
+		}
+		c := tip("hotfix")
+		if c.ParentHashes[0] != initial || contents(c, "a.txt") != "<missing>" {
+			t.Errorf("unexpected hotfix commit: parents %v", c.ParentHashes)
+		}
+	})
+
+	t.Run("Invalid requests", func(t *testing.T) {
+		for _, args := range [][]string{
+			{"x", "--delete", "missing.txt"},
+			{"x", "--write", "../escape=x"},
+			{"x", "--parent", "no-such-rev"},
+			{"x", "--date", "yesterday"},
+			{"x", "--tag", "v1.0"},
+		} {
+			if _, err := run(args...); err == nil {
+				t.Errorf("expected error for %v", args)
+			}
+		}
+	})
+
+	t.Run("Protected branches reject simulated pushes", func(t *testing.T) {
+		if err := sm.SetBranchProtection("team", []*git.BranchProtection{{Pattern: "master", RequirePullRequest: true}}); err != nil {
+			t.Fatal(err)
+		}
+		var pe *git.ProtectionError
+		if _, err := run("Direct push"); !errors.As(err, &pe) {
+			t.Errorf("expected protection error, got %v", err)
+		}
+	})
+}
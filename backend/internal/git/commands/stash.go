@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"strings"
-	"time"
 
 	gogit "github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
@@ -39,7 +38,7 @@ func (c *StashCommand) Execute(ctx context.Context, s *git.Session, args []strin
 
 	switch op {
 	case "push", "save":
-		return c.executePush(repo, args)
+		return c.executePush(s, repo, args)
 	case "pop":
 		return c.executePop(repo)
 	case "list":
@@ -51,11 +50,11 @@ func (c *StashCommand) Execute(ctx context.Context, s *git.Session, args []strin
 	default:
 		// If arg is not a known subcommand, it might be 'git stash -m "msg"' which implies push
 		// For simplicity, treat unknown as push options or error
-		return c.executePush(repo, args)
+		return c.executePush(s, repo, args)
 	}
 }
 
-func (c *StashCommand) executePush(repo *gogit.Repository, _ []string) (string, error) {
+func (c *StashCommand) executePush(s *git.Session, repo *gogit.Repository, _ []string) (string, error) {
 	w, err := repo.Worktree()
 	if err != nil {
 		return "", err
@@ -94,7 +93,8 @@ func (c *StashCommand) executePush(repo *gogit.Repository, _ []string) (string,
 		return "", fmt.Errorf("failed to add files for stash: %v", err)
 	}
 
-	stashMsg := "WIP on " + headRef.Name().Short() + ": " + time.Now().Format("15:04:05")
+	when := s.Now()
+	stashMsg := "WIP on " + headRef.Name().Short() + ": " + when.Format("15:04:05")
 	// If User provided a message (e.g. git stash push -m "msg"), parse it?
 	// Skipping detailed arg parsing for now.
 
@@ -103,7 +103,7 @@ func (c *StashCommand) executePush(repo *gogit.Repository, _ []string) (string,
 		Author: &object.Signature{
 			Name:  "GitGym Stash",
 			Email: "stash@gitgym.local",
-			When:  time.Now(),
+			When:  when,
 		},
 	})
 	if err != nil {
@@ -76,15 +76,26 @@ func (c *StatusCommand) executeStatus(_ *git.Session, repo *gogit.Repository, op
 	if err != nil {
 		return "", err
 	}
+	markIntentToAdd(repo, status)
+	renames := stagedRenames(repo, status)
 
 	if opts.Short {
-		return c.formatShortInfo(repo, status, opts.Branch)
+		return c.formatShortInfo(repo, status, renames, opts.Branch)
 	}
 
-	return c.formatLongInfo(repo, status)
+	return c.formatLongInfo(repo, status, renames)
 }
 
-func (c *StatusCommand) formatLongInfo(repo *gogit.Repository, status gogit.Status) (string, error) {
+// renameSources returns the old paths of renames, which are listed with the new path.
+func renameSources(renames map[string]renamePair) map[string]bool {
+	sources := map[string]bool{}
+	for _, r := range renames {
+		sources[r.From] = true
+	}
+	return sources
+}
+
+func (c *StatusCommand) formatLongInfo(repo *gogit.Repository, status gogit.Status, renames map[string]renamePair) (string, error) {
 	var sb strings.Builder
 
 	// 1. Branch Info
@@ -107,6 +118,7 @@ func (c *StatusCommand) formatLongInfo(repo *gogit.Repository, status gogit.Stat
 		paths = append(paths, path)
 	}
 	sort.Strings(paths)
+	sources := renameSources(renames)
 
 	for _, path := range paths {
 		s := status[path]
@@ -119,7 +131,13 @@ func (c *StatusCommand) formatLongInfo(repo *gogit.Repository, status gogit.Stat
 
 		// Staged changes (Staging has something other than Unmodified/Untracked)
 		// Note: A file can be both queued for commit AND modified (staged + unstaged changes)
-		if s.Staging != gogit.Unmodified && s.Staging != gogit.Untracked {
+		r, renamed := renames[path]
+		switch {
+		case renamed:
+			staged = append(staged, fmt.Sprintf("%-12s%s -> %s", mapStatus(gogit.Renamed), r.From, path))
+		case sources[path] && s.Staging == gogit.Deleted:
+			// Listed as the rename
+		case s.Staging != gogit.Unmodified && s.Staging != gogit.Untracked:
 			staged = append(staged, fmt.Sprintf("%-12s%s", mapStatus(s.Staging), path))
 		}
 
@@ -184,7 +202,7 @@ func mapStatus(s gogit.StatusCode) string {
 	}
 }
 
-func (c *StatusCommand) formatShortInfo(repo *gogit.Repository, status gogit.Status, showBranch bool) (string, error) {
+func (c *StatusCommand) formatShortInfo(repo *gogit.Repository, status gogit.Status, renames map[string]renamePair, showBranch bool) (string, error) {
 	var sb strings.Builder
 
 	if showBranch {
@@ -206,12 +224,20 @@ func (c *StatusCommand) formatShortInfo(repo *gogit.Repository, status gogit.Sta
 		paths = append(paths, path)
 	}
 	sort.Strings(paths)
+	sources := renameSources(renames)
 
 	for _, path := range paths {
 		s := status[path]
 		if s.Staging == gogit.Unmodified && s.Worktree == gogit.Unmodified {
 			continue
 		}
+		if sources[path] && s.Staging == gogit.Deleted && s.Worktree == gogit.Unmodified {
+			continue // Listed as the rename
+		}
+		if r, ok := renames[path]; ok {
+			sb.WriteString(fmt.Sprintf("R%c %s -> %s\n", getStatusCodeChar(s.Worktree), r.From, path))
+			continue
+		}
 
 		// X (Staging status), Y (Worktree status)
 		var x, y byte
@@ -3,6 +3,8 @@ package commands
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
 
@@ -24,10 +26,24 @@ var _ git.Command = (*TagCommand)(nil)
 type TagOptions struct {
 	List      bool
 	Delete    bool
+	Verify    bool
 	Annotated bool
+	Force     bool
 	Message   string
 	TagName   string
 	Commit    string
+
+	// Listing
+	Lines      int      // -n<num>: annotation lines to show
+	Patterns   []string // -l <pattern>...
+	Contains   string   // --contains [<commit>]
+	NoContains string   // --no-contains [<commit>]
+	Merged     string   // --merged [<commit>]
+	NoMerged   string   // --no-merged [<commit>]
+	PointsAt   string   // --points-at [<object>]
+	Sort       string   // --sort=[-]<key>
+
+	names []string // Positional arguments
 }
 
 func (c *TagCommand) Execute(ctx context.Context, s *git.Session, args []string) (string, error) {
@@ -47,89 +63,397 @@ func (c *TagCommand) Execute(ctx context.Context, s *git.Session, args []string)
 		return "", fmt.Errorf("fatal: not a git repository")
 	}
 
-	if opts.Delete {
-		return c.deleteTag(repo, opts)
+	switch {
+	case opts.Delete:
+		return c.deleteTags(repo, opts)
+	case opts.Verify:
+		return c.verifyTags(repo, opts)
+	case opts.List || opts.TagName == "":
+		return c.listTags(repo, opts)
 	}
-	if opts.TagName != "" {
-		return c.createTag(repo, opts)
-	}
-	return c.listTags(repo)
+	return c.createTag(s, repo, opts)
 }
 
 func (c *TagCommand) parseArgs(args []string) (*TagOptions, error) {
 	opts := &TagOptions{}
 	cmdArgs := args[1:]
 
+	// optionalRev reads the optional <commit> of --contains and friends
+	optionalRev := func(i *int) string {
+		if *i+1 < len(cmdArgs) && !strings.HasPrefix(cmdArgs[*i+1], "-") {
+			*i++
+			return cmdArgs[*i]
+		}
+		return "HEAD"
+	}
+
 	for i := 0; i < len(cmdArgs); i++ {
 		arg := cmdArgs[i]
-		switch arg {
-		case "-d", "--delete":
+		name, value, hasValue := strings.Cut(arg, "=")
+		switch {
+		case arg == "-d" || arg == "--delete":
 			opts.Delete = true
-		case "-a", "--annotate":
+		case arg == "-v" || arg == "--verify":
+			opts.Verify = true
+		case arg == "-a" || arg == "--annotate":
 			opts.Annotated = true
-		case "-m", "--message":
+		case arg == "-f" || arg == "--force":
+			opts.Force = true
+		case arg == "-l" || arg == "--list":
+			opts.List = true
+		case arg == "-m" || arg == "--message":
 			if i+1 < len(cmdArgs) {
 				opts.Message = cmdArgs[i+1]
 				i++
 			}
-		case "-h", "--help":
+		case strings.HasPrefix(arg, "-m") && len(arg) > 2:
+			opts.Message = arg[2:]
+		case name == "--message" && hasValue:
+			opts.Message = value
+		case arg == "-n":
+			opts.Lines = 1
+			opts.List = true
+		case strings.HasPrefix(arg, "-n"):
+			n, err := strconv.Atoi(arg[2:])
+			if err != nil {
+				return nil, fmt.Errorf("error: switch `n' expects a numerical value")
+			}
+			opts.Lines = n
+			opts.List = true
+		case name == "--contains" || name == "--no-contains" || name == "--merged" || name == "--no-merged" || name == "--points-at":
+			rev := value
+			if !hasValue {
+				rev = optionalRev(&i)
+			}
+			switch name {
+			case "--contains":
+				opts.Contains = rev
+			case "--no-contains":
+				opts.NoContains = rev
+			case "--merged":
+				opts.Merged = rev
+			case "--no-merged":
+				opts.NoMerged = rev
+			default:
+				opts.PointsAt = rev
+			}
+			opts.List = true
+		case name == "--sort":
+			if !hasValue {
+				if i+1 >= len(cmdArgs) {
+					return nil, fmt.Errorf("error: option `sort' requires a value")
+				}
+				i++
+				value = cmdArgs[i]
+			}
+			opts.Sort = value
+		case arg == "-h" || arg == "--help":
 			return nil, fmt.Errorf("help requested")
 		default:
-			if opts.TagName == "" {
-				opts.TagName = arg
-			} else if opts.Commit == "" {
-				opts.Commit = arg
+			if strings.HasPrefix(arg, "-") {
+				return nil, fmt.Errorf("error: unknown option `%s'", strings.TrimLeft(arg, "-"))
 			}
+			opts.names = append(opts.names, arg)
 		}
 	}
+
+	// A message always makes an annotated tag
+	if opts.Message != "" {
+		opts.Annotated = true
+	}
+	if opts.List {
+		opts.Patterns = opts.names
+		return opts, nil
+	}
+	if len(opts.names) > 0 {
+		opts.TagName = opts.names[0]
+	}
+	if len(opts.names) > 1 {
+		opts.Commit = opts.names[1]
+	}
 	return opts, nil
 }
 
-func (c *TagCommand) listTags(repo *gogit.Repository) (string, error) {
-	tags, err := repo.Tags()
+// tagEntry is a tag and what it points at.
+type tagEntry struct {
+	name   string
+	hash   plumbing.Hash // Target of refs/tags/<name>
+	tag    *object.Tag   // The tag object of an annotated tag, nil for a lightweight one
+	commit *object.Commit
+}
+
+// collectTags reads every tag, peeling annotated tags to their commit.
+func collectTags(repo *gogit.Repository) ([]*tagEntry, error) {
+	refs, err := repo.Tags()
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	var sb strings.Builder
-	err = tags.ForEach(func(r *plumbing.Reference) error {
-		sb.WriteString(r.Name().Short() + "\n")
+	var entries []*tagEntry
+	err = refs.ForEach(func(r *plumbing.Reference) error {
+		e := &tagEntry{name: r.Name().Short(), hash: r.Hash()}
+		target := r.Hash()
+		if tag, err := repo.TagObject(r.Hash()); err == nil {
+			e.tag = tag
+			// Follow tags of tags down to the commit
+			for tag != nil && tag.TargetType == plumbing.TagObject {
+				tag, _ = repo.TagObject(tag.Target)
+			}
+			if tag != nil {
+				target = tag.Target
+			}
+		}
+		e.commit, _ = repo.CommitObject(target)
+		entries = append(entries, e)
 		return nil
 	})
+	return entries, err
+}
+
+// date is when the tag was made: the tagger date of an annotated tag, the
+// commit date of a lightweight one.
+func (e *tagEntry) date() time.Time {
+	if e.tag != nil {
+		return e.tag.Tagger.When
+	}
+	if e.commit != nil {
+		return e.commit.Committer.When
+	}
+	return time.Time{}
+}
+
+func (e *tagEntry) commitHash() plumbing.Hash {
+	if e.commit == nil {
+		return plumbing.ZeroHash
+	}
+	return e.commit.Hash
+}
+
+func (c *TagCommand) listTags(repo *gogit.Repository, opts *TagOptions) (string, error) {
+	entries, err := collectTags(repo)
 	if err != nil {
 		return "", err
 	}
-	return sb.String(), nil
+	if entries, err = c.filterTags(repo, entries, opts); err != nil {
+		return "", err
+	}
+	if err := c.sortTags(repo, entries, opts.Sort); err != nil {
+		return "", err
+	}
+
+	var lines []string
+	for _, e := range entries {
+		if opts.Lines <= 0 {
+			lines = append(lines, e.name)
+			continue
+		}
+		// -n: the first lines of the annotation, or the commit subject
+		message := ""
+		if e.tag != nil {
+			message = e.tag.Message
+		} else if e.commit != nil {
+			message = e.commit.Message
+		}
+		annotation := strings.Split(strings.TrimRight(message, "\n"), "\n")
+		if len(annotation) > opts.Lines {
+			annotation = annotation[:opts.Lines]
+		}
+		lines = append(lines, strings.TrimRight(fmt.Sprintf("%-15s %s", e.name, strings.Join(annotation, "\n    ")), " "))
+	}
+	return strings.Join(lines, "\n"), nil
 }
 
-func (c *TagCommand) deleteTag(repo *gogit.Repository, opts *TagOptions) (string, error) {
-	if opts.TagName == "" {
-		return "", fmt.Errorf("tag name required")
+// filterTags applies the -l patterns and --contains, --no-contains,
+// --merged, --no-merged and --points-at.
+func (c *TagCommand) filterTags(repo *gogit.Repository, entries []*tagEntry, opts *TagOptions) ([]*tagEntry, error) {
+	if len(opts.Patterns) > 0 {
+		var kept []*tagEntry
+		for _, e := range entries {
+			if branchPatternMatches(opts.Patterns, e.name) {
+				kept = append(kept, e)
+			}
+		}
+		entries = kept
 	}
-	if err := repo.DeleteTag(opts.TagName); err != nil {
-		return "", err
+
+	// isAncestor reports whether a is in the history of b
+	isAncestor := func(a, b plumbing.Hash) bool {
+		ok, err := git.IsFastForward(repo, a, b)
+		return err == nil && ok
+	}
+	type filter struct {
+		rev  string
+		keep func(rev plumbing.Hash, e *tagEntry) bool
+	}
+	filters := []filter{
+		{opts.Contains, func(rev plumbing.Hash, e *tagEntry) bool { return isAncestor(rev, e.commitHash()) }},
+		{opts.NoContains, func(rev plumbing.Hash, e *tagEntry) bool { return !isAncestor(rev, e.commitHash()) }},
+		{opts.Merged, func(rev plumbing.Hash, e *tagEntry) bool { return isAncestor(e.commitHash(), rev) }},
+		{opts.NoMerged, func(rev plumbing.Hash, e *tagEntry) bool { return !isAncestor(e.commitHash(), rev) }},
+		{opts.PointsAt, func(rev plumbing.Hash, e *tagEntry) bool { return e.hash == rev || e.commitHash() == rev }},
+	}
+	for _, f := range filters {
+		if f.rev == "" {
+			continue
+		}
+		rev, err := git.ResolveRevision(repo, f.rev)
+		if err != nil {
+			return nil, fmt.Errorf("error: malformed object name %s", f.rev)
+		}
+		var kept []*tagEntry
+		for _, e := range entries {
+			if f.keep(*rev, e) {
+				kept = append(kept, e)
+			}
+		}
+		entries = kept
+	}
+	return entries, nil
+}
+
+// sortTags orders tags by key (or tag.sort), by name by default.
+func (c *TagCommand) sortTags(repo *gogit.Repository, entries []*tagEntry, key string) error {
+	if key == "" {
+		if cfg, err := repo.Config(); err == nil {
+			key = cfg.Raw.Section("tag").Option("sort")
+		}
+	}
+	if key == "" {
+		key = "refname"
+	}
+	reverse := strings.HasPrefix(key, "-")
+	key = strings.TrimPrefix(key, "-")
+
+	var less func(a, b *tagEntry) bool
+	switch key {
+	case "refname":
+		less = func(a, b *tagEntry) bool { return a.name < b.name }
+	case "version:refname", "v:refname":
+		less = func(a, b *tagEntry) bool { return compareVersions(a.name, b.name) < 0 }
+	case "creatordate", "taggerdate":
+		less = func(a, b *tagEntry) bool { return a.date().Before(b.date()) }
+	case "committerdate":
+		less = func(a, b *tagEntry) bool {
+			return a.commit != nil && b.commit != nil && a.commit.Committer.When.Before(b.commit.Committer.When)
+		}
+	case "objectname":
+		less = func(a, b *tagEntry) bool { return a.hash.String() < b.hash.String() }
+	default:
+		return fmt.Errorf("fatal: unknown field name: %s", key)
 	}
-	return "Deleted tag " + opts.TagName, nil
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		a, b := entries[i], entries[j]
+		if reverse {
+			a, b = b, a
+		}
+		if less(a, b) {
+			return true
+		}
+		if less(b, a) {
+			return false
+		}
+		return entries[i].name < entries[j].name
+	})
+	return nil
 }
 
-func (c *TagCommand) createTag(repo *gogit.Repository, opts *TagOptions) (string, error) {
-	var targetRef *plumbing.Reference
-	var err error
+// compareVersions compares tag names as versions, so v1.10 sorts after v1.9:
+// runs of digits compare as numbers, everything else as text.
+func compareVersions(a, b string) int {
+	for a != "" && b != "" {
+		ca, restA := versionChunk(a)
+		cb, restB := versionChunk(b)
+		na, errA := strconv.Atoi(ca)
+		nb, errB := strconv.Atoi(cb)
+		switch {
+		case errA == nil && errB == nil && na != nb:
+			if na < nb {
+				return -1
+			}
+			return 1
+		case (errA != nil || errB != nil) && ca != cb:
+			return strings.Compare(ca, cb)
+		}
+		a, b = restA, restB
+	}
+	return strings.Compare(a, b)
+}
 
-	if opts.Commit != "" {
-		// Resolve commit
-		h, err := repo.ResolveRevision(plumbing.Revision(opts.Commit))
+// versionChunk splits off the leading run of digits or non-digits.
+func versionChunk(s string) (chunk, rest string) {
+	digit := func(r byte) bool { return r >= '0' && r <= '9' }
+	i := 1
+	for i < len(s) && digit(s[i]) == digit(s[0]) {
+		i++
+	}
+	return s[:i], s[i:]
+}
+
+func (c *TagCommand) deleteTags(repo *gogit.Repository, opts *TagOptions) (string, error) {
+	if len(opts.names) == 0 {
+		return "", fmt.Errorf("tag name required")
+	}
+	var lines []string
+	for _, name := range opts.names {
+		ref, err := repo.Tag(name)
 		if err != nil {
+			err = fmt.Errorf("error: tag '%s' not found.", name)
+			if len(lines) > 0 {
+				return "", fmt.Errorf("%s\n%w", strings.Join(lines, "\n"), err)
+			}
+			return "", err
+		}
+		if err := repo.DeleteTag(name); err != nil {
 			return "", err
 		}
-		// We need a HashReference for CreateTag? No, CreateTag helper in go-git takes hash.
-		// For Lightweight tag, SetReference needs a ref.
-		// Let's create a temp ref object for target.
-		targetRef = plumbing.NewHashReference("refs/heads/temp", *h) // Dummy ref name just for hash carrier?
-	} else {
-		targetRef, err = repo.Head()
+		lines = append(lines, fmt.Sprintf("Deleted tag %s (was %s)", name, ref.Hash().String()[:7]))
+	}
+	return strings.Join(lines, "\n"), nil
+}
+
+// verifyTags checks the signatures of tags. The simulator never signs tags,
+// so this shows the tag and reports the missing signature like git does.
+func (c *TagCommand) verifyTags(repo *gogit.Repository, opts *TagOptions) (string, error) {
+	if len(opts.names) == 0 {
+		return "", fmt.Errorf("tag name required")
+	}
+	var out []string
+	for _, name := range opts.names {
+		ref, err := repo.Tag(name)
+		if err != nil {
+			return "", fmt.Errorf("error: tag '%s' not found.", name)
+		}
+		tag, err := repo.TagObject(ref.Hash())
 		if err != nil {
+			return "", fmt.Errorf("error: %s: cannot verify a non-tag object of type commit.", name)
+		}
+		out = append(out, fmt.Sprintf("object %s\ntype %s\ntag %s\ntagger %s <%s> %d %s\n\n%s",
+			tag.Target, tag.TargetType, tag.Name, tag.Tagger.Name, tag.Tagger.Email,
+			tag.Tagger.When.Unix(), tag.Tagger.When.Format("-0700"), strings.TrimRight(tag.Message, "\n")))
+	}
+	return "", fmt.Errorf("%s\nerror: no signature found", strings.Join(out, "\n"))
+}
+
+func (c *TagCommand) createTag(s *git.Session, repo *gogit.Repository, opts *TagOptions) (string, error) {
+	rev := opts.Commit
+	if rev == "" {
+		rev = "HEAD"
+	}
+	target, err := git.ResolveRevision(repo, rev)
+	if err != nil {
+		return "", fmt.Errorf("fatal: Failed to resolve '%s' as a valid ref.", rev)
+	}
+
+	// An existing tag is only replaced with -f
+	updated := ""
+	if existing, err := repo.Tag(opts.TagName); err == nil {
+		if !opts.Force {
+			return "", fmt.Errorf("fatal: tag '%s' already exists", opts.TagName)
+		}
+		if err := repo.DeleteTag(opts.TagName); err != nil {
 			return "", err
 		}
+		updated = fmt.Sprintf("Updated tag '%s' (was %s)", opts.TagName, existing.Hash().String()[:7])
 	}
 
 	if opts.Annotated {
@@ -137,26 +461,28 @@ func (c *TagCommand) createTag(repo *gogit.Repository, opts *TagOptions) (string
 		if msg == "" {
 			msg = "Tag message"
 		}
-		_, err = repo.CreateTag(opts.TagName, targetRef.Hash(), &gogit.CreateTagOptions{
+		_, err = repo.CreateTag(opts.TagName, *target, &gogit.CreateTagOptions{
 			Message: msg,
-			Tagger: &object.Signature{
-				Name:  "User",
-				Email: "user@example.com",
-				When:  time.Now(),
-			},
+			Tagger:  s.Signature(),
 		})
 		if err != nil {
 			return "", err
 		}
+		if updated != "" {
+			return updated, nil
+		}
 		return "Created annotated tag " + opts.TagName, nil
 	}
 
 	// Lightweight
-	refName := plumbing.ReferenceName("refs/tags/" + opts.TagName)
-	ref := plumbing.NewHashReference(refName, targetRef.Hash())
+	refName := plumbing.NewTagReferenceName(opts.TagName)
+	ref := plumbing.NewHashReference(refName, *target)
 	if err := repo.Storer.SetReference(ref); err != nil {
 		return "", err
 	}
+	if updated != "" {
+		return updated, nil
+	}
 	return "Created tag " + opts.TagName, nil
 }
 
@@ -165,24 +491,57 @@ func (c *TagCommand) Help() string {
 
  💡 DESCRIPTION
     タグ（コミットにつける名前・目印）に関する以下の操作を行います：
-    ・タグの一覧を表示する（引数なし）
+    ・タグの一覧を表示する（引数なし、-l）
     ・新しいタグを作成する
+    ・既存のタグを付け直す（-f）
     ・不要なタグを削除する（-d）
+    リリースのバージョン番号を付けるのによく使われます。
 
  📋 SYNOPSIS
-    git tag [-a] [-m <msg>] <tagname> [<commit>]
-    git tag -d <tagname>
+    git tag [-a] [-f] [-m <msg>] <tagname> [<commit>]
+    git tag -d <tagname>...
+    git tag -v <tagname>...
+    git tag [-n[<num>]] -l [--contains <commit>] [--no-contains <commit>]
+            [--merged <commit>] [--no-merged <commit>] [--points-at <object>]
+            [--sort=<key>] [<pattern>...]
 
  ⚙️  COMMON OPTIONS
     -a
         注釈付き（Annotated）タグを作成します。作成者や日時などの情報を含めます。
 
     -m <msg>
-        タグのメッセージを指定します。
+        タグのメッセージを指定します（-a を省略しても注釈付きタグになります）。
+
+    -f, --force
+        同じ名前のタグがあっても付け直します。
+        ※ 既に push したタグを付け直すと、他の人と食い違うので注意！
 
     -d
         タグを削除します。
 
+    -v
+        タグの署名を検証します（この環境では署名付きタグは作れません）。
+
+    -l [<pattern>]
+        タグを一覧表示します。'v1.*' のようなパターンで絞り込めます。
+
+    -n<num>
+        一覧で注釈（メッセージ）の先頭 <num> 行も表示します（省略時は 1 行）。
+
+    --contains [<commit>] / --no-contains [<commit>]
+        <commit> を含む / 含まないタグだけを表示します。
+        「このバグ修正はどのリリースに入った？」を調べるのに便利です。
+
+    --merged [<commit>] / --no-merged [<commit>]
+        <commit> から到達できる / できないタグだけを表示します。
+
+    --points-at [<object>]
+        <object> を指すタグだけを表示します。
+
+    --sort=<key>
+        並び順を指定します。refname（既定）, version:refname, creatordate など。
+        先頭に - を付けると逆順になります。
+
  🛠  EXAMPLES
     1. 軽量タグを作成（現在のHEADに）
        $ git tag v1.0
@@ -190,6 +549,18 @@ func (c *TagCommand) Help() string {
     2. 注釈付きタグを作成
        $ git tag -a v1.0 -m "Release version 1.0"
 
+    3. v1 系のタグを注釈付きで一覧
+       $ git tag -n -l 'v1.*'
+
+    4. バージョン順に並べる（v1.10 が v1.9 の後になります）
+       $ git tag --sort=version:refname
+
+    5. あるコミットを含むリリースを探す
+       $ git tag --contains abc1234
+
+ 💡 TIPS
+    現在のコミットが最新のタグからいくつ進んでいるかは git describe で確認できます。
+
  🔗 REFERENCE
     Full documentation: https://git-scm.com/docs/git-tag
 `
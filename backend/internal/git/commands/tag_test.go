@@ -89,3 +89,60 @@ func TestTagCommand(t *testing.T) {
 		}
 	})
 }
+
+func TestTagCommand_ListAndRetag(t *testing.T) {
+	sm := git.NewSessionManager()
+	s, _ := sm.CreateSession("test-tag-list")
+	s.InitRepo("testrepo")
+	s.CurrentDir = "/testrepo"
+	repo := s.GetRepo()
+	ctx := context.Background()
+	run := func(args ...string) string {
+		t.Helper()
+		out, err := git.Dispatch(ctx, s, "tag", append([]string{"tag"}, args...))
+		if err != nil {
+			t.Fatalf("git tag %v failed: %v", args, err)
+		}
+		return out
+	}
+
+	commitFile(t, repo, "a.txt", "a", "First release")
+	run("v1.9")
+	run("-m", "Release 1.10\n\nDetails", "v1.10")
+	commitFile(t, repo, "b.txt", "b", "Second release")
+	run("v2.0")
+
+	if out := run("-l", "v1.*"); out != "v1.10\nv1.9" {
+		t.Errorf("-l pattern = %q", out)
+	}
+	if out := run("--sort=version:refname"); out != "v1.9\nv1.10\nv2.0" {
+		t.Errorf("version sort = %q", out)
+	}
+	if out := run("--sort=-v:refname", "-l", "v1*"); out != "v1.10\nv1.9" {
+		t.Errorf("reverse version sort = %q", out)
+	}
+	if out := run("-n", "-l", "v1.10"); out != "v1.10           Release 1.10" {
+		t.Errorf("-n = %q", out)
+	}
+	if out := run("--contains", "HEAD"); out != "v2.0" {
+		t.Errorf("--contains = %q", out)
+	}
+	if out := run("--points-at", "HEAD~1"); out != "v1.10\nv1.9" {
+		t.Errorf("--points-at = %q", out)
+	}
+
+	// Retagging needs -f
+	if _, err := git.Dispatch(ctx, s, "tag", []string{"tag", "v1.9"}); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("expected already exists error, got %v", err)
+	}
+	if out := run("-f", "v1.9"); !strings.HasPrefix(out, "Updated tag 'v1.9' (was ") {
+		t.Errorf("-f = %q", out)
+	}
+	if out := run("--points-at", "HEAD"); out != "v1.9\nv2.0" {
+		t.Errorf("retagged v1.9 should point at HEAD: %q", out)
+	}
+
+	if _, err := git.Dispatch(ctx, s, "tag", []string{"tag", "-v", "v1.10"}); err == nil || !strings.Contains(err.Error(), "no signature found") {
+		t.Errorf("-v should report the missing signature, got %v", err)
+	}
+}
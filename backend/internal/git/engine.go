@@ -95,6 +95,9 @@ func ParseCommand(input string) (string, []string) {
 		case "rm":
 			// Special handling for git rm to separate from shell rm
 			return "git-rm", parts[1:]
+		case "mv":
+			// Likewise git mv, which also stages the move
+			return "git-mv", parts[1:]
 		}
 
 		// Block stupid things like "git ls" if "ls" is a shell command valid on its own but not as git subcommand
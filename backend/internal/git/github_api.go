@@ -97,7 +97,12 @@ func EstimateCloneTime(sizeKB int) time.Duration {
 
 // GetCloneEstimate fetches repo info and calculates clone time estimate
 // For non-GitHub URLs, returns default estimates without API call
+// Local paths, bundles and archives are measured on disk (see LocalRepoInfo)
 func GetCloneEstimate(url string) (*CloneEstimate, error) {
+	if DetectIngestSource(url) != SourceURL {
+		return getLocalEstimate(url)
+	}
+
 	info, err := FetchRepoInfo(url)
 	if err != nil {
 		// For non-GitHub URLs or API errors, return default estimate
@@ -140,6 +145,21 @@ func GetCloneEstimate(url string) (*CloneEstimate, error) {
 	}, nil
 }
 
+// getLocalEstimate estimates the ingest of a local source without any network access
+func getLocalEstimate(source string) (*CloneEstimate, error) {
+	info, err := LocalRepoInfo(source)
+	if err != nil {
+		return nil, err
+	}
+	sizeDisplay := formatSize(info.Size)
+	return &CloneEstimate{
+		RepoInfo:         info,
+		EstimatedSeconds: int(EstimateLocalIngestTime(info.Size).Seconds()),
+		SizeDisplay:      sizeDisplay,
+		Message:          fmt.Sprintf("%s (%s). Ingest runs offline.", info.Description, sizeDisplay),
+	}, nil
+}
+
 // parseGitHubURL extracts owner and repo name from a GitHub URL
 func parseGitHubURL(url string) (string, string, error) {
 	// Clean the URL
@@ -4,14 +4,14 @@ import (
 	"time"
 
 	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/kurobon/gitgym/backend/internal/state"
 )
 
 // GetDefaultSignature returns the default author/committer signature for operations.
-// In a real application, this should retrieve user configuration.
+// Commands running in a session should use Session.Signature instead, which
+// follows the session's deterministic clock.
 func GetDefaultSignature() *object.Signature {
-	return &object.Signature{
-		Name:  "User",
-		Email: "user@example.com",
-		When:  time.Now(),
-	}
+	sig := state.DefaultIdentity
+	sig.When = time.Now()
+	return &sig
 }
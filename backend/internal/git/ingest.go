@@ -0,0 +1,460 @@
+package git
+
+// ingest.go - Offline Ingest Sources
+//
+// Training rooms are often air-gapped, so besides a URL a shared remote can be
+// ingested from a local repository (bare or with a working tree), a `.bundle`
+// file, or a tar/tar.gz archive of a repository, either as a path on the server
+// or uploaded. None of these need a network or a git binary, and their size
+// estimates are read from the files instead of a hosting API.
+//
+// Local paths are confined to the configured ingest root (GITGYM_INGEST_ROOT):
+// relative paths resolve against it, and a path that leaves it once cleaned
+// and with symlinks resolved is refused, so a request cannot read arbitrary
+// repositories on the server.
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	appconfig "github.com/kurobon/gitgym/backend/internal/config"
+	"github.com/kurobon/gitgym/backend/internal/state"
+)
+
+// Ingest source kinds
+const (
+	SourceURL     = "url"     // Cloned over the network
+	SourceLocal   = "local"   // Directory holding a bare or non-bare repository
+	SourceBundle  = "bundle"  // File written by `git bundle create`
+	SourceArchive = "archive" // tar or tar.gz of a repository directory
+)
+
+// maxArchiveDepth bounds how deep inside an archive the repository may sit.
+const maxArchiveDepth = 3
+
+// maxExtractedBytes bounds the total size of the files unpacked from one
+// archive, since a small gzip stream can expand without limit.
+var maxExtractedBytes int64 = 4 << 30
+
+var gzipMagic = []byte{0x1f, 0x8b}
+
+// DetectIngestSource reports which kind of source an ingest URL or path is.
+// Anything that is not an existing path inside the ingest root is treated as a
+// URL; IngestSource refuses those that are not network URLs.
+func DetectIngestSource(source string) string {
+	if isNetworkURL(source) {
+		return SourceURL
+	}
+	path, err := ResolveIngestPath(source)
+	if err != nil {
+		return SourceURL
+	}
+	return detectPathSource(path)
+}
+
+// detectPathSource reports the kind of an existing local path.
+func detectPathSource(path string) string {
+	info, err := os.Stat(path)
+	if err != nil {
+		return ""
+	}
+	if info.IsDir() {
+		return SourceLocal
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+	return detectFileSource(bufio.NewReader(f))
+}
+
+// isNetworkURL reports whether source names a remote host: a URL with a
+// scheme other than file://, or scp-like "user@host:path".
+func isNetworkURL(source string) bool {
+	if i := strings.Index(source, "://"); i > 0 {
+		return source[:i] != "file"
+	}
+	colon := strings.Index(source, ":")
+	slash := strings.Index(source, "/")
+	return colon > 1 && (slash < 0 || colon < slash)
+}
+
+// ResolveIngestPath maps a local source to a path inside the ingest root.
+// Relative paths are taken from the root. The path is cleaned and its symlinks
+// resolved before checking that it stays inside the root.
+func ResolveIngestPath(source string) (string, error) {
+	root, err := filepath.Abs(appconfig.Global.IngestRoot)
+	if err != nil {
+		return "", err
+	}
+	if root, err = filepath.EvalSymlinks(root); err != nil {
+		return "", fmt.Errorf("ingest root %s is not available: %w", appconfig.Global.IngestRoot, err)
+	}
+
+	path := filepath.FromSlash(strings.TrimPrefix(source, "file://"))
+	if !filepath.IsAbs(path) {
+		path = filepath.Join(root, path)
+	}
+	resolved, err := filepath.EvalSymlinks(filepath.Clean(path))
+	if err != nil {
+		return "", fmt.Errorf("'%s' does not exist in the ingest root", source)
+	}
+	rel, err := filepath.Rel(root, resolved)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("'%s' is outside the ingest root", source)
+	}
+	return resolved, nil
+}
+
+// detectFileSource sniffs the first bytes of a file: bundle signature, gzip
+// magic, or a tar header. Unknown content is reported as "".
+func detectFileSource(r *bufio.Reader) string {
+	head, _ := r.Peek(512)
+	switch {
+	case IsBundle(head):
+		return SourceBundle
+	case bytes.HasPrefix(head, gzipMagic):
+		return SourceArchive
+	case len(head) >= 262 && string(head[257:262]) == "ustar":
+		return SourceArchive
+	}
+	return ""
+}
+
+// IngestSource creates the shared remote name from a URL, a local repository
+// path, a bundle file or an archive. depth only applies to URL clones.
+func IngestSource(ctx context.Context, sm *SessionManager, name, source string, depth int) error {
+	if isNetworkURL(source) {
+		return sm.IngestRemote(ctx, name, source, depth)
+	}
+
+	path, err := ResolveIngestPath(source)
+	if err != nil {
+		return err
+	}
+	switch detectPathSource(path) {
+	case SourceLocal:
+		return sm.IngestRepository(name, path, func(repoPath string) (*gogit.Repository, error) {
+			return copyLocalRepository(ctx, path, repoPath)
+		})
+	case "":
+		return fmt.Errorf("'%s' is not a repository, bundle or archive", source)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return ingestFile(ctx, sm, name, path, bufio.NewReader(f))
+}
+
+// IngestUpload creates the shared remote name from an uploaded bundle or
+// archive. filename only labels the source.
+func IngestUpload(ctx context.Context, sm *SessionManager, name, filename string, r io.Reader) error {
+	return ingestFile(ctx, sm, name, "upload:"+filename, bufio.NewReader(r))
+}
+
+func ingestFile(ctx context.Context, sm *SessionManager, name, source string, r *bufio.Reader) error {
+	switch detectFileSource(r) {
+	case SourceBundle:
+		state.ReportIngestPhase(ctx, "Unpacking bundle")
+		b, err := ReadBundle(r)
+		if err != nil {
+			return err
+		}
+		if len(b.Prerequisites) > 0 {
+			return fmt.Errorf("bundle requires %d prerequisite commit(s); ingest needs a complete bundle (git bundle create <file> --all)", len(b.Prerequisites))
+		}
+		return sm.IngestRepository(name, source, func(repoPath string) (*gogit.Repository, error) {
+			repo, err := gogit.PlainInit(repoPath, true)
+			if err != nil {
+				return nil, err
+			}
+			if err := b.Unbundle(repo.Storer); err != nil {
+				return nil, err
+			}
+			if err := b.setReferences(repo.Storer); err != nil {
+				return nil, err
+			}
+			return repo, nil
+		})
+
+	case SourceArchive:
+		tmpDir, err := os.MkdirTemp("", "gitgym-ingest-*")
+		if err != nil {
+			return fmt.Errorf("failed to create temp dir: %w", err)
+		}
+		defer os.RemoveAll(tmpDir)
+
+		state.ReportIngestPhase(ctx, "Extracting archive")
+		if err := extractArchive(ctx, r, tmpDir); err != nil {
+			return err
+		}
+		repoDir, err := findRepository(tmpDir)
+		if err != nil {
+			return err
+		}
+		return sm.IngestRepository(name, source, func(repoPath string) (*gogit.Repository, error) {
+			return copyLocalRepository(ctx, repoDir, repoPath)
+		})
+	}
+	return fmt.Errorf("%s is neither a git bundle nor a tar/tar.gz archive", source)
+}
+
+func copyLocalRepository(ctx context.Context, src, repoPath string) (*gogit.Repository, error) {
+	srcRepo, err := openLocalRepository(src)
+	if err != nil {
+		return nil, err
+	}
+	return state.CopyRepository(ctx, srcRepo, repoPath)
+}
+
+// openLocalRepository opens the repository at dir itself. Parent directories
+// are not searched and a .git file ("gitdir: <path>") is not followed, since
+// either could reach a repository outside the ingest root.
+func openLocalRepository(dir string) (*gogit.Repository, error) {
+	if !isRepositoryDir(dir) {
+		return nil, fmt.Errorf("not a git repository: %s", dir)
+	}
+	repo, err := gogit.PlainOpen(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open repository %s: %w", dir, err)
+	}
+	return repo, nil
+}
+
+// extractArchive unpacks a tar or tar.gz stream into dest, refusing entries
+// that would land outside it and stopping once maxExtractedBytes have been
+// written. Links are skipped; repositories do not need them.
+func extractArchive(ctx context.Context, r *bufio.Reader, dest string) error {
+	var stream io.Reader = r
+	if head, _ := r.Peek(2); bytes.Equal(head, gzipMagic) {
+		gz, err := gzip.NewReader(r)
+		if err != nil {
+			return fmt.Errorf("invalid gzip archive: %w", err)
+		}
+		defer gz.Close()
+		stream = gz
+	}
+
+	tr := tar.NewReader(stream)
+	budget := maxExtractedBytes
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("invalid tar archive: %w", err)
+		}
+
+		name := filepath.Clean(filepath.FromSlash(hdr.Name))
+		if filepath.IsAbs(name) || name == ".." || strings.HasPrefix(name, ".."+string(filepath.Separator)) {
+			return fmt.Errorf("archive entry '%s' escapes the archive", hdr.Name)
+		}
+		target := filepath.Join(dest, name)
+
+		switch hdr.Typeflag {
+		case tar.TypeDir:
+			if err := os.MkdirAll(target, 0750); err != nil {
+				return err
+			}
+		case tar.TypeReg:
+			if err := os.MkdirAll(filepath.Dir(target), 0750); err != nil {
+				return err
+			}
+			f, err := os.OpenFile(target, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0640)
+			if err != nil {
+				return err
+			}
+			n, err := io.CopyN(f, tr, budget+1)
+			if errors.Is(err, io.EOF) {
+				err = nil
+			}
+			if closeErr := f.Close(); err == nil {
+				err = closeErr
+			}
+			if err != nil {
+				return err
+			}
+			if budget -= n; budget < 0 {
+				return fmt.Errorf("archive expands to more than %d MB", maxExtractedBytes>>20)
+			}
+		}
+	}
+}
+
+// findRepository returns the first directory under root (in walk order, at most
+// maxArchiveDepth deep) that is a git repository: one with a .git entry, or a
+// bare one with HEAD and objects/.
+func findRepository(root string) (string, error) {
+	found := ""
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() || found != "" {
+			return err
+		}
+		rel, _ := filepath.Rel(root, path)
+		if rel != "." && strings.Count(rel, string(filepath.Separator)) >= maxArchiveDepth {
+			return filepath.SkipDir
+		}
+		if isRepositoryDir(path) {
+			found = path
+			return filepath.SkipAll
+		}
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	if found == "" {
+		return "", fmt.Errorf("archive does not contain a git repository")
+	}
+	return found, nil
+}
+
+// isRepositoryDir reports whether dir holds a .git directory or is a bare
+// repository. Symlinks and .git files are not accepted.
+func isRepositoryDir(dir string) bool {
+	if dotGit, err := os.Lstat(filepath.Join(dir, ".git")); err == nil {
+		return dotGit.IsDir()
+	}
+	head, errHead := os.Lstat(filepath.Join(dir, "HEAD"))
+	objects, errObjects := os.Lstat(filepath.Join(dir, "objects"))
+	return errHead == nil && head.Mode().IsRegular() && errObjects == nil && objects.IsDir()
+}
+
+// LocalRepoInfo describes a local ingest source without any network access.
+// Size is the size of the object store (or of the bundle/archive file) in KB.
+func LocalRepoInfo(source string) (*RepoInfo, error) {
+	path, err := ResolveIngestPath(source)
+	if err != nil {
+		return nil, err
+	}
+	name := localSourceName(path)
+	info := &RepoInfo{Name: name, FullName: name, DefaultBranch: "main"}
+
+	kind := detectPathSource(path)
+	switch kind {
+	case SourceLocal:
+		repo, err := openLocalRepository(path)
+		if err != nil {
+			return nil, err
+		}
+		objectsDir := filepath.Join(path, "objects")
+		if isRepositoryDir(path) && !dirExists(filepath.Join(path, ".git")) {
+			info.Description = "Local bare repository"
+		} else {
+			objectsDir = filepath.Join(path, ".git", "objects")
+			info.Description = "Local repository"
+		}
+		size, err := dirSize(objectsDir)
+		if err != nil {
+			return nil, err
+		}
+		info.Size = int(size / 1024)
+		if head, err := repo.Storer.Reference(plumbing.HEAD); err == nil && head.Type() == plumbing.SymbolicReference {
+			info.DefaultBranch = head.Target().Short()
+		}
+
+	case SourceBundle, SourceArchive:
+		st, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		info.Size = int(st.Size() / 1024)
+		info.Description = "Git bundle"
+		if kind == SourceArchive {
+			info.Description = "Repository archive"
+		} else if f, err := os.Open(path); err == nil {
+			if b, err := ReadBundle(f); err == nil {
+				if head := b.headTarget(bundleHead(b)); head != "" {
+					info.DefaultBranch = head.Short()
+				}
+			}
+			f.Close()
+		}
+
+	default:
+		return nil, fmt.Errorf("'%s' is not a local repository, bundle or archive", source)
+	}
+	return info, nil
+}
+
+// EstimateLocalIngestTime estimates how long copying a local source takes:
+// about a second per 50 MB, at least one second.
+func EstimateLocalIngestTime(sizeKB int) time.Duration {
+	seconds := sizeKB / (50 * 1024)
+	if seconds < 1 {
+		seconds = 1
+	}
+	if seconds > 300 {
+		seconds = 300
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+func bundleHead(b *Bundle) (plumbing.Hash, bool) {
+	for _, ref := range b.References {
+		if ref.Name() == plumbing.HEAD {
+			return ref.Hash(), true
+		}
+	}
+	return plumbing.ZeroHash, false
+}
+
+// localSourceName derives a display name from a path: the directory name
+// without .git, or the file name without its bundle/archive extension.
+func localSourceName(path string) string {
+	name := filepath.Base(filepath.Clean(path))
+	if name == ".git" {
+		name = filepath.Base(filepath.Dir(filepath.Clean(path)))
+	}
+	for _, ext := range []string{".tar.gz", ".tgz", ".tar", ".bundle", ".git"} {
+		name = strings.TrimSuffix(name, ext)
+	}
+	if name == "" || name == "." || name == string(filepath.Separator) {
+		return "repository"
+	}
+	return name
+}
+
+func dirExists(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+func dirSize(root string) (int64, error) {
+	var total int64
+	err := filepath.WalkDir(root, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			info, err := d.Info()
+			if err != nil {
+				return err
+			}
+			total += info.Size()
+		}
+		return nil
+	})
+	return total, err
+}
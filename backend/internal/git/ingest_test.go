@@ -0,0 +1,236 @@
+package git
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	appconfig "github.com/kurobon/gitgym/backend/internal/config"
+)
+
+func TestIngestSource_Offline(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldRoot, oldIngestRoot := appconfig.Global.DataRoot, appconfig.Global.IngestRoot
+	appconfig.Global.DataRoot = filepath.Join(tmpDir, "data")
+	appconfig.Global.IngestRoot = tmpDir
+	t.Cleanup(func() { appconfig.Global.DataRoot, appconfig.Global.IngestRoot = oldRoot, oldIngestRoot })
+
+	// A non-bare source repository on "trunk" with a tag
+	srcPath := filepath.Join(tmpDir, "project")
+	src, err := gogit.PlainInit(srcPath, false)
+	require.NoError(t, err)
+	require.NoError(t, src.Storer.SetReference(plumbing.NewSymbolicReference(plumbing.HEAD, "refs/heads/trunk")))
+	wt, _ := src.Worktree()
+	require.NoError(t, os.WriteFile(filepath.Join(srcPath, "README.md"), []byte("# Project\n"), 0644))
+	_, _ = wt.Add("README.md")
+	head, err := wt.Commit("Initial commit", &gogit.CommitOptions{Author: &object.Signature{Name: "Dev", Email: "dev@example.com", When: time.Now()}})
+	require.NoError(t, err)
+	_, err = src.CreateTag("v1.0", head, nil)
+	require.NoError(t, err)
+
+	ctx := context.Background()
+	assertIngested := func(t *testing.T, sm *SessionManager, name string) {
+		t.Helper()
+		repo, ok := sm.GetSharedRemote(name)
+		require.True(t, ok)
+		ref, err := repo.Reference(plumbing.NewBranchReferenceName("trunk"), true)
+		require.NoError(t, err)
+		assert.Equal(t, head, ref.Hash())
+		_, err = repo.Reference(plumbing.NewTagReferenceName("v1.0"), true)
+		assert.NoError(t, err)
+		headRef, err := repo.Head()
+		require.NoError(t, err)
+		assert.Equal(t, "trunk", headRef.Name().Short())
+		_, err = repo.CommitObject(head)
+		assert.NoError(t, err)
+	}
+
+	t.Run("Local non-bare path", func(t *testing.T) {
+		sm := NewSessionManager()
+		assert.Equal(t, SourceLocal, DetectIngestSource(srcPath))
+		require.NoError(t, IngestSource(ctx, sm, "local", srcPath, 0))
+		assertIngested(t, sm, "local")
+		_, ok := sm.GetSharedRemote(srcPath)
+		assert.True(t, ok, "the path is registered as an alias")
+	})
+
+	t.Run("Bundle file", func(t *testing.T) {
+		bundlePath := filepath.Join(tmpDir, "project.bundle")
+		f, err := os.Create(bundlePath)
+		require.NoError(t, err)
+		refs := []*plumbing.Reference{
+			plumbing.NewHashReference(plumbing.HEAD, head),
+			plumbing.NewHashReference("refs/heads/trunk", head),
+			plumbing.NewHashReference("refs/tags/v1.0", head),
+		}
+		_, err = WriteBundle(f, src.Storer, refs, nil)
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+
+		sm := NewSessionManager()
+		assert.Equal(t, SourceBundle, DetectIngestSource(bundlePath))
+		require.NoError(t, IngestSource(ctx, sm, "bundled", bundlePath, 0))
+		assertIngested(t, sm, "bundled")
+
+		estimate, err := GetCloneEstimate(bundlePath)
+		require.NoError(t, err)
+		assert.Equal(t, "project", estimate.RepoInfo.Name)
+		assert.Equal(t, "trunk", estimate.RepoInfo.DefaultBranch)
+		assert.Equal(t, 1, estimate.EstimatedSeconds)
+	})
+
+	t.Run("Uploaded tar.gz archive", func(t *testing.T) {
+		archivePath := filepath.Join(tmpDir, "project.tar.gz")
+		writeTarGz(t, archivePath, tmpDir, srcPath)
+
+		sm := NewSessionManager()
+		f, err := os.Open(archivePath)
+		require.NoError(t, err)
+		defer f.Close()
+		require.NoError(t, IngestUpload(ctx, sm, "archived", "project.tar.gz", f))
+		assertIngested(t, sm, "archived")
+	})
+
+	t.Run("Local estimate reads the object store", func(t *testing.T) {
+		estimate, err := GetCloneEstimate(srcPath)
+		require.NoError(t, err)
+		assert.Equal(t, "project", estimate.RepoInfo.Name)
+		assert.Equal(t, "trunk", estimate.RepoInfo.DefaultBranch)
+		assert.Contains(t, estimate.Message, "offline")
+	})
+
+	t.Run("Relative paths resolve against the ingest root", func(t *testing.T) {
+		sm := NewSessionManager()
+		assert.Equal(t, SourceLocal, DetectIngestSource("project"))
+		require.NoError(t, IngestSource(ctx, sm, "relative", "project", 0))
+		assertIngested(t, sm, "relative")
+	})
+
+	t.Run("Paths outside the ingest root are rejected", func(t *testing.T) {
+		outside := t.TempDir()
+		_, err := gogit.PlainInit(filepath.Join(outside, "secret"), true)
+		require.NoError(t, err)
+		inside := filepath.Join(tmpDir, "inside")
+		require.NoError(t, os.MkdirAll(inside, 0750))
+		require.NoError(t, os.Symlink(filepath.Join(outside, "secret"), filepath.Join(inside, "link")))
+		rel, err := filepath.Rel(tmpDir, filepath.Join(outside, "secret"))
+		require.NoError(t, err)
+
+		for _, source := range []string{
+			filepath.Join(outside, "secret"),             // absolute
+			"file://" + filepath.Join(outside, "secret"), // file URL
+			rel,                // ../ from the root
+			"inside/../" + rel, // ../ after cleaning
+			"inside/link",      // symlink out of the root
+		} {
+			err := IngestSource(ctx, NewSessionManager(), "escape", source, 0)
+			if assert.Error(t, err, source) {
+				assert.Contains(t, err.Error(), "outside the ingest root", source)
+			}
+			assert.Equal(t, SourceURL, DetectIngestSource(source), source)
+			_, err = LocalRepoInfo(source)
+			assert.Error(t, err, source)
+		}
+	})
+
+	t.Run(".git files are not followed", func(t *testing.T) {
+		outside := t.TempDir()
+		_, err := gogit.PlainInit(filepath.Join(outside, "secret"), true)
+		require.NoError(t, err)
+		pointer := filepath.Join(tmpDir, "pointer")
+		require.NoError(t, os.MkdirAll(pointer, 0750))
+		require.NoError(t, os.WriteFile(filepath.Join(pointer, ".git"), []byte("gitdir: "+filepath.Join(outside, "secret")+"\n"), 0644))
+
+		err = IngestSource(ctx, NewSessionManager(), "pointer", "pointer", 0)
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "not a git repository")
+		}
+		_, err = LocalRepoInfo("pointer")
+		assert.Error(t, err)
+
+		// The same pointer inside an uploaded archive
+		archivePath := filepath.Join(t.TempDir(), "pointer.tar.gz")
+		writeTarGz(t, archivePath, tmpDir, pointer)
+		f, err := os.Open(archivePath)
+		require.NoError(t, err)
+		defer f.Close()
+		err = IngestUpload(ctx, NewSessionManager(), "pointer", "pointer.tar.gz", f)
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "does not contain a git repository")
+		}
+	})
+
+	t.Run("Archives past the extraction budget are refused", func(t *testing.T) {
+		oldBudget := maxExtractedBytes
+		maxExtractedBytes = 16
+		t.Cleanup(func() { maxExtractedBytes = oldBudget })
+
+		archivePath := filepath.Join(t.TempDir(), "project.tar.gz")
+		writeTarGz(t, archivePath, tmpDir, srcPath)
+		f, err := os.Open(archivePath)
+		require.NoError(t, err)
+		defer f.Close()
+		err = IngestUpload(ctx, NewSessionManager(), "huge", "project.tar.gz", f)
+		if assert.Error(t, err) {
+			assert.Contains(t, err.Error(), "archive expands to more than")
+		}
+	})
+
+	t.Run("Unsupported files are rejected", func(t *testing.T) {
+		notes := filepath.Join(tmpDir, "notes.txt")
+		require.NoError(t, os.WriteFile(notes, []byte("hello"), 0644))
+		assert.Error(t, IngestSource(ctx, NewSessionManager(), "bad", notes, 0))
+	})
+}
+
+// writeTarGz archives dir (with paths relative to base) into path.
+func writeTarGz(t *testing.T, path, base, dir string) {
+	t.Helper()
+	out, err := os.Create(path)
+	require.NoError(t, err)
+	defer out.Close()
+	gz := gzip.NewWriter(out)
+	tw := tar.NewWriter(gz)
+
+	err = filepath.WalkDir(dir, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		hdr, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(base, p)
+		hdr.Name = filepath.ToSlash(rel)
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		if d.Type().IsRegular() {
+			data, err := os.ReadFile(p)
+			if err != nil {
+				return err
+			}
+			_, err = tw.Write(data)
+			return err
+		}
+		return nil
+	})
+	require.NoError(t, err)
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+}
@@ -0,0 +1,133 @@
+package integration_test
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/kurobon/gitgym/backend/internal/git"
+)
+
+func TestForkWorkflow(t *testing.T) {
+	ctx := context.Background()
+	sm := testSessionManager
+
+	maintainer := "fork-maintainer"
+	contributor := "fork-contributor"
+	for _, id := range []string{maintainer, contributor} {
+		if err := InitSession(id); err != nil {
+			t.Fatalf("Failed to init session: %v", err)
+		}
+	}
+	run := func(t *testing.T, sessionID string, args ...string) string {
+		t.Helper()
+		out, err := ExecuteGitCommand(sessionID, args)
+		if err != nil {
+			t.Fatalf("%s failed: %v", strings.Join(args, " "), err)
+		}
+		return out
+	}
+	tip := func(t *testing.T, remote, branch string) plumbing.Hash {
+		t.Helper()
+		repo, ok := sm.GetSharedRemote(remote)
+		if !ok {
+			t.Fatalf("remote %s not found", remote)
+		}
+		ref, err := repo.Reference(plumbing.NewBranchReferenceName(branch), true)
+		if err != nil {
+			t.Fatalf("%s/%s not found: %v", remote, branch, err)
+		}
+		return ref.Hash()
+	}
+
+	// Upstream project with one commit on main
+	session, _ := GetSession(maintainer)
+	if _, err := session.InitRepo(""); err != nil {
+		t.Fatalf("Failed to init repo: %v", err)
+	}
+	_ = TouchFile(maintainer, "README.md")
+	run(t, maintainer, "add", "README.md")
+	run(t, maintainer, "commit", "-m", "Initial commit")
+	if err := sm.CreateBareRepository(ctx, maintainer, "fork-upstream"); err != nil {
+		t.Fatalf("Create bare repo failed: %v", err)
+	}
+	run(t, maintainer, "remote", "add", "origin", "remote://gitgym/fork-upstream.git")
+	run(t, maintainer, "push", "origin", "main")
+
+	// Fork it
+	if err := sm.ForkRemote("fork-upstream", "fork-mine"); err != nil {
+		t.Fatalf("ForkRemote failed: %v", err)
+	}
+	if tip(t, "fork-mine", "main") != tip(t, "fork-upstream", "main") {
+		t.Fatal("fork should start at the upstream's main")
+	}
+	if parent, ok := sm.ForkParent("fork-mine"); !ok || parent != "fork-upstream" {
+		t.Errorf("expected fork parent fork-upstream, got %q", parent)
+	}
+
+	// Contributor clones the fork and adds upstream
+	run(t, contributor, "clone", "remote://gitgym/fork-mine.git")
+	run(t, contributor, "remote", "add", "upstream", "remote://gitgym/fork-upstream.git")
+	run(t, contributor, "switch", "-c", "feature")
+	run(t, contributor, "touch", "feature.txt")
+	run(t, contributor, "add", "feature.txt")
+	run(t, contributor, "commit", "-m", "Add feature")
+
+	// Upstream moves on meanwhile
+	_ = TouchFile(maintainer, "CHANGELOG.md")
+	run(t, maintainer, "add", "CHANGELOG.md")
+	run(t, maintainer, "commit", "-m", "Add changelog")
+	run(t, maintainer, "push", "origin", "main")
+
+	// fetch upstream + rebase, then push the branch to the fork
+	run(t, contributor, "fetch", "upstream")
+	run(t, contributor, "rebase", "upstream/main")
+	run(t, contributor, "push", "origin", "feature")
+
+	// Cross-repository pull request: fork-mine:feature -> fork-upstream:main
+	pr, err := sm.CreateForkPullRequest("Add feature", "", "fork-mine", "feature", "main", "contributor", "fork-upstream")
+	if err != nil {
+		t.Fatalf("CreateForkPullRequest failed: %v", err)
+	}
+	git.RefreshPullRequests(sm, nil)
+	if !pr.Mergeable || pr.AheadBy != 1 || pr.BehindBy != 0 {
+		t.Fatalf("expected mergeable PR 1 ahead / 0 behind, got %s %d/%d (%s)", pr.MergeableState, pr.AheadBy, pr.BehindBy, pr.StatusMessage)
+	}
+
+	out := run(t, maintainer, "merge-pr", strconv.Itoa(pr.ID), "fork-upstream")
+	if !strings.Contains(out, "Successfully merged") {
+		t.Errorf("unexpected merge output: %s", out)
+	}
+	merged, _ := sm.GetSharedRemote("fork-upstream")
+	mergeCommit, err := merged.CommitObject(tip(t, "fork-upstream", "main"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(mergeCommit.Message, "Merge pull request #"+strconv.Itoa(pr.ID)+" from fork-mine/feature") {
+		t.Errorf("unexpected merge message: %q", mergeCommit.Message)
+	}
+	if _, err := mergeCommit.File("feature.txt"); err != nil {
+		t.Errorf("merged tree is missing feature.txt: %v", err)
+	}
+
+	// Sync fork brings the fork's main up to date
+	head, err := sm.SyncFork("fork-mine", "main")
+	if err != nil {
+		t.Fatalf("SyncFork failed: %v", err)
+	}
+	if head != mergeCommit.Hash || tip(t, "fork-mine", "main") != mergeCommit.Hash {
+		t.Errorf("fork main should be at the merge commit")
+	}
+
+	if err := sm.RemoveRemote("fork-upstream"); err == nil || !strings.Contains(err.Error(), "has forks") {
+		t.Errorf("expected upstream removal to be refused while forks exist, got %v", err)
+	}
+	if err := sm.RemoveRemote("fork-mine"); err != nil {
+		t.Fatalf("RemoveRemote fork failed: %v", err)
+	}
+	if err := sm.RemoveRemote("fork-upstream"); err != nil {
+		t.Fatalf("RemoveRemote upstream failed: %v", err)
+	}
+}
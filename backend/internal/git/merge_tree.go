@@ -0,0 +1,95 @@
+package git
+
+// merge_tree.go - Tree-level 3-way Merge for Bare Repositories
+//
+// Merge3Way works on a worktree. Server-side operations (pull request merges on
+// shared remotes) have no worktree, so MergeTrees applies the same per-file
+// rules directly to tree objects and writes the resulting tree to the storer.
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+)
+
+// MergeConflictError lists the paths that could not be merged automatically.
+type MergeConflictError struct {
+	Paths []string
+}
+
+func (e *MergeConflictError) Error() string {
+	return fmt.Sprintf("%s in %s", ErrConflict, strings.Join(e.Paths, ", "))
+}
+
+// Unwrap lets errors.Is(err, ErrConflict) match.
+func (e *MergeConflictError) Unwrap() error {
+	return ErrConflict
+}
+
+// MergeTrees merges the trees of ours and theirs against base (which may be nil
+// for unrelated histories) and stores the merged tree in s.
+// On conflict it returns a *MergeConflictError and no tree.
+func MergeTrees(s storer.EncodedObjectStorer, base, ours, theirs *object.Commit) (plumbing.Hash, error) {
+	baseFiles, err := CommitFiles(base)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	oursFiles, err := CommitFiles(ours)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	theirsFiles, err := CommitFiles(theirs)
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+
+	merged, conflicts := mergeFiles(baseFiles, oursFiles, theirsFiles)
+	if len(conflicts) > 0 {
+		return plumbing.ZeroHash, &MergeConflictError{Paths: conflicts}
+	}
+	return WriteTree(s, merged)
+}
+
+// mergeFiles applies the per-file 3-way rules to flattened trees. It returns
+// the merged files and the sorted paths both sides changed differently.
+func mergeFiles(baseFiles, oursFiles, theirsFiles map[string]TreeFile) (map[string]TreeFile, []string) {
+	paths := make(map[string]struct{})
+	for _, files := range []map[string]TreeFile{baseFiles, oursFiles, theirsFiles} {
+		for p := range files {
+			paths[p] = struct{}{}
+		}
+	}
+
+	merged := make(map[string]TreeFile)
+	var conflicts []string
+	for p := range paths {
+		b, o, t := baseFiles[p], oursFiles[p], theirsFiles[p]
+		switch {
+		case o == t:
+			// Both sides agree (or neither has it)
+			if o.Hash.IsZero() {
+				continue
+			}
+			merged[p] = o
+		case b == o:
+			// Only theirs changed
+			if !t.Hash.IsZero() {
+				merged[p] = t
+			}
+		case b == t:
+			// Only ours changed
+			if !o.Hash.IsZero() {
+				merged[p] = o
+			}
+		default:
+			conflicts = append(conflicts, p)
+		}
+	}
+
+	sort.Strings(conflicts)
+	return merged, conflicts
+}
@@ -0,0 +1,325 @@
+package git
+
+// pull_request_status.go - Pull Request Comparison (mergeability, commits, diff)
+//
+// A pull request's status is derived from the current tips of its base and head
+// branches on the shared remote. Every code path that moves a shared remote ref
+// (push, smart HTTP receive-pack, simulated teammates, PR merges) calls
+// RefreshPullRequests so the PR panel always reflects the latest push.
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/go-git/go-git/v5/utils/merkletrie"
+)
+
+const (
+	prMessageClean     = "This branch has no conflicts with the base branch"
+	prMessageConflicts = "This branch has conflicts that must be resolved"
+	prMessageEmpty     = "There isn't anything to compare."
+
+	// maxPRDiffBytes caps the combined diff kept on a pull request
+	maxPRDiffBytes = 512 * 1024
+)
+
+// RefreshPullRequests recomputes the status of every open pull request whose
+// base or head remote is repo (all open pull requests when repo is nil).
+func RefreshPullRequests(sm *SessionManager, repo *gogit.Repository) {
+	if sm == nil {
+		return
+	}
+
+	var jobs []*PullRequest
+	sm.RLock()
+	for _, pr := range sm.PullRequests {
+		if pr.State != "OPEN" {
+			continue
+		}
+		baseRepo, headRepo := pullRequestRepos(sm, pr)
+		if baseRepo == nil || (repo != nil && baseRepo != repo && headRepo != repo) {
+			continue
+		}
+		jobs = append(jobs, pr)
+	}
+	sm.RUnlock()
+
+	for _, pr := range jobs {
+		if err := SyncPullRequestHead(sm, pr); err != nil {
+			log.Printf("RefreshPullRequests: PR #%d: %v", pr.ID, err)
+		}
+		sm.RLock()
+		baseRepo, _ := pullRequestRepos(sm, pr)
+		sm.RUnlock()
+		if baseRepo == nil {
+			continue
+		}
+
+		status := ComputePullRequestStatus(baseRepo, pr.BaseRef, PullRequestHeadRef(pr))
+		if status.HeadSHA != "" {
+			// The base remote's checks also cover heads pushed to a fork or before a rule existed
+			head := plumbing.NewHash(status.HeadSHA)
+			sm.RunMissingStatusChecks(baseRepo, head)
+			status.Checks = sm.GetCommitStatuses(baseRepo, head)
+			status.ChecksState = CombinedStatus(status.Checks)
+		}
+		sm.Lock()
+		pr.PullRequestStatus = status
+		sm.Unlock()
+	}
+}
+
+// PullRequestHeadRef is the ref in the base remote that holds the head of pr.
+// Cross-repository pull requests have their fork branch mirrored to
+// refs/pull/<id>/head, as hosted servers do.
+func PullRequestHeadRef(pr *PullRequest) plumbing.ReferenceName {
+	if pr.IsCrossRepository() {
+		return plumbing.ReferenceName(fmt.Sprintf("refs/pull/%d/head", pr.ID))
+	}
+	return plumbing.NewBranchReferenceName(pr.HeadRef)
+}
+
+// SyncPullRequestHead copies the fork branch of a cross-repository pull request
+// into the base remote. It is a no-op for same-repository pull requests.
+func SyncPullRequestHead(sm *SessionManager, pr *PullRequest) error {
+	if !pr.IsCrossRepository() {
+		return nil
+	}
+
+	sm.RLock()
+	baseRepo, headRepo := pullRequestRepos(sm, pr)
+	sm.RUnlock()
+	if baseRepo == nil || headRepo == nil {
+		return fmt.Errorf("remote of pull request #%d not found", pr.ID)
+	}
+
+	ref, err := headRepo.Reference(plumbing.NewBranchReferenceName(pr.HeadRef), true)
+	if err != nil {
+		return fmt.Errorf("branch '%s' not found in '%s'", pr.HeadRef, pr.HeadRepo)
+	}
+	if err := CopyCommitRecursive(headRepo, baseRepo, ref.Hash()); err != nil {
+		return fmt.Errorf("failed to copy '%s:%s': %w", pr.HeadRepo, pr.HeadRef, err)
+	}
+	return baseRepo.Storer.SetReference(plumbing.NewHashReference(PullRequestHeadRef(pr), ref.Hash()))
+}
+
+// pullRequestRepos resolves the base and head remotes of a pull request. Caller holds sm's read lock.
+func pullRequestRepos(sm *SessionManager, pr *PullRequest) (base, head *gogit.Repository) {
+	name := pr.RemoteName
+	if name == "" {
+		name = "origin"
+	}
+	base = sm.SharedRemotes[name]
+	if pr.IsCrossRepository() {
+		return base, sm.SharedRemotes[pr.HeadRepo]
+	}
+	return base, base
+}
+
+// ComputePullRequestStatus compares head against base in repo.
+func ComputePullRequestStatus(repo *gogit.Repository, baseBranch string, head plumbing.ReferenceName) PullRequestStatus {
+	status := PullRequestStatus{MergeableState: "unknown"}
+
+	baseCommit, err := refCommit(repo, plumbing.NewBranchReferenceName(baseBranch))
+	if err != nil {
+		status.StatusMessage = fmt.Sprintf("The base branch '%s' could not be found", baseBranch)
+		return status
+	}
+	headCommit, err := refCommit(repo, head)
+	if err != nil {
+		status.StatusMessage = fmt.Sprintf("The head branch '%s' could not be found", head.Short())
+		return status
+	}
+	status.BaseSHA = baseCommit.Hash.String()
+	status.HeadSHA = headCommit.Hash.String()
+
+	ahead, err := CommitsBetween(repo, baseCommit.Hash, headCommit.Hash)
+	if err != nil {
+		log.Printf("ComputePullRequestStatus: failed to list commits: %v", err)
+		return status
+	}
+	behind, err := CommitsBetween(repo, headCommit.Hash, baseCommit.Hash)
+	if err != nil {
+		log.Printf("ComputePullRequestStatus: failed to list base commits: %v", err)
+		return status
+	}
+	status.AheadBy = len(ahead)
+	status.BehindBy = len(behind)
+	for _, c := range ahead {
+		status.Commits = append(status.Commits, toViewCommit(c))
+	}
+
+	var mergeBase *object.Commit
+	if bases, err := baseCommit.MergeBase(headCommit); err == nil && len(bases) > 0 {
+		mergeBase = bases[0]
+	}
+
+	files, diff, err := compareCommits(mergeBase, headCommit)
+	if err != nil {
+		log.Printf("ComputePullRequestStatus: failed to diff: %v", err)
+	} else {
+		status.Files = files
+		status.Diff = diff
+	}
+
+	if len(ahead) == 0 {
+		status.StatusMessage = prMessageEmpty
+		return status
+	}
+
+	// Trial merge into a throwaway overlay so the shared remote stays untouched
+	scratch := NewHybridStorer(memory.NewStorage(), repo.Storer)
+	if _, err := MergeTrees(scratch, mergeBase, baseCommit, headCommit); err != nil {
+		var conflictErr *MergeConflictError
+		if !errors.As(err, &conflictErr) {
+			log.Printf("ComputePullRequestStatus: trial merge failed: %v", err)
+			return status
+		}
+		status.MergeableState = "dirty"
+		status.StatusMessage = prMessageConflicts
+		status.Conflicts = conflictErr.Paths
+		return status
+	}
+
+	status.Mergeable = true
+	status.MergeableState = "clean"
+	status.StatusMessage = prMessageClean
+	return status
+}
+
+// CommitsBetween returns the commits reachable from include but not from
+// exclude (git log exclude..include), parents before children.
+func CommitsBetween(repo *gogit.Repository, exclude, include plumbing.Hash) ([]*object.Commit, error) {
+	excluded := make(map[plumbing.Hash]bool)
+	queue := []plumbing.Hash{exclude}
+	for len(queue) > 0 {
+		h := queue[0]
+		queue = queue[1:]
+		if excluded[h] {
+			continue
+		}
+		excluded[h] = true
+		c, err := repo.CommitObject(h)
+		if err != nil {
+			return nil, err
+		}
+		queue = append(queue, c.ParentHashes...)
+	}
+
+	var result []*object.Commit
+	visited := make(map[plumbing.Hash]bool)
+	var visit func(h plumbing.Hash) error
+	visit = func(h plumbing.Hash) error {
+		if visited[h] || excluded[h] {
+			return nil
+		}
+		visited[h] = true
+		c, err := repo.CommitObject(h)
+		if err != nil {
+			return err
+		}
+		for _, p := range c.ParentHashes {
+			if err := visit(p); err != nil {
+				return err
+			}
+		}
+		result = append(result, c)
+		return nil
+	}
+	if err := visit(include); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+func refCommit(repo *gogit.Repository, name plumbing.ReferenceName) (*object.Commit, error) {
+	ref, err := repo.Reference(name, true)
+	if err != nil {
+		return nil, err
+	}
+	return repo.CommitObject(ref.Hash())
+}
+
+func toViewCommit(c *object.Commit) Commit {
+	vc := Commit{
+		ID:        c.Hash.String(),
+		Message:   c.Message,
+		Timestamp: c.Author.When.Format(time.RFC3339),
+		Author:    c.Author.Name,
+		TreeID:    c.TreeHash.String(),
+	}
+	if len(c.ParentHashes) > 0 {
+		vc.ParentID = c.ParentHashes[0].String()
+	}
+	if len(c.ParentHashes) > 1 {
+		vc.SecondParentID = c.ParentHashes[1].String()
+	}
+	return vc
+}
+
+// compareCommits lists the files changed from base (nil for an empty tree) to
+// head and renders the combined unified diff ("Files changed" of a PR).
+func compareCommits(base, head *object.Commit) ([]PullRequestFile, string, error) {
+	var fromTree *object.Tree
+	if base != nil {
+		t, err := base.Tree()
+		if err != nil {
+			return nil, "", err
+		}
+		fromTree = t
+	}
+	toTree, err := head.Tree()
+	if err != nil {
+		return nil, "", err
+	}
+
+	changes, err := object.DiffTree(fromTree, toTree)
+	if err != nil {
+		return nil, "", err
+	}
+	patch, err := changes.Patch()
+	if err != nil {
+		return nil, "", err
+	}
+
+	stats := make(map[string]object.FileStat)
+	for _, st := range patch.Stats() {
+		stats[st.Name] = st
+	}
+
+	files := make([]PullRequestFile, 0, len(changes))
+	for _, ch := range changes {
+		action, err := ch.Action()
+		if err != nil {
+			return nil, "", err
+		}
+		name := ch.To.Name
+		status := "modified"
+		switch action {
+		case merkletrie.Insert:
+			status = "added"
+		case merkletrie.Delete:
+			status = "removed"
+			name = ch.From.Name
+		}
+		st := stats[name]
+		files = append(files, PullRequestFile{
+			Filename:  name,
+			Status:    status,
+			Additions: st.Addition,
+			Deletions: st.Deletion,
+		})
+	}
+
+	diff := patch.String()
+	if len(diff) > maxPRDiffBytes {
+		diff = diff[:maxPRDiffBytes] + "\n... diff truncated ...\n"
+	}
+	return files, diff, nil
+}
@@ -0,0 +1,101 @@
+package git
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+func TestRefreshPullRequests(t *testing.T) {
+	sm := NewSessionManager()
+	repo, err := gogit.Init(memory.NewStorage(), memfs.New())
+	if err != nil {
+		t.Fatal(err)
+	}
+	w, _ := repo.Worktree()
+
+	write := func(name, content, msg string) {
+		f, _ := w.Filesystem.Create(name)
+		f.Write([]byte(content))
+		f.Close()
+		w.Add(name)
+		if _, err := w.Commit(msg, &gogit.CommitOptions{
+			Author: &object.Signature{Name: "Dev", Email: "dev@example.com", When: time.Now()},
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	checkout := func(branch string, create bool) {
+		if err := w.Checkout(&gogit.CheckoutOptions{Branch: plumbing.NewBranchReferenceName(branch), Create: create}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("README.md", "line1\nline2\n", "Initial commit")
+	checkout("feature", true)
+	write("README.md", "line1\nfeature\n", "Edit readme")
+	write("new.txt", "new\n", "Add new file")
+	checkout("master", false)
+	write("other.txt", "other\n", "Unrelated work")
+
+	sm.SharedRemotes["origin"] = repo
+	pr, err := sm.CreatePullRequest("Feature", "", "feature", "master", "Dev", "origin")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	RefreshPullRequests(sm, repo)
+
+	if !pr.Mergeable || pr.MergeableState != "clean" {
+		t.Fatalf("expected clean PR, got %q (%s)", pr.MergeableState, pr.StatusMessage)
+	}
+	if pr.AheadBy != 2 || pr.BehindBy != 1 {
+		t.Errorf("expected ahead 2 / behind 1, got %d / %d", pr.AheadBy, pr.BehindBy)
+	}
+	if len(pr.Commits) != 2 || !strings.HasPrefix(pr.Commits[0].Message, "Edit readme") {
+		t.Errorf("expected commits oldest first, got %+v", pr.Commits)
+	}
+	if len(pr.Files) != 2 {
+		t.Fatalf("expected 2 changed files, got %+v", pr.Files)
+	}
+	for _, f := range pr.Files {
+		switch f.Filename {
+		case "README.md":
+			if f.Status != "modified" || f.Additions != 1 || f.Deletions != 1 {
+				t.Errorf("unexpected README.md entry: %+v", f)
+			}
+		case "new.txt":
+			if f.Status != "added" || f.Additions != 1 {
+				t.Errorf("unexpected new.txt entry: %+v", f)
+			}
+		default:
+			t.Errorf("unexpected file %s (base-only changes must not be listed)", f.Filename)
+		}
+	}
+	if !strings.Contains(pr.Diff, "+feature") {
+		t.Errorf("expected combined diff to contain the change, got:\n%s", pr.Diff)
+	}
+
+	// Someone pushes a conflicting change to the base branch
+	write("README.md", "line1\nmaster\n", "Conflicting edit")
+	RefreshPullRequests(sm, repo)
+
+	if pr.Mergeable || pr.MergeableState != "dirty" {
+		t.Fatalf("expected dirty PR, got %q", pr.MergeableState)
+	}
+	if pr.StatusMessage != "This branch has conflicts that must be resolved" {
+		t.Errorf("unexpected status message: %q", pr.StatusMessage)
+	}
+	if len(pr.Conflicts) != 1 || pr.Conflicts[0] != "README.md" {
+		t.Errorf("expected README.md conflict, got %v", pr.Conflicts)
+	}
+	if pr.BehindBy != 2 {
+		t.Errorf("expected behind 2, got %d", pr.BehindBy)
+	}
+}
@@ -0,0 +1,358 @@
+package git
+
+// remote_commit.go - Commits Written Straight into a Shared Remote
+//
+// Scenarios need "a teammate pushed X" without a working copy. WriteRemoteCommit
+// builds the new tree from the parent commit(s), applies file edits, and moves
+// the branch (and optionally a tag) inside the shared repository's object
+// store, subject to the same branch protection as a real push.
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"path"
+	"strings"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// File change operations
+const (
+	FileWrite  = "write"  // Create or overwrite Path with Content
+	FileAppend = "append" // Append Content to Path, creating it if missing
+	FileDelete = "delete" // Remove Path
+	FileRename = "rename" // Move Path to To
+)
+
+// FileChange is one edit applied to the tree of a simulated commit.
+type FileChange struct {
+	Op      string `json:"op,omitempty"` // One of the File* constants; defaults to "write"
+	Path    string `json:"path"`
+	Content string `json:"content,omitempty"` // write, append
+	To      string `json:"to,omitempty"`      // rename
+}
+
+// RemoteCommit describes a commit made on a shared remote on a teammate's behalf.
+type RemoteCommit struct {
+	Branch     string       `json:"branch,omitempty"`  // Defaults to the branch HEAD points to; created if missing
+	Parents    []string     `json:"parents,omitempty"` // Revisions; defaults to the branch tip, or HEAD for a new branch
+	Merge      []string     `json:"merge,omitempty"`   // Revisions merged in as additional parents
+	Changes    []FileChange `json:"changes,omitempty"`
+	Message    string       `json:"message"`
+	Author     string       `json:"author,omitempty"`
+	Email      string       `json:"email,omitempty"`
+	Date       time.Time    `json:"date,omitempty"`       // Defaults to now
+	Tag        string       `json:"tag,omitempty"`        // Optional tag pointing at the new commit
+	TagMessage string       `json:"tagMessage,omitempty"` // Makes Tag an annotated tag
+}
+
+// WriteRemoteCommit creates the commit described by spec in repo, moves its
+// branch and returns the commit hash and the branch reference.
+// With several parents the trees are 3-way merged; conflicting paths must be
+// resolved by a write or delete in Changes. Without changes or extra parents a
+// placeholder file is added so the commit is never empty.
+// Caller holds sm's write lock, and runs CI, push triggers and the pull
+// request refresh for the new commit after releasing it.
+func WriteRemoteCommit(sm *SessionManager, repo *gogit.Repository, spec *RemoteCommit) (plumbing.Hash, plumbing.ReferenceName, error) {
+	if strings.TrimSpace(spec.Message) == "" {
+		return plumbing.ZeroHash, "", fmt.Errorf("commit message required")
+	}
+
+	refName, err := remoteCommitBranch(repo, spec.Branch)
+	if err != nil {
+		return plumbing.ZeroHash, "", err
+	}
+	oldHash := plumbing.ZeroHash
+	if ref, err := repo.Reference(refName, true); err == nil {
+		oldHash = ref.Hash()
+	}
+
+	parentRevs := spec.Parents
+	if len(parentRevs) == 0 {
+		if !oldHash.IsZero() {
+			parentRevs = []string{oldHash.String()}
+		} else if head, err := repo.Head(); err == nil {
+			parentRevs = []string{head.Hash().String()}
+		}
+	}
+	parentRevs = append(append([]string(nil), parentRevs...), spec.Merge...)
+
+	var parents []*object.Commit
+	for _, rev := range parentRevs {
+		hash, err := repo.ResolveRevision(plumbing.Revision(rev))
+		if err != nil {
+			return plumbing.ZeroHash, "", fmt.Errorf("revision '%s' not found", rev)
+		}
+		c, err := repo.CommitObject(*hash)
+		if err != nil {
+			return plumbing.ZeroHash, "", fmt.Errorf("revision '%s' is not a commit", rev)
+		}
+		parents = append(parents, c)
+	}
+
+	sig := remoteCommitSignature(spec)
+	changes := spec.Changes
+	if len(changes) == 0 && len(parents) < 2 {
+		changes = []FileChange{{
+			Path:    fmt.Sprintf("simulated_%d.txt", sig.When.Unix()),
+			Content: "Simulated content",
+		}}
+	}
+
+	files, conflicts, err := mergeParentTrees(parents)
+	if err != nil {
+		return plumbing.ZeroHash, "", err
+	}
+	resolved, err := applyFileChanges(repo, files, changes)
+	if err != nil {
+		return plumbing.ZeroHash, "", err
+	}
+	var unresolved []string
+	for _, p := range conflicts {
+		if !resolved[p] {
+			unresolved = append(unresolved, p)
+		}
+	}
+	if len(unresolved) > 0 {
+		return plumbing.ZeroHash, "", &MergeConflictError{Paths: unresolved}
+	}
+
+	treeHash, err := WriteTree(repo.Storer, files)
+	if err != nil {
+		return plumbing.ZeroHash, "", err
+	}
+
+	commit := &object.Commit{Author: sig, Committer: sig, Message: spec.Message, TreeHash: treeHash}
+	for _, p := range parents {
+		commit.ParentHashes = append(commit.ParentHashes, p.Hash)
+	}
+	hash, err := StoreObject(repo, commit)
+	if err != nil {
+		return plumbing.ZeroHash, "", err
+	}
+
+	updates := []*plumbing.Reference{plumbing.NewHashReference(refName, hash)}
+	if spec.Tag != "" {
+		tagRef, err := remoteCommitTag(repo, spec, hash, sig)
+		if err != nil {
+			return plumbing.ZeroHash, "", err
+		}
+		updates = append(updates, tagRef)
+	}
+	for _, ref := range updates {
+		old := plumbing.ZeroHash
+		if ref.Name() == refName {
+			old = oldHash
+		}
+		if err := sm.CheckRefUpdateLocked(repo, RefUpdate{Name: ref.Name(), Old: old, New: ref.Hash(), Pusher: sig}); err != nil {
+			var pe *ProtectionError
+			if errors.As(err, &pe) {
+				return plumbing.ZeroHash, "", fmt.Errorf("%w: %s", pe, strings.Join(pe.Reasons, " "))
+			}
+			return plumbing.ZeroHash, "", err
+		}
+	}
+	for _, ref := range updates {
+		if err := repo.Storer.SetReference(ref); err != nil {
+			return plumbing.ZeroHash, "", err
+		}
+	}
+
+	return hash, refName, nil
+}
+
+// remoteCommitBranch returns the branch a simulated commit goes to: branch, or
+// the branch HEAD points to (even when it has no commits yet).
+func remoteCommitBranch(repo *gogit.Repository, branch string) (plumbing.ReferenceName, error) {
+	if branch != "" {
+		refName := plumbing.NewBranchReferenceName(strings.TrimPrefix(branch, "refs/heads/"))
+		if err := refName.Validate(); err != nil {
+			return "", fmt.Errorf("invalid branch name '%s'", branch)
+		}
+		return refName, nil
+	}
+	if head, err := repo.Storer.Reference(plumbing.HEAD); err == nil && head.Type() == plumbing.SymbolicReference {
+		return head.Target(), nil
+	}
+	return plumbing.NewBranchReferenceName("main"), nil
+}
+
+// mergeParentTrees flattens the first parent's tree and merges every further
+// parent into it against their merge base. Conflicting paths keep the first
+// parent's version and are returned so the caller can insist on a resolution.
+func mergeParentTrees(parents []*object.Commit) (map[string]TreeFile, []string, error) {
+	if len(parents) == 0 {
+		return make(map[string]TreeFile), nil, nil
+	}
+	files, err := CommitFiles(parents[0])
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var conflicts []string
+	for _, other := range parents[1:] {
+		var base *object.Commit
+		if bases, err := parents[0].MergeBase(other); err == nil && len(bases) > 0 {
+			base = bases[0]
+		}
+		baseFiles, err := CommitFiles(base)
+		if err != nil {
+			return nil, nil, err
+		}
+		otherFiles, err := CommitFiles(other)
+		if err != nil {
+			return nil, nil, err
+		}
+		merged, paths := mergeFiles(baseFiles, files, otherFiles)
+		for _, p := range paths {
+			if f, ok := files[p]; ok {
+				merged[p] = f
+			}
+		}
+		files = merged
+		conflicts = append(conflicts, paths...)
+	}
+	return files, conflicts, nil
+}
+
+// applyFileChanges edits files in place, storing new blobs in repo, and
+// returns the set of paths the changes wrote or removed.
+func applyFileChanges(repo *gogit.Repository, files map[string]TreeFile, changes []FileChange) (map[string]bool, error) {
+	touched := make(map[string]bool)
+	for _, change := range changes {
+		p, err := cleanChangePath(change.Path)
+		if err != nil {
+			return nil, err
+		}
+
+		switch change.Op {
+		case "", FileWrite, FileAppend:
+			content := change.Content
+			if change.Op == FileAppend {
+				if existing, ok := files[p]; ok {
+					old, err := blobContents(repo, existing.Hash)
+					if err != nil {
+						return nil, err
+					}
+					if old != "" && !strings.HasSuffix(old, "\n") {
+						old += "\n"
+					}
+					content = old + content
+				}
+			}
+			blob, err := StoreBlob(repo, content)
+			if err != nil {
+				return nil, err
+			}
+			mode := filemode.Regular
+			if existing, ok := files[p]; ok {
+				mode = existing.Mode
+			}
+			files[p] = TreeFile{Hash: blob, Mode: mode}
+
+		case FileDelete:
+			if _, ok := files[p]; !ok {
+				return nil, fmt.Errorf("cannot delete '%s': no such file", p)
+			}
+			delete(files, p)
+
+		case FileRename:
+			to, err := cleanChangePath(change.To)
+			if err != nil {
+				return nil, err
+			}
+			f, ok := files[p]
+			if !ok {
+				return nil, fmt.Errorf("cannot rename '%s': no such file", p)
+			}
+			delete(files, p)
+			files[to] = f
+			touched[to] = true
+
+		default:
+			return nil, fmt.Errorf("unknown file change '%s' (expected write, append, delete or rename)", change.Op)
+		}
+		touched[p] = true
+	}
+
+	// A file and a directory cannot share a path
+	for p := range files {
+		for dir := path.Dir(p); dir != "."; dir = path.Dir(dir) {
+			if _, ok := files[dir]; ok {
+				return nil, fmt.Errorf("'%s' is a file, cannot create '%s'", dir, p)
+			}
+		}
+	}
+	return touched, nil
+}
+
+// cleanChangePath normalises a repository-relative path and rejects paths
+// that escape the tree.
+func cleanChangePath(p string) (string, error) {
+	clean := path.Clean(strings.TrimPrefix(p, "/"))
+	if p == "" || clean == "." || clean == ".." || strings.HasPrefix(clean, "../") || clean == ".git" || strings.HasPrefix(clean, ".git/") {
+		return "", fmt.Errorf("invalid path '%s'", p)
+	}
+	return clean, nil
+}
+
+func remoteCommitSignature(spec *RemoteCommit) object.Signature {
+	sig := object.Signature{Name: spec.Author, Email: spec.Email, When: spec.Date}
+	if sig.Name == "" {
+		sig.Name = "Simulated User"
+	}
+	if sig.Email == "" {
+		sig.Email = "simulated@example.com"
+	}
+	if sig.When.IsZero() {
+		sig.When = time.Now()
+	}
+	return sig
+}
+
+// remoteCommitTag builds the tag reference for spec, storing a tag object
+// first when the tag is annotated.
+func remoteCommitTag(repo *gogit.Repository, spec *RemoteCommit, commit plumbing.Hash, tagger object.Signature) (*plumbing.Reference, error) {
+	refName := plumbing.NewTagReferenceName(spec.Tag)
+	if err := refName.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid tag name '%s'", spec.Tag)
+	}
+	if _, err := repo.Reference(refName, false); err == nil {
+		return nil, fmt.Errorf("tag '%s' already exists", spec.Tag)
+	}
+	if spec.TagMessage == "" {
+		return plumbing.NewHashReference(refName, commit), nil
+	}
+
+	tag := &object.Tag{
+		Name:       spec.Tag,
+		Tagger:     tagger,
+		Message:    spec.TagMessage,
+		TargetType: plumbing.CommitObject,
+		Target:     commit,
+	}
+	hash, err := StoreObject(repo, tag)
+	if err != nil {
+		return nil, err
+	}
+	return plumbing.NewHashReference(refName, hash), nil
+}
+
+func blobContents(repo *gogit.Repository, hash plumbing.Hash) (string, error) {
+	blob, err := repo.BlobObject(hash)
+	if err != nil {
+		return "", err
+	}
+	r, err := blob.Reader()
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	return string(data), err
+}
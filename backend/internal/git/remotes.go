@@ -0,0 +1,100 @@
+package git
+
+// remotes.go - Session Remotes
+//
+// A session repository can point several remotes (origin, upstream, a
+// teammate's fork) at different shared remotes at once. These helpers resolve
+// a remote's URL to the repository it simulates and pick the remote a command
+// talks to when none is named, so fetch and push work on each independently.
+
+import (
+	"fmt"
+	"strings"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+)
+
+// ResolveRemoteURL returns the repository a remote URL simulates: a repository
+// of the session, a shared remote registered under the URL (its name,
+// pseudo-URL or path), or a repository on the server's disk.
+func ResolveRemoteURL(s *Session, url string) (*gogit.Repository, error) {
+	lookupKey := strings.TrimPrefix(url, "/")
+
+	if repo, ok := s.Repos[lookupKey]; ok {
+		return repo, nil
+	}
+	if s.Manager != nil {
+		if repo, ok := s.Manager.GetSharedRemote(lookupKey); ok {
+			return repo, nil
+		}
+		if repo, ok := s.Manager.GetSharedRemote(url); ok {
+			return repo, nil
+		}
+	}
+
+	// Persistent remotes addressed by their directory
+	if repo, err := gogit.PlainOpen(url); err == nil {
+		return repo, nil
+	}
+	if repo, err := gogit.PlainOpen(lookupKey); err == nil {
+		return repo, nil
+	}
+	return nil, fmt.Errorf("remote repository '%s' not found (only local simulation supported)", url)
+}
+
+// ValidateRemoteName reports whether name can be used for a remote, i.e. whether
+// refs/remotes/<name>/ is a valid ref prefix.
+func ValidateRemoteName(name string) error {
+	if name == "" || strings.HasPrefix(name, "-") || plumbing.ReferenceName("refs/remotes/"+name+"/HEAD").Validate() != nil {
+		return fmt.Errorf("fatal: '%s' is not a valid remote name", name)
+	}
+	return nil
+}
+
+// DefaultRemote returns the remote of the current branch's upstream
+// (branch.<name>.remote), or "origin" like git when it has none.
+func DefaultRemote(repo *gogit.Repository) string {
+	if head, err := repo.Head(); err == nil && head.Name().IsBranch() {
+		if remote, _, ok := BranchUpstream(repo, head.Name().Short()); ok {
+			return remote
+		}
+	}
+	return "origin"
+}
+
+// BranchUpstream returns the remote and remote branch configured as the
+// upstream of branch.
+func BranchUpstream(repo *gogit.Repository, branch string) (remote string, merge plumbing.ReferenceName, ok bool) {
+	cfg, err := repo.Config()
+	if err != nil {
+		return "", "", false
+	}
+	b, exists := cfg.Branches[branch]
+	if !exists || b.Remote == "" || b.Merge == "" {
+		return "", "", false
+	}
+	return b.Remote, b.Merge, true
+}
+
+// SetBranchUpstream makes branch track merge (a branch name on the remote) of remote.
+func SetBranchUpstream(repo *gogit.Repository, branch, remote string, merge plumbing.ReferenceName) error {
+	cfg, err := repo.Config()
+	if err != nil {
+		return err
+	}
+	b, exists := cfg.Branches[branch]
+	if !exists {
+		b = &config.Branch{Name: branch}
+		cfg.Branches[branch] = b
+	}
+	b.Remote = remote
+	b.Merge = merge
+	return repo.Storer.SetConfig(cfg)
+}
+
+// RemoteTrackingRef returns the remote-tracking ref a branch of remote is fetched into.
+func RemoteTrackingRef(remote string, branch plumbing.ReferenceName) plumbing.ReferenceName {
+	return plumbing.NewRemoteReferenceName(remote, branch.Short())
+}
@@ -1,6 +1,12 @@
 package git
 
 import (
+	"context"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
 	"github.com/kurobon/gitgym/backend/internal/state"
 )
 
@@ -12,9 +18,101 @@ type SessionManager = state.SessionManager
 type ReflogEntry = state.ReflogEntry
 type Commit = state.Commit
 type PullRequest = state.PullRequest
+type PullRequestStatus = state.PullRequestStatus
+type PullRequestFile = state.PullRequestFile
+type Review = state.Review
+type ReviewComment = state.ReviewComment
+type DraftReviewComment = state.DraftReviewComment
+type BranchProtection = state.BranchProtection
+type RefUpdate = state.RefUpdate
+type ProtectionError = state.ProtectionError
+type StatusCheck = state.StatusCheck
+type CommitStatus = state.CommitStatus
+type IngestJob = state.IngestJob
+type IngestProgress = state.IngestProgress
+type TreeFile = state.TreeFile
+
+// Ingest job states
+const (
+	IngestRunning   = state.IngestRunning
+	IngestSucceeded = state.IngestSucceeded
+	IngestFailed    = state.IngestFailed
+	IngestCanceled  = state.IngestCanceled
+)
+
+// Review states
+const (
+	ReviewApproved         = state.ReviewApproved
+	ReviewChangesRequested = state.ReviewChangesRequested
+	ReviewCommented        = state.ReviewCommented
+)
+
+// Commit status states
+const (
+	StatusPending = state.StatusPending
+	StatusSuccess = state.StatusSuccess
+	StatusFailure = state.StatusFailure
+	StatusError   = state.StatusError
+)
+
+// CombinedStatus folds commit statuses into one state
+// Wrapper around state.CombinedStatus
+func CombinedStatus(statuses []*CommitStatus) string {
+	return state.CombinedStatus(statuses)
+}
+
+// StoreObject encodes a commit, tree or tag into repo's object store
+// Wrapper around state.StoreObject
+func StoreObject(repo *gogit.Repository, o interface {
+	Encode(plumbing.EncodedObject) error
+}) (plumbing.Hash, error) {
+	return state.StoreObject(repo, o)
+}
+
+// StoreBlob writes content as a blob into repo's object store
+// Wrapper around state.StoreBlob
+func StoreBlob(repo *gogit.Repository, content string) (plumbing.Hash, error) {
+	return state.StoreBlob(repo, content)
+}
+
+// WriteTree stores a nested tree built from a flat path -> file map
+// Wrapper around state.WriteTree
+func WriteTree(s storer.EncodedObjectStorer, files map[string]TreeFile) (plumbing.Hash, error) {
+	return state.WriteTree(s, files)
+}
+
+// CommitFiles flattens the tree of a commit into path -> file
+// Wrapper around state.CommitFiles
+func CommitFiles(c *object.Commit) (map[string]TreeFile, error) {
+	return state.CommitFiles(c)
+}
+
+// DefaultNotesRef is where git notes are kept unless --ref says otherwise
+const DefaultNotesRef = state.DefaultNotesRef
+
+// ReadNotes maps each annotated object to its note blob
+// Wrapper around state.ReadNotes
+func ReadNotes(repo *gogit.Repository, ref plumbing.ReferenceName) (map[plumbing.Hash]plumbing.Hash, error) {
+	return state.ReadNotes(repo, ref)
+}
+
+// NoteText returns the contents of a note blob
+// Wrapper around state.NoteText
+func NoteText(repo *gogit.Repository, blob plumbing.Hash) (string, error) {
+	return state.NoteText(repo, blob)
+}
 
 // NewSessionManager creates a new session manager
 // Wrapper around state.NewSessionManager
 func NewSessionManager() *SessionManager {
-	return state.NewSessionManager()
+	sm := state.NewSessionManager()
+	// Teammate bots move shared branches too; keep open PRs in sync
+	sm.Bots.AfterCommit = func(repo *gogit.Repository) {
+		RefreshPullRequests(sm, repo)
+	}
+	// Background ingests accept every source kind, not only URLs
+	sm.Ingests.Run = func(ctx context.Context, name, source string, depth int) error {
+		return IngestSource(ctx, sm, name, source, depth)
+	}
+	return sm
 }
@@ -0,0 +1,140 @@
+package git
+
+// shallow.go - Shallow History
+//
+// Shallow clones and fetches (--depth, --shallow-since, --deepen) copy only
+// the recent part of a remote's history. The commits at the cut are recorded
+// in the repository's shallow list (.git/shallow); their parents are absent,
+// and git shows them as "grafted" root commits. --unshallow copies the rest.
+
+import (
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// ShallowLimit bounds the history CopyShallowHistory copies. The zero value
+// copies everything.
+type ShallowLimit struct {
+	Depth int       // Commits per line of history, counting the tip (1 = tips only); 0 = no limit
+	Since time.Time // Oldest committer date to copy; tips are always copied
+}
+
+// CopyShallowHistory copies the commits reachable from tips within limit,
+// with their trees, then refreshes dst's shallow list. Annotated tags among
+// the tips are copied with their targets.
+func CopyShallowHistory(src, dst *gogit.Repository, tips []plumbing.Hash, limit ShallowLimit) error {
+	type entry struct {
+		hash plumbing.Hash
+		gen  int
+	}
+	var queue []entry
+	for _, tip := range tips {
+		commit, ok := PeelToCommit(src, tip)
+		if !ok {
+			continue
+		}
+		queue = append(queue, entry{commit, 1})
+	}
+
+	// Breadth-first, so each commit is first reached at its shortest distance
+	seen := make(map[plumbing.Hash]bool)
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		if seen[current.hash] {
+			continue
+		}
+		seen[current.hash] = true
+
+		obj, err := src.Storer.EncodedObject(plumbing.CommitObject, current.hash)
+		if err != nil {
+			return err
+		}
+		commit, err := object.DecodeCommit(src.Storer, obj)
+		if err != nil {
+			return err
+		}
+		if current.gen > 1 && !limit.Since.IsZero() && commit.Committer.When.Before(limit.Since) {
+			continue
+		}
+		if !HasObject(dst, current.hash) {
+			if _, err := dst.Storer.SetEncodedObject(obj); err != nil {
+				return err
+			}
+		}
+		if err := CopyTreeRecursive(src, dst, commit.TreeHash); err != nil {
+			return err
+		}
+		if limit.Depth == 0 || current.gen < limit.Depth {
+			for _, p := range commit.ParentHashes {
+				queue = append(queue, entry{p, current.gen + 1})
+			}
+		}
+	}
+
+	for _, tip := range tips {
+		if obj, err := src.Storer.EncodedObject(plumbing.TagObject, tip); err == nil {
+			if err := CopyObjectRecursive(src, dst, obj.Hash()); err != nil {
+				return err
+			}
+		}
+	}
+	return UpdateShallow(dst)
+}
+
+// UpdateShallow rewrites the shallow list of repo: every commit whose parents
+// are not all present locally.
+func UpdateShallow(repo *gogit.Repository) error {
+	iter, err := repo.CommitObjects()
+	if err != nil {
+		return err
+	}
+	var shallow []plumbing.Hash
+	err = iter.ForEach(func(c *object.Commit) error {
+		for _, p := range c.ParentHashes {
+			if !HasObject(repo, p) {
+				shallow = append(shallow, c.Hash)
+				break
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	if len(shallow) == 0 && len(ShallowCommits(repo)) == 0 {
+		return nil // Complete history: no shallow file
+	}
+	return repo.Storer.SetShallow(shallow)
+}
+
+// ShallowCommits returns the grafted commits of a shallow repository, or nil
+// when its history is complete.
+func ShallowCommits(repo *gogit.Repository) []plumbing.Hash {
+	shallow, err := repo.Storer.Shallow()
+	if err != nil {
+		return nil
+	}
+	return shallow
+}
+
+// GraftedParents returns the missing parents of the grafted commits, for
+// history walks that must stop at the shallow boundary.
+func GraftedParents(repo *gogit.Repository) []plumbing.Hash {
+	var parents []plumbing.Hash
+	for _, hash := range ShallowCommits(repo) {
+		commit, err := repo.CommitObject(hash)
+		if err != nil {
+			continue
+		}
+		for _, p := range commit.ParentHashes {
+			if !HasObject(repo, p) {
+				parents = append(parents, p)
+			}
+		}
+	}
+	return parents
+}
@@ -1,6 +1,8 @@
 package git
 
 import (
+	"fmt"
+
 	gogit "github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
 	"github.com/go-git/go-git/v5/plumbing/object"
@@ -128,3 +130,56 @@ func IsFastForward(repo *gogit.Repository, oldHash, newHash plumbing.Hash) (bool
 
 	return false, nil
 }
+
+// CopyObjectRecursive copies the object a ref points to and everything it
+// references: annotated tags are copied with their target.
+func CopyObjectRecursive(src, dst *gogit.Repository, hash plumbing.Hash) error {
+	obj, err := src.Storer.EncodedObject(plumbing.AnyObject, hash)
+	if err != nil {
+		return err
+	}
+	switch obj.Type() {
+	case plumbing.CommitObject:
+		return CopyCommitRecursive(src, dst, hash)
+	case plumbing.TreeObject:
+		return CopyTreeRecursive(src, dst, hash)
+	case plumbing.BlobObject:
+		return CopyBlob(src, dst, hash)
+	case plumbing.TagObject:
+		tag, err := object.DecodeTag(src.Storer, obj)
+		if err != nil {
+			return err
+		}
+		if err := CopyObjectRecursive(src, dst, tag.Target); err != nil {
+			return err
+		}
+		if HasObject(dst, hash) {
+			return nil
+		}
+		_, err = dst.Storer.SetEncodedObject(obj)
+		return err
+	}
+	return fmt.Errorf("unsupported object type: %s", obj.Type())
+}
+
+// PeelToCommit returns the commit a hash points to, following annotated tags.
+func PeelToCommit(repo *gogit.Repository, hash plumbing.Hash) (plumbing.Hash, bool) {
+	for {
+		obj, err := repo.Storer.EncodedObject(plumbing.AnyObject, hash)
+		if err != nil {
+			return plumbing.ZeroHash, false
+		}
+		switch obj.Type() {
+		case plumbing.CommitObject:
+			return hash, true
+		case plumbing.TagObject:
+			tag, err := object.DecodeTag(repo.Storer, obj)
+			if err != nil {
+				return plumbing.ZeroHash, false
+			}
+			hash = tag.Target
+		default:
+			return plumbing.ZeroHash, false
+		}
+	}
+}
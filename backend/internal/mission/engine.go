@@ -6,6 +6,7 @@ import (
 	"io"
 	"os"
 	"strings"
+	"time"
 
 	gogit "github.com/go-git/go-git/v5"
 	"github.com/go-git/go-git/v5/plumbing"
@@ -73,6 +74,13 @@ func (e *Engine) StartMission(ctx context.Context, missionID string) (string, er
 	_ = sess.Filesystem.MkdirAll("/project", 0755)
 	sess.CurrentDir = "/project"
 
+	// A fresh virtual clock, so every start of the mission yields the same commit IDs
+	if m.Deterministic {
+		sess.SetDeterministic(state.NewVirtualClock(time.Time{}, 0))
+	} else {
+		sess.SetDeterministic(nil)
+	}
+
 	// 2. Run Setup Commands
 	for _, cmdStr := range m.Setup {
 		ignoreError := false
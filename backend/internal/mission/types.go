@@ -2,16 +2,17 @@ package mission
 
 // Mission defines the structure of a practice mission loaded from YAML.
 type Mission struct {
-	ID           string                        `yaml:"id" json:"id"`
-	Title        string                        `yaml:"title" json:"title"`
-	Description  string                        `yaml:"description" json:"description"`
-	Difficulty   Difficulty                    `yaml:"difficulty" json:"difficulty"`
-	Skill        string                        `yaml:"skill" json:"skill"`
-	Setup        []string                      `yaml:"setup" json:"-"`         // Commands to run for setup
-	Validation   Validation                    `yaml:"validation" json:"-"`    // Validation rules
-	Hints        []string                      `yaml:"hints" json:"hints"`     // Hints for the user
-	Scoring      Scoring                       `yaml:"scoring" json:"scoring"` // Scoring rules
-	Translations map[string]MissionTranslation `yaml:"translations" json:"-"`  // Localized content
+	ID            string                        `yaml:"id" json:"id"`
+	Title         string                        `yaml:"title" json:"title"`
+	Description   string                        `yaml:"description" json:"description"`
+	Difficulty    Difficulty                    `yaml:"difficulty" json:"difficulty"`
+	Skill         string                        `yaml:"skill" json:"skill"`
+	Setup         []string                      `yaml:"setup" json:"-"`                     // Commands to run for setup
+	Deterministic bool                          `yaml:"deterministic" json:"deterministic"` // Stamp commits from a virtual clock so commit IDs are reproducible
+	Validation    Validation                    `yaml:"validation" json:"-"`                // Validation rules
+	Hints         []string                      `yaml:"hints" json:"hints"`                 // Hints for the user
+	Scoring       Scoring                       `yaml:"scoring" json:"scoring"`             // Scoring rules
+	Translations  map[string]MissionTranslation `yaml:"translations" json:"-"`              // Localized content
 }
 
 type MissionTranslation struct {
@@ -26,6 +26,7 @@ func NewServer(sm *git.SessionManager, me *mission.Engine) *Server {
 func (s *Server) routes() {
 	s.Mux.HandleFunc("/ping", s.handlePing)
 	s.Mux.HandleFunc("/api/session/init", s.handleInitSession)
+	s.Mux.HandleFunc("/api/session/deterministic", s.handleDeterministicMode)
 	s.Mux.HandleFunc("/api/command", s.handleExecCommand)
 	s.Mux.HandleFunc("/api/state", s.handleGetGraphState)
 	s.Mux.HandleFunc("/api/remote/state", s.handleGetRemoteState)
@@ -33,15 +34,35 @@ func (s *Server) routes() {
 
 	// Remote / Simulation
 	s.Mux.HandleFunc("/api/remote/ingest", s.handleIngestRemote)
+	s.Mux.HandleFunc("/api/remote/ingest/upload", s.handleIngestUpload)
+	s.Mux.HandleFunc("/api/remote/ingest/jobs", s.handleIngestJobs)
+	s.Mux.HandleFunc("/api/remote/ingest/events", s.handleIngestEvents)
+	s.Mux.HandleFunc("/api/remote/ingest/cancel", s.handleCancelIngest)
 	s.Mux.HandleFunc("/api/remote/simulate-commit", s.handleSimulateRemoteCommit)
 	s.Mux.HandleFunc("/api/remote/pull-requests", s.handleGetPullRequests)
 	s.Mux.HandleFunc("/api/remote/pull-requests/create", s.handleCreatePullRequest)
 	s.Mux.HandleFunc("/api/remote/pull-requests/merge", s.handleMergePullRequest)
 	s.Mux.HandleFunc("/api/remote/pull-requests/delete", s.handleDeletePullRequest)
+	s.Mux.HandleFunc("/api/remote/pull-requests/review", s.handleReviewPullRequest)
+	s.Mux.HandleFunc("/api/remote/pull-requests/comment", s.handleCommentPullRequest)
 	s.Mux.HandleFunc("/api/remote/reset", s.handleResetRemote)
 	s.Mux.HandleFunc("/api/remote/info", s.handleGetRemoteInfo)
 	s.Mux.HandleFunc("/api/remote/create", s.handleCreateRemote)
 	s.Mux.HandleFunc("/api/remote/list", s.handleListRemotes)
+	s.Mux.HandleFunc("/api/remote/protection", s.handleRemoteProtection)
+	s.Mux.HandleFunc("/api/remote/checks", s.handleRemoteChecks)
+	s.Mux.HandleFunc("/api/remote/statuses", s.handleCommitStatuses)
+	s.Mux.HandleFunc("/api/remote/fork", s.handleForkRemote)
+	s.Mux.HandleFunc("/api/remote/fork/sync", s.handleSyncFork)
+
+	// Teammate bots
+	s.Mux.HandleFunc("/api/bots", s.handleBots)
+	s.Mux.HandleFunc("/api/bots/pause", s.handleBotControl)
+	s.Mux.HandleFunc("/api/bots/resume", s.handleBotControl)
+	s.Mux.HandleFunc("/api/bots/step", s.handleBotControl)
+
+	// Git Smart HTTP (real git clients)
+	s.Mux.HandleFunc("/git/", s.handleGitHTTP)
 
 	// Mission
 	s.Mux.HandleFunc("/api/mission/list", s.handleListMissions)
@@ -0,0 +1,65 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/kurobon/gitgym/backend/internal/state"
+)
+
+// handleBots reads (GET) or replaces (POST) the teammate bot scripts
+func (s *Server) handleBots(w http.ResponseWriter, r *http.Request) {
+	bots := s.SessionManager.Bots
+	switch r.Method {
+	case http.MethodGet:
+	case http.MethodPost, http.MethodPut:
+		var req struct {
+			Scripts []*state.BotScript `json:"scripts"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		if err := bots.Load(req.Scripts); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(bots.Status())
+}
+
+// handleBotControl pauses, resumes or steps the bot scheduler
+func (s *Server) handleBotControl(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	bots := s.SessionManager.Bots
+	resp := map[string]interface{}{}
+	switch r.URL.Path {
+	case "/api/bots/pause":
+		bots.Pause()
+	case "/api/bots/resume":
+		bots.Resume()
+	case "/api/bots/step":
+		event, err := bots.Step()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusConflict)
+			return
+		}
+		resp["event"] = event
+	default:
+		http.NotFound(w, r)
+		return
+	}
+
+	resp["status"] = bots.Status()
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,149 @@
+package server
+
+import (
+	"encoding/json"
+	"net/http"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/kurobon/gitgym/backend/internal/git"
+	"github.com/kurobon/gitgym/backend/internal/state"
+)
+
+// handleRemoteChecks reads (GET ?name=) or replaces (POST) the CI check rules of a shared remote
+func (s *Server) handleRemoteChecks(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		name := r.URL.Query().Get("name")
+		if name == "" {
+			http.Error(w, "name required", http.StatusBadRequest)
+			return
+		}
+		if _, ok := s.SessionManager.GetSharedRemote(name); !ok {
+			http.Error(w, "remote not found", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"name":   name,
+			"checks": s.SessionManager.GetStatusChecks(name),
+		})
+
+	case http.MethodPost, http.MethodPut:
+		var req struct {
+			Name   string               `json:"name"`
+			Checks []*state.StatusCheck `json:"checks"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		if req.Name == "" {
+			http.Error(w, "name required", http.StatusBadRequest)
+			return
+		}
+		repo, ok := s.SessionManager.GetSharedRemote(req.Name)
+		if !ok {
+			http.Error(w, "remote not found", http.StatusNotFound)
+			return
+		}
+		if err := s.SessionManager.SetStatusChecks(req.Name, req.Checks); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		// Re-run the new rules on every branch tip so the graph reflects them right away
+		if branches, err := repo.Branches(); err == nil {
+			_ = branches.ForEach(func(ref *plumbing.Reference) error {
+				s.SessionManager.RunStatusChecks(repo, ref.Hash())
+				return nil
+			})
+		}
+		git.RefreshPullRequests(s.SessionManager, repo)
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"name":   req.Name,
+			"checks": s.SessionManager.GetStatusChecks(req.Name),
+		})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleCommitStatuses lists (GET ?name=&sha=) or reports (POST) the statuses of a commit
+// on a shared remote. sha may also be a branch or tag name.
+func (s *Server) handleCommitStatuses(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		query := r.URL.Query()
+		repo, commit, ok := s.resolveStatusCommit(w, query.Get("name"), query.Get("sha"))
+		if !ok {
+			return
+		}
+		statuses := s.SessionManager.GetCommitStatuses(repo, commit)
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"sha":      commit.String(),
+			"state":    state.CombinedStatus(statuses),
+			"statuses": statuses,
+		})
+
+	case http.MethodPost:
+		var req struct {
+			Name        string `json:"name"`
+			SHA         string `json:"sha"`
+			Context     string `json:"context"`
+			State       string `json:"state"`
+			Description string `json:"description"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		if req.Context == "" || !state.ValidStatusState(req.State) {
+			http.Error(w, "context and state (pending, success, failure, error) required", http.StatusBadRequest)
+			return
+		}
+		repo, commit, ok := s.resolveStatusCommit(w, req.Name, req.SHA)
+		if !ok {
+			return
+		}
+
+		status := &state.CommitStatus{Context: req.Context, State: req.State, Description: req.Description}
+		s.SessionManager.SetCommitStatus(repo, commit, status)
+		git.RefreshPullRequests(s.SessionManager, repo)
+
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusCreated)
+		_ = json.NewEncoder(w).Encode(status)
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// resolveStatusCommit finds the shared remote and commit a status request refers to,
+// writing the HTTP error itself when it cannot.
+func (s *Server) resolveStatusCommit(w http.ResponseWriter, name, rev string) (*gogit.Repository, plumbing.Hash, bool) {
+	if name == "" || rev == "" {
+		http.Error(w, "name and sha required", http.StatusBadRequest)
+		return nil, plumbing.ZeroHash, false
+	}
+	repo, ok := s.SessionManager.GetSharedRemote(name)
+	if !ok {
+		http.Error(w, "remote not found", http.StatusNotFound)
+		return nil, plumbing.ZeroHash, false
+	}
+	hash, err := repo.ResolveRevision(plumbing.Revision(rev))
+	if err != nil {
+		http.Error(w, "commit not found", http.StatusNotFound)
+		return nil, plumbing.ZeroHash, false
+	}
+	if _, err := repo.CommitObject(*hash); err != nil {
+		http.Error(w, "commit not found", http.StatusNotFound)
+		return nil, plumbing.ZeroHash, false
+	}
+	return repo, *hash, true
+}
@@ -0,0 +1,103 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kurobon/gitgym/backend/internal/git"
+	"github.com/kurobon/gitgym/backend/internal/mission"
+)
+
+func TestHandleStatusChecks(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("GITGYM_DATA_ROOT", tmpDir)
+
+	sm := git.NewSessionManager()
+	ml := mission.NewLoader(tmpDir)
+	me := mission.NewEngine(ml, sm)
+	s := NewServer(sm, me)
+
+	repo, err := gogit.Init(memory.NewStorage(), memfs.New())
+	require.NoError(t, err)
+	wt, _ := repo.Worktree()
+	f, _ := wt.Filesystem.Create("README.md")
+	_, _ = f.Write([]byte("hello"))
+	_ = f.Close()
+	_, _ = wt.Add("README.md")
+	head, err := wt.Commit("wip", &gogit.CommitOptions{Author: &object.Signature{Name: "Dev", Email: "dev@example.com", When: time.Now()}})
+	require.NoError(t, err)
+	require.NoError(t, repo.Storer.SetReference(plumbing.NewHashReference(plumbing.NewBranchReferenceName("main"), head)))
+	sm.SharedRemotes["ci-repo"] = repo
+
+	post := func(path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodPost, path, bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, req)
+		return w
+	}
+	statuses := func() (resp struct {
+		SHA      string             `json:"sha"`
+		State    string             `json:"state"`
+		Statuses []git.CommitStatus `json:"statuses"`
+	}) {
+		req, _ := http.NewRequest(http.MethodGet, "/api/remote/statuses?name=ci-repo&sha=main", nil)
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		return resp
+	}
+
+	t.Run("Rules run on existing branch tips", func(t *testing.T) {
+		w := post("/api/remote/checks", `{"name":"ci-repo","checks":[{"context":"commit-style","type":"message-matches","pattern":"^(feat|fix): "}]}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+		resp := statuses()
+		assert.Equal(t, head.String(), resp.SHA)
+		assert.Equal(t, "failure", resp.State)
+		require.Len(t, resp.Statuses, 1)
+		assert.Equal(t, "commit-style", resp.Statuses[0].Context)
+	})
+
+	t.Run("Invalid rule", func(t *testing.T) {
+		w := post("/api/remote/checks", `{"name":"ci-repo","checks":[{"context":"x","type":"message-matches","pattern":"("}]}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Report external status", func(t *testing.T) {
+		w := post("/api/remote/statuses", `{"name":"ci-repo","sha":"main","context":"deploy","state":"pending"}`)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+		resp := statuses()
+		require.Len(t, resp.Statuses, 2)
+		assert.Equal(t, "failure", resp.State)
+
+		w = post("/api/remote/statuses", `{"name":"ci-repo","sha":"main","context":"deploy","state":"unknown"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Remote graph carries combined statuses", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/api/remote/state?name=ci-repo", nil)
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, req)
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var graph struct {
+			CommitStatuses map[string]string `json:"commitStatuses"`
+		}
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&graph))
+		assert.Equal(t, "failure", graph.CommitStatuses[head.String()])
+	})
+}
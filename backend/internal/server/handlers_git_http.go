@@ -0,0 +1,345 @@
+package server
+
+// handlers_git_http.go - Git Smart HTTP Transport for Shared Remotes
+//
+// Exposes every shared remote at /git/<name>.git using git's smart HTTP
+// protocol, so a stock git client can clone, fetch and push against the same
+// repositories the simulated commands operate on:
+//
+//	GET  /git/<name>.git/info/refs?service=git-upload-pack|git-receive-pack
+//	POST /git/<name>.git/git-upload-pack
+//	POST /git/<name>.git/git-receive-pack
+
+import (
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/packfile"
+	"github.com/go-git/go-git/v5/plumbing/format/pktline"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/protocol/packp"
+	"github.com/go-git/go-git/v5/plumbing/protocol/packp/capability"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/go-git/go-git/v5/plumbing/transport"
+	gitserver "github.com/go-git/go-git/v5/plumbing/transport/server"
+
+	"github.com/kurobon/gitgym/backend/internal/git"
+	"github.com/kurobon/gitgym/backend/internal/state"
+)
+
+const (
+	serviceUploadPack  = "git-upload-pack"
+	serviceReceivePack = "git-receive-pack"
+)
+
+// sharedRemoteLoader resolves transport endpoints ("/<name>") to shared remote storers.
+type sharedRemoteLoader struct {
+	server *Server
+}
+
+func (l *sharedRemoteLoader) Load(ep *transport.Endpoint) (storer.Storer, error) {
+	name := strings.TrimPrefix(ep.Path, "/")
+	repo, ok := l.server.SessionManager.GetSharedRemote(name)
+	if !ok {
+		return nil, transport.ErrRepositoryNotFound
+	}
+	return repo.Storer, nil
+}
+
+// parseGitHTTPPath splits "/git/<name>.git/<service path>" into its parts.
+func parseGitHTTPPath(urlPath string) (name, action string, ok bool) {
+	rest := strings.TrimPrefix(urlPath, "/git/")
+	if rest == urlPath {
+		return "", "", false
+	}
+
+	for _, suffix := range []string{"/info/refs", "/" + serviceUploadPack, "/" + serviceReceivePack} {
+		if strings.HasSuffix(rest, suffix) {
+			name = strings.TrimSuffix(strings.TrimSuffix(rest, suffix), ".git")
+			return name, strings.TrimPrefix(suffix, "/"), name != ""
+		}
+	}
+	return "", "", false
+}
+
+func (s *Server) handleGitHTTP(w http.ResponseWriter, r *http.Request) {
+	name, action, ok := parseGitHTTPPath(r.URL.Path)
+	if !ok {
+		http.NotFound(w, r)
+		return
+	}
+
+	if _, exists := s.SessionManager.GetSharedRemote(name); !exists {
+		http.Error(w, "repository not found", http.StatusNotFound)
+		return
+	}
+
+	ep, err := transport.NewEndpoint("/" + name)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	srv := gitserver.NewServer(&sharedRemoteLoader{server: s})
+
+	switch action {
+	case "info/refs":
+		if r.Method != http.MethodGet {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.handleGitInfoRefs(w, r, srv, ep)
+	case serviceUploadPack:
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.handleGitUploadPack(w, r, srv, ep)
+	case serviceReceivePack:
+		if r.Method != http.MethodPost {
+			http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		s.handleGitReceivePack(w, r, srv, ep, name)
+	}
+}
+
+func (s *Server) handleGitInfoRefs(w http.ResponseWriter, r *http.Request, srv transport.Transport, ep *transport.Endpoint) {
+	service := r.URL.Query().Get("service")
+
+	var ar *packp.AdvRefs
+	var err error
+	switch service {
+	case serviceUploadPack:
+		var sess transport.UploadPackSession
+		sess, err = srv.NewUploadPackSession(ep, nil)
+		if err == nil {
+			ar, err = sess.AdvertisedReferencesContext(r.Context())
+		}
+	case serviceReceivePack:
+		var sess transport.ReceivePackSession
+		sess, err = srv.NewReceivePackSession(ep, nil)
+		if err == nil {
+			ar, err = sess.AdvertisedReferencesContext(r.Context())
+		}
+	default:
+		// Dumb HTTP is not supported; git falls back to it only for old servers.
+		http.Error(w, "smart HTTP service required (?service=git-upload-pack or git-receive-pack)", http.StatusForbidden)
+		return
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", fmt.Sprintf("application/x-%s-advertisement", service))
+	w.Header().Set("Cache-Control", "no-cache")
+
+	enc := pktline.NewEncoder(w)
+	if err := enc.EncodeString(fmt.Sprintf("# service=%s\n", service)); err != nil {
+		log.Printf("git-http: failed to write service header: %v", err)
+		return
+	}
+	if err := enc.Flush(); err != nil {
+		log.Printf("git-http: failed to write flush: %v", err)
+		return
+	}
+	if err := ar.Encode(w); err != nil {
+		log.Printf("git-http: failed to encode advertised refs: %v", err)
+	}
+}
+
+func (s *Server) handleGitUploadPack(w http.ResponseWriter, r *http.Request, srv transport.Transport, ep *transport.Endpoint) {
+	body, err := gitRequestBody(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer body.Close()
+
+	req := packp.NewUploadPackRequest()
+	if err := req.Decode(body); err != nil {
+		http.Error(w, fmt.Sprintf("invalid upload-pack request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	sess, err := srv.NewUploadPackSession(ep, nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	resp, err := sess.UploadPack(r.Context(), req)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	defer resp.Close()
+
+	w.Header().Set("Content-Type", "application/x-git-upload-pack-result")
+	w.Header().Set("Cache-Control", "no-cache")
+	if err := resp.Encode(w); err != nil {
+		log.Printf("git-http: failed to encode upload-pack response: %v", err)
+	}
+}
+
+func (s *Server) handleGitReceivePack(w http.ResponseWriter, r *http.Request, srv transport.Transport, ep *transport.Endpoint, name string) {
+	repo, _ := s.SessionManager.GetSharedRemote(name)
+
+	body, err := gitRequestBody(r)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	defer body.Close()
+
+	req := packp.NewReferenceUpdateRequest()
+	if err := req.Decode(body); err != nil {
+		http.Error(w, fmt.Sprintf("invalid receive-pack request: %v", err), http.StatusBadRequest)
+		return
+	}
+
+	// The pusher is whoever the HTTP basic-auth user name says; the password is
+	// not checked, so the identity is self-asserted like git's user.name
+	var pusher object.Signature
+	if user, _, ok := r.BasicAuth(); ok {
+		pusher.Name = user
+	}
+
+	// Load the repository before locking: the loader looks it up in the manager
+	sess, err := srv.NewReceivePackSession(ep, nil)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	// Simulated pushes, simulated commits and bots write to shared remotes under
+	// the session manager's lock
+	s.SessionManager.Lock()
+	report, updated, err := s.receivePack(r, sess, repo, req, pusher, name)
+	s.SessionManager.Unlock()
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+
+	for _, cmd := range req.Commands {
+		if updated[cmd.Name] && cmd.Name.IsBranch() && !cmd.New.IsZero() {
+			s.SessionManager.RunStatusChecks(repo, cmd.New)
+			s.SessionManager.Bots.NotifyPush(repo, cmd.Name)
+		}
+	}
+	if len(updated) > 0 {
+		git.RefreshPullRequests(s.SessionManager, repo)
+	}
+	writeReportStatus(w, req, report)
+}
+
+// receivePack applies a push to repo. Objects are received into a quarantine so
+// the protection hook can inspect the pushed commits; only accepted updates
+// bring theirs into the remote. It returns the report for the client and the
+// refs that were updated. Caller holds the session manager's lock.
+func (s *Server) receivePack(r *http.Request, sess transport.ReceivePackSession, repo *gogit.Repository, req *packp.ReferenceUpdateRequest, pusher object.Signature, name string) (*packp.ReportStatus, map[plumbing.ReferenceName]bool, error) {
+	report := packp.NewReportStatus()
+	report.UnpackStatus = "ok"
+	commands := req.Commands
+
+	quarantine, err := git.Quarantine(repo)
+	if err != nil {
+		return nil, nil, err
+	}
+	// git sends no packfile when it only deletes refs
+	if !onlyDeletes(commands) && req.Packfile != nil {
+		if err := packfile.UpdateObjectStorage(quarantine.Storer, req.Packfile); err != nil {
+			report.UnpackStatus = err.Error()
+			for _, cmd := range commands {
+				report.CommandStatuses = append(report.CommandStatuses, &packp.CommandStatus{ReferenceName: cmd.Name, Status: "unpacker error"})
+			}
+			return report, nil, nil
+		}
+	}
+
+	// Pre-receive: branch protection rules
+	declined := make(map[plumbing.ReferenceName]string)
+	var accepted []*packp.Command
+	for _, cmd := range commands {
+		update := state.RefUpdate{Name: cmd.Name, Old: cmd.Old, New: cmd.New, Pusher: pusher, Quarantine: quarantine}
+		if err := s.SessionManager.CheckRefUpdateLocked(repo, update); err != nil {
+			declined[cmd.Name] = err.Error()
+			continue
+		}
+		if !cmd.New.IsZero() {
+			if err := git.CopyObjectRecursive(quarantine, repo, cmd.New); err != nil {
+				declined[cmd.Name] = "unpacker error"
+				continue
+			}
+		}
+		accepted = append(accepted, cmd)
+	}
+
+	statuses := make(map[plumbing.ReferenceName]string)
+	if len(accepted) > 0 {
+		// Ref update failures are reported to the client via report-status, not HTTP errors.
+		status, err := sess.ReceivePack(r.Context(), &packp.ReferenceUpdateRequest{Capabilities: req.Capabilities, Commands: accepted, Options: req.Options})
+		if err != nil {
+			log.Printf("git-http: receive-pack into %q reported: %v", name, err)
+		}
+		if status != nil {
+			for _, cs := range status.CommandStatuses {
+				statuses[cs.ReferenceName] = cs.Status
+			}
+		}
+	}
+
+	updated := make(map[plumbing.ReferenceName]bool)
+	for _, cmd := range commands {
+		status, ok := declined[cmd.Name]
+		if !ok {
+			if status, ok = statuses[cmd.Name]; !ok {
+				status = "ok"
+			}
+			updated[cmd.Name] = status == "ok"
+		}
+		report.CommandStatuses = append(report.CommandStatuses, &packp.CommandStatus{ReferenceName: cmd.Name, Status: status})
+	}
+	return report, updated, nil
+}
+
+// writeReportStatus sends the receive-pack result if the client asked for report-status.
+func writeReportStatus(w http.ResponseWriter, req *packp.ReferenceUpdateRequest, report *packp.ReportStatus) {
+	w.Header().Set("Content-Type", "application/x-git-receive-pack-result")
+	w.Header().Set("Cache-Control", "no-cache")
+	if !req.Capabilities.Supports(capability.ReportStatus) {
+		return
+	}
+	if err := report.Encode(w); err != nil {
+		log.Printf("git-http: failed to encode report-status: %v", err)
+	}
+}
+
+func onlyDeletes(cmds []*packp.Command) bool {
+	for _, cmd := range cmds {
+		if cmd.Action() != packp.Delete {
+			return false
+		}
+	}
+	return len(cmds) > 0
+}
+
+// gitRequestBody returns the request body, transparently inflating gzip
+// payloads (git compresses large negotiation requests).
+func gitRequestBody(r *http.Request) (io.ReadCloser, error) {
+	if r.Header.Get("Content-Encoding") != "gzip" {
+		return r.Body, nil
+	}
+	zr, err := gzip.NewReader(r.Body)
+	if err != nil {
+		return nil, errors.New("invalid gzip request body")
+	}
+	return zr, nil
+}
@@ -0,0 +1,183 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	githttp "github.com/go-git/go-git/v5/plumbing/transport/http"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kurobon/gitgym/backend/internal/git"
+	"github.com/kurobon/gitgym/backend/internal/mission"
+	"github.com/kurobon/gitgym/backend/internal/state"
+)
+
+func TestGitSmartHTTP(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("GITGYM_DATA_ROOT", tmpDir)
+
+	sm := git.NewSessionManager()
+	ml := mission.NewLoader(tmpDir)
+	me := mission.NewEngine(ml, sm)
+	s := NewServer(sm, me)
+
+	ts := httptest.NewServer(s)
+	defer ts.Close()
+
+	ctx := context.Background()
+	require.NoError(t, sm.CreateBareRepository(ctx, "test-session", "shared"))
+	url := ts.URL + "/git/shared.git"
+
+	repo, err := gogit.Init(memory.NewStorage(), memfs.New())
+	require.NoError(t, err)
+
+	t.Run("Push to empty remote", func(t *testing.T) {
+		w, err := repo.Worktree()
+		require.NoError(t, err)
+
+		f, err := w.Filesystem.Create("README.md")
+		require.NoError(t, err)
+		_, _ = f.Write([]byte("hello"))
+		_ = f.Close()
+		_, _ = w.Add("README.md")
+		hash, err := w.Commit("Initial commit", &gogit.CommitOptions{
+			Author: &object.Signature{Name: "User", Email: "user@example.com", When: time.Now()},
+		})
+		require.NoError(t, err)
+
+		_, err = repo.CreateRemote(&config.RemoteConfig{Name: "origin", URLs: []string{url}})
+		require.NoError(t, err)
+		err = repo.Push(&gogit.PushOptions{
+			RemoteName: "origin",
+			RefSpecs:   []config.RefSpec{"refs/heads/master:refs/heads/main"},
+		})
+		require.NoError(t, err)
+
+		shared, ok := sm.GetSharedRemote("shared")
+		require.True(t, ok)
+		ref, err := shared.Reference(plumbing.NewBranchReferenceName("main"), true)
+		require.NoError(t, err)
+		assert.Equal(t, hash, ref.Hash())
+	})
+
+	t.Run("Delete branch", func(t *testing.T) {
+		err := repo.Push(&gogit.PushOptions{
+			RemoteName: "origin",
+			RefSpecs:   []config.RefSpec{"refs/heads/master:refs/heads/topic"},
+		})
+		require.NoError(t, err)
+
+		err = repo.Push(&gogit.PushOptions{
+			RemoteName: "origin",
+			RefSpecs:   []config.RefSpec{":refs/heads/topic"},
+		})
+		require.NoError(t, err)
+
+		shared, _ := sm.GetSharedRemote("shared")
+		_, err = shared.Reference(plumbing.NewBranchReferenceName("topic"), true)
+		assert.ErrorIs(t, err, plumbing.ErrReferenceNotFound)
+	})
+
+	t.Run("Protected branch rejected", func(t *testing.T) {
+		require.NoError(t, sm.SetBranchProtection("shared", []*state.BranchProtection{
+			{Pattern: "main", RequirePullRequest: true},
+		}))
+		defer func() { _ = sm.SetBranchProtection("shared", nil) }()
+
+		w, err := repo.Worktree()
+		require.NoError(t, err)
+		_, err = w.Commit("Direct push", &gogit.CommitOptions{
+			AllowEmptyCommits: true,
+			Author:            &object.Signature{Name: "User", Email: "user@example.com", When: time.Now()},
+		})
+		require.NoError(t, err)
+
+		err = repo.Push(&gogit.PushOptions{
+			RemoteName: "origin",
+			RefSpecs:   []config.RefSpec{"refs/heads/master:refs/heads/main"},
+		})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "protected branch hook declined")
+
+		shared, _ := sm.GetSharedRemote("shared")
+		ref, err := shared.Reference(plumbing.NewBranchReferenceName("main"), true)
+		require.NoError(t, err)
+		head, _ := repo.Head()
+		assert.NotEqual(t, head.Hash(), ref.Hash())
+		assert.Error(t, shared.Storer.HasEncodedObject(head.Hash()), "rejected objects must stay in quarantine")
+	})
+
+	t.Run("Allowed pushers use the authenticated user", func(t *testing.T) {
+		require.NoError(t, sm.SetBranchProtection("shared", []*state.BranchProtection{
+			{Pattern: "release", AllowedPushers: []string{"alice"}},
+		}))
+		defer func() { _ = sm.SetBranchProtection("shared", nil) }()
+		push := func(auth *githttp.BasicAuth) error {
+			return repo.Push(&gogit.PushOptions{
+				RemoteName: "origin",
+				RefSpecs:   []config.RefSpec{"refs/heads/master:refs/heads/release"},
+				Auth:       auth,
+			})
+		}
+
+		err := push(&githttp.BasicAuth{Username: "mallory", Password: "x"})
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "protected branch hook declined")
+
+		require.NoError(t, push(&githttp.BasicAuth{Username: "alice", Password: "x"}))
+		shared, _ := sm.GetSharedRemote("shared")
+		ref, err := shared.Reference(plumbing.NewBranchReferenceName("release"), true)
+		require.NoError(t, err)
+		head, _ := repo.Head()
+		assert.Equal(t, head.Hash(), ref.Hash())
+	})
+
+	t.Run("Clone", func(t *testing.T) {
+		clone, err := gogit.Clone(memory.NewStorage(), memfs.New(), &gogit.CloneOptions{
+			URL:           url,
+			ReferenceName: plumbing.NewBranchReferenceName("main"),
+		})
+		require.NoError(t, err)
+
+		head, err := clone.Head()
+		require.NoError(t, err)
+		commit, err := clone.CommitObject(head.Hash())
+		require.NoError(t, err)
+		assert.Equal(t, "Initial commit", commit.Message)
+	})
+
+	t.Run("Advertisement", func(t *testing.T) {
+		resp, err := http.Get(url + "/info/refs?service=git-upload-pack")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusOK, resp.StatusCode)
+		assert.Equal(t, "application/x-git-upload-pack-advertisement", resp.Header.Get("Content-Type"))
+	})
+
+	t.Run("Unknown repository", func(t *testing.T) {
+		resp, err := http.Get(ts.URL + "/git/missing.git/info/refs?service=git-upload-pack")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+	})
+
+	t.Run("Dumb HTTP refused", func(t *testing.T) {
+		resp, err := http.Get(url + "/info/refs")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+
+		assert.Equal(t, http.StatusForbidden, resp.StatusCode)
+	})
+}
@@ -0,0 +1,125 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/kurobon/gitgym/backend/internal/git"
+)
+
+// ingestHeartbeat keeps idle event streams open through proxies
+const ingestHeartbeat = 15 * time.Second
+
+// handleIngestJobs lists ingest jobs, or returns one with ?id=
+func (s *Server) handleIngestJobs(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	if id := r.URL.Query().Get("id"); id != "" {
+		job, ok := s.SessionManager.Ingests.Get(id)
+		if !ok {
+			http.Error(w, "ingest job not found", http.StatusNotFound)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(job)
+		return
+	}
+	_ = json.NewEncoder(w).Encode(map[string]interface{}{"jobs": s.SessionManager.Ingests.List()})
+}
+
+// handleIngestEvents streams the progress of an ingest job as Server-Sent Events:
+// "progress" events while it runs and one "done" event with the final state
+func (s *Server) handleIngestEvents(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	current, updates, unsubscribe, ok := s.SessionManager.Ingests.Subscribe(r.URL.Query().Get("id"))
+	if !ok {
+		http.Error(w, "ingest job not found", http.StatusNotFound)
+		return
+	}
+	defer unsubscribe()
+
+	// The server's write timeout would cut long clones short
+	_ = http.NewResponseController(w).SetWriteDeadline(time.Time{})
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.WriteHeader(http.StatusOK)
+
+	send := func(job git.IngestJob) {
+		event := "progress"
+		if job.Finished() {
+			event = "done"
+		}
+		data, _ := json.Marshal(job)
+		fmt.Fprintf(w, "event: %s\ndata: %s\n\n", event, data)
+		flusher.Flush()
+	}
+
+	send(current)
+	if current.Finished() {
+		return
+	}
+
+	heartbeat := time.NewTicker(ingestHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case job, open := <-updates:
+			if !open {
+				return
+			}
+			send(job)
+			if job.Finished() {
+				return
+			}
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			flusher.Flush()
+		case <-r.Context().Done():
+			return
+		}
+	}
+}
+
+// handleCancelIngest cancels a running ingest job
+func (s *Server) handleCancelIngest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req struct {
+		ID string `json:"id"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.ID == "" {
+		http.Error(w, "id required", http.StatusBadRequest)
+		return
+	}
+
+	job, ok := s.SessionManager.Ingests.Get(req.ID)
+	if !ok {
+		http.Error(w, "ingest job not found", http.StatusNotFound)
+		return
+	}
+	if err := s.SessionManager.Ingests.Cancel(req.ID); err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]string{"id": job.ID, "status": "canceling"})
+}
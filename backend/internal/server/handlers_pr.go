@@ -26,16 +26,18 @@ func (s *Server) handleCreatePullRequest(w http.ResponseWriter, r *http.Request)
 		TargetBranch string `json:"targetBranch"`
 		Creator      string `json:"creator"`
 		RemoteName   string `json:"remoteName"`
+		HeadRepo     string `json:"headRepo"` // Optional: fork holding sourceBranch
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	pr, err := s.SessionManager.CreatePullRequest(req.Title, req.Description, req.SourceBranch, req.TargetBranch, req.Creator, req.RemoteName)
+	pr, err := s.SessionManager.CreateForkPullRequest(req.Title, req.Description, req.HeadRepo, req.SourceBranch, req.TargetBranch, req.Creator, req.RemoteName)
 	if err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
+	git.RefreshPullRequests(s.SessionManager, nil)
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(pr)
 }
@@ -48,6 +50,8 @@ func (s *Server) handleMergePullRequest(w http.ResponseWriter, r *http.Request)
 	var req struct {
 		ID         int    `json:"id"`
 		RemoteName string `json:"remoteName"`
+		Strategy   string `json:"strategy"` // Optional: "merge" (default), "squash", "rebase", "ff-only"
+		MergedBy   string `json:"mergedBy"` // Optional: "Name <email>" of the merging user
 	}
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		http.Error(w, err.Error(), http.StatusBadRequest)
@@ -67,14 +71,21 @@ func (s *Server) handleMergePullRequest(w http.ResponseWriter, r *http.Request)
 	}
 
 	// Dispatch "merge-pr"
-	// Output is ignored for now, checking error
-	_, err := git.Dispatch(r.Context(), session, "merge-pr", []string{"merge-pr", fmt.Sprintf("%d", req.ID), req.RemoteName})
+	args := []string{"merge-pr", fmt.Sprintf("%d", req.ID), req.RemoteName}
+	if req.Strategy != "" {
+		args = append(args, "--strategy="+req.Strategy)
+	}
+	if req.MergedBy != "" {
+		args = append(args, "--author="+req.MergedBy)
+	}
+	output, err := git.Dispatch(r.Context(), session, "merge-pr", args)
 
 	if err != nil {
 		http.Error(w, err.Error(), http.StatusInternalServerError)
 		return
 	}
-	w.WriteHeader(http.StatusOK)
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]string{"output": output})
 }
 
 func (s *Server) handleDeletePullRequest(w http.ResponseWriter, r *http.Request) {
@@ -95,3 +106,68 @@ func (s *Server) handleDeletePullRequest(w http.ResponseWriter, r *http.Request)
 	}
 	w.WriteHeader(http.StatusOK)
 }
+
+func (s *Server) handleReviewPullRequest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req struct {
+		ID       int                      `json:"id"`
+		Author   string                   `json:"author"`
+		State    string                   `json:"state"` // "APPROVED", "CHANGES_REQUESTED", "COMMENTED"
+		Body     string                   `json:"body"`
+		Comments []git.DraftReviewComment `json:"comments"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	// Anchor line comments against the latest diff
+	git.RefreshPullRequests(s.SessionManager, nil)
+
+	review, err := s.SessionManager.SubmitReview(req.ID, req.Author, req.State, req.Body, req.Comments)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(review)
+}
+
+func (s *Server) handleCommentPullRequest(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req struct {
+		ID        int    `json:"id"`
+		Author    string `json:"author"`
+		Body      string `json:"body"`
+		Path      string `json:"path"`
+		Position  int    `json:"position"`
+		InReplyTo int    `json:"inReplyTo"` // Optional: reply to an existing comment thread
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var (
+		comment *git.ReviewComment
+		err     error
+	)
+	if req.InReplyTo != 0 {
+		comment, err = s.SessionManager.ReplyToReviewComment(req.ID, req.InReplyTo, req.Author, req.Body)
+	} else {
+		git.RefreshPullRequests(s.SessionManager, nil)
+		comment, err = s.SessionManager.AddReviewComment(req.ID, req.Author, req.Path, req.Position, req.Body)
+	}
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(comment)
+}
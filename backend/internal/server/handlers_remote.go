@@ -2,9 +2,9 @@ package server
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
-	"strings"
 
 	"github.com/kurobon/gitgym/backend/internal/git"
 	"github.com/kurobon/gitgym/backend/internal/state"
@@ -39,6 +39,7 @@ func (s *Server) handleGetRemoteState(w http.ResponseWriter, r *http.Request) {
 	stateObj := state.BuildGraphState(repo, true)
 	// Add logic to populate shared remotes
 	stateObj.SharedRemotes = []string{name} // The requested one is definitely there.
+	stateObj.CommitStatuses = s.SessionManager.CommitStatusSummary(repo)
 
 	// CLEANUP FOR VISUALIZATION:
 	// The "Remote View" represents the server state.
@@ -87,10 +88,8 @@ func (s *Server) handleSimulateRemoteCommit(w http.ResponseWriter, r *http.Reque
 	}
 
 	var req struct {
-		Name    string `json:"name"`
-		Message string `json:"message"`
-		Author  string `json:"author"` // Optional
-		Email   string `json:"email"`  // Optional
+		Name string `json:"name"`
+		git.RemoteCommit
 	}
 
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -106,33 +105,40 @@ func (s *Server) handleSimulateRemoteCommit(w http.ResponseWriter, r *http.Reque
 		req.Message = "Simulated commit from team member"
 	}
 
-	// Resolve Session
-	sessionID := "user-session-1"
-	session, ok := s.SessionManager.GetSession(sessionID)
+	repo, ok := s.SessionManager.GetSharedRemote(req.Name)
 	if !ok {
-		var err error
-		session, err = s.SessionManager.CreateSession(sessionID)
-		if err != nil {
-			http.Error(w, "failed to create session: "+err.Error(), http.StatusInternalServerError)
-			return
-		}
-	}
-
-	// Dispatch simulate-commit
-	args := []string{"simulate-commit", req.Name, req.Message}
-	if req.Author != "" && req.Email != "" {
-		args = append(args, req.Author, req.Email)
+		http.Error(w, "remote not found", http.StatusNotFound)
+		return
 	}
 
-	_, err := git.Dispatch(r.Context(), session, "simulate-commit", args)
-
+	sm := s.SessionManager
+	sm.Lock()
+	hash, branch, err := git.WriteRemoteCommit(sm, repo, &req.RemoteCommit)
+	sm.Unlock()
 	if err != nil {
-		http.Error(w, fmt.Sprintf("failed to simulate commit: %v", err), http.StatusInternalServerError)
+		var protErr *git.ProtectionError
+		switch {
+		case errors.Is(err, git.ErrConflict):
+			http.Error(w, fmt.Sprintf("failed to simulate commit: %v", err), http.StatusConflict)
+		case errors.As(err, &protErr):
+			http.Error(w, fmt.Sprintf("failed to simulate commit: %v", err), http.StatusForbidden)
+		default:
+			http.Error(w, fmt.Sprintf("failed to simulate commit: %v", err), http.StatusBadRequest)
+		}
 		return
 	}
 
-	w.WriteHeader(http.StatusOK)
-	_ = json.NewEncoder(w).Encode(map[string]string{"status": "ok"})
+	// CI checks the teammate's commit, bots react to it, then open PRs pick it up
+	sm.RunStatusChecks(repo, hash)
+	sm.Bots.NotifyPush(repo, branch)
+	git.RefreshPullRequests(sm, repo)
+
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"status": "ok",
+		"commit": hash.String(),
+		"branch": branch.Short(),
+	})
 }
 
 func (s *Server) handleIngestRemote(w http.ResponseWriter, r *http.Request) {
@@ -149,12 +155,52 @@ func (s *Server) handleIngestRemote(w http.ResponseWriter, r *http.Request) {
 		http.Error(w, err.Error(), http.StatusBadRequest)
 		return
 	}
-	// Propagate Context
-	if err := s.SessionManager.IngestRemote(r.Context(), req.Name, req.URL, req.Depth); err != nil {
-		http.Error(w, err.Error(), http.StatusInternalServerError)
+	if req.Name == "" || req.URL == "" {
+		http.Error(w, "name and url required", http.StatusBadRequest)
 		return
 	}
-	w.WriteHeader(http.StatusOK)
+
+	// Cloning can take minutes: run it as a job whose progress is streamed from
+	// /api/remote/ingest/events
+	job := s.SessionManager.Ingests.Start(req.Name, req.URL, req.Depth)
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusAccepted)
+	_ = json.NewEncoder(w).Encode(job)
+}
+
+// maxIngestUpload caps uploaded bundles and archives
+const maxIngestUpload = 1 << 30
+
+// handleIngestUpload creates a shared remote from an uploaded bundle or tar(.gz)
+// archive (multipart form fields "name" and "file")
+func (s *Server) handleIngestUpload(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	r.Body = http.MaxBytesReader(w, r.Body, maxIngestUpload)
+	if err := r.ParseMultipartForm(32 << 20); err != nil {
+		http.Error(w, "invalid upload: "+err.Error(), http.StatusBadRequest)
+		return
+	}
+	name := r.FormValue("name")
+	if name == "" {
+		http.Error(w, "name required", http.StatusBadRequest)
+		return
+	}
+	file, header, err := r.FormFile("file")
+	if err != nil {
+		http.Error(w, "file required", http.StatusBadRequest)
+		return
+	}
+	defer file.Close()
+
+	if err := git.IngestUpload(r.Context(), s.SessionManager, name, header.Filename, file); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]string{"name": name, "source": "upload:" + header.Filename})
 }
 
 func (s *Server) handleResetRemote(w http.ResponseWriter, r *http.Request) {
@@ -275,26 +321,149 @@ func (s *Server) handleListRemotes(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Get unique keys (filter out duplicates like path aliases)
-	s.SessionManager.RLock()
-	seen := make(map[string]bool)
+	// Registered names only (no URL or path aliases); "origin" is a session-side name
 	var names []string
-	for key := range s.SessionManager.SharedRemotes {
-		// Only include simple names (no paths, no URLs)
-		if key != "" && key[0] != '/' && len(key) < 50 && key != "origin" {
-			// Filter out keys that look like URLs or paths (contain : or /)
-			if !strings.Contains(key, ":") && !strings.Contains(key, "/") {
-				if _, dup := seen[key]; !dup {
-					seen[key] = true
-					names = append(names, key)
-				}
-			}
+	for _, name := range s.SessionManager.SharedRemoteNames() {
+		if name != "origin" {
+			names = append(names, name)
 		}
 	}
-	s.SessionManager.RUnlock()
 
 	w.Header().Set("Content-Type", "application/json")
 	_ = json.NewEncoder(w).Encode(map[string]interface{}{
 		"remotes": names,
 	})
 }
+
+// handleRemoteProtection reads (GET ?name=) or replaces (POST) the branch protection rules of a shared remote
+func (s *Server) handleRemoteProtection(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		name := r.URL.Query().Get("name")
+		if name == "" {
+			http.Error(w, "name required", http.StatusBadRequest)
+			return
+		}
+		if _, ok := s.SessionManager.GetSharedRemote(name); !ok {
+			http.Error(w, "remote not found", http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"name":  name,
+			"rules": s.SessionManager.GetBranchProtection(name),
+		})
+
+	case http.MethodPost, http.MethodPut:
+		var req struct {
+			Name  string                    `json:"name"`
+			Rules []*state.BranchProtection `json:"rules"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+		if req.Name == "" {
+			http.Error(w, "name required", http.StatusBadRequest)
+			return
+		}
+		if _, ok := s.SessionManager.GetSharedRemote(req.Name); !ok {
+			http.Error(w, "remote not found", http.StatusNotFound)
+			return
+		}
+		if err := s.SessionManager.SetBranchProtection(req.Name, req.Rules); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"name":  req.Name,
+			"rules": s.SessionManager.GetBranchProtection(req.Name),
+		})
+
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// handleForkRemote creates a fork of a shared remote under a new name
+func (s *Server) handleForkRemote(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req struct {
+		Upstream string `json:"upstream"`
+		Name     string `json:"name"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Upstream == "" || req.Name == "" {
+		http.Error(w, "upstream and name required", http.StatusBadRequest)
+		return
+	}
+	if err := s.SessionManager.ForkRemote(req.Upstream, req.Name); err != nil {
+		status := http.StatusInternalServerError
+		switch {
+		case errors.Is(err, state.ErrRemoteNotFound):
+			status = http.StatusNotFound
+		case errors.Is(err, state.ErrRemoteExists):
+			status = http.StatusConflict
+		case errors.Is(err, state.ErrInvalidRepositoryName):
+			status = http.StatusBadRequest
+		}
+		http.Error(w, fmt.Sprintf("Failed to fork repository: %v", err), status)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusCreated)
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"name":      req.Name,
+		"parent":    req.Upstream,
+		"remoteUrl": fmt.Sprintf("remote://gitgym/%s.git", req.Name),
+	})
+}
+
+// handleSyncFork fast-forwards a branch of a fork to its upstream ("Sync fork")
+func (s *Server) handleSyncFork(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	var req struct {
+		Name   string `json:"name"`
+		Branch string `json:"branch"`
+	}
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "invalid request body", http.StatusBadRequest)
+		return
+	}
+	if req.Name == "" {
+		http.Error(w, "name required", http.StatusBadRequest)
+		return
+	}
+	if req.Branch == "" {
+		req.Branch = "main"
+	}
+
+	head, err := s.SessionManager.SyncFork(req.Name, req.Branch)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusConflict)
+		return
+	}
+	if repo, ok := s.SessionManager.GetSharedRemote(req.Name); ok {
+		git.RefreshPullRequests(s.SessionManager, repo)
+	}
+
+	parent, _ := s.SessionManager.ForkParent(req.Name)
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(map[string]string{
+		"name":     req.Name,
+		"upstream": parent,
+		"branch":   req.Branch,
+		"head":     head.String(),
+	})
+}
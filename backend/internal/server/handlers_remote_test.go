@@ -1,18 +1,27 @@
 package server
 
 import (
+	"bufio"
 	"bytes"
 	"encoding/json"
 	"fmt"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
+	"time"
 
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing/object"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	appconfig "github.com/kurobon/gitgym/backend/internal/config"
 	"github.com/kurobon/gitgym/backend/internal/git"
 	"github.com/kurobon/gitgym/backend/internal/mission"
+	"github.com/kurobon/gitgym/backend/internal/state"
 )
 
 func TestHandleCreateRemote(t *testing.T) {
@@ -153,3 +162,241 @@ func TestHandleListRemotes(t *testing.T) {
 		assert.Equal(t, http.StatusMethodNotAllowed, w.Code)
 	})
 }
+
+func TestHandleRemoteProtection(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("GITGYM_DATA_ROOT", tmpDir)
+
+	sm := git.NewSessionManager()
+	ml := mission.NewLoader(tmpDir)
+	me := mission.NewEngine(ml, sm)
+	s := NewServer(sm, me)
+
+	require.NoError(t, sm.CreateBareRepository(t.Context(), "test-session", "protected-repo"))
+
+	t.Run("Set rules", func(t *testing.T) {
+		body := `{"name":"protected-repo","rules":[{"pattern":"main","requirePullRequest":true,"allowedPushers":["alice"]}]}`
+		req, _ := http.NewRequest(http.MethodPost, "/api/remote/protection", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		rules := sm.GetBranchProtection("protected-repo")
+		require.Len(t, rules, 1)
+		assert.Equal(t, "main", rules[0].Pattern)
+		assert.True(t, rules[0].RequirePullRequest)
+		assert.Equal(t, []string{"alice"}, rules[0].AllowedPushers)
+	})
+
+	t.Run("Get rules", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/api/remote/protection?name=protected-repo", nil)
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, req)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp struct {
+			Name  string `json:"name"`
+			Rules []struct {
+				Pattern            string `json:"pattern"`
+				RequirePullRequest bool   `json:"requirePullRequest"`
+			} `json:"rules"`
+		}
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		assert.Equal(t, "protected-repo", resp.Name)
+		require.Len(t, resp.Rules, 1)
+		assert.True(t, resp.Rules[0].RequirePullRequest)
+	})
+
+	t.Run("Invalid pattern", func(t *testing.T) {
+		body := `{"name":"protected-repo","rules":[{"pattern":"["}]}`
+		req, _ := http.NewRequest(http.MethodPost, "/api/remote/protection", bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Unknown remote", func(t *testing.T) {
+		req, _ := http.NewRequest(http.MethodGet, "/api/remote/protection?name=missing", nil)
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+}
+
+func TestHandleForkRemote(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("GITGYM_DATA_ROOT", tmpDir)
+
+	sm := git.NewSessionManager()
+	ml := mission.NewLoader(tmpDir)
+	me := mission.NewEngine(ml, sm)
+	s := NewServer(sm, me)
+
+	require.NoError(t, sm.CreateBareRepository(t.Context(), "test-session", "fork-source"))
+
+	post := func(path, body string) *httptest.ResponseRecorder {
+		req, _ := http.NewRequest(http.MethodPost, path, bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Fork", func(t *testing.T) {
+		w := post("/api/remote/fork", `{"upstream":"fork-source","name":"fork-copy"}`)
+		require.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+
+		var resp map[string]string
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+		assert.Equal(t, "fork-source", resp["parent"])
+		assert.Equal(t, "remote://gitgym/fork-copy.git", resp["remoteUrl"])
+
+		_, ok := sm.GetSharedRemote("remote://gitgym/fork-copy.git")
+		assert.True(t, ok)
+	})
+
+	t.Run("Duplicate name", func(t *testing.T) {
+		w := post("/api/remote/fork", `{"upstream":"fork-source","name":"fork-copy"}`)
+		assert.Equal(t, http.StatusConflict, w.Code)
+	})
+
+	t.Run("Invalid name", func(t *testing.T) {
+		w := post("/api/remote/fork", `{"upstream":"fork-source","name":"bad/name"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Unknown upstream", func(t *testing.T) {
+		w := post("/api/remote/fork", `{"upstream":"missing","name":"other"}`)
+		assert.Equal(t, http.StatusNotFound, w.Code)
+	})
+
+	t.Run("Cross-repository PR requires a fork", func(t *testing.T) {
+		require.NoError(t, sm.CreateBareRepository(t.Context(), "test-session", "unrelated"))
+		w := post("/api/remote/pull-requests/create", `{"title":"x","sourceBranch":"feature","targetBranch":"main","remoteName":"fork-source","headRepo":"unrelated"}`)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+
+		w = post("/api/remote/pull-requests/create", `{"title":"x","sourceBranch":"feature","targetBranch":"main","remoteName":"fork-source","headRepo":"fork-copy"}`)
+		require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+		var pr struct {
+			HeadRepo string `json:"headRepo"`
+			BaseRepo string `json:"baseRepo"`
+		}
+		require.NoError(t, json.NewDecoder(w.Body).Decode(&pr))
+		assert.Equal(t, "fork-copy", pr.HeadRepo)
+		assert.Equal(t, "fork-source", pr.BaseRepo)
+	})
+}
+
+func TestHandleIngestJobs(t *testing.T) {
+	tmpDir := t.TempDir()
+	oldRoot, oldIngestRoot := appconfig.Global.DataRoot, appconfig.Global.IngestRoot
+	appconfig.Global.DataRoot = filepath.Join(tmpDir, "data")
+	appconfig.Global.IngestRoot = tmpDir
+	t.Cleanup(func() { appconfig.Global.DataRoot, appconfig.Global.IngestRoot = oldRoot, oldIngestRoot })
+
+	srcPath := filepath.Join(tmpDir, "project")
+	src, err := gogit.PlainInit(srcPath, false)
+	require.NoError(t, err)
+	wt, _ := src.Worktree()
+	require.NoError(t, os.WriteFile(filepath.Join(srcPath, "README.md"), []byte("# Project\n"), 0644))
+	_, _ = wt.Add("README.md")
+	_, err = wt.Commit("Initial commit", &gogit.CommitOptions{Author: &object.Signature{Name: "Dev", Email: "dev@example.com", When: time.Now()}})
+	require.NoError(t, err)
+
+	sm := git.NewSessionManager()
+	ml := mission.NewLoader(tmpDir)
+	me := mission.NewEngine(ml, sm)
+	server := httptest.NewServer(NewServer(sm, me))
+	defer server.Close()
+
+	body, _ := json.Marshal(map[string]string{"name": "project", "url": srcPath})
+	resp, err := http.Post(server.URL+"/api/remote/ingest", "application/json", bytes.NewReader(body))
+	require.NoError(t, err)
+	var job git.IngestJob
+	require.NoError(t, json.NewDecoder(resp.Body).Decode(&job))
+	resp.Body.Close()
+	require.Equal(t, http.StatusAccepted, resp.StatusCode)
+	assert.Equal(t, "project", job.Name)
+
+	t.Run("Events stream ends with done", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/api/remote/ingest/events?id=" + job.ID)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+
+		var event string
+		var final git.IngestJob
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			line := scanner.Text()
+			if name, ok := strings.CutPrefix(line, "event: "); ok {
+				event = name
+			} else if data, ok := strings.CutPrefix(line, "data: "); ok && event == "done" {
+				require.NoError(t, json.Unmarshal([]byte(data), &final))
+			}
+		}
+		assert.Equal(t, "done", event)
+		assert.Equal(t, git.IngestSucceeded, final.State, final.Error)
+
+		_, ok := sm.GetSharedRemote("project")
+		assert.True(t, ok)
+	})
+
+	t.Run("Jobs list", func(t *testing.T) {
+		resp, err := http.Get(server.URL + "/api/remote/ingest/jobs")
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		var list struct {
+			Jobs []git.IngestJob `json:"jobs"`
+		}
+		require.NoError(t, json.NewDecoder(resp.Body).Decode(&list))
+		require.Len(t, list.Jobs, 1)
+		assert.Equal(t, job.ID, list.Jobs[0].ID)
+
+		missing, err := http.Get(server.URL + "/api/remote/ingest/jobs?id=ingest-99")
+		require.NoError(t, err)
+		missing.Body.Close()
+		assert.Equal(t, http.StatusNotFound, missing.StatusCode)
+	})
+
+	t.Run("Cancel a finished job", func(t *testing.T) {
+		resp, err := http.Post(server.URL+"/api/remote/ingest/cancel", "application/json", strings.NewReader(`{"id":"`+job.ID+`"}`))
+		require.NoError(t, err)
+		resp.Body.Close()
+		assert.Equal(t, http.StatusConflict, resp.StatusCode)
+	})
+}
+
+func TestHandleSimulateRemoteCommit(t *testing.T) {
+	tmpDir := t.TempDir()
+	t.Setenv("GITGYM_DATA_ROOT", tmpDir)
+
+	sm := git.NewSessionManager()
+	ml := mission.NewLoader(tmpDir)
+	me := mission.NewEngine(ml, sm)
+	s := NewServer(sm, me)
+
+	require.NoError(t, sm.CreateBareRepository(t.Context(), "test-session", "team-repo"))
+	require.NoError(t, sm.Bots.Load([]*state.BotScript{{
+		Name:    "Alice",
+		Remote:  "team-repo",
+		Actions: []*state.BotAction{{OnPush: "main", Branch: "review", File: "notes.txt", Content: "seen"}},
+	}}))
+
+	body := `{"name":"team-repo","branch":"main","message":"Teammate commit","changes":[{"op":"write","path":"a.txt","content":"a"}]}`
+	req, _ := http.NewRequest(http.MethodPost, "/api/remote/simulate-commit", bytes.NewBufferString(body))
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+
+	var resp map[string]string
+	require.NoError(t, json.NewDecoder(w.Body).Decode(&resp))
+	assert.Equal(t, "main", resp["branch"])
+
+	// The simulated commit counts as a push for the bots
+	events := sm.Bots.Tick()
+	require.Len(t, events, 1)
+	assert.Empty(t, events[0].Error)
+	assert.Equal(t, "review", events[0].Branch)
+}
@@ -5,6 +5,8 @@ import (
 	"fmt"
 	"net/http"
 	"time"
+
+	"github.com/kurobon/gitgym/backend/internal/state"
 )
 
 func (s *Server) handlePing(w http.ResponseWriter, r *http.Request) {
@@ -34,3 +36,54 @@ func (s *Server) handleInitSession(w http.ResponseWriter, r *http.Request) {
 		"sessionId": sessionID,
 	})
 }
+
+// handleDeterministicMode reads (GET) or switches (POST) a session's
+// deterministic mode: commits stamped from a virtual clock with a fixed
+// identity, so the same commands always produce the same commit IDs.
+func (s *Server) handleDeterministicMode(w http.ResponseWriter, r *http.Request) {
+	var req struct {
+		SessionID   string    `json:"sessionId"`
+		Enabled     bool      `json:"enabled"`
+		Start       time.Time `json:"start"`       // Default 2024-01-01T09:00:00Z
+		StepSeconds int       `json:"stepSeconds"` // Default 60
+	}
+	switch r.Method {
+	case http.MethodGet:
+		req.SessionID = r.URL.Query().Get("sessionId")
+	case http.MethodPost:
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid request body", http.StatusBadRequest)
+			return
+		}
+	default:
+		http.Error(w, "Method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	session, ok := s.SessionManager.GetSession(req.SessionID)
+	if !ok {
+		http.Error(w, "Session not found", http.StatusNotFound)
+		return
+	}
+
+	if r.Method == http.MethodPost {
+		if req.Enabled {
+			session.SetDeterministic(state.NewVirtualClock(req.Start, time.Duration(req.StepSeconds)*time.Second))
+		} else {
+			session.SetDeterministic(nil)
+		}
+	}
+
+	resp := map[string]interface{}{"enabled": false}
+	if clock := session.Clock(); clock != nil {
+		resp = map[string]interface{}{
+			"enabled":     true,
+			"start":       clock.Start,
+			"stepSeconds": int(clock.Step / time.Second),
+			"ticks":       clock.Ticks(),
+			"identity":    state.DefaultIdentity.Name + " <" + state.DefaultIdentity.Email + ">",
+		}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	_ = json.NewEncoder(w).Encode(resp)
+}
@@ -0,0 +1,70 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/kurobon/gitgym/backend/internal/git"
+)
+
+func TestHandleDeterministicMode(t *testing.T) {
+	sm := git.NewSessionManager()
+	s := NewServer(sm, nil)
+	session, err := sm.CreateSession("det-session")
+	require.NoError(t, err)
+
+	do := func(method, url, body string) (int, map[string]interface{}) {
+		req, _ := http.NewRequest(method, url, bytes.NewBufferString(body))
+		w := httptest.NewRecorder()
+		s.ServeHTTP(w, req)
+		var resp map[string]interface{}
+		_ = json.NewDecoder(w.Body).Decode(&resp)
+		return w.Code, resp
+	}
+
+	t.Run("Off by default", func(t *testing.T) {
+		code, resp := do(http.MethodGet, "/api/session/deterministic?sessionId=det-session", "")
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, false, resp["enabled"])
+	})
+
+	t.Run("Enable", func(t *testing.T) {
+		code, resp := do(http.MethodPost, "/api/session/deterministic", `{"sessionId":"det-session","enabled":true,"start":"2025-05-01T12:00:00Z","stepSeconds":30}`)
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, true, resp["enabled"])
+		assert.Equal(t, "2025-05-01T12:00:00Z", resp["start"])
+		assert.Equal(t, float64(30), resp["stepSeconds"])
+		assert.Equal(t, "User <user@example.com>", resp["identity"])
+
+		// Each object the session creates reads the clock once
+		first, second := session.Now(), session.Now()
+		assert.Equal(t, time.Date(2025, 5, 1, 12, 0, 0, 0, time.UTC), first.UTC())
+		assert.Equal(t, 30*time.Second, second.Sub(first))
+
+		_, resp = do(http.MethodGet, "/api/session/deterministic?sessionId=det-session", "")
+		assert.Equal(t, float64(2), resp["ticks"])
+	})
+
+	t.Run("Disable", func(t *testing.T) {
+		code, resp := do(http.MethodPost, "/api/session/deterministic", `{"sessionId":"det-session","enabled":false}`)
+		require.Equal(t, http.StatusOK, code)
+		assert.Equal(t, false, resp["enabled"])
+		assert.Nil(t, session.Clock())
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		code, _ := do(http.MethodGet, "/api/session/deterministic?sessionId=missing", "")
+		assert.Equal(t, http.StatusNotFound, code)
+		code, _ = do(http.MethodPost, "/api/session/deterministic", "{")
+		assert.Equal(t, http.StatusBadRequest, code)
+		code, _ = do(http.MethodDelete, "/api/session/deterministic", "")
+		assert.Equal(t, http.StatusMethodNotAllowed, code)
+	})
+}
@@ -4,10 +4,13 @@ import (
 	"context"
 	"crypto/sha256"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
 	gogit "github.com/go-git/go-git/v5"
@@ -21,18 +24,14 @@ func (sm *SessionManager) IngestRemote(ctx context.Context, name, url string, de
 	// Define local path for persistence
 	baseDir := appconfig.Global.RemotesDir()
 
-	// Requirement: Single persistent remote. Clean up others.
-	// We use URL hash for directory name so clients pointing to old URL paths fail.
-	hash := sha256.Sum256([]byte(url))
-	dirName := hex.EncodeToString(hash[:])
-	repoPath := filepath.Join(baseDir, dirName)
-	if absPath, err := filepath.Abs(repoPath); err == nil {
-		repoPath = absPath
-	}
+	// Each source gets its own directory (named by the URL hash), so several
+	// shared remotes coexist and sessions can attach to any number of them.
+	repoPath := sharedRemotePath(url)
 
-	// Serialized Ingestion to prevent race conditions (main vs frontend)
-	sm.ingestMu.Lock()
-	defer sm.ingestMu.Unlock()
+	// Serialized Ingestion of the same source to prevent race conditions (main vs frontend)
+	unlock := sm.lockRemotePath(repoPath)
+	defer unlock()
+	progress := ingestProgressWriter(ctx)
 
 	// 1. Ensure Base Directory exists
 	if err := os.MkdirAll(baseDir, 0750); err != nil {
@@ -92,11 +91,14 @@ func (sm *SessionManager) IngestRemote(ctx context.Context, name, url string, de
 			}
 
 			// It exists. Fetch to update refs.
-			errFetch := r.Fetch(&gogit.FetchOptions{
-				Progress: os.Stdout,
+			errFetch := r.FetchContext(ctx, &gogit.FetchOptions{
+				Progress: progress,
 				Force:    true, // Force update refs
 				Tags:     gogit.AllTags,
 			})
+			if ctx.Err() != nil {
+				return fmt.Errorf("ingest canceled: %w", ctx.Err())
+			}
 			if errFetch != nil && errFetch != gogit.NoErrAlreadyUpToDate {
 				log.Printf("IngestRemote: Fetch failed (%v), falling back to fresh clone", errFetch)
 				// Fallthrough to clone is risky if we have bad config, but we just fixed config.
@@ -142,13 +144,19 @@ func (sm *SessionManager) IngestRemote(ctx context.Context, name, url string, de
 		// Setup clone options
 		cloneOpts := &gogit.CloneOptions{
 			URL:      url,
-			Progress: os.Stdout,
+			Progress: progress,
 			Depth:    depth,
 			Tags:     gogit.AllTags,
 		}
 
+		if reporter := ingestReporterFrom(ctx); reporter != nil {
+			stop := reporter.watchObjectStore(repoPath)
+			defer stop()
+		}
 		r, errClone := gogit.PlainCloneContext(ctx, repoPath, true, cloneOpts)
 		if errClone != nil {
+			// Do not leave a partial clone behind for the next ingest to "update"
+			_ = os.RemoveAll(repoPath)
 			return fmt.Errorf("failed to clone remote: %w", errClone)
 		}
 
@@ -166,7 +174,7 @@ func (sm *SessionManager) IngestRemote(ctx context.Context, name, url string, de
 		}
 
 		// Force fetch with new refspecs
-		errFetch := r.Fetch(&gogit.FetchOptions{
+		errFetch := r.FetchContext(ctx, &gogit.FetchOptions{
 			Force: true,
 			Tags:  gogit.AllTags,
 		})
@@ -178,12 +186,83 @@ func (sm *SessionManager) IngestRemote(ctx context.Context, name, url string, de
 		log.Printf("IngestRemote: Clone and refspec fix successful")
 	}
 
-	// 4. Update State - Needs LOCK
+	// 4. Update State
+	sm.registerSharedRemote(name, url, repoPath, repo)
+
+	// 5. Prune Stale Workspaces - DISABLED
+	// go sm.pruneStaleWorkspaces(oldPaths)
+
+	return nil
+}
+
+// IngestRepository creates a shared remote from a source that cannot be cloned
+// (a bundle file, an unpacked archive). populate receives an empty directory and
+// must leave a bare repository in it. source is registered as an alias like a URL.
+func (sm *SessionManager) IngestRepository(name, source string, populate func(repoPath string) (*gogit.Repository, error)) error {
+	repoPath := sharedRemotePath(source)
+
+	unlock := sm.lockRemotePath(repoPath)
+	defer unlock()
+
+	_ = os.RemoveAll(repoPath)
+	if err := os.MkdirAll(repoPath, 0750); err != nil {
+		return fmt.Errorf("failed to create remote dir: %w", err)
+	}
+	repo, err := populate(repoPath)
+	if err != nil {
+		_ = os.RemoveAll(repoPath)
+		return err
+	}
+
+	sm.registerSharedRemote(name, source, repoPath, repo)
+	return nil
+}
+
+// CopyRepository initialises a bare repository at repoPath holding all objects,
+// branches and tags of src. Unlike a clone it needs neither a git binary nor a
+// network, so offline ingest sources use it. It stops when ctx is cancelled.
+func CopyRepository(ctx context.Context, src *gogit.Repository, repoPath string) (*gogit.Repository, error) {
+	repo, err := gogit.PlainInit(repoPath, true)
+	if err != nil {
+		return nil, fmt.Errorf("failed to init repository: %w", err)
+	}
+	if err := copyAllObjects(ctx, src.Storer, repo.Storer); err != nil {
+		return nil, fmt.Errorf("failed to copy objects: %w", err)
+	}
+	if err := copyRefs(src, repo); err != nil {
+		return nil, err
+	}
+	return repo, nil
+}
+
+// sharedRemotePath returns the directory a shared remote ingested from source is stored in.
+func sharedRemotePath(source string) string {
+	hash := sha256.Sum256([]byte(source))
+	repoPath := filepath.Join(appconfig.Global.RemotesDir(), hex.EncodeToString(hash[:]))
+	if absPath, err := filepath.Abs(repoPath); err == nil {
+		repoPath = absPath
+	}
+	return repoPath
+}
+
+// registerSharedRemote makes repo reachable under its name, its source URL and its
+// internal path.
+func (sm *SessionManager) registerSharedRemote(name, url, repoPath string, repo *gogit.Repository) {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
+	sm.registerSharedRemoteLocked(name, url, repoPath, repo)
+}
+
+// registerSharedRemoteLocked is registerSharedRemote without locking. Caller holds sm.mu.
+func (sm *SessionManager) registerSharedRemoteLocked(name, url, repoPath string, repo *gogit.Repository) {
+	// A name re-ingested from another source must not stay reachable under the old URL
+	if oldPath, ok := sm.SharedRemotePaths[name]; ok && oldPath != repoPath {
+		sm.dropStaleAliases(name, oldPath)
+	}
 
 	// Store under Name
 	sm.SharedRemotes[name] = repo
+	sm.remoteNames[name] = true
 	sm.SharedRemotePaths[name] = repoPath
 
 	// Store under URL (so git clone <url> works)
@@ -193,14 +272,26 @@ func (sm *SessionManager) IngestRemote(ctx context.Context, name, url string, de
 	// Store under Internal Path (so fetches using internal path work)
 	sm.SharedRemotes[repoPath] = repo
 	sm.SharedRemotePaths[repoPath] = repoPath
+}
 
-	// 5. Prune Stale Workspaces - DISABLED
-	// go sm.pruneStaleWorkspaces(oldPaths)
-
-	return nil
+// dropStaleAliases removes the URL and path aliases of oldPath once name moves
+// away from it, unless another registered name still lives there. Caller holds sm.mu.
+func (sm *SessionManager) dropStaleAliases(name, oldPath string) {
+	for k, p := range sm.SharedRemotePaths {
+		if p == oldPath && k != name && sm.remoteNames[k] {
+			return
+		}
+	}
+	for k, p := range sm.SharedRemotePaths {
+		if p == oldPath && k != name {
+			delete(sm.SharedRemotes, k)
+			delete(sm.SharedRemotePaths, k)
+		}
+	}
 }
 
-// RemoveRemote removes a shared remote and cleans up all shared remotes (Single Residency)
+// RemoveRemote removes a shared remote with its aliases, pull requests and settings.
+// Other shared remotes are left alone.
 func (sm *SessionManager) RemoveRemote(name string) error {
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
@@ -211,6 +302,18 @@ func (sm *SessionManager) RemoveRemote(name string) error {
 		return fmt.Errorf("remote '%s' not found", name)
 	}
 
+	// Forks borrow objects from their upstream, so it must outlive them
+	var forks []string
+	for fork, parent := range sm.ForkParents {
+		if parent == name {
+			forks = append(forks, fork)
+		}
+	}
+	if len(forks) > 0 {
+		sort.Strings(forks)
+		return fmt.Errorf("remote '%s' has forks (%s); remove them first", name, strings.Join(forks, ", "))
+	}
+
 	// 1. Resolve Path and Clean up disk if it exists
 	path, pathOk := sm.SharedRemotePaths[name]
 	if pathOk && path != "" {
@@ -223,25 +326,31 @@ func (sm *SessionManager) RemoveRemote(name string) error {
 	}
 
 	// 2. Clear specific entries in SharedRemotes
+	delete(sm.CommitStatuses, name)
 	delete(sm.SharedRemotes, name)
 	delete(sm.SharedRemotePaths, name)
+	delete(sm.remoteNames, name)
 
 	// Clean up related mappings (URL, Path aliases)
 	for k, v := range sm.SharedRemotePaths {
 		if v == path {
 			delete(sm.SharedRemotes, k)
 			delete(sm.SharedRemotePaths, k)
+			delete(sm.remoteNames, k)
 		}
 	}
 
-	// 3. Clear pull requests associated with this remote (by RemoteName)
+	// 3. Clear pull requests associated with this remote (by RemoteName or fork HeadRepo)
 	var keptPRs []*PullRequest
 	for _, pr := range sm.PullRequests {
-		if pr.RemoteName != name {
+		if pr.RemoteName != name && pr.HeadRepo != name {
 			keptPRs = append(keptPRs, pr)
 		}
 	}
 	sm.PullRequests = keptPRs
+	delete(sm.Protections, name)
+	delete(sm.StatusChecks, name)
+	delete(sm.ForkParents, name)
 
 	return nil
 }
@@ -264,6 +373,44 @@ func (sm *SessionManager) CreatePullRequest(title, description, sourceBranch, ta
 	sm.mu.Lock()
 	defer sm.mu.Unlock()
 
+	return sm.createPullRequest(title, description, sourceBranch, targetBranch, creator, remoteName), nil
+}
+
+// CreateForkPullRequest creates a pull request from headRepo:sourceBranch into
+// remoteName:targetBranch. headRepo must be remoteName or belong to its fork network.
+func (sm *SessionManager) CreateForkPullRequest(title, description, headRepo, sourceBranch, targetBranch, creator, remoteName string) (*PullRequest, error) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if headRepo == "" || headRepo == remoteName {
+		return sm.createPullRequest(title, description, sourceBranch, targetBranch, creator, remoteName), nil
+	}
+	if _, ok := sm.SharedRemotes[headRepo]; !ok {
+		return nil, fmt.Errorf("remote '%s' not found", headRepo)
+	}
+	if sm.forkNetworkRoot(headRepo) != sm.forkNetworkRoot(remoteName) {
+		return nil, fmt.Errorf("'%s' is not a fork of '%s'", headRepo, remoteName)
+	}
+
+	pr := sm.createPullRequest(title, description, sourceBranch, targetBranch, creator, remoteName)
+	pr.HeadRepo = headRepo
+	pr.BaseRepo = remoteName
+	return pr, nil
+}
+
+// forkNetworkRoot follows fork parents up to the original repository. Caller holds sm.mu.
+func (sm *SessionManager) forkNetworkRoot(name string) string {
+	for {
+		parent, ok := sm.ForkParents[name]
+		if !ok {
+			return name
+		}
+		name = parent
+	}
+}
+
+// createPullRequest appends a new open pull request. Caller holds sm.mu.
+func (sm *SessionManager) createPullRequest(title, description, sourceBranch, targetBranch, creator, remoteName string) *PullRequest {
 	id := sm.NextPRID
 	sm.NextPRID++
 	pr := &PullRequest{
@@ -278,7 +425,7 @@ func (sm *SessionManager) CreatePullRequest(title, description, sourceBranch, ta
 		RemoteName:  remoteName,
 	}
 	sm.PullRequests = append(sm.PullRequests, pr)
-	return pr, nil
+	return pr
 }
 
 // DeletePullRequest removes a pull request by ID
@@ -305,28 +452,16 @@ func (sm *SessionManager) DeletePullRequest(id int) error {
 // This only creates the remote repository - users must manually git clone or git init
 func (sm *SessionManager) CreateBareRepository(ctx context.Context, sessionID, name string) error {
 	// 1. Validate Name (Simple alphanumeric check)
-	for _, r := range name {
-		if !((r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-' || r == '_') {
-			return fmt.Errorf("invalid repository name: only alphanumeric, hyphen and underscore allowed")
-		}
+	if err := validateRepositoryName(name); err != nil {
+		return err
 	}
 
 	// Define local path for persistence
 	baseDir := appconfig.Global.RemotesDir()
+	pseudoURL, repoPath := remoteLocation(name)
 
-	// Use name or hash for directory? "remote://gitgym/{name}" -> hash
-	// To be consistent with IngestRemote which hashes the URL.
-	// Let's construct a pseudo-URL for consistency.
-	pseudoURL := fmt.Sprintf("remote://gitgym/%s.git", name)
-	hash := sha256.Sum256([]byte(pseudoURL))
-	dirName := hex.EncodeToString(hash[:])
-	repoPath := filepath.Join(baseDir, dirName)
-	if absPath, err := filepath.Abs(repoPath); err == nil {
-		repoPath = absPath
-	}
-
-	sm.ingestMu.Lock()
-	defer sm.ingestMu.Unlock()
+	unlock := sm.lockRemotePath(repoPath)
+	defer unlock()
 
 	// 2. Ensure Base Directory exists
 	if err := os.MkdirAll(baseDir, 0750); err != nil {
@@ -345,21 +480,40 @@ func (sm *SessionManager) CreateBareRepository(ctx context.Context, sessionID, n
 		return fmt.Errorf("failed to init bare repo: %w", err)
 	}
 
+	// Default branch 'main' to match InitRepo, so clones over HTTP check out the pushed branch
+	headRef := plumbing.NewSymbolicReference(plumbing.HEAD, plumbing.NewBranchReferenceName("main"))
+	if err := repo.Storer.SetReference(headRef); err != nil {
+		return fmt.Errorf("failed to set HEAD: %w", err)
+	}
+
 	// 4. Update Session Manager State
 	sm.mu.Lock()
 
 	// Register under Name, PseudoURL, and Path
-	sm.SharedRemotes[name] = repo
-	sm.SharedRemotePaths[name] = repoPath
-
-	sm.SharedRemotes[pseudoURL] = repo
-	sm.SharedRemotePaths[pseudoURL] = repoPath
-
-	sm.SharedRemotes[repoPath] = repo
-	sm.SharedRemotePaths[repoPath] = repoPath
+	sm.registerSharedRemoteLocked(name, pseudoURL, repoPath, repo)
 	sm.mu.Unlock()
 
 	log.Printf("Created bare repository: %s at %s", name, repoPath)
 
 	return nil
 }
+
+// ErrInvalidRepositoryName is returned for names that are not safe as URL path segments.
+var ErrInvalidRepositoryName = errors.New("invalid repository name")
+
+// validateRepositoryName allows only names that are safe as URL path segments.
+func validateRepositoryName(name string) error {
+	for _, r := range name {
+		if !((r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '-' || r == '_') {
+			return fmt.Errorf("%w: only alphanumeric, hyphen and underscore allowed", ErrInvalidRepositoryName)
+		}
+	}
+	return nil
+}
+
+// remoteLocation returns the pseudo-URL and on-disk path of a created remote.
+// The directory is named after the hashed pseudo-URL, consistent with IngestRemote.
+func remoteLocation(name string) (pseudoURL, repoPath string) {
+	pseudoURL = fmt.Sprintf("remote://gitgym/%s.git", name)
+	return pseudoURL, sharedRemotePath(pseudoURL)
+}
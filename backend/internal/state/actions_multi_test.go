@@ -2,7 +2,16 @@ package state
 
 import (
 	"context"
+	"strings"
 	"testing"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestMultiRemotePersistence(t *testing.T) {
@@ -75,3 +84,52 @@ func TestPRRemoteAssociation(t *testing.T) {
 		t.Error("PR on 'upstream' should have been deleted")
 	}
 }
+
+func TestReingestRemote(t *testing.T) {
+	sm := NewSessionManager()
+	repoA, head := newCommittedRepo(t)
+	repoB, _ := newCommittedRepo(t)
+	other, _ := newCommittedRepo(t)
+	ingest := func(name, source string, repo *gogit.Repository) {
+		require.NoError(t, sm.IngestRepository(name, source, func(string) (*gogit.Repository, error) { return repo, nil }))
+	}
+
+	ingest("origin", "https://a.example.com/repo.git", repoA)
+	ingest("mirror", "https://a.example.com/repo.git", other)
+	longName := strings.Repeat("r", 60)
+	ingest(longName, "https://c.example.com/repo.git", other)
+	sm.SetCommitStatus(repoA, head, &CommitStatus{Context: "ci", State: StatusSuccess})
+
+	ingest("origin", "https://b.example.com/repo.git", repoB)
+
+	// Statuses follow the name, not the repository it was ingested into
+	statuses := sm.GetCommitStatuses(sm.SharedRemotes["origin"], head)
+	require.Len(t, statuses, 1)
+	assert.Equal(t, "ci", statuses[0].Context)
+
+	// The old URL stays while mirror still uses it
+	_, ok := sm.GetSharedRemote("https://a.example.com/repo.git")
+	assert.True(t, ok)
+	ingest("mirror", "https://b.example.com/repo.git", other)
+	_, ok = sm.GetSharedRemote("https://a.example.com/repo.git")
+	assert.False(t, ok, "the old source should no longer resolve")
+
+	assert.Equal(t, []string{"mirror", "origin", longName}, sm.SharedRemoteNames())
+	name, ok := sm.SharedRemoteName("https://c.example.com/repo.git")
+	assert.True(t, ok)
+	assert.Equal(t, longName, name)
+}
+
+// newCommittedRepo returns an in-memory repository with one commit.
+func newCommittedRepo(t *testing.T) (*gogit.Repository, plumbing.Hash) {
+	t.Helper()
+	repo, err := gogit.Init(memory.NewStorage(), memfs.New())
+	require.NoError(t, err)
+	w, _ := repo.Worktree()
+	hash, err := w.Commit("Initial commit", &gogit.CommitOptions{
+		AllowEmptyCommits: true,
+		Author:            &object.Signature{Name: "Dev", Email: "dev@example.com"},
+	})
+	require.NoError(t, err)
+	return repo, hash
+}
@@ -21,6 +21,7 @@ func TestCreateBareRepository(t *testing.T) {
 		sessions:          make(map[string]*Session),
 		SharedRemotes:     make(map[string]*gogit.Repository),
 		SharedRemotePaths: make(map[string]string),
+		remoteNames:       make(map[string]bool),
 	}
 
 	// Create a mock session
@@ -78,6 +79,7 @@ func TestRemoveRemote(t *testing.T) {
 		sessions:          make(map[string]*Session),
 		SharedRemotes:     make(map[string]*gogit.Repository),
 		SharedRemotePaths: make(map[string]string),
+		remoteNames:       make(map[string]bool),
 		PullRequests:      []*PullRequest{},
 	}
 
@@ -133,6 +135,7 @@ func TestMultipleRemotesCoexistence(t *testing.T) {
 		sessions:          make(map[string]*Session),
 		SharedRemotes:     make(map[string]*gogit.Repository),
 		SharedRemotePaths: make(map[string]string),
+		remoteNames:       make(map[string]bool),
 		PullRequests:      []*PullRequest{},
 	}
 
@@ -0,0 +1,516 @@
+package state
+
+// bots.go - Scripted Teammate Bots
+//
+// Bots are simulated teammates that commit to shared remotes on their own,
+// following declarative scripts such as "every 2m commit to feature-x" or
+// "after someone pushes main, push a change to README.md". The BotScheduler
+// owned by the SessionManager runs the scripts on a ticker and can be paused,
+// resumed or stepped one action at a time, so multi-developer scenarios unfold
+// without an instructor clicking "simulate commit".
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+const maxBotEvents = 100
+
+// BotScript is one simulated teammate and the actions it performs on a shared remote.
+type BotScript struct {
+	Name    string       `json:"name"`            // Author name, e.g. "Alice"
+	Email   string       `json:"email,omitempty"` // Defaults to <name>@example.com
+	Remote  string       `json:"remote"`          // Shared remote the bot pushes to
+	Actions []*BotAction `json:"actions"`
+}
+
+// BotAction is a commit a bot pushes when its trigger fires.
+// "{n}" in File, Content and Message is replaced with the run number.
+type BotAction struct {
+	Every  string `json:"every,omitempty"`  // Repeat interval, e.g. "2m"
+	After  string `json:"after,omitempty"`  // Delay before the first run, or after the push for OnPush
+	OnPush string `json:"onPush,omitempty"` // Run after someone else pushes this branch
+	Times  int    `json:"times,omitempty"`  // Maximum runs; 0 means unlimited (once for After-only actions)
+
+	Branch  string `json:"branch"`            // Branch to commit to; created from From when missing
+	From    string `json:"from,omitempty"`    // Start point of a new Branch (default: the remote's HEAD)
+	File    string `json:"file,omitempty"`    // Default: <name>_{n}.txt
+	Content string `json:"content,omitempty"` // Line appended to File
+	Replace bool   `json:"replace,omitempty"` // Overwrite File with Content instead of appending
+	Message string `json:"message,omitempty"`
+}
+
+// BotEvent records one action a bot ran.
+type BotEvent struct {
+	Time    time.Time `json:"time"`
+	Bot     string    `json:"bot"`
+	Remote  string    `json:"remote"`
+	Branch  string    `json:"branch"`
+	Commit  string    `json:"commit,omitempty"`
+	Message string    `json:"message,omitempty"`
+	Error   string    `json:"error,omitempty"`
+}
+
+// BotJobStatus describes a scheduled action for the API.
+type BotJobStatus struct {
+	Bot     string     `json:"bot"`
+	Remote  string     `json:"remote"`
+	Branch  string     `json:"branch"`
+	Trigger string     `json:"trigger"` // e.g. "every 2m0s", "on push to main"
+	Runs    int        `json:"runs"`
+	Next    *time.Time `json:"next,omitempty"` // nil when waiting for a push or finished
+}
+
+// BotStatus is a snapshot of the scheduler.
+type BotStatus struct {
+	Running bool           `json:"running"`
+	Scripts []*BotScript   `json:"scripts"`
+	Jobs    []BotJobStatus `json:"jobs"`
+	Events  []BotEvent     `json:"events"`
+}
+
+type botJob struct {
+	script *BotScript
+	action *BotAction
+	every  time.Duration
+	after  time.Duration
+	next   time.Time // Zero when not scheduled
+	runs   int
+	busy   bool // Claimed by Tick or Step and not finished yet
+}
+
+func (j *botJob) exhausted() bool {
+	limit := j.action.Times
+	if limit == 0 && j.every == 0 && j.action.OnPush == "" {
+		limit = 1
+	}
+	return limit > 0 && j.runs >= limit
+}
+
+func (j *botJob) trigger() string {
+	var parts []string
+	if j.action.OnPush != "" {
+		parts = append(parts, "on push to "+j.action.OnPush)
+	}
+	if j.after > 0 {
+		parts = append(parts, "after "+j.after.String())
+	}
+	if j.every > 0 {
+		parts = append(parts, "every "+j.every.String())
+	}
+	return strings.Join(parts, ", ")
+}
+
+// BotScheduler runs bot scripts against the shared remotes of a SessionManager.
+type BotScheduler struct {
+	// AfterCommit is called after a bot moves a branch (e.g. to refresh pull requests).
+	AfterCommit func(repo *gogit.Repository)
+	// Interval is how often a running scheduler looks for due actions.
+	Interval time.Duration
+
+	sm    *SessionManager
+	now   func() time.Time
+	mu    sync.Mutex
+	runMu sync.Mutex // Serializes bot commits
+
+	scripts []*BotScript
+	jobs    []*botJob
+	events  []BotEvent
+	running bool
+	stop    chan struct{}
+}
+
+func newBotScheduler(sm *SessionManager) *BotScheduler {
+	return &BotScheduler{sm: sm, now: time.Now, Interval: time.Second}
+}
+
+// Load validates scripts and replaces the current ones. Time-based actions are
+// scheduled from now; the running/paused state is kept.
+func (b *BotScheduler) Load(scripts []*BotScript) error {
+	var jobs []*botJob
+	for _, script := range scripts {
+		if script.Name == "" {
+			return fmt.Errorf("bot requires a name")
+		}
+		if _, ok := b.sm.GetSharedRemote(script.Remote); !ok {
+			return fmt.Errorf("bot %s: remote '%s' not found", script.Name, script.Remote)
+		}
+		if len(script.Actions) == 0 {
+			return fmt.Errorf("bot %s has no actions", script.Name)
+		}
+		for i, action := range script.Actions {
+			job, err := newBotJob(script, action)
+			if err != nil {
+				return fmt.Errorf("bot %s, action %d: %w", script.Name, i+1, err)
+			}
+			jobs = append(jobs, job)
+		}
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	for _, job := range jobs {
+		if job.action.OnPush != "" {
+			continue
+		}
+		if job.after > 0 {
+			job.next = now.Add(job.after)
+		} else {
+			job.next = now.Add(job.every)
+		}
+	}
+	b.scripts = scripts
+	b.jobs = jobs
+	return nil
+}
+
+func newBotJob(script *BotScript, action *BotAction) (*botJob, error) {
+	job := &botJob{script: script, action: action}
+	var err error
+	if action.Every != "" {
+		if job.every, err = time.ParseDuration(action.Every); err != nil || job.every <= 0 {
+			return nil, fmt.Errorf("invalid interval '%s'", action.Every)
+		}
+	}
+	if action.After != "" {
+		if job.after, err = time.ParseDuration(action.After); err != nil || job.after < 0 {
+			return nil, fmt.Errorf("invalid delay '%s'", action.After)
+		}
+	}
+	if action.Every == "" && action.After == "" && action.OnPush == "" {
+		return nil, fmt.Errorf("needs a trigger (every, after or onPush)")
+	}
+	if action.Times < 0 {
+		return nil, fmt.Errorf("times must not be negative")
+	}
+	if action.Branch == "" {
+		return nil, fmt.Errorf("needs a branch")
+	}
+	if !plumbing.NewBranchReferenceName(action.Branch).IsBranch() || strings.Contains(action.Branch, "..") {
+		return nil, fmt.Errorf("invalid branch name '%s'", action.Branch)
+	}
+	if action.File != "" {
+		if clean := path.Clean(action.File); clean != action.File || strings.HasPrefix(clean, "../") || path.IsAbs(clean) || clean == ".." {
+			return nil, fmt.Errorf("invalid file path '%s'", action.File)
+		}
+	}
+	return job, nil
+}
+
+// Resume starts running due actions every Interval.
+func (b *BotScheduler) Resume() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.running {
+		return
+	}
+	b.running = true
+	b.stop = make(chan struct{})
+	go b.loop(b.stop, b.Interval)
+}
+
+// Pause stops the scheduler; pending actions keep their schedule.
+func (b *BotScheduler) Pause() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !b.running {
+		return
+	}
+	close(b.stop)
+	b.running = false
+}
+
+func (b *BotScheduler) loop(stop chan struct{}, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			b.Tick()
+		}
+	}
+}
+
+// Tick runs every action that is due.
+func (b *BotScheduler) Tick() []BotEvent {
+	b.mu.Lock()
+	now := b.now()
+	var due []*botJob
+	for _, job := range b.jobs {
+		if !job.busy && !job.next.IsZero() && !job.next.After(now) {
+			job.busy = true // A concurrent Tick or Step must not run it again
+			due = append(due, job)
+		}
+	}
+	sort.SliceStable(due, func(i, j int) bool { return due[i].next.Before(due[j].next) })
+	b.mu.Unlock()
+
+	var events []BotEvent
+	for _, job := range due {
+		events = append(events, b.run(job))
+	}
+	return events
+}
+
+// Step runs the scheduled action that is due next, without waiting for it.
+func (b *BotScheduler) Step() (BotEvent, error) {
+	b.mu.Lock()
+	var next *botJob
+	for _, job := range b.jobs {
+		if !job.busy && !job.next.IsZero() && (next == nil || job.next.Before(next.next)) {
+			next = job
+		}
+	}
+	if next != nil {
+		next.busy = true
+	}
+	b.mu.Unlock()
+
+	if next == nil {
+		return BotEvent{}, fmt.Errorf("no bot actions are scheduled")
+	}
+	return b.run(next), nil
+}
+
+// NotifyPush schedules the actions waiting for a push of ref to repo.
+// Bots' own commits do not trigger it.
+func (b *BotScheduler) NotifyPush(repo *gogit.Repository, ref plumbing.ReferenceName) {
+	if b == nil || !ref.IsBranch() {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	for _, job := range b.jobs {
+		if job.action.OnPush != ref.Short() || job.exhausted() {
+			continue
+		}
+		if remote, ok := b.sm.GetSharedRemote(job.script.Remote); !ok || remote != repo {
+			continue
+		}
+		job.next = now.Add(job.after)
+	}
+}
+
+// Status returns a snapshot of the scripts, their schedule and recent events.
+func (b *BotScheduler) Status() BotStatus {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	status := BotStatus{
+		Running: b.running,
+		Scripts: append([]*BotScript{}, b.scripts...),
+		Jobs:    []BotJobStatus{},
+		Events:  append([]BotEvent{}, b.events...),
+	}
+	for _, job := range b.jobs {
+		js := BotJobStatus{
+			Bot:     job.script.Name,
+			Remote:  job.script.Remote,
+			Branch:  job.action.Branch,
+			Trigger: job.trigger(),
+			Runs:    job.runs,
+		}
+		if !job.next.IsZero() {
+			next := job.next
+			js.Next = &next
+		}
+		status.Jobs = append(status.Jobs, js)
+	}
+	return status
+}
+
+// run performs one action claimed by Tick or Step and reschedules it.
+func (b *BotScheduler) run(job *botJob) BotEvent {
+	b.runMu.Lock()
+	defer b.runMu.Unlock()
+
+	b.mu.Lock()
+	now := b.now()
+	job.runs++
+	n := job.runs
+	job.next = time.Time{}
+	if job.every > 0 && !job.exhausted() {
+		job.next = now.Add(job.every)
+	}
+	b.mu.Unlock()
+
+	event := BotEvent{Time: now, Bot: job.script.Name, Remote: job.script.Remote, Branch: job.action.Branch}
+	repo, ok := b.sm.GetSharedRemote(job.script.Remote)
+	if !ok {
+		event.Error = fmt.Sprintf("remote '%s' not found", job.script.Remote)
+	} else if hash, message, err := b.commit(repo, job, n, now); err != nil {
+		event.Error = err.Error()
+	} else {
+		event.Commit = hash.String()
+		event.Message = message
+		b.sm.RunStatusChecks(repo, hash)
+		if b.AfterCommit != nil {
+			b.AfterCommit(repo)
+		}
+	}
+
+	b.mu.Lock()
+	job.busy = false
+	b.events = append(b.events, event)
+	if len(b.events) > maxBotEvents {
+		b.events = b.events[len(b.events)-maxBotEvents:]
+	}
+	b.mu.Unlock()
+	return event
+}
+
+// commit writes the action's change on top of its branch and moves the branch,
+// subject to the remote's branch protection like any other push. Like a push it
+// holds the session manager's lock and builds the commit in a quarantine, so a
+// rejected commit leaves no objects in the remote.
+func (b *BotScheduler) commit(remote *gogit.Repository, job *botJob, n int, when time.Time) (plumbing.Hash, string, error) {
+	b.sm.mu.Lock()
+	defer b.sm.mu.Unlock()
+
+	repo, err := Quarantine(remote)
+	if err != nil {
+		return plumbing.ZeroHash, "", err
+	}
+	script, action := job.script, job.action
+	expand := func(s string) string {
+		return strings.ReplaceAll(s, "{n}", strconv.Itoa(n))
+	}
+
+	refName := plumbing.NewBranchReferenceName(action.Branch)
+	oldHash := plumbing.ZeroHash
+	var parent *object.Commit
+	if ref, err := repo.Reference(refName, true); err == nil {
+		oldHash = ref.Hash()
+		if parent, err = repo.CommitObject(oldHash); err != nil {
+			return plumbing.ZeroHash, "", err
+		}
+	} else if start, ok := botStartPoint(repo, action.From); ok {
+		if parent, err = repo.CommitObject(start); err != nil {
+			return plumbing.ZeroHash, "", err
+		}
+	} else if action.From != "" {
+		return plumbing.ZeroHash, "", fmt.Errorf("start point '%s' not found", action.From)
+	}
+
+	file := action.File
+	if file == "" {
+		file = strings.ToLower(strings.ReplaceAll(script.Name, " ", "_")) + "_{n}.txt"
+	}
+	file = expand(file)
+	line := expand(action.Content)
+	if line == "" {
+		line = fmt.Sprintf("%s's change #%d", script.Name, n)
+	}
+	if !strings.HasSuffix(line, "\n") {
+		line += "\n"
+	}
+
+	files, err := CommitFiles(parent)
+	if err != nil {
+		return plumbing.ZeroHash, "", err
+	}
+	content, mode := line, filemode.Regular
+	if f, ok := files[file]; ok {
+		if f.Mode == filemode.Executable {
+			mode = filemode.Executable
+		}
+		if !action.Replace {
+			blob, err := repo.BlobObject(f.Hash)
+			if err != nil {
+				return plumbing.ZeroHash, "", err
+			}
+			old, err := object.NewFile(file, f.Mode, blob).Contents()
+			if err != nil {
+				return plumbing.ZeroHash, "", err
+			}
+			if old != "" && !strings.HasSuffix(old, "\n") {
+				old += "\n"
+			}
+			content = old + line
+		}
+	}
+	// The file replaces whatever sits on its path: a file at a parent
+	// directory's name, or a directory at its own
+	for p := range files {
+		if strings.HasPrefix(file, p+"/") || strings.HasPrefix(p, file+"/") {
+			delete(files, p)
+		}
+	}
+
+	blobHash, err := StoreBlob(repo, content)
+	if err != nil {
+		return plumbing.ZeroHash, "", err
+	}
+	files[file] = TreeFile{Hash: blobHash, Mode: mode}
+	treeHash, err := WriteTree(repo.Storer, files)
+	if err != nil {
+		return plumbing.ZeroHash, "", err
+	}
+
+	message := expand(action.Message)
+	if message == "" {
+		message = fmt.Sprintf("Update %s", file)
+	}
+	email := script.Email
+	if email == "" {
+		email = strings.ToLower(strings.ReplaceAll(script.Name, " ", ".")) + "@example.com"
+	}
+	sig := object.Signature{Name: script.Name, Email: email, When: when}
+	commit := &object.Commit{Author: sig, Committer: sig, Message: message, TreeHash: treeHash}
+	if parent != nil {
+		commit.ParentHashes = []plumbing.Hash{parent.Hash}
+	}
+	hash, err := StoreObject(repo, commit)
+	if err != nil {
+		return plumbing.ZeroHash, "", err
+	}
+
+	if err := b.sm.CheckRefUpdateLocked(remote, RefUpdate{Name: refName, Old: oldHash, New: hash, Pusher: sig, Quarantine: repo}); err != nil {
+		var pe *ProtectionError
+		if errors.As(err, &pe) {
+			return plumbing.ZeroHash, "", fmt.Errorf("%s: %s", pe.Error(), strings.Join(pe.Reasons, " "))
+		}
+		return plumbing.ZeroHash, "", err
+	}
+	if err := AcceptQuarantine(repo, remote); err != nil {
+		return plumbing.ZeroHash, "", err
+	}
+	if err := remote.Storer.SetReference(plumbing.NewHashReference(refName, hash)); err != nil {
+		return plumbing.ZeroHash, "", err
+	}
+	return hash, message, nil
+}
+
+// botStartPoint resolves the commit a new bot branch starts from: the branch
+// from, or the branch the remote's HEAD points to.
+func botStartPoint(repo *gogit.Repository, from string) (plumbing.Hash, bool) {
+	if from != "" {
+		ref, err := repo.Reference(plumbing.NewBranchReferenceName(from), true)
+		if err != nil {
+			return plumbing.ZeroHash, false
+		}
+		return ref.Hash(), true
+	}
+	ref, err := repo.Reference(plumbing.HEAD, true)
+	if err != nil {
+		return plumbing.ZeroHash, false
+	}
+	return ref.Hash(), true
+}
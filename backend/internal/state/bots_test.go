@@ -0,0 +1,173 @@
+package state
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/go-git/go-billy/v5/memfs"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage/memory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBotScheduler(t *testing.T) {
+	sm := NewSessionManager()
+	repo, err := gogit.Init(memory.NewStorage(), memfs.New())
+	require.NoError(t, err)
+	wt, _ := repo.Worktree()
+	f, _ := wt.Filesystem.Create("README.md")
+	_, _ = f.Write([]byte("# Project\n"))
+	_ = f.Close()
+	_, _ = wt.Add("README.md")
+	_, err = wt.Commit("Initial commit", &gogit.CommitOptions{Author: &object.Signature{Name: "Dev", Email: "dev@example.com", When: time.Now()}})
+	require.NoError(t, err)
+	sm.SharedRemotes["team"] = repo
+
+	clock := time.Date(2026, 1, 1, 9, 0, 0, 0, time.UTC)
+	sm.Bots.now = func() time.Time { return clock }
+	var refreshed int
+	sm.Bots.AfterCommit = func(*gogit.Repository) { refreshed++ }
+
+	tip := func(branch string) *object.Commit {
+		t.Helper()
+		ref, err := repo.Reference(plumbing.NewBranchReferenceName(branch), true)
+		require.NoError(t, err)
+		c, err := repo.CommitObject(ref.Hash())
+		require.NoError(t, err)
+		return c
+	}
+
+	require.NoError(t, sm.Bots.Load([]*BotScript{{
+		Name:   "Alice",
+		Remote: "team",
+		Actions: []*BotAction{
+			{Every: "2m", Times: 2, Branch: "feature-x", File: "src/feature.txt", Content: "step {n}", Message: "Feature step {n}"},
+			{OnPush: "master", Branch: "master", File: "README.md", Content: "# Alice's title", Replace: true, Message: "Retitle README"},
+		},
+	}}))
+
+	t.Run("Nothing is due yet", func(t *testing.T) {
+		assert.Empty(t, sm.Bots.Tick())
+	})
+
+	t.Run("Every interval commits on a new branch", func(t *testing.T) {
+		clock = clock.Add(2 * time.Minute)
+		events := sm.Bots.Tick()
+		require.Len(t, events, 1)
+		assert.Empty(t, events[0].Error)
+
+		c := tip("feature-x")
+		assert.Equal(t, "Feature step 1", c.Message)
+		assert.Equal(t, "Alice", c.Author.Name)
+		assert.Equal(t, "alice@example.com", c.Author.Email)
+		assert.Equal(t, tip("master").Hash, c.ParentHashes[0], "new branch should start at the remote's HEAD")
+
+		file, err := c.File("src/feature.txt")
+		require.NoError(t, err)
+		content, _ := file.Contents()
+		assert.Equal(t, "step 1\n", content)
+		_, err = c.File("README.md")
+		assert.NoError(t, err, "existing files are kept")
+	})
+
+	t.Run("Step runs the next action early and appends", func(t *testing.T) {
+		event, err := sm.Bots.Step()
+		require.NoError(t, err)
+		assert.Equal(t, "Feature step 2", event.Message)
+
+		file, _ := tip("feature-x").File("src/feature.txt")
+		content, _ := file.Contents()
+		assert.Equal(t, "step 1\nstep 2\n", content)
+
+		// Times: 2 reached and the push trigger has not fired
+		_, err = sm.Bots.Step()
+		assert.Error(t, err)
+	})
+
+	t.Run("Push trigger", func(t *testing.T) {
+		sm.Bots.NotifyPush(repo, plumbing.NewBranchReferenceName("feature-x"))
+		assert.Empty(t, sm.Bots.Tick(), "pushes to other branches are ignored")
+
+		sm.Bots.NotifyPush(repo, plumbing.NewBranchReferenceName("master"))
+		events := sm.Bots.Tick()
+		require.Len(t, events, 1)
+
+		file, _ := tip("master").File("README.md")
+		content, _ := file.Contents()
+		assert.Equal(t, "# Alice's title\n", content)
+	})
+
+	t.Run("Protected branches reject bots too", func(t *testing.T) {
+		require.NoError(t, sm.SetBranchProtection("team", []*BranchProtection{{Pattern: "master", RequirePullRequest: true}}))
+		before := tip("master").Hash
+		countObjects := func() int {
+			iter, err := repo.Storer.IterEncodedObjects(plumbing.AnyObject)
+			require.NoError(t, err)
+			n := 0
+			_ = iter.ForEach(func(plumbing.EncodedObject) error { n++; return nil })
+			return n
+		}
+		objects := countObjects()
+
+		sm.Bots.NotifyPush(repo, plumbing.NewBranchReferenceName("master"))
+		events := sm.Bots.Tick()
+		require.Len(t, events, 1)
+		assert.Contains(t, events[0].Error, ProtectionHookDeclined)
+		assert.Equal(t, before, tip("master").Hash)
+		assert.Equal(t, objects, countObjects(), "a rejected commit leaves no objects behind")
+	})
+
+	status := sm.Bots.Status()
+	assert.False(t, status.Running)
+	require.Len(t, status.Jobs, 2)
+	assert.Equal(t, "every 2m0s", status.Jobs[0].Trigger)
+	assert.Equal(t, 2, status.Jobs[0].Runs)
+	assert.Nil(t, status.Jobs[0].Next)
+	assert.Len(t, status.Events, 4)
+	assert.Equal(t, 3, refreshed)
+
+	t.Run("Invalid scripts are rejected", func(t *testing.T) {
+		assert.Error(t, sm.Bots.Load([]*BotScript{{Name: "Bob", Remote: "missing", Actions: []*BotAction{{Every: "1m", Branch: "x"}}}}))
+		assert.Error(t, sm.Bots.Load([]*BotScript{{Name: "Bob", Remote: "team", Actions: []*BotAction{{Branch: "x"}}}}))
+		assert.Error(t, sm.Bots.Load([]*BotScript{{Name: "Bob", Remote: "team", Actions: []*BotAction{{Every: "soon", Branch: "x"}}}}))
+		assert.Error(t, sm.Bots.Load([]*BotScript{{Name: "Bob", Remote: "team", Actions: []*BotAction{{Every: "1m", Branch: "x", File: "../escape"}}}}))
+	})
+
+	t.Run("Concurrent ticks run a due action once", func(t *testing.T) {
+		require.NoError(t, sm.SetBranchProtection("team", nil))
+		require.NoError(t, sm.Bots.Load([]*BotScript{{Name: "Bob", Remote: "team", Actions: []*BotAction{
+			{After: "1m", Branch: "feature-x", File: "src.txt", Content: "bob"},
+		}}}))
+		clock = clock.Add(time.Minute)
+
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		var events []BotEvent
+		for range 4 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				e := sm.Bots.Tick()
+				mu.Lock()
+				events = append(events, e...)
+				mu.Unlock()
+			}()
+		}
+		wg.Wait()
+		require.Len(t, events, 1)
+		assert.Empty(t, events[0].Error)
+
+		// git sorts the directory src as "src/", after the file src.txt
+		tree, err := tip("feature-x").Tree()
+		require.NoError(t, err)
+		var names []string
+		for _, e := range tree.Entries {
+			names = append(names, e.Name)
+		}
+		assert.Equal(t, []string{"README.md", "src.txt", "src"}, names)
+	})
+}
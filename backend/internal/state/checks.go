@@ -0,0 +1,324 @@
+package state
+
+// checks.go - Simulated CI Status Checks
+//
+// Shared remotes can define check rules that behave like a CI service: every
+// push runs them against the pushed commit and records a commit status
+// (pending, success, failure) under the rule's context, e.g. "ci/tests".
+// Branch protection can require contexts to pass before a pull request is
+// merged, so learners can practise "CI is red, fix it before merging" offline.
+
+import (
+	"bufio"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Commit status states, as reported by hosted servers
+const (
+	StatusPending = "pending"
+	StatusSuccess = "success"
+	StatusFailure = "failure"
+	StatusError   = "error"
+)
+
+// Check rule types
+const (
+	CheckFileExists        = "file-exists"         // Path must exist in the commit's tree
+	CheckNoConflictMarkers = "no-conflict-markers" // No file may contain <<<<<<< / >>>>>>> lines
+	CheckMessageMatches    = "message-matches"     // Commit message must match Pattern
+)
+
+// StatusCheck is a rule evaluated on every commit pushed to a shared remote.
+type StatusCheck struct {
+	Context string `json:"context"`           // Status name, e.g. "ci/tests"
+	Type    string `json:"type"`              // One of the Check* constants
+	Path    string `json:"path,omitempty"`    // file-exists
+	Pattern string `json:"pattern,omitempty"` // message-matches: regular expression
+}
+
+// CommitStatus is the result of one context for one commit.
+type CommitStatus struct {
+	Context     string    `json:"context"`
+	State       string    `json:"state"` // "pending", "success", "failure", "error"
+	Description string    `json:"description,omitempty"`
+	SHA         string    `json:"sha"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// ValidStatusState reports whether state is a commit status state.
+func ValidStatusState(state string) bool {
+	switch state {
+	case StatusPending, StatusSuccess, StatusFailure, StatusError:
+		return true
+	}
+	return false
+}
+
+func (c *StatusCheck) validate() error {
+	if c.Context == "" {
+		return fmt.Errorf("status check requires a context")
+	}
+	switch c.Type {
+	case CheckFileExists:
+		if c.Path == "" {
+			return fmt.Errorf("status check '%s' requires a path", c.Context)
+		}
+	case CheckNoConflictMarkers:
+	case CheckMessageMatches:
+		if _, err := regexp.Compile(c.Pattern); err != nil {
+			return fmt.Errorf("invalid pattern for status check '%s': %w", c.Context, err)
+		}
+	default:
+		return fmt.Errorf("unknown status check type '%s'", c.Type)
+	}
+	return nil
+}
+
+// SetStatusChecks replaces the check rules of a shared remote.
+func (sm *SessionManager) SetStatusChecks(remote string, checks []*StatusCheck) error {
+	seen := make(map[string]bool)
+	for _, check := range checks {
+		if err := check.validate(); err != nil {
+			return err
+		}
+		if seen[check.Context] {
+			return fmt.Errorf("duplicate status check context '%s'", check.Context)
+		}
+		seen[check.Context] = true
+	}
+
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if _, ok := sm.SharedRemotes[remote]; !ok {
+		return fmt.Errorf("remote '%s' not found", remote)
+	}
+	if len(checks) == 0 {
+		delete(sm.StatusChecks, remote)
+		return nil
+	}
+	sm.StatusChecks[remote] = checks
+	return nil
+}
+
+// GetStatusChecks returns the check rules configured for a shared remote.
+func (sm *SessionManager) GetStatusChecks(remote string) []*StatusCheck {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	result := make([]*StatusCheck, len(sm.StatusChecks[remote]))
+	copy(result, sm.StatusChecks[remote])
+	return result
+}
+
+// RunStatusChecks evaluates the check rules of repo (under any alias) against
+// commit and records their statuses. Rules report pending until evaluated.
+func (sm *SessionManager) RunStatusChecks(repo *gogit.Repository, commit plumbing.Hash) {
+	sm.runStatusChecks(repo, commit, false)
+}
+
+// RunMissingStatusChecks is RunStatusChecks limited to the rules that have not
+// reported on commit yet, e.g. for commits pushed before the rule was added.
+func (sm *SessionManager) RunMissingStatusChecks(repo *gogit.Repository, commit plumbing.Hash) {
+	sm.runStatusChecks(repo, commit, true)
+}
+
+func (sm *SessionManager) runStatusChecks(repo *gogit.Repository, commit plumbing.Hash, onlyMissing bool) {
+	sm.mu.RLock()
+	reported := make(map[string]bool)
+	for _, s := range sm.CommitStatuses[sm.remoteNameFor(repo)][commit] {
+		reported[s.Context] = true
+	}
+	var checks []*StatusCheck
+	for name, rules := range sm.StatusChecks {
+		if sm.SharedRemotes[name] != repo {
+			continue
+		}
+		for _, rule := range rules {
+			if !onlyMissing || !reported[rule.Context] {
+				checks = append(checks, rule)
+			}
+		}
+	}
+	sm.mu.RUnlock()
+	if len(checks) == 0 {
+		return
+	}
+
+	for _, check := range checks {
+		sm.SetCommitStatus(repo, commit, &CommitStatus{Context: check.Context, State: StatusPending, Description: "Running " + check.Type})
+	}
+
+	c, err := repo.CommitObject(commit)
+	for _, check := range checks {
+		status := &CommitStatus{Context: check.Context}
+		if err != nil {
+			status.State, status.Description = StatusError, err.Error()
+		} else {
+			status.State, status.Description = evaluateStatusCheck(c, check)
+		}
+		sm.SetCommitStatus(repo, commit, status)
+	}
+}
+
+// SetCommitStatus records status for commit in repo, replacing any earlier
+// status with the same context.
+func (sm *SessionManager) SetCommitStatus(repo *gogit.Repository, commit plumbing.Hash, status *CommitStatus) {
+	status.SHA = commit.String()
+	if status.UpdatedAt.IsZero() {
+		status.UpdatedAt = time.Now()
+	}
+
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	name := sm.remoteNameFor(repo)
+	byCommit, ok := sm.CommitStatuses[name]
+	if !ok {
+		byCommit = make(map[plumbing.Hash][]*CommitStatus)
+		sm.CommitStatuses[name] = byCommit
+	}
+	statuses := byCommit[commit]
+	for i, existing := range statuses {
+		if existing.Context == status.Context {
+			statuses[i] = status
+			return
+		}
+	}
+	byCommit[commit] = append(statuses, status)
+}
+
+// GetCommitStatuses returns the statuses of commit in repo, sorted by context.
+func (sm *SessionManager) GetCommitStatuses(repo *gogit.Repository, commit plumbing.Hash) []*CommitStatus {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	byCommit := sm.CommitStatuses[sm.remoteNameFor(repo)]
+	statuses := make([]*CommitStatus, len(byCommit[commit]))
+	copy(statuses, byCommit[commit])
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Context < statuses[j].Context })
+	return statuses
+}
+
+// CommitStatusSummary maps commit SHAs to their combined status in repo, or
+// across all shared remotes when repo is nil.
+func (sm *SessionManager) CommitStatusSummary(repo *gogit.Repository) map[string]string {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	name := ""
+	if repo != nil {
+		name = sm.remoteNameFor(repo)
+	}
+	all := make(map[plumbing.Hash][]*CommitStatus)
+	for remote, byCommit := range sm.CommitStatuses {
+		if repo != nil && remote != name {
+			continue
+		}
+		for commit, statuses := range byCommit {
+			all[commit] = append(all[commit], statuses...)
+		}
+	}
+
+	summary := make(map[string]string, len(all))
+	for commit, statuses := range all {
+		if state := CombinedStatus(statuses); state != "" {
+			summary[commit.String()] = state
+		}
+	}
+	return summary
+}
+
+// CombinedStatus folds statuses into one state: failure if any failed,
+// pending if any is still running, success if all passed, "" if there are none.
+func CombinedStatus(statuses []*CommitStatus) string {
+	if len(statuses) == 0 {
+		return ""
+	}
+	combined := StatusSuccess
+	for _, s := range statuses {
+		switch s.State {
+		case StatusFailure, StatusError:
+			return StatusFailure
+		case StatusPending:
+			combined = StatusPending
+		}
+	}
+	return combined
+}
+
+func evaluateStatusCheck(commit *object.Commit, check *StatusCheck) (state, description string) {
+	switch check.Type {
+	case CheckFileExists:
+		if _, err := commit.File(check.Path); err != nil {
+			return StatusFailure, fmt.Sprintf("%s is missing", check.Path)
+		}
+		return StatusSuccess, fmt.Sprintf("%s exists", check.Path)
+
+	case CheckNoConflictMarkers:
+		files, err := conflictMarkerFiles(commit)
+		if err != nil {
+			return StatusError, err.Error()
+		}
+		if len(files) > 0 {
+			return StatusFailure, "Conflict markers found in " + strings.Join(files, ", ")
+		}
+		return StatusSuccess, "No conflict markers"
+
+	case CheckMessageMatches:
+		re, err := regexp.Compile(check.Pattern)
+		if err != nil {
+			return StatusError, err.Error()
+		}
+		if !re.MatchString(commit.Message) {
+			return StatusFailure, fmt.Sprintf("Commit message does not match %s", check.Pattern)
+		}
+		return StatusSuccess, fmt.Sprintf("Commit message matches %s", check.Pattern)
+	}
+	return StatusError, fmt.Sprintf("unknown status check type '%s'", check.Type)
+}
+
+// conflictMarkerFiles lists the text files of commit that still contain merge
+// conflict markers.
+func conflictMarkerFiles(commit *object.Commit) ([]string, error) {
+	files, err := commit.Files()
+	if err != nil {
+		return nil, err
+	}
+
+	var found []string
+	err = files.ForEach(func(f *object.File) error {
+		if binary, err := f.IsBinary(); err != nil || binary {
+			return nil
+		}
+		contents, err := f.Contents()
+		if err != nil {
+			return err
+		}
+		if hasConflictMarkers(contents) {
+			found = append(found, f.Name)
+		}
+		return nil
+	})
+	return found, err
+}
+
+func hasConflictMarkers(contents string) bool {
+	scanner := bufio.NewScanner(strings.NewReader(contents))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if strings.HasPrefix(line, "<<<<<<<") || strings.HasPrefix(line, ">>>>>>>") {
+			return true
+		}
+	}
+	return false
+}
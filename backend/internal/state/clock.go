@@ -0,0 +1,100 @@
+package state
+
+import (
+	"sync"
+	"time"
+
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// Deterministic mode
+//
+// Commits normally carry the wall-clock time, so replaying the same commands
+// yields different hashes on every run. A session in deterministic mode stamps
+// commits from a virtual clock instead, which starts at a fixed instant and
+// advances by a fixed step on every reading, and always uses the same
+// identity. The same commands then produce the same commit IDs everywhere.
+
+// Defaults of deterministic mode
+var (
+	DefaultClockStart = time.Date(2024, time.January, 1, 9, 0, 0, 0, time.UTC)
+	DefaultClockStep  = time.Minute
+)
+
+// DefaultIdentity is the author and committer of commands run by the user.
+var DefaultIdentity = object.Signature{Name: "User", Email: "user@example.com"}
+
+// VirtualClock hands out Start, Start+Step, Start+2*Step, ...
+type VirtualClock struct {
+	Start time.Time
+	Step  time.Duration
+
+	ticks int
+	mu    sync.Mutex
+}
+
+// NewVirtualClock creates a clock at start (DefaultClockStart when zero)
+// advancing by step (DefaultClockStep when zero or negative).
+func NewVirtualClock(start time.Time, step time.Duration) *VirtualClock {
+	if start.IsZero() {
+		start = DefaultClockStart
+	}
+	if step <= 0 {
+		step = DefaultClockStep
+	}
+	return &VirtualClock{Start: start.UTC(), Step: step}
+}
+
+// Now returns the current virtual time and advances the clock by one step.
+func (c *VirtualClock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	now := c.Start.Add(time.Duration(c.ticks) * c.Step)
+	c.ticks++
+	return now
+}
+
+// Ticks returns how many times the clock has been read.
+func (c *VirtualClock) Ticks() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ticks
+}
+
+// SetDeterministic switches the session to a fresh virtual clock, or back to
+// the wall clock when clock is nil.
+func (s *Session) SetDeterministic(clock *VirtualClock) {
+	s.clockMu.Lock()
+	defer s.clockMu.Unlock()
+	s.clock = clock
+}
+
+// Clock returns the session's virtual clock, or nil in normal mode.
+func (s *Session) Clock() *VirtualClock {
+	s.clockMu.RLock()
+	defer s.clockMu.RUnlock()
+	return s.clock
+}
+
+// Now returns the timestamp for the next object the session creates: the wall
+// clock normally, the virtual clock in deterministic mode.
+func (s *Session) Now() time.Time {
+	if clock := s.Clock(); clock != nil {
+		return clock.Now()
+	}
+	return time.Now()
+}
+
+// Signature returns DefaultIdentity stamped with s.Now(). Use one signature
+// for both author and committer so a commit reads the clock once.
+func (s *Session) Signature() *object.Signature {
+	sig := DefaultIdentity
+	sig.When = s.Now()
+	return &sig
+}
+
+// Identity returns DefaultIdentity without a timestamp, for actions that name
+// the user but create no object (pushes, reviews). It leaves the clock alone.
+func (s *Session) Identity() object.Signature {
+	return DefaultIdentity
+}
@@ -0,0 +1,263 @@
+package state
+
+// fork.go - Forks of Shared Remotes
+//
+// A fork is a new shared remote that starts with the branches and tags of its
+// upstream and borrows the upstream's objects through objects/info/alternates,
+// the way hosted servers share storage across a fork network. Pull requests can
+// then go from fork:branch to upstream:branch (see PullRequest.HeadRepo).
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/go-git/go-billy/v5/osfs"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/cache"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/go-git/go-git/v5/storage"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+)
+
+// Errors returned by ForkRemote, so callers can tell a missing upstream from a taken name.
+var (
+	ErrRemoteNotFound = errors.New("remote not found")
+	ErrRemoteExists   = errors.New("remote already exists")
+)
+
+// ForkRemote creates the shared remote name as a fork of upstream.
+func (sm *SessionManager) ForkRemote(upstream, name string) error {
+	if err := validateRepositoryName(name); err != nil {
+		return err
+	}
+
+	sm.mu.RLock()
+	upstreamRepo, ok := sm.SharedRemotes[upstream]
+	upstreamPath := sm.SharedRemotePaths[upstream]
+	_, exists := sm.SharedRemotes[name]
+	sm.mu.RUnlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrRemoteNotFound, upstream)
+	}
+	if exists {
+		return fmt.Errorf("%w: %s", ErrRemoteExists, name)
+	}
+
+	pseudoURL, repoPath := remoteLocation(name)
+
+	unlock := sm.lockRemotePath(repoPath)
+	defer unlock()
+
+	_ = os.RemoveAll(repoPath)
+	if err := os.MkdirAll(repoPath, 0750); err != nil {
+		return fmt.Errorf("failed to create repo dir: %w", err)
+	}
+	if _, err := gogit.PlainInit(repoPath, true); err != nil {
+		return fmt.Errorf("failed to init bare repo: %w", err)
+	}
+
+	// Share the upstream object database when it lives on disk; in-memory
+	// remotes have nothing to point at, so their objects are copied instead.
+	objectsDir := filepath.Join(upstreamPath, "objects")
+	shared := false
+	if fi, err := os.Stat(objectsDir); upstreamPath != "" && err == nil && fi.IsDir() {
+		if err := writeAlternates(repoPath, objectsDir); err != nil {
+			return err
+		}
+		shared = true
+	}
+
+	var (
+		repo *gogit.Repository
+		err  error
+	)
+	if shared {
+		repo, err = openForkRepository(repoPath, upstreamRepo.Storer)
+	} else {
+		repo, err = gogit.PlainOpen(repoPath)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to open fork: %w", err)
+	}
+	if !shared {
+		if err := copyAllObjects(context.Background(), upstreamRepo.Storer, repo.Storer); err != nil {
+			return fmt.Errorf("failed to copy objects: %w", err)
+		}
+	}
+
+	if err := copyRefs(upstreamRepo, repo); err != nil {
+		return err
+	}
+
+	sm.mu.Lock()
+	sm.registerSharedRemoteLocked(name, pseudoURL, repoPath, repo)
+	sm.ForkParents[name] = upstream
+	sm.mu.Unlock()
+
+	return nil
+}
+
+// ForkParent returns the upstream a fork was created from.
+func (sm *SessionManager) ForkParent(name string) (string, bool) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	parent, ok := sm.ForkParents[name]
+	return parent, ok
+}
+
+// SyncFork fast-forwards branch of the fork to the same branch of its upstream
+// ("Sync fork"). It returns the new tip, or an error when the fork has commits
+// the upstream does not.
+func (sm *SessionManager) SyncFork(name, branch string) (plumbing.Hash, error) {
+	sm.mu.RLock()
+	upstream, isFork := sm.ForkParents[name]
+	forkRepo := sm.SharedRemotes[name]
+	upstreamRepo := sm.SharedRemotes[upstream]
+	sm.mu.RUnlock()
+
+	if !isFork {
+		return plumbing.ZeroHash, fmt.Errorf("remote '%s' is not a fork", name)
+	}
+	if upstreamRepo == nil {
+		return plumbing.ZeroHash, fmt.Errorf("upstream '%s' of fork '%s' not found", upstream, name)
+	}
+
+	refName := plumbing.NewBranchReferenceName(branch)
+	upstreamRef, err := upstreamRepo.Reference(refName, true)
+	if err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("branch '%s' not found in upstream '%s'", branch, upstream)
+	}
+	target := upstreamRef.Hash()
+
+	if forkRef, err := forkRepo.Reference(refName, true); err == nil {
+		if forkRef.Hash() == target {
+			return target, nil
+		}
+		if !isAncestor(upstreamRepo, forkRef.Hash(), target) {
+			return plumbing.ZeroHash, fmt.Errorf("branch '%s' of '%s' has commits that are not in '%s'; it cannot be synced by fast-forward", branch, name, upstream)
+		}
+	}
+
+	// Upstream objects are visible through alternates; copied forks need them brought over
+	if err := forkRepo.Storer.HasEncodedObject(target); err != nil {
+		if err := copyAllObjects(context.Background(), upstreamRepo.Storer, forkRepo.Storer); err != nil {
+			return plumbing.ZeroHash, fmt.Errorf("failed to copy objects: %w", err)
+		}
+	}
+	if err := forkRepo.Storer.SetReference(plumbing.NewHashReference(refName, target)); err != nil {
+		return plumbing.ZeroHash, fmt.Errorf("failed to update %s: %w", refName, err)
+	}
+	return target, nil
+}
+
+// forkStorage also lists the objects borrowed through alternates, so
+// whole-repository copies (e.g. clone) see everything the fork can read.
+type forkStorage struct {
+	*filesystem.Storage
+	upstream storer.EncodedObjectStorer
+}
+
+func (s *forkStorage) IterEncodedObjects(t plumbing.ObjectType) (storer.EncodedObjectIter, error) {
+	own, err := s.Storage.IterEncodedObjects(t)
+	if err != nil {
+		return nil, err
+	}
+	borrowed, err := s.upstream.IterEncodedObjects(t)
+	if err != nil {
+		own.Close()
+		return nil, err
+	}
+	return storer.NewMultiEncodedObjectIter([]storer.EncodedObjectIter{own, borrowed}), nil
+}
+
+// openForkRepository opens a bare repository whose objects/info/alternates
+// point outside of it (PlainOpen resolves alternates inside the repo only).
+func openForkRepository(path string, upstream storer.EncodedObjectStorer) (*gogit.Repository, error) {
+	st := filesystem.NewStorageWithOptions(osfs.New(path), cache.NewObjectLRUDefault(), filesystem.Options{
+		AlternatesFS: osfs.New("/"),
+	})
+	return gogit.Open(&forkStorage{Storage: st, upstream: upstream}, nil)
+}
+
+// writeAlternates points the fork at objectsDir, plus whatever that object
+// database borrows itself (forks of forks).
+func writeAlternates(repoPath, objectsDir string) error {
+	lines := objectsDir + "\n"
+	if inherited, err := os.ReadFile(filepath.Join(objectsDir, "info", "alternates")); err == nil {
+		lines += string(inherited)
+	}
+
+	infoDir := filepath.Join(repoPath, "objects", "info")
+	if err := os.MkdirAll(infoDir, 0750); err != nil {
+		return fmt.Errorf("failed to create objects/info: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(infoDir, "alternates"), []byte(lines), 0640); err != nil {
+		return fmt.Errorf("failed to write alternates: %w", err)
+	}
+	return nil
+}
+
+// copyAllObjects copies every object of src missing from dst, reporting
+// "Copying objects" progress to an ingest listener on ctx and stopping when
+// ctx is cancelled.
+func copyAllObjects(ctx context.Context, src, dst storage.Storer) error {
+	iter, err := src.IterEncodedObjects(plumbing.AnyObject)
+	if err != nil {
+		return err
+	}
+	progress := ingestReporterFrom(ctx)
+	var copied int
+	var bytes int64
+	err = iter.ForEach(func(obj plumbing.EncodedObject) error {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if dst.HasEncodedObject(obj.Hash()) == nil {
+			return nil
+		}
+		if _, err := dst.SetEncodedObject(obj); err != nil {
+			return err
+		}
+		copied++
+		bytes += obj.Size()
+		if progress != nil && copied%500 == 0 {
+			progress.setPhase("Copying objects", -1, copied, 0)
+			progress.setBytes(bytes)
+		}
+		return nil
+	})
+	if progress != nil && err == nil {
+		progress.setPhase("Copying objects", 100, copied, copied)
+		progress.setBytes(bytes)
+	}
+	return err
+}
+
+// copyRefs gives dst the branches, tags and default branch of src, e.g. a new fork
+// those of its upstream.
+func copyRefs(src, dst *gogit.Repository) error {
+	refs, err := src.References()
+	if err != nil {
+		return err
+	}
+	err = refs.ForEach(func(ref *plumbing.Reference) error {
+		if ref.Type() != plumbing.HashReference || !(ref.Name().IsBranch() || ref.Name().IsTag()) {
+			return nil
+		}
+		return dst.Storer.SetReference(ref)
+	})
+	if err != nil {
+		return fmt.Errorf("failed to copy refs: %w", err)
+	}
+
+	if head, err := src.Storer.Reference(plumbing.HEAD); err == nil && head.Type() == plumbing.SymbolicReference {
+		if err := dst.Storer.SetReference(head); err != nil {
+			return fmt.Errorf("failed to set HEAD: %w", err)
+		}
+	}
+	return nil
+}
@@ -39,6 +39,16 @@ func (sm *SessionManager) GetGraphState(sessionID string, showAll bool) (*GraphS
 	sm.mu.RUnlock()
 	sort.Strings(state.SharedRemotes)
 
+	// A session can attach to several shared remotes (origin, upstream, forks)
+	for i, remote := range state.Remotes {
+		if len(remote.URLs) > 0 {
+			state.Remotes[i].SharedRemote, _ = sm.SharedRemoteName(remote.URLs[0])
+		}
+	}
+
+	// CI statuses reported by shared remotes for commits in this graph
+	state.CommitStatuses = sm.CommitStatusSummary(nil)
+
 	// 6. Active Project detection
 	state.ActiveProject = findActiveProject(session)
 
@@ -2,6 +2,7 @@ package state
 
 import (
 	"sort"
+	"strings"
 	"time"
 
 	gogit "github.com/go-git/go-git/v5"
@@ -26,9 +27,14 @@ func populateCommits(repo *gogit.Repository, state *GraphState, showAll bool) {
 
 	if showAll && !isHybrid {
 		// Scan ALL objects - only safe for non-hybrid repos (e.g., shared bare repo)
+		// The history of the notes refs is not part of the project, so skip it
+		notesHistory := notesCommits(repo)
 		cIter, err := repo.CommitObjects()
 		if err == nil {
 			_ = cIter.ForEach(func(c *object.Commit) error {
+				if notesHistory[c.Hash] {
+					return nil
+				}
 				collectedCommits = append(collectedCommits, c)
 				return nil
 			})
@@ -170,15 +176,28 @@ func populateCommits(repo *gogit.Repository, state *GraphState, showAll bool) {
 		return tI.After(tJ)
 	})
 
+	// Commits at a shallow boundary are shown like git does: as roots
+	grafted := make(map[plumbing.Hash]bool)
+	if shallow, err := repo.Storer.Shallow(); err == nil {
+		for _, h := range shallow {
+			grafted[h] = true
+		}
+	}
+
+	// Notes are looked up by the annotated commit's hash
+	notes, _ := ReadNotes(repo, DefaultNotesRef)
+
 	// Convert to View Model
 	for _, c := range collectedCommits {
 		parentID := ""
-		if len(c.ParentHashes) > 0 {
-			parentID = c.ParentHashes[0].String()
-		}
 		secondParentID := ""
-		if len(c.ParentHashes) > 1 {
-			secondParentID = c.ParentHashes[1].String()
+		if !grafted[c.Hash] {
+			if len(c.ParentHashes) > 0 {
+				parentID = c.ParentHashes[0].String()
+			}
+			if len(c.ParentHashes) > 1 {
+				secondParentID = c.ParentHashes[1].String()
+			}
 		}
 		state.Commits = append(state.Commits, Commit{
 			ID:             c.Hash.String(),
@@ -187,6 +206,48 @@ func populateCommits(repo *gogit.Repository, state *GraphState, showAll bool) {
 			SecondParentID: secondParentID,
 			Timestamp:      c.Committer.When.Format(time.RFC3339),
 			TreeID:         c.TreeHash.String(),
+			Grafted:        grafted[c.Hash],
+			Note:           noteFor(repo, notes, c.Hash),
 		})
 	}
 }
+
+func noteFor(repo *gogit.Repository, notes map[plumbing.Hash]plumbing.Hash, commit plumbing.Hash) string {
+	blob, ok := notes[commit]
+	if !ok {
+		return ""
+	}
+	text, err := NoteText(repo, blob)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimRight(text, "\n")
+}
+
+// notesCommits returns the commits making up the history of the notes refs.
+func notesCommits(repo *gogit.Repository) map[plumbing.Hash]bool {
+	history := make(map[plumbing.Hash]bool)
+	refs, err := repo.References()
+	if err != nil {
+		return history
+	}
+	var queue []plumbing.Hash
+	_ = refs.ForEach(func(r *plumbing.Reference) error {
+		if r.Type() == plumbing.HashReference && r.Name().IsNote() {
+			queue = append(queue, r.Hash())
+		}
+		return nil
+	})
+	for len(queue) > 0 {
+		h := queue[0]
+		queue = queue[1:]
+		if history[h] {
+			continue
+		}
+		history[h] = true
+		if c, err := repo.CommitObject(h); err == nil {
+			queue = append(queue, c.ParentHashes...)
+		}
+	}
+	return history
+}
@@ -0,0 +1,430 @@
+package state
+
+// ingest.go - Background Ingest Jobs
+//
+// Cloning a large remote can take minutes, so ingests run as background jobs
+// with an ID. A job's progress (the counting/compressing/receiving phases git
+// servers report, a percentage and the bytes written so far) can be streamed to
+// clients, and a job can be cancelled through its context. Ingests only lock the
+// directory they write, so different sources are ingested concurrently.
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Ingest job states
+const (
+	IngestRunning   = "running"
+	IngestSucceeded = "succeeded"
+	IngestFailed    = "failed"
+	IngestCanceled  = "canceled"
+)
+
+// maxIngestJobs is how many jobs are remembered; the oldest finished ones are dropped.
+const maxIngestJobs = 50
+
+// defaultIngestTimeout bounds one ingest, as the HTTP write timeout did when
+// ingests ran inside the request.
+const defaultIngestTimeout = 300 * time.Second
+
+// IngestProgress is a snapshot of what a running ingest is doing.
+type IngestProgress struct {
+	Phase   string `json:"phase"`             // e.g. "Counting objects", "Receiving objects", "Copying objects"
+	Percent int    `json:"percent"`           // 0-100, or -1 when the phase has no known total
+	Current int    `json:"current,omitempty"` // Objects processed in this phase
+	Total   int    `json:"total,omitempty"`
+	Bytes   int64  `json:"bytes"` // Bytes written to the remote's object store so far
+}
+
+// IngestJob is an ingest running (or finished) in the background.
+type IngestJob struct {
+	ID         string         `json:"id"`
+	Name       string         `json:"name"`
+	Source     string         `json:"source"`
+	Depth      int            `json:"depth,omitempty"`
+	State      string         `json:"state"`
+	Error      string         `json:"error,omitempty"`
+	Progress   IngestProgress `json:"progress"`
+	StartedAt  time.Time      `json:"startedAt"`
+	FinishedAt *time.Time     `json:"finishedAt,omitempty"`
+}
+
+// Finished reports whether the job has stopped running.
+func (j IngestJob) Finished() bool {
+	return j.State != IngestRunning
+}
+
+type ingestJob struct {
+	IngestJob
+	cancel context.CancelFunc
+	subs   map[chan IngestJob]struct{}
+}
+
+// IngestJobs runs ingests in the background and tracks their progress.
+type IngestJobs struct {
+	// Run performs one ingest. It defaults to SessionManager.IngestRemote;
+	// git.NewSessionManager points it at git.IngestSource so jobs accept
+	// local paths, bundles and archives too.
+	Run func(ctx context.Context, name, source string, depth int) error
+	// Timeout bounds each job; a job running longer fails as timed out.
+	Timeout time.Duration
+
+	mu     sync.Mutex
+	nextID int
+	jobs   map[string]*ingestJob
+	order  []string
+}
+
+func newIngestJobs(sm *SessionManager) *IngestJobs {
+	return &IngestJobs{Run: sm.IngestRemote, Timeout: defaultIngestTimeout, jobs: make(map[string]*ingestJob)}
+}
+
+// Start launches an ingest of source into the shared remote name and returns the new job.
+func (j *IngestJobs) Start(name, source string, depth int) IngestJob {
+	j.mu.Lock()
+	ctx, cancel := context.WithTimeout(context.Background(), j.Timeout)
+	j.nextID++
+	job := &ingestJob{
+		IngestJob: IngestJob{
+			ID:        fmt.Sprintf("ingest-%d", j.nextID),
+			Name:      name,
+			Source:    source,
+			Depth:     depth,
+			State:     IngestRunning,
+			Progress:  IngestProgress{Phase: "Starting", Percent: -1},
+			StartedAt: time.Now(),
+		},
+		cancel: cancel,
+		subs:   make(map[chan IngestJob]struct{}),
+	}
+	j.jobs[job.ID] = job
+	j.order = append(j.order, job.ID)
+	j.prune()
+	snapshot := job.IngestJob
+	j.mu.Unlock()
+
+	ctx = WithIngestProgress(ctx, func(p IngestProgress) {
+		j.update(snapshot.ID, func(job *IngestJob) { job.Progress = p })
+	})
+	go func() {
+		err := j.Run(ctx, name, source, depth)
+		j.finish(ctx, snapshot.ID, err)
+		cancel()
+	}()
+	return snapshot
+}
+
+// Get returns a snapshot of the job id.
+func (j *IngestJobs) Get(id string) (IngestJob, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	job, ok := j.jobs[id]
+	if !ok {
+		return IngestJob{}, false
+	}
+	return job.IngestJob, true
+}
+
+// List returns snapshots of all remembered jobs, oldest first.
+func (j *IngestJobs) List() []IngestJob {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	jobs := make([]IngestJob, 0, len(j.order))
+	for _, id := range j.order {
+		jobs = append(jobs, j.jobs[id].IngestJob)
+	}
+	return jobs
+}
+
+// Cancel stops the running job id. The job finishes as canceled once the
+// ingest notices; a partially cloned remote is removed.
+func (j *IngestJobs) Cancel(id string) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	job, ok := j.jobs[id]
+	if !ok {
+		return fmt.Errorf("ingest job '%s' not found", id)
+	}
+	if job.Finished() {
+		return fmt.Errorf("ingest job '%s' already %s", id, job.State)
+	}
+	job.cancel()
+	return nil
+}
+
+// Subscribe returns the current state of job id and a channel that receives
+// every later change. Updates are coalesced for slow readers; the final state
+// is always delivered and the channel is then closed. Call unsubscribe when done.
+func (j *IngestJobs) Subscribe(id string) (current IngestJob, updates <-chan IngestJob, unsubscribe func(), ok bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	job, ok := j.jobs[id]
+	if !ok {
+		return IngestJob{}, nil, nil, false
+	}
+
+	ch := make(chan IngestJob, 1)
+	if job.Finished() {
+		close(ch)
+		return job.IngestJob, ch, func() {}, true
+	}
+	job.subs[ch] = struct{}{}
+	return job.IngestJob, ch, func() {
+		j.mu.Lock()
+		defer j.mu.Unlock()
+		delete(job.subs, ch)
+	}, true
+}
+
+func (j *IngestJobs) update(id string, change func(*IngestJob)) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	job, ok := j.jobs[id]
+	if !ok || job.Finished() {
+		return
+	}
+	change(&job.IngestJob)
+	job.broadcast()
+}
+
+func (j *IngestJobs) finish(ctx context.Context, id string, err error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	job, ok := j.jobs[id]
+	if !ok {
+		return
+	}
+
+	now := time.Now()
+	job.FinishedAt = &now
+	switch {
+	case errors.Is(ctx.Err(), context.DeadlineExceeded):
+		job.State, job.Error = IngestFailed, fmt.Sprintf("ingest timed out after %s", j.Timeout)
+	case ctx.Err() != nil:
+		job.State, job.Error = IngestCanceled, "ingest canceled"
+	case err != nil:
+		job.State, job.Error = IngestFailed, err.Error()
+	default:
+		job.State = IngestSucceeded
+		job.Progress.Phase, job.Progress.Percent = "Done", 100
+	}
+
+	job.broadcast()
+	for ch := range job.subs {
+		close(ch)
+	}
+	job.subs = nil
+}
+
+// broadcast sends the job's state to its subscribers, replacing a state they
+// have not read yet. Callers hold IngestJobs.mu, so no other sender interferes.
+func (job *ingestJob) broadcast() {
+	for ch := range job.subs {
+		select {
+		case <-ch:
+		default:
+		}
+		ch <- job.IngestJob
+	}
+}
+
+// prune forgets the oldest finished jobs beyond maxIngestJobs.
+func (j *IngestJobs) prune() {
+	for i := 0; len(j.order) > maxIngestJobs && i < len(j.order); {
+		id := j.order[i]
+		if !j.jobs[id].Finished() {
+			i++
+			continue
+		}
+		delete(j.jobs, id)
+		j.order = append(j.order[:i], j.order[i+1:]...)
+	}
+}
+
+// pathLock is the lock of one remote directory and the number of writers
+// holding or waiting for it.
+type pathLock struct {
+	mu   sync.Mutex
+	refs int
+}
+
+// lockRemotePath serializes writers of one remote directory and returns the
+// unlock function. Ingests of different sources run concurrently. The last
+// writer to unlock drops the entry, so finished ingests leave nothing behind.
+func (sm *SessionManager) lockRemotePath(repoPath string) func() {
+	sm.ingestLocksMu.Lock()
+	if sm.ingestLocks == nil {
+		sm.ingestLocks = make(map[string]*pathLock)
+	}
+	l := sm.ingestLocks[repoPath]
+	if l == nil {
+		l = &pathLock{}
+		sm.ingestLocks[repoPath] = l
+	}
+	l.refs++
+	sm.ingestLocksMu.Unlock()
+
+	l.mu.Lock()
+	return func() {
+		l.mu.Unlock()
+		sm.ingestLocksMu.Lock()
+		defer sm.ingestLocksMu.Unlock()
+		if l.refs--; l.refs == 0 {
+			delete(sm.ingestLocks, repoPath)
+		}
+	}
+}
+
+type ingestProgressKey struct{}
+
+// WithIngestProgress returns a context whose ingests report progress to fn.
+func WithIngestProgress(ctx context.Context, fn func(IngestProgress)) context.Context {
+	return context.WithValue(ctx, ingestProgressKey{}, newIngestReporter(fn))
+}
+
+// ReportIngestPhase starts a new phase on the progress listener of ctx, if any.
+func ReportIngestPhase(ctx context.Context, phase string) {
+	if r := ingestReporterFrom(ctx); r != nil {
+		r.setPhase(phase, -1, 0, 0)
+	}
+}
+
+func ingestReporterFrom(ctx context.Context) *ingestReporter {
+	r, _ := ctx.Value(ingestProgressKey{}).(*ingestReporter)
+	return r
+}
+
+// ingestProgressWriter is the go-git Progress writer for an ingest: the
+// context's listener, or stdout as before when nobody is listening.
+func ingestProgressWriter(ctx context.Context) io.Writer {
+	if r := ingestReporterFrom(ctx); r != nil {
+		return r
+	}
+	return os.Stdout
+}
+
+// progressLine matches git sideband progress such as
+// "Counting objects:  45% (9/20)" or "Enumerating objects: 5, done."
+var progressLine = regexp.MustCompile(`^(?:remote: )?([A-Z][A-Za-z ]+):\s+(?:(\d+)% \((\d+)/(\d+)\)|(\d+))`)
+
+// ingestReporter turns sideband text and byte counts into IngestProgress updates.
+type ingestReporter struct {
+	mu      sync.Mutex
+	fn      func(IngestProgress)
+	current IngestProgress
+	partial []byte
+}
+
+func newIngestReporter(fn func(IngestProgress)) *ingestReporter {
+	return &ingestReporter{fn: fn, current: IngestProgress{Percent: -1}}
+}
+
+// Write parses progress lines, which git terminates with \r while a phase is
+// running and \n when it is done.
+func (r *ingestReporter) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	r.partial = append(r.partial, p...)
+	var lines []string
+	for {
+		i := strings.IndexAny(string(r.partial), "\r\n")
+		if i < 0 {
+			break
+		}
+		lines = append(lines, string(r.partial[:i]))
+		r.partial = r.partial[i+1:]
+	}
+	r.mu.Unlock()
+
+	for _, line := range lines {
+		m := progressLine.FindStringSubmatch(strings.TrimSpace(line))
+		if m == nil {
+			continue
+		}
+		if m[2] != "" {
+			percent, _ := strconv.Atoi(m[2])
+			current, _ := strconv.Atoi(m[3])
+			total, _ := strconv.Atoi(m[4])
+			r.setPhase(m[1], percent, current, total)
+		} else {
+			current, _ := strconv.Atoi(m[5])
+			r.setPhase(m[1], -1, current, 0)
+		}
+	}
+	return len(p), nil
+}
+
+func (r *ingestReporter) setPhase(phase string, percent, current, total int) {
+	r.mu.Lock()
+	r.current.Phase, r.current.Percent, r.current.Current, r.current.Total = phase, percent, current, total
+	p := r.current
+	r.mu.Unlock()
+	r.fn(p)
+}
+
+func (r *ingestReporter) setBytes(n int64) {
+	r.mu.Lock()
+	if n == r.current.Bytes {
+		r.mu.Unlock()
+		return
+	}
+	r.current.Bytes = n
+	p := r.current
+	r.mu.Unlock()
+	r.fn(p)
+}
+
+// watchObjectStore reports how much the objects directory under repoPath
+// grows until the returned stop function is called. go-git does not report
+// received bytes itself, so this is how clones show "Receiving objects".
+func (r *ingestReporter) watchObjectStore(repoPath string) (stop func()) {
+	objectsDir := filepath.Join(repoPath, "objects")
+	baseline := objectStoreSize(objectsDir)
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		ticker := time.NewTicker(250 * time.Millisecond)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-done:
+				r.setBytes(objectStoreSize(objectsDir) - baseline)
+				return
+			case <-ticker.C:
+				r.setBytes(objectStoreSize(objectsDir) - baseline)
+			}
+		}
+	}()
+	return func() {
+		close(done)
+		<-finished
+	}
+}
+
+func objectStoreSize(dir string) int64 {
+	var total int64
+	_ = filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				return nil
+			}
+			return err
+		}
+		if info, err := d.Info(); err == nil && info.Mode().IsRegular() {
+			total += info.Size()
+		}
+		return nil
+	})
+	return total
+}
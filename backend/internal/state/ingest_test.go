@@ -0,0 +1,190 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// waitIngest collects the updates of job id until it finishes.
+func waitIngest(t *testing.T, jobs *IngestJobs, id string) (IngestJob, []IngestJob) {
+	t.Helper()
+	current, updates, unsubscribe, ok := jobs.Subscribe(id)
+	require.True(t, ok)
+	defer unsubscribe()
+
+	var seen []IngestJob
+	timeout := time.After(5 * time.Second)
+	for !current.Finished() {
+		select {
+		case job, open := <-updates:
+			if !open {
+				require.True(t, current.Finished(), "stream closed before the final state")
+				break
+			}
+			seen = append(seen, job)
+			current = job
+		case <-timeout:
+			t.Fatalf("ingest %s did not finish", id)
+		}
+	}
+	return current, seen
+}
+
+func TestIngestJobs(t *testing.T) {
+	sm := NewSessionManager()
+	jobs := sm.Ingests
+
+	t.Run("Progress from sideband lines", func(t *testing.T) {
+		release := make(chan struct{})
+		jobs.Run = func(ctx context.Context, name, source string, depth int) error {
+			<-release
+			w := ingestProgressWriter(ctx)
+			fmt.Fprint(w, "Enumerating objects: 20, done.\n")
+			fmt.Fprint(w, "Counting objects:  45% (9/20)\rCounting objects: 100% (20/20), done.\n")
+			fmt.Fprint(w, "remote: Compressing objects:  50% (5/10)\r")
+			return nil
+		}
+
+		job := jobs.Start("origin", "https://example.com/repo.git", 1)
+		assert.Equal(t, IngestRunning, job.State)
+		assert.Equal(t, "ingest-1", job.ID)
+
+		current, updates, unsubscribe, ok := jobs.Subscribe(job.ID)
+		require.True(t, ok)
+		assert.Equal(t, "Starting", current.Progress.Phase)
+		close(release)
+
+		var phases []string
+		for update := range updates {
+			phases = append(phases, update.Progress.Phase)
+			current = update
+		}
+		unsubscribe()
+
+		assert.Equal(t, IngestSucceeded, current.State)
+		assert.Equal(t, "Done", current.Progress.Phase)
+		assert.NotNil(t, current.FinishedAt)
+		assert.Equal(t, "Done", phases[len(phases)-1], "the final state is always delivered")
+
+		// Subscribing to a finished job yields its state and a closed channel
+		final, closed, _, ok := jobs.Subscribe(job.ID)
+		require.True(t, ok)
+		assert.Equal(t, IngestSucceeded, final.State)
+		_, open := <-closed
+		assert.False(t, open)
+	})
+
+	t.Run("Sideband parsing", func(t *testing.T) {
+		var got []IngestProgress
+		r := newIngestReporter(func(p IngestProgress) { got = append(got, p) })
+		_, _ = r.Write([]byte("Counting obj"))
+		assert.Empty(t, got, "partial lines wait for their terminator")
+		_, _ = r.Write([]byte("ects:  45% (9/20)\rTotal 3 (delta 0)\n"))
+		require.Len(t, got, 1)
+		assert.Equal(t, IngestProgress{Phase: "Counting objects", Percent: 45, Current: 9, Total: 20}, got[0])
+	})
+
+	t.Run("Failure", func(t *testing.T) {
+		jobs.Run = func(context.Context, string, string, int) error {
+			return errors.New("repository not found")
+		}
+		job := jobs.Start("origin", "https://example.com/missing.git", 0)
+		final, _ := waitIngest(t, jobs, job.ID)
+		assert.Equal(t, IngestFailed, final.State)
+		assert.Equal(t, "repository not found", final.Error)
+	})
+
+	t.Run("Cancel", func(t *testing.T) {
+		started := make(chan struct{})
+		jobs.Run = func(ctx context.Context, _, _ string, _ int) error {
+			close(started)
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		job := jobs.Start("origin", "https://example.com/huge.git", 0)
+		<-started
+		require.NoError(t, jobs.Cancel(job.ID))
+
+		final, _ := waitIngest(t, jobs, job.ID)
+		assert.Equal(t, IngestCanceled, final.State)
+		assert.Error(t, jobs.Cancel(job.ID), "finished jobs cannot be cancelled")
+		assert.Error(t, jobs.Cancel("ingest-99"))
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		jobs.Timeout = 10 * time.Millisecond
+		defer func() { jobs.Timeout = defaultIngestTimeout }()
+		jobs.Run = func(ctx context.Context, _, _ string, _ int) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}
+		job := jobs.Start("origin", "https://example.com/slow.git", 0)
+
+		final, _ := waitIngest(t, jobs, job.ID)
+		assert.Equal(t, IngestFailed, final.State)
+		assert.Equal(t, "ingest timed out after 10ms", final.Error)
+	})
+
+	assert.Len(t, jobs.List(), 4)
+
+	t.Run("Different paths do not serialize", func(t *testing.T) {
+		unlockA := sm.lockRemotePath("/remotes/a")
+		defer unlockA()
+
+		done := make(chan struct{})
+		go func() {
+			unlockB := sm.lockRemotePath("/remotes/b")
+			unlockB()
+			close(done)
+		}()
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("lock on another path blocked")
+		}
+
+		blocked := make(chan struct{})
+		go func() {
+			unlock := sm.lockRemotePath("/remotes/a")
+			unlock()
+			close(blocked)
+		}()
+		select {
+		case <-blocked:
+			t.Fatal("same path should wait for the first writer")
+		case <-time.After(50 * time.Millisecond):
+		}
+	})
+}
+
+func TestLockRemotePath(t *testing.T) {
+	sm := NewSessionManager()
+	unlock := sm.lockRemotePath("/remotes/a")
+
+	// A second writer of the same path waits for the first
+	acquired := make(chan struct{})
+	go func() {
+		defer sm.lockRemotePath("/remotes/a")()
+		close(acquired)
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("the second writer should wait")
+	case <-time.After(20 * time.Millisecond):
+	}
+	sm.lockRemotePath("/remotes/b")() // Other paths are not blocked
+
+	unlock()
+	<-acquired
+	assert.Eventually(t, func() bool {
+		sm.ingestLocksMu.Lock()
+		defer sm.ingestLocksMu.Unlock()
+		return len(sm.ingestLocks) == 0
+	}, time.Second, time.Millisecond, "finished writers should drop their locks")
+}
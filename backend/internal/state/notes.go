@@ -0,0 +1,60 @@
+package state
+
+// notes.go - Notes Attached to Objects (git notes)
+//
+// Notes live on their own ref, refs/notes/commits by default. It points at an
+// ordinary commit whose tree holds one blob per annotated object, named after
+// that object's hash, so adding a note never changes the commit it describes.
+
+import (
+	"io"
+	"strings"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// DefaultNotesRef is where git notes are kept unless --ref says otherwise.
+const DefaultNotesRef plumbing.ReferenceName = "refs/notes/commits"
+
+// ReadNotes maps each annotated object to its note blob. Besides flat names it
+// follows git's fan-out directories ("ab/cdef..."). A missing ref has no notes.
+func ReadNotes(repo *gogit.Repository, ref plumbing.ReferenceName) (map[plumbing.Hash]plumbing.Hash, error) {
+	notes := map[plumbing.Hash]plumbing.Hash{}
+	r, err := repo.Storer.Reference(ref)
+	if err != nil {
+		return notes, nil
+	}
+	commit, err := repo.CommitObject(r.Hash())
+	if err != nil {
+		return nil, err
+	}
+	tree, err := commit.Tree()
+	if err != nil {
+		return nil, err
+	}
+	err = tree.Files().ForEach(func(f *object.File) error {
+		name := strings.ReplaceAll(f.Name, "/", "")
+		if plumbing.IsHash(name) {
+			notes[plumbing.NewHash(name)] = f.Hash
+		}
+		return nil
+	})
+	return notes, err
+}
+
+// NoteText returns the contents of a note blob.
+func NoteText(repo *gogit.Repository, blob plumbing.Hash) (string, error) {
+	b, err := repo.BlobObject(blob)
+	if err != nil {
+		return "", err
+	}
+	r, err := b.Reader()
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+	data, err := io.ReadAll(r)
+	return string(data), err
+}
@@ -0,0 +1,125 @@
+package state
+
+// objects.go - Writing Objects Without a Worktree
+//
+// Shared remotes are bare, so teammate bots, simulated remote commits, server-side
+// merges and notes build their blobs, trees and commits directly in the object
+// store. These helpers are the one place that does it.
+
+import (
+	"errors"
+	"io"
+	"sort"
+	"strings"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+)
+
+// TreeFile is a file entry of a flattened tree.
+type TreeFile struct {
+	Hash plumbing.Hash
+	Mode filemode.FileMode
+}
+
+// StoreObject encodes o (a commit, tree or tag) into repo's object store.
+func StoreObject(repo *gogit.Repository, o interface {
+	Encode(plumbing.EncodedObject) error
+}) (plumbing.Hash, error) {
+	obj := repo.Storer.NewEncodedObject()
+	if err := o.Encode(obj); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return repo.Storer.SetEncodedObject(obj)
+}
+
+// StoreBlob writes content as a blob into repo's object store.
+func StoreBlob(repo *gogit.Repository, content string) (plumbing.Hash, error) {
+	obj := repo.Storer.NewEncodedObject()
+	obj.SetType(plumbing.BlobObject)
+	w, err := obj.Writer()
+	if err != nil {
+		return plumbing.ZeroHash, err
+	}
+	if _, err := w.Write([]byte(content)); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	if err := w.Close(); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return repo.Storer.SetEncodedObject(obj)
+}
+
+// WriteTree stores a nested tree built from a flat path -> file map and returns its hash.
+func WriteTree(s storer.EncodedObjectStorer, files map[string]TreeFile) (plumbing.Hash, error) {
+	var entries []object.TreeEntry
+	subdirs := make(map[string]map[string]TreeFile)
+
+	for p, f := range files {
+		dir, rest, nested := strings.Cut(p, "/")
+		if !nested {
+			entries = append(entries, object.TreeEntry{Name: p, Mode: f.Mode, Hash: f.Hash})
+			continue
+		}
+		if subdirs[dir] == nil {
+			subdirs[dir] = make(map[string]TreeFile)
+		}
+		subdirs[dir][rest] = f
+	}
+
+	for dir, sub := range subdirs {
+		h, err := WriteTree(s, sub)
+		if err != nil {
+			return plumbing.ZeroHash, err
+		}
+		entries = append(entries, object.TreeEntry{Name: dir, Mode: filemode.Dir, Hash: h})
+	}
+
+	// git orders entries by name, comparing directories as if they end in "/"
+	sortKey := func(e object.TreeEntry) string {
+		if e.Mode == filemode.Dir {
+			return e.Name + "/"
+		}
+		return e.Name
+	}
+	sort.Slice(entries, func(i, j int) bool { return sortKey(entries[i]) < sortKey(entries[j]) })
+
+	tree := &object.Tree{Entries: entries}
+	obj := s.NewEncodedObject()
+	if err := tree.Encode(obj); err != nil {
+		return plumbing.ZeroHash, err
+	}
+	return s.SetEncodedObject(obj)
+}
+
+// CommitFiles flattens the tree of c into path -> file. A nil commit has no files.
+func CommitFiles(c *object.Commit) (map[string]TreeFile, error) {
+	files := make(map[string]TreeFile)
+	if c == nil {
+		return files, nil
+	}
+	tree, err := c.Tree()
+	if err != nil {
+		return nil, err
+	}
+
+	walker := object.NewTreeWalker(tree, true, nil)
+	defer walker.Close()
+	for {
+		name, entry, err := walker.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if entry.Mode == filemode.Dir {
+			continue
+		}
+		files[name] = TreeFile{Hash: entry.Hash, Mode: entry.Mode}
+	}
+	return files, nil
+}
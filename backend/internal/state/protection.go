@@ -0,0 +1,278 @@
+package state
+
+// protection.go - Branch Protection Rules for Shared Remotes
+//
+// Shared remotes can carry per-branch protection rules that are evaluated like
+// a server-side pre-receive hook. Every push path (simulated `git push` and
+// smart HTTP receive-pack) calls CheckRefUpdate before moving a ref, so
+// learners see the same "protected branch hook declined" rejection they would
+// get from a hosted git server at work.
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// ProtectionHookDeclined is the reason git prints for refs rejected by protection rules.
+const ProtectionHookDeclined = "protected branch hook declined"
+
+// BranchProtection describes the rules enforced on branches matching Pattern.
+type BranchProtection struct {
+	Pattern              string   `json:"pattern"` // Branch name or glob, e.g. "main", "release/*"
+	AllowForcePushes     bool     `json:"allowForcePushes"`
+	AllowDeletions       bool     `json:"allowDeletions"`
+	RequireLinearHistory bool     `json:"requireLinearHistory"`
+	RequirePullRequest   bool     `json:"requirePullRequest"`
+	RequiredApprovals    int      `json:"requiredApprovals,omitempty"`    // Approving reviews needed to merge a PR into the branch
+	RequiredStatusChecks []string `json:"requiredStatusChecks,omitempty"` // Status contexts that must succeed before a PR merges
+	AllowedPushers       []string `json:"allowedPushers,omitempty"`       // Pusher names or emails; empty means anyone
+}
+
+// Matches reports whether the rule applies to the given branch ref.
+func (p *BranchProtection) Matches(ref plumbing.ReferenceName) bool {
+	if !ref.IsBranch() {
+		return false
+	}
+	ok, err := path.Match(p.Pattern, ref.Short())
+	return err == nil && ok
+}
+
+// RefUpdate is a single ref change proposed by a push.
+// Objects for New must already be present in the target repository, or in
+// Quarantine while the push waits to be accepted.
+type RefUpdate struct {
+	Name       plumbing.ReferenceName
+	Old        plumbing.Hash     // ZeroHash when creating
+	New        plumbing.Hash     // ZeroHash when deleting
+	Pusher     object.Signature  // Who pushes, checked against AllowedPushers
+	Quarantine *gogit.Repository // The target's refs plus the pushed objects, if not in the target yet
+}
+
+// ProtectionError is returned when a ref update violates a protection rule.
+type ProtectionError struct {
+	Ref     plumbing.ReferenceName
+	Reasons []string
+}
+
+func (e *ProtectionError) Error() string {
+	return ProtectionHookDeclined
+}
+
+// RemoteMessages renders the explanation a hosted server sends back over the sideband.
+func (e *ProtectionError) RemoteMessages() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "remote: error: Protected branch update failed for %s.\n", e.Ref)
+	for _, r := range e.Reasons {
+		fmt.Fprintf(&sb, "remote: error: %s\n", r)
+	}
+	return sb.String()
+}
+
+// SetBranchProtection replaces the protection rules of a shared remote.
+func (sm *SessionManager) SetBranchProtection(remote string, rules []*BranchProtection) error {
+	for _, rule := range rules {
+		if rule.Pattern == "" {
+			return fmt.Errorf("protection rule requires a branch pattern")
+		}
+		if _, err := path.Match(rule.Pattern, ""); err != nil {
+			return fmt.Errorf("invalid branch pattern '%s': %w", rule.Pattern, err)
+		}
+		if rule.RequiredApprovals < 0 {
+			return fmt.Errorf("requiredApprovals must not be negative")
+		}
+		for _, context := range rule.RequiredStatusChecks {
+			if context == "" {
+				return fmt.Errorf("required status check context must not be empty")
+			}
+		}
+	}
+
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	if _, ok := sm.SharedRemotes[remote]; !ok {
+		return fmt.Errorf("remote '%s' not found", remote)
+	}
+	if len(rules) == 0 {
+		delete(sm.Protections, remote)
+		return nil
+	}
+	sm.Protections[remote] = rules
+	return nil
+}
+
+// GetBranchProtection returns the protection rules configured for a shared remote.
+func (sm *SessionManager) GetBranchProtection(remote string) []*BranchProtection {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+
+	result := make([]*BranchProtection, len(sm.Protections[remote]))
+	copy(result, sm.Protections[remote])
+	return result
+}
+
+// BranchProtectionFor returns the first rule covering ref in any alias of repo, or nil.
+func (sm *SessionManager) BranchProtectionFor(repo *gogit.Repository, ref plumbing.ReferenceName) *BranchProtection {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return sm.branchProtectionFor(repo, ref)
+}
+
+// branchProtectionFor is BranchProtectionFor without locking. Caller holds sm.mu.
+func (sm *SessionManager) branchProtectionFor(repo *gogit.Repository, ref plumbing.ReferenceName) *BranchProtection {
+	for name, rules := range sm.Protections {
+		if sm.SharedRemotes[name] != repo {
+			continue
+		}
+		for _, rule := range rules {
+			if rule.Matches(ref) {
+				return rule
+			}
+		}
+	}
+	return nil
+}
+
+// CheckRefUpdate runs the pre-receive protection checks for a push into repo.
+// It returns a *ProtectionError when the update must be rejected.
+func (sm *SessionManager) CheckRefUpdate(repo *gogit.Repository, update RefUpdate) error {
+	return checkRefUpdate(sm.BranchProtectionFor(repo, update.Name), repo, update)
+}
+
+// CheckRefUpdateLocked is CheckRefUpdate for callers already holding sm's lock.
+func (sm *SessionManager) CheckRefUpdateLocked(repo *gogit.Repository, update RefUpdate) error {
+	return checkRefUpdate(sm.branchProtectionFor(repo, update.Name), repo, update)
+}
+
+func checkRefUpdate(rule *BranchProtection, repo *gogit.Repository, update RefUpdate) error {
+	if rule == nil {
+		return nil
+	}
+
+	var reasons []string
+	deleting := update.New.IsZero()
+
+	if rule.RequirePullRequest {
+		reasons = append(reasons, "Changes must be made through a pull request.")
+	}
+
+	// The pusher, not the commit author: anyone can write any author. This
+	// covers deletions too
+	if len(rule.AllowedPushers) > 0 && !identityAllowed(rule.AllowedPushers, update.Pusher) {
+		who := "Anonymous"
+		if update.Pusher.Name != "" {
+			who = fmt.Sprintf("%s <%s>", update.Pusher.Name, update.Pusher.Email)
+		}
+		reasons = append(reasons, fmt.Sprintf("%s is not allowed to push to this branch.", who))
+	}
+
+	if deleting {
+		if !rule.AllowDeletions {
+			reasons = append(reasons, "Cannot delete this protected branch.")
+		}
+		return protectionResult(update.Name, reasons)
+	}
+
+	objects := repo
+	if update.Quarantine != nil {
+		objects = update.Quarantine
+	}
+
+	if !update.Old.IsZero() && !rule.AllowForcePushes && !isAncestor(objects, update.Old, update.New) {
+		reasons = append(reasons, "Cannot force-push to this branch.")
+	}
+
+	if rule.RequireLinearHistory && introducesMerge(objects, update) {
+		reasons = append(reasons, "This branch must not contain merge commits.")
+	}
+
+	return protectionResult(update.Name, reasons)
+}
+
+func protectionResult(ref plumbing.ReferenceName, reasons []string) error {
+	if len(reasons) == 0 {
+		return nil
+	}
+	return &ProtectionError{Ref: ref, Reasons: reasons}
+}
+
+func identityAllowed(allowed []string, sig object.Signature) bool {
+	for _, a := range allowed {
+		if strings.EqualFold(a, sig.Name) || strings.EqualFold(a, sig.Email) {
+			return true
+		}
+	}
+	return false
+}
+
+// isAncestor reports whether ancestor is reachable from descendant.
+func isAncestor(repo *gogit.Repository, ancestor, descendant plumbing.Hash) bool {
+	if ancestor == descendant {
+		return true
+	}
+	a, err := repo.CommitObject(ancestor)
+	if err != nil {
+		return false
+	}
+	d, err := repo.CommitObject(descendant)
+	if err != nil {
+		return false
+	}
+	ok, err := a.IsAncestor(d)
+	return err == nil && ok
+}
+
+// introducesMerge reports whether the update adds any merge commit that is not
+// already reachable from the branches of repo.
+func introducesMerge(repo *gogit.Repository, update RefUpdate) bool {
+	known := make(map[plumbing.Hash]bool)
+	if refs, err := repo.Branches(); err == nil {
+		_ = refs.ForEach(func(ref *plumbing.Reference) error {
+			markReachable(repo, ref.Hash(), known)
+			return nil
+		})
+	}
+
+	seen := make(map[plumbing.Hash]bool)
+	queue := []plumbing.Hash{update.New}
+	for len(queue) > 0 {
+		h := queue[0]
+		queue = queue[1:]
+		if seen[h] || known[h] {
+			continue
+		}
+		seen[h] = true
+
+		c, err := repo.CommitObject(h)
+		if err != nil {
+			continue
+		}
+		if c.NumParents() > 1 {
+			return true
+		}
+		queue = append(queue, c.ParentHashes...)
+	}
+	return false
+}
+
+func markReachable(repo *gogit.Repository, from plumbing.Hash, seen map[plumbing.Hash]bool) {
+	queue := []plumbing.Hash{from}
+	for len(queue) > 0 {
+		h := queue[0]
+		queue = queue[1:]
+		if seen[h] {
+			continue
+		}
+		seen[h] = true
+		c, err := repo.CommitObject(h)
+		if err != nil {
+			continue
+		}
+		queue = append(queue, c.ParentHashes...)
+	}
+}
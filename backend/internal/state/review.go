@@ -0,0 +1,295 @@
+package state
+
+// review.go - Pull Request Reviews (line comments, approvals, change requests)
+//
+// Reviews are submitted by named users (the learner or simulated teammates).
+// Line comments are anchored to a position in the pull request's diff, counted
+// the way hosted servers do: the line right below the first "@@" hunk header of
+// a file is position 1, and later hunk headers count as positions too.
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Review states
+const (
+	ReviewApproved         = "APPROVED"
+	ReviewChangesRequested = "CHANGES_REQUESTED"
+	ReviewCommented        = "COMMENTED"
+)
+
+// Review is a submitted review of a pull request.
+type Review struct {
+	ID          int       `json:"id"`
+	Author      string    `json:"author"`
+	State       string    `json:"state"` // "APPROVED", "CHANGES_REQUESTED", "COMMENTED"
+	Body        string    `json:"body,omitempty"`
+	CommitID    string    `json:"commitId"` // Head commit the review was made against
+	SubmittedAt time.Time `json:"submittedAt"`
+}
+
+// ReviewComment is a comment on a line of a pull request's diff.
+type ReviewComment struct {
+	ID        int       `json:"id"`
+	ReviewID  int       `json:"reviewId,omitempty"`  // Review the comment was submitted with, 0 for standalone comments
+	InReplyTo int       `json:"inReplyTo,omitempty"` // Top-level comment of the thread, 0 for a new thread
+	Author    string    `json:"author"`
+	Body      string    `json:"body"`
+	Path      string    `json:"path"`
+	Position  int       `json:"position"`
+	DiffLine  string    `json:"diffLine"` // The diff line the comment is anchored to
+	CommitID  string    `json:"commitId"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// DraftReviewComment is a line comment submitted together with a review.
+type DraftReviewComment struct {
+	Path     string `json:"path"`
+	Position int    `json:"position"`
+	Body     string `json:"body"`
+}
+
+// Outdated reports whether the head branch has moved since the comment was made.
+func (c *ReviewComment) Outdated(headSHA string) bool {
+	return headSHA != "" && c.CommitID != headSHA
+}
+
+// SubmitReview records a review of an open pull request along with its line comments.
+func (sm *SessionManager) SubmitReview(prID int, author, state, body string, comments []DraftReviewComment) (*Review, error) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	pr, err := sm.findOpenPullRequest(prID)
+	if err != nil {
+		return nil, err
+	}
+	if author == "" {
+		return nil, fmt.Errorf("review requires an author")
+	}
+
+	switch state {
+	case ReviewApproved, ReviewChangesRequested:
+		if strings.EqualFold(author, pr.Creator) {
+			return nil, fmt.Errorf("%s cannot review their own pull request", author)
+		}
+	case ReviewCommented:
+		if body == "" && len(comments) == 0 {
+			return nil, fmt.Errorf("a comment review needs a body or line comments")
+		}
+	default:
+		return nil, fmt.Errorf("unknown review state %q (expected APPROVED, CHANGES_REQUESTED or COMMENTED)", state)
+	}
+	if state == ReviewChangesRequested && body == "" && len(comments) == 0 {
+		return nil, fmt.Errorf("requesting changes needs a body or line comments")
+	}
+
+	// Validate every anchor before recording anything
+	lines := make([]string, len(comments))
+	for i, dc := range comments {
+		line, err := diffLineAt(pr.Diff, dc.Path, dc.Position)
+		if err != nil {
+			return nil, err
+		}
+		lines[i] = line
+	}
+
+	now := time.Now()
+	review := &Review{
+		ID:          len(pr.Reviews) + 1,
+		Author:      author,
+		State:       state,
+		Body:        body,
+		CommitID:    pr.HeadSHA,
+		SubmittedAt: now,
+	}
+	pr.Reviews = append(pr.Reviews, review)
+
+	for i, dc := range comments {
+		pr.Comments = append(pr.Comments, &ReviewComment{
+			ID:        len(pr.Comments) + 1,
+			ReviewID:  review.ID,
+			Author:    author,
+			Body:      dc.Body,
+			Path:      dc.Path,
+			Position:  dc.Position,
+			DiffLine:  lines[i],
+			CommitID:  pr.HeadSHA,
+			CreatedAt: now,
+		})
+	}
+	pr.ReviewDecision = reviewDecision(pr)
+	return review, nil
+}
+
+// AddReviewComment starts a new comment thread on a line of the pull request's diff.
+func (sm *SessionManager) AddReviewComment(prID int, author, path string, position int, body string) (*ReviewComment, error) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	pr, err := sm.findOpenPullRequest(prID)
+	if err != nil {
+		return nil, err
+	}
+	if author == "" || body == "" {
+		return nil, fmt.Errorf("comment requires an author and a body")
+	}
+	line, err := diffLineAt(pr.Diff, path, position)
+	if err != nil {
+		return nil, err
+	}
+
+	comment := &ReviewComment{
+		ID:        len(pr.Comments) + 1,
+		Author:    author,
+		Body:      body,
+		Path:      path,
+		Position:  position,
+		DiffLine:  line,
+		CommitID:  pr.HeadSHA,
+		CreatedAt: time.Now(),
+	}
+	pr.Comments = append(pr.Comments, comment)
+	return comment, nil
+}
+
+// ReplyToReviewComment adds a reply to the thread containing commentID.
+func (sm *SessionManager) ReplyToReviewComment(prID, commentID int, author, body string) (*ReviewComment, error) {
+	sm.mu.Lock()
+	defer sm.mu.Unlock()
+
+	pr, err := sm.findOpenPullRequest(prID)
+	if err != nil {
+		return nil, err
+	}
+	if author == "" || body == "" {
+		return nil, fmt.Errorf("reply requires an author and a body")
+	}
+
+	var target *ReviewComment
+	for _, c := range pr.Comments {
+		if c.ID == commentID {
+			target = c
+			break
+		}
+	}
+	if target == nil {
+		return nil, fmt.Errorf("comment #%d not found on pull request #%d", commentID, prID)
+	}
+
+	// Replies always hang off the top-level comment of the thread
+	root := target.ID
+	if target.InReplyTo != 0 {
+		root = target.InReplyTo
+	}
+
+	reply := &ReviewComment{
+		ID:        len(pr.Comments) + 1,
+		InReplyTo: root,
+		Author:    author,
+		Body:      body,
+		Path:      target.Path,
+		Position:  target.Position,
+		DiffLine:  target.DiffLine,
+		CommitID:  target.CommitID,
+		CreatedAt: time.Now(),
+	}
+	pr.Comments = append(pr.Comments, reply)
+	return reply, nil
+}
+
+// ReviewSummary returns who currently approves the pull request and who has
+// outstanding change requests, based on each reviewer's latest decisive review.
+func (sm *SessionManager) ReviewSummary(pr *PullRequest) (approvedBy, changesRequestedBy []string) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	return latestReviewStates(pr)
+}
+
+func latestReviewStates(pr *PullRequest) (approvedBy, changesRequestedBy []string) {
+	latest := make(map[string]string)
+	for _, r := range pr.Reviews {
+		// A plain comment does not withdraw an earlier approval or change request
+		if r.State == ReviewCommented {
+			continue
+		}
+		latest[r.Author] = r.State
+	}
+	for author, st := range latest {
+		if st == ReviewApproved {
+			approvedBy = append(approvedBy, author)
+		} else {
+			changesRequestedBy = append(changesRequestedBy, author)
+		}
+	}
+	sort.Strings(approvedBy)
+	sort.Strings(changesRequestedBy)
+	return approvedBy, changesRequestedBy
+}
+
+// reviewDecision summarises the reviews the way the PR list shows them.
+func reviewDecision(pr *PullRequest) string {
+	approved, changes := latestReviewStates(pr)
+	switch {
+	case len(changes) > 0:
+		return ReviewChangesRequested
+	case len(approved) > 0:
+		return ReviewApproved
+	default:
+		return ""
+	}
+}
+
+// findOpenPullRequest looks up an open pull request. Caller holds sm.mu.
+func (sm *SessionManager) findOpenPullRequest(id int) (*PullRequest, error) {
+	for _, pr := range sm.PullRequests {
+		if pr.ID == id {
+			if pr.State != "OPEN" {
+				return nil, fmt.Errorf("pull request #%d is not OPEN (current state: %s)", id, pr.State)
+			}
+			return pr, nil
+		}
+	}
+	return nil, fmt.Errorf("pull request #%d not found", id)
+}
+
+// diffLineAt returns the line at a diff position within the section of path.
+func diffLineAt(diff, path string, position int) (string, error) {
+	if diff == "" {
+		return "", fmt.Errorf("pull request has no diff to comment on")
+	}
+	if position < 1 {
+		return "", fmt.Errorf("invalid diff position %d", position)
+	}
+
+	found, inFile, inHunks := false, false, false
+	pos := 0
+	for _, line := range strings.Split(diff, "\n") {
+		if strings.HasPrefix(line, "diff --git ") {
+			if inFile {
+				break
+			}
+			inFile = strings.HasSuffix(line, " b/"+path) || strings.HasPrefix(line, "diff --git a/"+path+" ")
+			found = found || inFile
+			continue
+		}
+		if !inFile || line == "" {
+			continue
+		}
+		if !inHunks {
+			inHunks = strings.HasPrefix(line, "@@")
+			continue
+		}
+		pos++
+		if pos == position {
+			return line, nil
+		}
+	}
+
+	if !found {
+		return "", fmt.Errorf("path '%s' is not part of the pull request diff", path)
+	}
+	return "", fmt.Errorf("position %d is outside the diff of '%s'", position, path)
+}
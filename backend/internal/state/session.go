@@ -1,6 +1,8 @@
 package state
 
 import (
+	"sort"
+	"strings"
 	"sync"
 	"time"
 
@@ -24,6 +26,9 @@ type Session struct {
 	Manager          *SessionManager // Reference to manager for shared state
 	FileCache        *FileCache      // Cached file listing for performance
 	mu               sync.RWMutex
+
+	clock   *VirtualClock // Deterministic mode (nil = wall clock), see clock.go
+	clockMu sync.RWMutex
 }
 
 // SessionManager handles concurrent access to sessions
@@ -31,11 +36,22 @@ type SessionManager struct {
 	sessions          map[string]*Session
 	SharedRemotes     map[string]*gogit.Repository // Share repositories across all sessions
 	SharedRemotePaths map[string]string            // Maps remote name to local filesystem path
+	remoteNames       map[string]bool              // Keys of SharedRemotes that are names rather than URL or path aliases
 	PullRequests      []*PullRequest
 	NextPRID          int
+	Protections       map[string][]*BranchProtection // Branch protection rules keyed by remote name
+	ForkParents       map[string]string              // Fork remote name -> upstream remote name
+	StatusChecks      map[string][]*StatusCheck      // CI check rules keyed by remote name
 	DataDir           string
 	mu                sync.RWMutex
-	ingestMu          sync.Mutex // Serializes ingestion operations
+	ingestLocksMu     sync.Mutex
+	ingestLocks       map[string]*pathLock // Repo path -> lock serializing writers of that directory
+
+	// Commit statuses per remote name, so forks keep their own and a re-ingest keeps them
+	CommitStatuses map[string]map[plumbing.Hash][]*CommitStatus
+
+	Bots    *BotScheduler // Scripted teammates acting on the shared remotes
+	Ingests *IngestJobs   // Background ingests with streamable progress
 }
 
 // ReflogEntry records a command executed in the session
@@ -56,6 +72,8 @@ type Commit struct {
 	Timestamp      string `json:"timestamp"`
 	Author         string `json:"author,omitempty"`
 	TreeID         string `json:"treeId,omitempty"`
+	Grafted        bool   `json:"grafted,omitempty"` // Shallow boundary: parents were not fetched
+	Note           string `json:"note,omitempty"`    // Note from refs/notes/commits
 }
 
 // PullRequest structure
@@ -65,24 +83,71 @@ type PullRequest struct {
 	Description string    `json:"description"`
 	State       string    `json:"status"`       // "OPEN", "CLOSED", "MERGED"
 	RemoteName  string    `json:"remoteName"`   // The shared remote this PR belongs to
-	HeadRepo    string    `json:"headRepo"`     // simulating fork
+	HeadRepo    string    `json:"headRepo"`     // Fork the head branch lives in; empty for same-repository PRs
 	HeadRef     string    `json:"sourceBranch"` // branch
 	BaseRepo    string    `json:"baseRepo"`
 	BaseRef     string    `json:"targetBranch"`
 	Creator     string    `json:"creator"`
 	CreatedAt   time.Time `json:"createdAt"`
+
+	MergeStrategy string `json:"mergeStrategy,omitempty"` // "merge", "squash", "rebase", "ff-only"
+	MergeCommit   string `json:"mergeCommit,omitempty"`   // New tip of the base branch after merging
+
+	Reviews        []*Review        `json:"reviews,omitempty"`
+	Comments       []*ReviewComment `json:"comments,omitempty"`
+	ReviewDecision string           `json:"reviewDecision,omitempty"` // "APPROVED", "CHANGES_REQUESTED" or empty
+
+	PullRequestStatus // Computed; refreshed whenever either branch moves
+}
+
+// IsCrossRepository reports whether the head branch lives in a fork of the base remote.
+func (pr *PullRequest) IsCrossRepository() bool {
+	return pr.HeadRepo != "" && pr.HeadRepo != pr.RemoteName
+}
+
+// PullRequestStatus is the computed comparison of a pull request's head against its base
+type PullRequestStatus struct {
+	BaseSHA        string            `json:"baseSha,omitempty"`
+	HeadSHA        string            `json:"headSha,omitempty"`
+	Mergeable      bool              `json:"mergeable"`
+	MergeableState string            `json:"mergeableState,omitempty"` // "clean", "dirty" (conflicts), "unknown"
+	StatusMessage  string            `json:"statusMessage,omitempty"`  // e.g. "This branch has conflicts that must be resolved"
+	Conflicts      []string          `json:"conflicts,omitempty"`
+	AheadBy        int               `json:"aheadBy"`  // Commits on head not on base
+	BehindBy       int               `json:"behindBy"` // Commits on base not on head
+	Commits        []Commit          `json:"commits,omitempty"`
+	Files          []PullRequestFile `json:"files,omitempty"`
+	Diff           string            `json:"diff,omitempty"`
+	ChecksState    string            `json:"checksState,omitempty"` // Combined status of the head commit
+	Checks         []*CommitStatus   `json:"checks,omitempty"`
+}
+
+// PullRequestFile is one entry of a pull request's "Files changed" list
+type PullRequestFile struct {
+	Filename  string `json:"filename"`
+	Status    string `json:"status"` // "added", "modified", "removed"
+	Additions int    `json:"additions"`
+	Deletions int    `json:"deletions"`
 }
 
 // NewSessionManager creates a new session manager
 func NewSessionManager() *SessionManager {
-	return &SessionManager{
+	sm := &SessionManager{
 		sessions:          make(map[string]*Session),
 		SharedRemotes:     make(map[string]*gogit.Repository),
 		SharedRemotePaths: make(map[string]string),
+		remoteNames:       make(map[string]bool),
 		PullRequests:      []*PullRequest{},
 		NextPRID:          1,
+		Protections:       make(map[string][]*BranchProtection),
+		ForkParents:       make(map[string]string),
+		StatusChecks:      make(map[string][]*StatusCheck),
+		CommitStatuses:    make(map[string]map[plumbing.Hash][]*CommitStatus),
 		DataDir:           ".gitgym-data/remotes",
 	}
+	sm.Bots = newBotScheduler(sm)
+	sm.Ingests = newIngestJobs(sm)
+	return sm
 }
 
 // CreateSession initializes a new session
@@ -124,6 +189,68 @@ func (sm *SessionManager) GetSharedRemote(name string) (*gogit.Repository, bool)
 	return repo, ok
 }
 
+// SharedRemoteNames returns the names shared remotes were registered under,
+// leaving out their URL and path aliases.
+func (sm *SessionManager) SharedRemoteNames() []string {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	var names []string
+	for name := range sm.remoteNames {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// SharedRemoteName returns the name of the shared remote reachable under key
+// (its name, URL, pseudo-URL or path), so a session's remotes can show which
+// shared remote each of them points at.
+func (sm *SessionManager) SharedRemoteName(key string) (string, bool) {
+	sm.mu.RLock()
+	defer sm.mu.RUnlock()
+	path, ok := sm.SharedRemotePaths[key]
+	if !ok {
+		if path, ok = sm.SharedRemotePaths[strings.TrimPrefix(key, "/")]; !ok {
+			return "", false
+		}
+	}
+	var names []string
+	for k, p := range sm.SharedRemotePaths {
+		if p == path && sm.remoteNames[k] {
+			names = append(names, k)
+		}
+	}
+	if len(names) == 0 {
+		return "", false
+	}
+	sort.Strings(names)
+	return names[0], true
+}
+
+// remoteNameFor returns the name repo is registered under, for keying per-remote
+// state. A repository put into SharedRemotes without registering it falls back
+// to the first key it is stored under. Caller holds sm.mu.
+func (sm *SessionManager) remoteNameFor(repo *gogit.Repository) string {
+	var names, keys []string
+	for key, r := range sm.SharedRemotes {
+		if r != repo {
+			continue
+		}
+		if sm.remoteNames[key] {
+			names = append(names, key)
+		}
+		keys = append(keys, key)
+	}
+	if len(names) == 0 {
+		names = keys
+	}
+	if len(names) == 0 {
+		return ""
+	}
+	sort.Strings(names)
+	return names[0]
+}
+
 // Global Lock/RLock for Manager if needed (though mostly internal methods handle it)
 func (sm *SessionManager) Lock() {
 	sm.mu.Lock()
@@ -0,0 +1,113 @@
+package state
+
+import (
+	"fmt"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/storer"
+	"github.com/go-git/go-git/v5/storage"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+// HybridStorer implements storage.Storer by embedding the Local storer.
+// It overrides specific ObjectRead methods to delegate to Shared if not found locally.
+type HybridStorer struct {
+	storage.Storer // Embed Local storer to inherit all standard methods (Refs, Config, Index, etc.)
+	Shared         storage.Storer
+}
+
+func NewHybridStorer(local, shared storage.Storer) *HybridStorer {
+	return &HybridStorer{
+		Storer: local,
+		Shared: shared,
+	}
+}
+
+// -- ObjectStorer Overrides (Blobs, Trees, Commits) --
+
+// EncodedObject tries Local first, then Shared
+func (s *HybridStorer) EncodedObject(t plumbing.ObjectType, h plumbing.Hash) (plumbing.EncodedObject, error) {
+	// Try Local first
+	obj, err := s.Storer.EncodedObject(t, h)
+	if err == nil {
+		return obj, nil
+	}
+
+	// If not found locally, try Shared
+	return s.Shared.EncodedObject(t, h)
+}
+
+// EncodedObjectSize tries Local first, then Shared
+func (s *HybridStorer) EncodedObjectSize(h plumbing.Hash) (int64, error) {
+	sz, err := s.Storer.EncodedObjectSize(h)
+	if err == nil {
+		return sz, nil
+	}
+	return s.Shared.EncodedObjectSize(h)
+}
+
+// HasEncodedObject checks Local first, then Shared
+func (s *HybridStorer) HasEncodedObject(h plumbing.Hash) (err error) {
+	err = s.Storer.HasEncodedObject(h)
+	if err == nil {
+		return nil
+	}
+	return s.Shared.HasEncodedObject(h)
+}
+
+// IterEncodedObjects - Iterate over both Local and Shared objects using MultiEncodedObjectIter
+func (s *HybridStorer) IterEncodedObjects(t plumbing.ObjectType) (storer.EncodedObjectIter, error) {
+	// Local Iterator
+	localIter, err := s.Storer.IterEncodedObjects(t)
+	if err != nil {
+		return nil, err
+	}
+
+	// Shared Iterator
+	sharedIter, err := s.Shared.IterEncodedObjects(t)
+	if err != nil {
+		return nil, err
+	}
+
+	// Combine them
+	return storer.NewMultiEncodedObjectIter([]storer.EncodedObjectIter{localIter, sharedIter}), nil
+}
+
+// LocalStorer returns the underlying local storage without the shared fallback.
+// Use this when you need to iterate only locally-stored objects (e.g., for local graph view).
+func (s *HybridStorer) LocalStorer() storage.Storer {
+	return s.Storer
+}
+
+// Quarantine returns a view of repo that receives a push before it is accepted,
+// like the quarantine directory of git's receive-pack. It reads repo's refs and
+// objects, but objects written to it stay out of repo until CopyObjectRecursive
+// moves them over (or AcceptQuarantine), so a rejected push leaves nothing behind.
+func Quarantine(repo *gogit.Repository) (*gogit.Repository, error) {
+	scratch := memory.NewStorage()
+	refs, err := repo.Storer.IterReferences()
+	if err != nil {
+		return nil, err
+	}
+	if err := refs.ForEach(scratch.SetReference); err != nil {
+		return nil, err
+	}
+	return gogit.Open(NewHybridStorer(scratch, repo.Storer), nil)
+}
+
+// AcceptQuarantine copies every object written to a Quarantine view into repo.
+func AcceptQuarantine(quarantine, repo *gogit.Repository) error {
+	hybrid, ok := quarantine.Storer.(*HybridStorer)
+	if !ok {
+		return fmt.Errorf("repository is not a quarantine")
+	}
+	iter, err := hybrid.LocalStorer().IterEncodedObjects(plumbing.AnyObject)
+	if err != nil {
+		return err
+	}
+	return iter.ForEach(func(obj plumbing.EncodedObject) error {
+		_, err := repo.Storer.SetEncodedObject(obj)
+		return err
+	})
+}
@@ -21,6 +21,7 @@ type GraphState struct {
 	SharedRemotes    []string                   `json:"sharedRemotes"`
 	Initialized      bool                       `json:"initialized"`
 	ActiveProject    string                     `json:"activeProject"`
+	CommitStatuses   map[string]string          `json:"commitStatuses,omitempty"` // Commit SHA -> combined CI status
 }
 
 type ProjectMetadata struct {
@@ -28,8 +29,9 @@ type ProjectMetadata struct {
 }
 
 type Remote struct {
-	Name string   `json:"name"`
-	URLs []string `json:"urls"`
+	Name         string   `json:"name"`
+	URLs         []string `json:"urls"`
+	SharedRemote string   `json:"sharedRemote,omitempty"` // Shared remote the URL resolves to, if any
 }
 
 type Head struct {
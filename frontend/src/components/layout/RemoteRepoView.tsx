@@ -42,6 +42,7 @@ const RemoteRepoView: React.FC<RemoteRepoViewProps> = ({ topHeight, onResizeStar
         elapsedSeconds,
         repoInfo,
         errorMessage,
+        ingestProgress,
         performClone,
         cancelClone
     } = useRemoteClone();
@@ -223,6 +224,7 @@ const RemoteRepoView: React.FC<RemoteRepoViewProps> = ({ topHeight, onResizeStar
                             elapsedSeconds={elapsedSeconds}
                             repoInfo={repoInfo}
                             errorMessage={errorMessage}
+                            ingestProgress={ingestProgress}
                             onRetry={handleRetry}
                             onCancel={cancelClone}
                         />
@@ -2,6 +2,7 @@ import React from 'react';
 import { Loader2, AlertCircle, CheckCircle, RefreshCw, XCircle } from 'lucide-react';
 import { useTranslation } from 'react-i18next';
 import { Button } from '../../common/Button';
+import type { IngestProgress } from '../../../types/gitTypes';
 
 export type CloneStatus = 'idle' | 'fetching_info' | 'cloning' | 'creating' | 'complete' | 'error';
 
@@ -15,6 +16,8 @@ interface CloneProgressProps {
         message: string;
     };
     errorMessage?: string;
+    // Server-reported ingest progress; preferred over the time estimate when it has a percentage
+    ingestProgress?: IngestProgress;
     onRetry?: () => void;
     onCancel?: () => void;
     successMessage?: string;
@@ -29,6 +32,7 @@ const CloneProgress: React.FC<CloneProgressProps> = ({
     elapsedSeconds,
     repoInfo,
     errorMessage,
+    ingestProgress,
     onRetry,
     onCancel,
     successMessage,
@@ -41,10 +45,13 @@ const CloneProgress: React.FC<CloneProgressProps> = ({
         return null;
     }
 
-    // Calculation logic - simple linear progress
-    const progress = estimatedSeconds > 0 && elapsedSeconds >= 0
-        ? Math.min(100, (elapsedSeconds / estimatedSeconds) * 100)
-        : 0;
+    // Real progress when the server knows the total, otherwise linear progress over the estimate
+    const hasPercent = ingestProgress !== undefined && ingestProgress.percent >= 0;
+    const progress = hasPercent
+        ? Math.min(100, ingestProgress?.percent ?? 0)
+        : estimatedSeconds > 0 && elapsedSeconds >= 0
+            ? Math.min(100, (elapsedSeconds / estimatedSeconds) * 100)
+            : 0;
 
     const remainingSeconds = Math.max(0, estimatedSeconds - elapsedSeconds);
     const formatTime = (seconds: number) => {
@@ -90,7 +97,7 @@ const CloneProgress: React.FC<CloneProgressProps> = ({
                 </div>
 
                 {/* Progress Bar */}
-                {status === 'cloning' && estimatedSeconds > 0 && (
+                {status === 'cloning' && (estimatedSeconds > 0 || hasPercent) && (
                     <div style={{ marginTop: '8px' }}>
                         <div style={{ height: '6px', background: 'var(--bg-secondary)', borderRadius: '3px', overflow: 'hidden' }}>
                             <div style={{
@@ -102,6 +109,7 @@ const CloneProgress: React.FC<CloneProgressProps> = ({
                             }} />
                         </div>
                         <div style={{ fontSize: '11px', color: 'var(--text-tertiary)', marginTop: '4px' }}>
+                            {ingestProgress?.phase && `${ingestProgress.phase}${hasPercent ? ` ${Math.round(progress)}%` : ''} · `}
                             {remainingSeconds > 0 ? `~${formatTime(remainingSeconds)} ${t('remote.status.remaining')}` : t('remote.status.almostDone')}
                         </div>
                     </div>
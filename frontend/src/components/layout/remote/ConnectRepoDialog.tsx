@@ -27,7 +27,8 @@ const ConnectRepoDialog: React.FC<ConnectRepoDialogProps> = ({ isOpen, onClose,
         errorMessage,
         elapsedSeconds,
         estimatedSeconds,
-        repoInfo
+        repoInfo,
+        ingestProgress
     } = useRemoteClone();
 
     // Check availability
@@ -178,6 +179,7 @@ const ConnectRepoDialog: React.FC<ConnectRepoDialogProps> = ({ isOpen, onClose,
                             estimatedSeconds={estimatedSeconds}
                             repoInfo={repoInfo}
                             errorMessage={errorMessage}
+                            ingestProgress={ingestProgress}
                             onRetry={() => performClone(repoUrl)}
                             onCancel={cancelClone}
                             hideCancelButton={true}
@@ -90,7 +90,23 @@ export const CommitRow: React.FC<CommitRowProps> = ({
             }}
         >
             {node.isGhost && '[SIMULATION] '}
+            {node.grafted && (
+                <span
+                    title="shallow clone: older history was not fetched"
+                    style={{ color: 'var(--text-tertiary)', marginRight: '4px' }}
+                >
+                    (grafted)
+                </span>
+            )}
             {node.message}
+            {node.note && (
+                <span
+                    title={node.note}
+                    style={{ color: 'var(--text-tertiary)', marginLeft: '4px' }}
+                >
+                    (notes)
+                </span>
+            )}
         </span>
 
 
@@ -1,5 +1,5 @@
 import React, { createContext, useContext, useCallback } from 'react';
-import type { GitState, PullRequest } from '../types/gitTypes';
+import type { GitState, IngestJob, PullRequest } from '../types/gitTypes';
 import { useTerminalTranscript, type TranscriptLine } from '../hooks/useTerminalTranscript';
 import { useGitSession } from '../hooks/useGitSession';
 import { useGitData } from '../hooks/useGitData';
@@ -30,7 +30,7 @@ interface GitContextType {
     removeDeveloper: (name: string) => Promise<void>;
     pullRequests: PullRequest[];
     refreshPullRequests: () => Promise<void>;
-    ingestRemote: (name: string, url: string, depth?: number) => Promise<void>;
+    ingestRemote: (name: string, url: string, depth?: number, onProgress?: (job: IngestJob) => void) => Promise<IngestJob>;
     createPullRequest: (title: string, desc: string, source: string, target: string) => Promise<void>;
     mergePullRequest: (id: number) => Promise<void>;
     deletePullRequest: (id: number) => Promise<void>;
@@ -135,14 +135,11 @@ export const GitProvider: React.FC<{ children: React.ReactNode }> = ({ children
         await runCommand(`restore --staged ${file}`);
     }, [runCommand]);
 
-    const ingestRemote = useCallback(async (name: string, url: string, depth?: number) => {
-        // We use gitService inside hooks, but ingestRemote is complex?
-        // Actually runCommand implementation encapsulates service logic mostly.
-        // But ingestRemote is distinct service call.
-        // We can just import service here or put in useGitCommand?
-        // Let's keep it clean and use service + fetchState.
-        await gitService.ingestRemote(name, url, depth);
+    // Resolves with the finished ingest job; onProgress sees every update on the way
+    const ingestRemote = useCallback(async (name: string, url: string, depth?: number, onProgress?: (job: IngestJob) => void) => {
+        const job = await gitService.ingestRemote(name, url, depth, onProgress);
         await fetchState(sessionId);
+        return job;
     }, [sessionId, fetchState]);
 
     const createPullRequest = useCallback(async (title: string, desc: string, source: string, target: string) => {
@@ -3,6 +3,7 @@ import { useGit } from '../context/GitAPIContext';
 import { useTranslation } from 'react-i18next';
 import type { CloneStatus } from '../components/layout/remote/CloneProgress';
 import { gitService } from '../services/gitService';
+import type { IngestProgress } from '../types/gitTypes';
 
 export interface RepoInfo {
     name: string;
@@ -19,6 +20,8 @@ export const useRemoteClone = () => {
     const [elapsedSeconds, setElapsedSeconds] = useState(0);
     const [repoInfo, setRepoInfo] = useState<RepoInfo | undefined>(undefined);
     const [errorMessage, setErrorMessage] = useState<string | undefined>(undefined);
+    // Live progress reported by the server while the ingest job runs
+    const [ingestProgress, setIngestProgress] = useState<IngestProgress | undefined>(undefined);
 
     // Timer ref
     const timerRef = useRef<number | null>(null);
@@ -53,6 +56,7 @@ export const useRemoteClone = () => {
         setElapsedSeconds(0);
         setEstimatedSeconds(0);
         setRepoInfo(undefined);
+        setIngestProgress(undefined);
 
         const validationError = validateUrl(url);
         if (validationError) {
@@ -100,7 +104,7 @@ export const useRemoteClone = () => {
             console.log('Ingesting remote with name:', remoteName, 'URL:', url);
 
             // Use derived name instead of 'origin' so it shows up in the backend list (which filters out 'origin')
-            await ingestRemote(remoteName, url, depth);
+            await ingestRemote(remoteName, url, depth, (job) => setIngestProgress(job.progress));
             await fetchServerState(remoteName);
 
             if (timerRef.current) {
@@ -189,6 +193,7 @@ export const useRemoteClone = () => {
         }
         setCloneStatus('idle');
         setErrorMessage(undefined);
+        setIngestProgress(undefined);
     }, []);
 
     return {
@@ -198,6 +203,7 @@ export const useRemoteClone = () => {
         elapsedSeconds,
         repoInfo,
         errorMessage,
+        ingestProgress,
         performClone,
         performCreate,
         cancelClone
@@ -1,10 +1,18 @@
-import type { GitState, PullRequest } from '../types/gitTypes';
+import type { BotEvent, BotScript, BotStatus, GitState, IngestJob, PullRequest, RemoteCommitRequest, PullRequestReview, PullRequestReviewComment, PullRequestReviewState } from '../types/gitTypes';
 
 interface InitResponse {
     status: string;
     sessionId: string;
 }
 
+export interface DeterministicMode {
+    enabled: boolean;
+    start?: string;
+    stepSeconds?: number;
+    ticks?: number;
+    identity?: string;
+}
+
 interface CommandResponse {
     output?: string;
     error?: string;
@@ -17,6 +25,25 @@ export const gitService = {
         return res.json();
     },
 
+    async getDeterministicMode(sessionId: string): Promise<DeterministicMode> {
+        const res = await fetch(`/api/session/deterministic?sessionId=${sessionId}`);
+        if (!res.ok) throw new Error('Failed to fetch deterministic mode');
+        return res.json();
+    },
+
+    async setDeterministicMode(sessionId: string, enabled: boolean, options: { start?: string; stepSeconds?: number } = {}): Promise<DeterministicMode> {
+        const res = await fetch('/api/session/deterministic', {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ sessionId, enabled, ...options })
+        });
+        if (!res.ok) {
+            const errText = await res.text();
+            throw new Error(errText || 'Failed to set deterministic mode');
+        }
+        return res.json();
+    },
+
     async fetchState(sessionId: string, showAll: boolean = false): Promise<GitState> {
         const res = await fetch(`/api/state?sessionId=${sessionId}&t=${Date.now()}&showAll=${showAll}`);
         if (!res.ok) throw new Error('Failed to fetch state');
@@ -84,7 +111,7 @@ export const gitService = {
         return res.json();
     },
 
-    async ingestRemote(name: string, url: string, depth?: number): Promise<void> {
+    async ingestRemote(name: string, url: string, depth?: number, onProgress?: (job: IngestJob) => void): Promise<IngestJob> {
         const res = await fetch('/api/remote/ingest', {
             method: 'POST',
             headers: { 'Content-Type': 'application/json' },
@@ -94,6 +121,56 @@ export const gitService = {
             const errText = await res.text();
             throw new Error(errText || 'Failed to ingest remote');
         }
+        const job: IngestJob = await res.json();
+        const final = await this.watchIngest(job.id, onProgress);
+        if (final.state !== 'succeeded') {
+            throw new Error(final.error || `Ingest ${final.state}`);
+        }
+        return final;
+    },
+
+    // Follows an ingest job's event stream until it finishes
+    watchIngest(id: string, onProgress?: (job: IngestJob) => void): Promise<IngestJob> {
+        return new Promise((resolve, reject) => {
+            const source = new EventSource(`/api/remote/ingest/events?id=${encodeURIComponent(id)}`);
+            source.addEventListener('progress', (e) => {
+                onProgress?.(JSON.parse((e as MessageEvent).data));
+            });
+            source.addEventListener('done', (e) => {
+                source.close();
+                const job: IngestJob = JSON.parse((e as MessageEvent).data);
+                onProgress?.(job);
+                resolve(job);
+            });
+            source.onerror = () => {
+                source.close();
+                reject(new Error('Lost connection to ingest progress'));
+            };
+        });
+    },
+
+    async cancelIngest(id: string): Promise<void> {
+        const res = await fetch('/api/remote/ingest/cancel', {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ id })
+        });
+        if (!res.ok) {
+            const errText = await res.text();
+            throw new Error(errText || 'Failed to cancel ingest');
+        }
+    },
+
+    async uploadRemote(name: string, file: File): Promise<{ name: string; source: string }> {
+        const form = new FormData();
+        form.append('name', name);
+        form.append('file', file);
+        const res = await fetch('/api/remote/ingest/upload', { method: 'POST', body: form });
+        if (!res.ok) {
+            const errText = await res.text();
+            throw new Error(errText || 'Failed to upload remote');
+        }
+        return res.json();
     },
 
     async getRemoteInfo(url: string): Promise<{
@@ -146,7 +223,7 @@ export const gitService = {
         return res.json();
     },
 
-    async createPullRequest(pr: { title: string; description: string; sourceBranch: string; targetBranch: string; creator: string; remoteName: string }): Promise<PullRequest> {
+    async createPullRequest(pr: { title: string; description: string; sourceBranch: string; targetBranch: string; creator: string; remoteName: string; headRepo?: string }): Promise<PullRequest> {
         const res = await fetch('/api/remote/pull-requests/create', {
             method: 'POST',
             headers: { 'Content-Type': 'application/json' },
@@ -156,11 +233,11 @@ export const gitService = {
         return res.json();
     },
 
-    async mergePullRequest(id: number, remoteName: string = 'origin'): Promise<void> {
+    async mergePullRequest(id: number, remoteName: string = 'origin', strategy?: 'merge' | 'squash' | 'rebase' | 'ff-only'): Promise<void> {
         const res = await fetch('/api/remote/pull-requests/merge', {
             method: 'POST',
             headers: { 'Content-Type': 'application/json' },
-            body: JSON.stringify({ id, remoteName })
+            body: JSON.stringify({ id, remoteName, strategy })
         });
         if (!res.ok) {
             const errText = await res.text();
@@ -180,6 +257,110 @@ export const gitService = {
         }
     },
 
+    async submitReview(
+        id: number,
+        author: string,
+        state: PullRequestReviewState,
+        body: string = '',
+        comments: { path: string; position: number; body: string }[] = []
+    ): Promise<PullRequestReview> {
+        const res = await fetch('/api/remote/pull-requests/review', {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ id, author, state, body, comments })
+        });
+        if (!res.ok) {
+            const errText = await res.text();
+            throw new Error(errText || 'Failed to submit review');
+        }
+        return res.json();
+    },
+
+    async addReviewComment(
+        id: number,
+        author: string,
+        body: string,
+        anchor: { path: string; position: number } | { inReplyTo: number }
+    ): Promise<PullRequestReviewComment> {
+        const res = await fetch('/api/remote/pull-requests/comment', {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ id, author, body, ...anchor })
+        });
+        if (!res.ok) {
+            const errText = await res.text();
+            throw new Error(errText || 'Failed to add comment');
+        }
+        return res.json();
+    },
+
+    async forkRemote(upstream: string, name: string): Promise<{ name: string; parent: string; remoteUrl: string }> {
+        const res = await fetch('/api/remote/fork', {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ upstream, name })
+        });
+        if (!res.ok) {
+            const errText = await res.text();
+            throw new Error(errText || 'Failed to fork remote');
+        }
+        return res.json();
+    },
+
+    async syncFork(name: string, branch: string = 'main'): Promise<{ name: string; upstream: string; branch: string; head: string }> {
+        const res = await fetch('/api/remote/fork/sync', {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ name, branch })
+        });
+        if (!res.ok) {
+            const errText = await res.text();
+            throw new Error(errText || 'Failed to sync fork');
+        }
+        return res.json();
+    },
+
+    async fetchBots(): Promise<BotStatus> {
+        const res = await fetch('/api/bots');
+        if (!res.ok) throw new Error('Failed to fetch bots');
+        return res.json();
+    },
+
+    async loadBots(scripts: BotScript[]): Promise<BotStatus> {
+        const res = await fetch('/api/bots', {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify({ scripts })
+        });
+        if (!res.ok) {
+            const errText = await res.text();
+            throw new Error(errText || 'Failed to load bots');
+        }
+        return res.json();
+    },
+
+    async controlBots(action: 'pause' | 'resume' | 'step'): Promise<{ status: BotStatus; event?: BotEvent }> {
+        const res = await fetch(`/api/bots/${action}`, { method: 'POST' });
+        if (!res.ok) {
+            const errText = await res.text();
+            throw new Error(errText || `Failed to ${action} bots`);
+        }
+        return res.json();
+    },
+
+    async simulateRemoteCommit(request: RemoteCommitRequest): Promise<{ status: string; commit: string; branch: string }> {
+        const res = await fetch('/api/remote/simulate-commit', {
+            method: 'POST',
+            headers: { 'Content-Type': 'application/json' },
+            body: JSON.stringify(request)
+        });
+        if (!res.ok) {
+            const errText = await res.text();
+            throw new Error(errText || 'Failed to simulate commit');
+        }
+        return res.json();
+    },
+
     async resetRemote(name: string = 'origin'): Promise<void> {
         const res = await fetch('/api/remote/reset', {
             method: 'POST',
@@ -6,12 +6,15 @@ export interface Commit {
     branch: string;
     timestamp: string;
     author: string;
+    grafted?: boolean; // Shallow boundary: parents were not fetched
+    note?: string; // Note from refs/notes/commits (git notes)
 }
 
 
 export interface Remote {
     name: string;
     urls: string[];
+    sharedRemote?: string; // Shared remote this remote's URL resolves to
 }
 
 export interface GitState {
@@ -34,6 +37,7 @@ export interface GitState {
     activeProject?: string;
     remotes?: Remote[]; // Defined remotes
     sharedRemotes?: string[];
+    commitStatuses?: Record<string, CommitStatusState>; // commitId -> combined CI status
 
 
     output: string[];
@@ -55,4 +59,152 @@ export interface PullRequest {
     creator: string;
     createdAt: string;
     remoteName?: string;
+    // Set for pull requests opened from a fork of remoteName
+    headRepo?: string;
+    baseRepo?: string;
+    mergeStrategy?: string;
+    mergeCommit?: string;
+    // Computed comparison, refreshed whenever either branch moves
+    baseSha?: string;
+    headSha?: string;
+    mergeable?: boolean;
+    mergeableState?: 'clean' | 'dirty' | 'unknown';
+    statusMessage?: string;
+    conflicts?: string[];
+    aheadBy?: number;
+    behindBy?: number;
+    commits?: Commit[];
+    files?: PullRequestFile[];
+    diff?: string;
+    reviews?: PullRequestReview[];
+    comments?: PullRequestReviewComment[];
+    reviewDecision?: 'APPROVED' | 'CHANGES_REQUESTED';
+    checksState?: CommitStatusState;
+    checks?: CommitStatus[];
+}
+
+export type PullRequestReviewState = 'APPROVED' | 'CHANGES_REQUESTED' | 'COMMENTED';
+
+export interface PullRequestReview {
+    id: number;
+    author: string;
+    state: PullRequestReviewState;
+    body?: string;
+    commitId: string;
+    submittedAt: string;
+}
+
+export interface PullRequestReviewComment {
+    id: number;
+    reviewId?: number;
+    inReplyTo?: number;
+    author: string;
+    body: string;
+    path: string;
+    position: number;
+    diffLine: string;
+    commitId: string;
+    createdAt: string;
+}
+
+export type CommitStatusState = 'pending' | 'success' | 'failure' | 'error';
+
+export interface CommitStatus {
+    context: string;
+    state: CommitStatusState;
+    description?: string;
+    sha: string;
+    updatedAt: string;
+}
+
+export interface StatusCheckRule {
+    context: string;
+    type: 'file-exists' | 'no-conflict-markers' | 'message-matches';
+    path?: string;
+    pattern?: string;
+}
+
+export interface PullRequestFile {
+    filename: string;
+    status: 'added' | 'modified' | 'removed';
+    additions: number;
+    deletions: number;
+}
+
+export interface BotAction {
+    every?: string;
+    after?: string;
+    onPush?: string;
+    times?: number;
+    branch: string;
+    from?: string;
+    file?: string;
+    content?: string;
+    replace?: boolean;
+    message?: string;
+}
+
+export interface BotScript {
+    name: string;
+    email?: string;
+    remote: string;
+    actions: BotAction[];
+}
+
+export interface BotEvent {
+    time: string;
+    bot: string;
+    remote: string;
+    branch: string;
+    commit?: string;
+    message?: string;
+    error?: string;
+}
+
+export interface BotStatus {
+    running: boolean;
+    scripts: BotScript[];
+    jobs: { bot: string; remote: string; branch: string; trigger: string; runs: number; next?: string }[];
+    events: BotEvent[];
+}
+
+export interface FileChange {
+    op?: 'write' | 'append' | 'delete' | 'rename';
+    path: string;
+    content?: string;
+    to?: string;
+}
+
+export interface RemoteCommitRequest {
+    name: string;
+    message?: string;
+    branch?: string;
+    parents?: string[];
+    merge?: string[];
+    changes?: FileChange[];
+    author?: string;
+    email?: string;
+    date?: string;
+    tag?: string;
+    tagMessage?: string;
+}
+
+export interface IngestProgress {
+    phase: string;
+    percent: number; // -1 when the phase has no known total
+    current?: number;
+    total?: number;
+    bytes: number;
+}
+
+export interface IngestJob {
+    id: string;
+    name: string;
+    source: string;
+    depth?: number;
+    state: 'running' | 'succeeded' | 'failed' | 'canceled';
+    error?: string;
+    progress: IngestProgress;
+    startedAt: string;
+    finishedAt?: string;
 }